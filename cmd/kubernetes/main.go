@@ -17,18 +17,60 @@ limitations under the License.
 package main
 
 import (
+	"log"
+	"os"
+
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/manualapprovalgate"
+	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektonchain"
 	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektonconfig"
 	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektondashboard"
+	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektonhub"
+	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektoninstallerset"
 	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektonpipeline"
+	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektonresult"
 	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektontrigger"
+	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/trustbundle"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/adopt"
 	"knative.dev/pkg/injection/sharedmain"
+	"knative.dev/pkg/signals"
 )
 
 func main() {
-	sharedmain.Main("tekton-operator",
+	if len(os.Args) > 1 && os.Args[1] == adopt.Command {
+		adopt.Main(os.Args[2:])
+		return
+	}
+	timings, err := common.TimingsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	election, err := common.OperatorElectionFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	standby := common.NewStandby()
+	ctors := standby.Controllers(timings.Controllers(
 		tektonpipeline.NewController,
 		tektontrigger.NewController,
 		tektondashboard.NewController,
+		tektonresult.NewController,
+		tektonchain.NewController,
+		tektonhub.NewController,
+		manualapprovalgate.NewController,
 		tektonconfig.NewController,
-	)
+		tektoninstallerset.NewController,
+		trustbundle.NewController,
+	)...)
+	cfg := sharedmain.ParseAndGetConfigOrDie()
+	ctx := timings.Context(signals.NewContext())
+	// every replica runs the controllers to keep their caches warm, the
+	// replicas electing the one reconciling, so that the controllers don't
+	// need to elect leaders of their own
+	go func() {
+		if err := election.Run(ctx, cfg, standby); err != nil {
+			log.Fatal(err)
+		}
+	}()
+	sharedmain.MainWithConfig(sharedmain.WithHADisabled(ctx), "tekton-operator", cfg, ctors...)
 }
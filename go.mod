@@ -8,6 +8,7 @@ require (
 	github.com/manifestival/manifestival v0.6.1
 	github.com/markbates/inflect v1.0.4
 	github.com/tektoncd/plumbing v0.0.0-20201021153918-6b7e894737b5
+	go.opencensus.io v0.22.4
 	go.uber.org/zap v1.15.0
 	golang.org/x/mod v0.3.0
 	gomodules.xyz/jsonpatch/v2 v2.1.0
@@ -17,8 +17,11 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"knative.dev/pkg/apis"
 )
 
@@ -32,6 +35,71 @@ const (
 	// DeploymentsAvailable is a Condition indicating whether or not the Deployments of
 	// the respective component have come up successfully.
 	DeploymentsAvailable apis.ConditionType = "DeploymentsAvailable"
+	// DeprecatedFieldsUnset is a Condition indicating whether the spec sets
+	// fields slated for removal. It doesn't affect the readiness of the
+	// component.
+	DeprecatedFieldsUnset apis.ConditionType = "DeprecatedFieldsUnset"
+	// DowngradeBlocked is a Condition set while the install of a payload
+	// older than the installed one is blocked. It is cleared once the
+	// payload isn't older anymore, or the downgrade is allowed.
+	DowngradeBlocked apis.ConditionType = "DowngradeBlocked"
+)
+
+const (
+	// ManagedByKey labels the payload resources the operator applies, with
+	// the ManagedByValue value, for its informers to watch those only
+	ManagedByKey = "app.kubernetes.io/managed-by"
+	// ManagedByValue is the value of the ManagedByKey label
+	ManagedByValue = "tekton-operator"
+)
+
+// The reasons of the conditions of the components, for automation to
+// branch on rather than on their messages.
+const (
+	// ReasonError is the reason of failures without a more specific one.
+	ReasonError = "Error"
+	// ReasonTransformError is the reason of a payload which couldn't be
+	// transformed, e.g. because of an invalid spec.
+	ReasonTransformError = "TransformError"
+	// ReasonDependencyMissing is the reason of a component waiting for
+	// something it needs, e.g. another component or a secret.
+	ReasonDependencyMissing = "DependencyMissing"
+	// ReasonApplyConflict is the reason of a payload resource which was
+	// changed concurrently while applying it.
+	ReasonApplyConflict = "ApplyConflict"
+	// ReasonTimeoutWaitingReady is the reason of deployments which didn't
+	// become available within their progress deadline.
+	ReasonTimeoutWaitingReady = "TimeoutWaitingReady"
+	// ReasonApplyFailed is the reason of payload resources which couldn't
+	// be applied or deleted for any other reason than a conflict.
+	ReasonApplyFailed = "ApplyFailed"
+	// ReasonResourceIgnored is the reason of an instance the operator
+	// doesn't reconcile, e.g. named other than the expected singleton.
+	ReasonResourceIgnored = "ResourceIgnored"
+	// ReasonInvalidPayload is the reason of a payload whose resources are
+	// invalid, e.g. don't match the schemas of their CRDs.
+	ReasonInvalidPayload = "InvalidPayload"
+	// ReasonResourceTooLarge is the reason of payload resources exceeding
+	// the size etcd accepts.
+	ReasonResourceTooLarge = "ResourceTooLarge"
+	// ReasonImagePinFailed is the reason of payload images whose digest
+	// couldn't be resolved to pin them.
+	ReasonImagePinFailed = "ImagePinFailed"
+	// ReasonDeprecatedFields is the reason of a spec setting fields slated
+	// for removal.
+	ReasonDeprecatedFields = "DeprecatedFields"
+	// ReasonImagePullPrecheckFailed is the reason of a payload deployment
+	// image which couldn't be resolved against its registry.
+	ReasonImagePullPrecheckFailed = "ImagePullPrecheckFailed"
+	// ReasonSignatureVerificationFailed is the reason of payload images
+	// without a valid cosign signature made with the configured keys.
+	ReasonSignatureVerificationFailed = "SignatureVerificationFailed"
+	// ReasonReconciling is the reason of a component installing a
+	// generation of its spec not observed yet.
+	ReasonReconciling = "Reconciling"
+	// ReasonDowngradeBlocked is the reason of a payload older than the
+	// installed one, which isn't installed unless the downgrade is allowed.
+	ReasonDowngradeBlocked = "DowngradeBlocked"
 )
 
 // TektonComponent is a common interface for accessing meta, spec and status of all known types.
@@ -49,21 +117,64 @@ type TektonComponent interface {
 type TektonComponentSpec interface {
 	// GetTargetNamespace gets the version to be installed
 	GetTargetNamespace() string
+	// IsTargetNamespaceManaged returns true if the operator owns the target namespace
+	IsTargetNamespaceManaged() bool
+	// GetPodAnnotations gets the annotations to add to payload pods
+	GetPodAnnotations() map[string]string
+	// GetKubeconfigSecret gets the reference to the kubeconfig of a remote target cluster
+	GetKubeconfigSecret() *KubeconfigSecretReference
+	// GetRegistry gets the registry settings of the payload images
+	GetRegistry() Registry
+	// GetSignatureVerification gets the signature verification settings of the payload images
+	GetSignatureVerification() *SignatureVerification
+	// GetProxy gets the egress proxy of the payload deployments
+	GetProxy() *Proxy
+	// GetOptionsProfile gets the name of the options profile of the payload deployments
+	GetOptionsProfile() string
+	// GetAllowDowngrade gets whether a payload older than the installed one may be installed
+	GetAllowDowngrade() bool
+	// GetHighAvailability gets the high availability settings of the payload controllers
+	GetHighAvailability() *HighAvailability
+	// GetConfig gets the configuration of the payload components
+	GetConfig() Config
+	// GetOptions gets the per deployment options of the payload
+	GetOptions() Options
+	// GetLabels gets the labels to add to every payload resource
+	GetLabels() map[string]string
+	// GetAnnotations gets the annotations to add to every payload resource
+	GetAnnotations() map[string]string
+	// GetEnv gets the environment variables to set on payload containers
+	GetEnv() []EnvOverride
+	// GetCostAllocation gets the cost allocation labels of the payload
+	GetCostAllocation() *CostAllocation
+	// GetDisruptionPolicy gets the disruption policy of the payload pods
+	GetDisruptionPolicy() *DisruptionPolicy
 }
 
 // TektonComponentStatus is a common interface for status mutations of all known types.
 type TektonComponentStatus interface {
+	// InitializeConditions initializes the conditions to unknown.
+	InitializeConditions()
 	// MarkInstallSucceeded marks the InstallationSucceeded status as true.
 	MarkInstallSucceeded()
 	// MarkInstallFailed marks the InstallationSucceeded status as false with the given
 	// message.
 	MarkInstallFailed(msg string)
+	// MarkInstallFailedWithReason marks the InstallationSucceeded status as false
+	// with the given reason and message.
+	MarkInstallFailedWithReason(reason, msg string)
+	// MarkInstallReconciling marks the InstallSucceeded status as unknown while
+	// a generation of the spec not observed yet is being installed.
+	MarkInstallReconciling()
 
 	// MarkDeploymentsAvailable marks the DeploymentsAvailable status as true.
 	MarkDeploymentsAvailable()
 	// MarkDeploymentsNotReady marks the DeploymentsAvailable status as false and calls out
 	// it's waiting for deployments.
 	MarkDeploymentsNotReady()
+	// MarkDeploymentsTimedOut marks the DeploymentsAvailable status as false as
+	// the deployments didn't become available in time.
+	MarkDeploymentsTimedOut(msg string)
 
 	// MarkDependenciesInstalled marks the DependenciesInstalled status as true.
 	MarkDependenciesInstalled()
@@ -74,6 +185,18 @@ type TektonComponentStatus interface {
 	// given message.
 	MarkDependencyMissing(msg string)
 
+	// MarkDeprecatedFieldsUnset marks the DeprecatedFieldsUnset status as true.
+	MarkDeprecatedFieldsUnset()
+	// MarkDeprecatedFieldsSet marks the DeprecatedFieldsUnset status as false
+	// with the given message.
+	MarkDeprecatedFieldsSet(msg string)
+
+	// MarkDowngradeBlocked sets the DowngradeBlocked status to true with
+	// the given message.
+	MarkDowngradeBlocked(msg string)
+	// ClearDowngradeBlocked removes the DowngradeBlocked status.
+	ClearDowngradeBlocked()
+
 	// GetVersion gets the currently installed version of the component.
 	GetVersion() string
 	// SetVersion sets the currently installed version of the component.
@@ -82,6 +205,21 @@ type TektonComponentStatus interface {
 	// GetManifests gets the url links of the manifests
 	GetManifests() []string
 
+	// GetObservedGeneration gets the generation of the spec installed last.
+	GetObservedGeneration() int64
+	// SetObservedGeneration sets the generation of the spec installed last.
+	SetObservedGeneration(generation int64)
+
+	// GetPinnedImages gets the digest references the images were pinned to.
+	GetPinnedImages() map[string]string
+	// SetPinnedImages sets the digest references the images were pinned to.
+	SetPinnedImages(pinned map[string]string)
+
+	// GetAPIVersions gets the API versions the installed CRDs serve.
+	GetAPIVersions() []string
+	// SetAPIVersions sets the API versions the installed CRDs serve.
+	SetAPIVersions(versions []string)
+
 	// IsReady return true if all conditions are satisfied
 	IsReady() bool
 }
@@ -91,9 +229,560 @@ type CommonSpec struct {
 	// TargetNamespace is where resources will be installed
 	// +optional
 	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// ManageTargetNamespace controls whether the operator creates the target
+	// namespace and deletes it on uninstall. When false the namespace is
+	// expected to exist already and is never modified, but for the owner
+	// reference to the component set while it was true, which is removed.
+	// Defaults to true.
+	// +optional
+	ManageTargetNamespace *bool `json:"manageTargetNamespace,omitempty"`
+
+	// Labels are added to every resource of the payload, e.g. for cost
+	// allocation, without replacing the labels the payload ships
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to every resource of the payload, without
+	// replacing the annotations the payload ships
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// PodAnnotations are added to the pod template of every payload
+	// workload, e.g. cluster-autoscaler.kubernetes.io/safe-to-evict
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+
+	// CostAllocation labels the payload workloads and their pods, and the
+	// pods the components generate, for per component cost reporting
+	// +optional
+	CostAllocation *CostAllocation `json:"costAllocation,omitempty"`
+
+	// DisruptionPolicy sets the PodDisruptionBudgets, the priority class
+	// and the cluster autoscaler eviction of the payload pods together,
+	// taking precedence over spec.config.podDisruptionBudget,
+	// spec.config.priorityClassName and the safe-to-evict pod annotation
+	// +optional
+	DisruptionPolicy *DisruptionPolicy `json:"disruptionPolicy,omitempty"`
+
+	// KubeconfigSecret references a Secret in the operator namespace holding
+	// the kubeconfig of a remote cluster the component is installed to
+	// instead of the cluster the operator runs in. Experimental.
+	// +optional
+	KubeconfigSecret *KubeconfigSecretReference `json:"kubeconfigSecret,omitempty"`
+
+	// Registry configures where the payload images are pulled from
+	// +optional
+	Registry Registry `json:"registry,omitempty"`
+
+	// SignatureVerification requires the payload images to be signed with
+	// cosign before anything is installed
+	// +optional
+	SignatureVerification *SignatureVerification `json:"signatureVerification,omitempty"`
+
+	// Proxy sets the egress proxy of the payload deployments, overriding the
+	// proxy environment of the operator
+	// +optional
+	Proxy *Proxy `json:"proxy,omitempty"`
+
+	// Env sets environment variables on containers of the payload
+	// deployments, e.g. GODEBUG, replacing the variables of the same name
+	// +optional
+	Env []EnvOverride `json:"env,omitempty"`
+
+	// OptionsProfile applies the recommended replicas, resources and probe
+	// timings of an environment to the payload deployments, one of
+	// development, staging or production
+	// +optional
+	OptionsProfile string `json:"optionsProfile,omitempty"`
+
+	// AllowDowngrade installs a payload older than the installed one, e.g.
+	// once the operator was rolled back, which is blocked otherwise as the
+	// older payload may not serve the stored versions of its resources
+	// +optional
+	AllowDowngrade bool `json:"allowDowngrade,omitempty"`
+
+	// HighAvailability runs the payload controllers with several replicas,
+	// spread across nodes or zones
+	// +optional
+	HighAvailability *HighAvailability `json:"highAvailability,omitempty"`
+
+	// Config holds the configuration of the payload components
+	// +optional
+	Config Config `json:"config,omitempty"`
+
+	// Options overrides settings of individual payload deployments
+	// +optional
+	Options Options `json:"options,omitempty"`
+}
+
+// CostAllocation configures the labels cost reporting tools, e.g. Kubecost
+// or OpenCost, aggregate the payload by.
+type CostAllocation struct {
+	// Labels are set on every component
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Components are labels of individual components, by kind, e.g.
+	// TektonPipeline, replacing the values of labels
+	// +optional
+	Components map[string]map[string]string `json:"components,omitempty"`
+}
+
+// LabelsFor returns the cost allocation labels of the component of the
+// kind.
+func (c *CostAllocation) LabelsFor(kind string) map[string]string {
+	if c == nil {
+		return nil
+	}
+	labels := map[string]string{}
+	for k, v := range c.Labels {
+		labels[k] = v
+	}
+	for k, v := range c.Components[kind] {
+		labels[k] = v
+	}
+	return labels
+}
+
+// DisruptionPolicy configures how the payload pods go through node drains,
+// whether issued by hand or by the cluster autoscaler scaling down.
+type DisruptionPolicy struct {
+	// MinAvailable is the number or percentage of pods of each controller
+	// and webhook deployment which must stay available during voluntary
+	// disruptions. Unset, their pods are evicted one at a time.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// PriorityClassName is set on the pods of the payload Deployments and
+	// StatefulSets, so that they aren't preempted by workloads of lower
+	// priority
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// SafeToEvict lets the cluster autoscaler evict the pods of the
+	// controller and webhook deployments when scaling down a node, within
+	// the bounds of their PodDisruptionBudget, defaults to true
+	// +optional
+	SafeToEvict *bool `json:"safeToEvict,omitempty"`
+}
+
+// The kinds of objects exposing a service
+const (
+	ExposeIngress = "Ingress"
+	ExposeRoute   = "Route"
+)
+
+// ServiceExpose configures the object exposing a service of the payload,
+// e.g. the dashboard, outside the cluster
+type ServiceExpose struct {
+	// Type of the object exposing the service, Ingress or Route
+	Type string `json:"type"`
+	// Host the service is served at. Routes without host get one
+	// generated by the router.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// TLSSecret names a kubernetes.io/tls Secret of the target namespace
+	// holding the certificate of the host. Routes without one are
+	// terminated with the certificate of the router.
+	// +optional
+	TLSSecret string `json:"tlsSecret,omitempty"`
+	// Annotations of the object, e.g. for the ingress controller
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// HostTemplate is a Go template of the host, used when Host is unset,
+	// with the Name and the Namespace of the service, e.g.
+	// {{.Name}}.{{.Namespace}}.apps.example.com
+	// +optional
+	HostTemplate string `json:"hostTemplate,omitempty"`
+	// ExternalDNS annotates the object for external-dns to manage a DNS
+	// record of the host, deleted along with the object
+	// +optional
+	ExternalDNS *ExternalDNS `json:"externalDNS,omitempty"`
+}
+
+// ExternalDNS configures the DNS record external-dns creates for the host
+// of an exposed service
+type ExternalDNS struct {
+	// TTL of the record in seconds, the default of external-dns when unset
+	// +optional
+	TTL *int64 `json:"ttl,omitempty"`
+	// Target of the record, e.g. the address of the load balancer in front
+	// of the ingress controller. external-dns reads the status of the
+	// object when unset.
+	// +optional
+	Target string `json:"target,omitempty"`
+}
+
+// Options overrides settings of individual payload deployments.
+type Options struct {
+	// Deployments are the options of payload deployments, by name
+	// +optional
+	Deployments []DeploymentOptions `json:"deployments,omitempty"`
+	// ConfigMaps are data merged into the payload ConfigMaps of the same
+	// name, e.g. feature-flags, replacing the values of the payload
+	// +optional
+	ConfigMaps map[string]map[string]string `json:"configMaps,omitempty"`
+}
+
+// DeploymentOptions are the options of a payload deployment.
+type DeploymentOptions struct {
+	// Name of the deployment, e.g. tekton-pipelines-controller
+	Name string `json:"name"`
+	// Args of the deployment's container
+	// +optional
+	Args ContainerArgs `json:"args,omitempty"`
+}
+
+// ContainerArgs changes the command line flags of a container. Flags must
+// be known to the operator for the deployment.
+type ContainerArgs struct {
+	// Add sets flags, e.g. -namespace=ci, replacing their value in the
+	// payload
+	// +optional
+	Add []string `json:"add,omitempty"`
+	// Remove drops flags of the payload by name, e.g. -namespace
+	// +optional
+	Remove []string `json:"remove,omitempty"`
+}
+
+// Config configures the payload components.
+type Config struct {
+	// Autoscaling generates HorizontalPodAutoscalers for the webhook and
+	// dashboard deployments, which then no longer have fixed replicas
+	// +optional
+	Autoscaling *Autoscaling `json:"autoscaling,omitempty"`
+
+	// CABundleConfigMap is a ConfigMap of the target namespace holding PEM
+	// encoded certificates, mounted into every controller and webhook and
+	// trusted in addition to the system certificates
+	// +optional
+	CABundleConfigMap string `json:"caBundleConfigMap,omitempty"`
+
+	// LogLevels sets the level of the loggers of the payload components,
+	// by component, e.g. controller: debug or webhook: info, rendered into
+	// the loglevel keys of their config-logging ConfigMap
+	// +optional
+	LogLevels map[string]string `json:"logLevels,omitempty"`
+
+	// NetworkPolicy generates NetworkPolicies for the payload deployments of
+	// the target namespace, letting webhooks and dashboards accept traffic
+	// and every deployment reach the API server, DNS and the namespace
+	// +optional
+	NetworkPolicy bool `json:"networkPolicy,omitempty"`
+
+	// Observability deploys the monitoring bundle shipped with the payload,
+	// ServiceMonitors, PrometheusRules and Grafana dashboards, to a
+	// namespace of its own
+	// +optional
+	Observability *Observability `json:"observability,omitempty"`
+
+	// PodDisruptionBudget generates PodDisruptionBudgets for the controller
+	// and webhook deployments, so that node drains don't evict all their
+	// pods at once
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	// PriorityClassName is set on the pods of the payload Deployments and
+	// StatefulSets
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// ResourceSizeWarning is the size of a payload resource, as stored,
+	// with its last-applied-configuration annotation unless applied server
+	// side, above which a warning event is recorded, defaults to 1Mi.
+	// Resources beyond the 1.5Mi etcd limit fail the install.
+	// +optional
+	ResourceSizeWarning *resource.Quantity `json:"resourceSizeWarning,omitempty"`
+
+	// RestrictedPodSecurity makes the pods of the payload comply with the
+	// restricted Pod Security Standard, for namespaces enforcing it
+	// +optional
+	RestrictedPodSecurity bool `json:"restrictedPodSecurity,omitempty"`
+
+	// ServerSideApply applies the payload resources server side, the
+	// operator owning the fields it sets only, rather than updating them
+	// whole. Fields set by others, e.g. the replicas of an autoscaler or
+	// the injections of an admission mutator, are left alone: the replicas
+	// of the workloads a payload HorizontalPodAutoscaler scales aren't
+	// applied, and the fields other managers hold fail the apply with a
+	// conflict rather than being taken over.
+	// +optional
+	ServerSideApply bool `json:"serverSideApply,omitempty"`
+
+	// SchedulerName is set on the pods of the payload Deployments,
+	// StatefulSets and Jobs, e.g. for clusters running a custom or
+	// bin-packing scheduler
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
+
+	// ServiceMonitors generates Prometheus Operator ServiceMonitors for the
+	// metrics Services of the payload controllers, and for the operator
+	// itself when set on the TektonConfig
+	// +optional
+	ServiceMonitors *ServiceMonitors `json:"serviceMonitors,omitempty"`
+
+	// TopologySpread adds a topologySpreadConstraint to the pods of the
+	// payload deployments, spreading their replicas across zones
+	// +optional
+	TopologySpread *TopologySpread `json:"topologySpread,omitempty"`
+}
+
+// Autoscaling configures the HorizontalPodAutoscalers of the webhook and
+// dashboard deployments.
+type Autoscaling struct {
+	// MinReplicas of each deployment, defaults to 1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas of each deployment
+	MaxReplicas int32 `json:"maxReplicas"`
+	// TargetCPUUtilizationPercentage of the requested CPU, defaults to 80
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+}
+
+// PodDisruptionBudget configures the PodDisruptionBudgets of the controller
+// and webhook deployments.
+type PodDisruptionBudget struct {
+	// MinAvailable is the number or percentage of pods of each deployment
+	// which must stay available during voluntary disruptions, defaults to 1
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+}
+
+// ServiceMonitors configures the generated ServiceMonitors.
+type ServiceMonitors struct {
+	// Interval the metrics are scraped at, defaults to 30s
+	// +optional
+	Interval string `json:"interval,omitempty"`
+	// Labels to add to the ServiceMonitors, e.g. the ones the
+	// serviceMonitorSelector of the Prometheus selects
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Observability configures the monitoring bundle of the payload.
+type Observability struct {
+	// Namespace the bundle is deployed to, e.g. monitoring
+	Namespace string `json:"namespace"`
+	// CreateNamespace makes the namespace part of the bundle, created with
+	// it and deleted with the component. A namespace the operator didn't
+	// create is left alone
+	// +optional
+	CreateNamespace bool `json:"createNamespace,omitempty"`
+	// OpenTelemetry adds the configuration of an OpenTelemetry collector
+	// scraping the payload to the bundle
+	// +optional
+	OpenTelemetry bool `json:"openTelemetry,omitempty"`
+}
+
+// EnvOverride sets environment variables on containers of a payload
+// deployment.
+type EnvOverride struct {
+	// Deployment is the name of the deployment, e.g. tekton-pipelines-controller
+	Deployment string `json:"deployment"`
+	// Container is the name of the container, all containers of the
+	// deployment when empty
+	// +optional
+	Container string `json:"container,omitempty"`
+	// Env are the variables to set
+	Env []corev1.EnvVar `json:"env"`
+}
+
+// TopologySpread configures the topologySpreadConstraint of the payload
+// deployments.
+type TopologySpread struct {
+	// TopologyKey is the node label of the topology domains, defaults to
+	// topology.kubernetes.io/zone
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+	// MaxSkew is the largest difference of the number of pods between two
+	// domains, defaults to 1
+	// +optional
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+	// WhenUnsatisfiable is DoNotSchedule or ScheduleAnyway, the default
+	// +optional
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+}
+
+// Spread values of HighAvailability
+const (
+	// SpreadZone prefers the replicas in distinct zones, then on distinct
+	// nodes, never keeping one from being scheduled
+	SpreadZone = "zone"
+	// SpreadNode requires the replicas on distinct nodes
+	SpreadNode = "node"
+	// SpreadNone doesn't constrain the placement of the replicas
+	SpreadNone = "none"
+)
+
+// HighAvailability configures the replicas of the payload controllers.
+type HighAvailability struct {
+	// Replicas of each payload controller, leader election sharing the
+	// work among them, 2 by default and at least 1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Spread is how the replicas of the controllers, and of the other
+	// payload deployments running more than one, are placed: zone, the
+	// default, node or none
+	// +optional
+	Spread string `json:"spread,omitempty"`
+}
+
+// Registry configures the registry of the payload images.
+type Registry struct {
+	// Override replaces the registry of every payload image, e.g.
+	// mirror.corp.local/tekton turns gcr.io/tekton-releases/controller into
+	// mirror.corp.local/tekton/tekton-releases/controller
+	// +optional
+	Override string `json:"override,omitempty"`
+
+	// PinDigests resolves the tag of every payload image to its digest on
+	// install, through the registry API reached with spec.proxy and the
+	// credentials of ImagePullSecrets, and applies the digest.
+	// The pins are recorded in status.pinnedImages and kept until the next
+	// upgrade.
+	// +optional
+	PinDigests bool `json:"pinDigests,omitempty"`
+
+	// ImagePullSecrets are secrets of the target namespace added to the
+	// image pull secrets of the payload workloads and ServiceAccounts, to
+	// pull from authenticated registries
+	// +optional
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+
+	// PullPrecheck resolves the images of the payload deployments against
+	// their registry before applying them, with the image pull secrets and
+	// the proxy, failing the install with ImagePullPrecheckFailed rather
+	// than rolling out pods unable to pull them
+	// +optional
+	PullPrecheck bool `json:"pullPrecheck,omitempty"`
+}
+
+// SignatureVerification configures the verification of the cosign
+// signatures of the payload images.
+type SignatureVerification struct {
+	// CosignPublicKey is the PEM encoded ECDSA public key the payload images
+	// must be signed with, e.g. the cosign.pub of the release
+	// +optional
+	CosignPublicKey string `json:"cosignPublicKey,omitempty"`
+	// CosignPublicKeys are more PEM encoded ECDSA public keys, the payload
+	// images being signed with any of the keys, e.g. while a key is rotated
+	// or for images mirrored and signed again
+	// +optional
+	CosignPublicKeys []string `json:"cosignPublicKeys,omitempty"`
+}
+
+// PublicKeys returns the PEM encoded public keys of the verification.
+func (v *SignatureVerification) PublicKeys() []string {
+	var keys []string
+	if v.CosignPublicKey != "" {
+		keys = append(keys, v.CosignPublicKey)
+	}
+	return append(keys, v.CosignPublicKeys...)
+}
+
+// Proxy configures the egress proxy of the payload deployments. Unset
+// fields fall back to the proxy environment of the operator.
+type Proxy struct {
+	// HTTPProxy is set as HTTP_PROXY
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// HTTPSProxy is set as HTTPS_PROXY
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	// NoProxy is set as NO_PROXY
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// KubeconfigSecretReference points to a kubeconfig stored in a Secret.
+type KubeconfigSecretReference struct {
+	// Name of the Secret in the operator namespace
+	Name string `json:"name"`
+	// Key of the kubeconfig within the Secret, defaults to "kubeconfig"
+	// +optional
+	Key string `json:"key,omitempty"`
 }
 
 // GetTargetNamespace implements KComponentSpec.
 func (c *CommonSpec) GetTargetNamespace() string {
 	return c.TargetNamespace
 }
+
+// IsTargetNamespaceManaged implements TektonComponentSpec.
+func (c *CommonSpec) IsTargetNamespaceManaged() bool {
+	return c.ManageTargetNamespace == nil || *c.ManageTargetNamespace
+}
+
+// GetPodAnnotations implements TektonComponentSpec.
+func (c *CommonSpec) GetPodAnnotations() map[string]string {
+	return c.PodAnnotations
+}
+
+// GetKubeconfigSecret implements TektonComponentSpec.
+func (c *CommonSpec) GetKubeconfigSecret() *KubeconfigSecretReference {
+	return c.KubeconfigSecret
+}
+
+// GetRegistry implements TektonComponentSpec.
+func (c *CommonSpec) GetRegistry() Registry {
+	return c.Registry
+}
+
+// GetSignatureVerification implements TektonComponentSpec.
+func (c *CommonSpec) GetSignatureVerification() *SignatureVerification {
+	return c.SignatureVerification
+}
+
+// GetProxy implements TektonComponentSpec.
+func (c *CommonSpec) GetProxy() *Proxy {
+	return c.Proxy
+}
+
+// GetOptionsProfile implements TektonComponentSpec.
+func (c *CommonSpec) GetOptionsProfile() string {
+	return c.OptionsProfile
+}
+
+// GetAllowDowngrade implements TektonComponentSpec.
+func (c *CommonSpec) GetAllowDowngrade() bool {
+	return c.AllowDowngrade
+}
+
+// GetHighAvailability implements TektonComponentSpec.
+func (c *CommonSpec) GetHighAvailability() *HighAvailability {
+	return c.HighAvailability
+}
+
+// GetConfig implements TektonComponentSpec.
+func (c *CommonSpec) GetConfig() Config {
+	return c.Config
+}
+
+// GetOptions implements TektonComponentSpec.
+func (c *CommonSpec) GetOptions() Options {
+	return c.Options
+}
+
+// GetLabels implements TektonComponentSpec.
+func (c *CommonSpec) GetLabels() map[string]string {
+	return c.Labels
+}
+
+// GetAnnotations implements TektonComponentSpec.
+func (c *CommonSpec) GetAnnotations() map[string]string {
+	return c.Annotations
+}
+
+// GetEnv implements TektonComponentSpec.
+func (c *CommonSpec) GetEnv() []EnvOverride {
+	return c.Env
+}
+
+// GetCostAllocation implements TektonComponentSpec.
+func (c *CommonSpec) GetCostAllocation() *CostAllocation {
+	return c.CostAllocation
+}
+
+// GetDisruptionPolicy implements TektonComponentSpec.
+func (c *CommonSpec) GetDisruptionPolicy() *DisruptionPolicy {
+	return c.DisruptionPolicy
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+)
+
+var (
+	_ TektonComponentStatus = (*ManualApprovalGateStatus)(nil)
+
+	magCondSet = apis.NewLivingConditionSet(
+		DependenciesInstalled,
+		DeploymentsAvailable,
+		InstallSucceeded,
+	)
+)
+
+// GroupVersionKind returns SchemeGroupVersion of a ManualApprovalGate
+func (tp *ManualApprovalGate) GroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind(KindManualApprovalGate)
+}
+
+// GetCondition returns the current condition of a given condition type
+func (tps *ManualApprovalGateStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return magCondSet.Manage(tps).GetCondition(t)
+}
+
+// InitializeConditions initializes conditions of an ManualApprovalGateStatus
+func (tps *ManualApprovalGateStatus) InitializeConditions() {
+	magCondSet.Manage(tps).InitializeConditions()
+}
+
+// IsReady looks at the conditions returns true if they are all true.
+func (tps *ManualApprovalGateStatus) IsReady() bool {
+	return magCondSet.Manage(tps).IsHappy()
+}
+
+// MarkInstallSucceeded marks the InstallationSucceeded status as true.
+func (tps *ManualApprovalGateStatus) MarkInstallSucceeded() {
+	magCondSet.Manage(tps).MarkTrue(InstallSucceeded)
+	if tps.GetCondition(DependenciesInstalled).IsUnknown() {
+		// Assume deps are installed if we're not sure
+		tps.MarkDependenciesInstalled()
+	}
+}
+
+// MarkInstallFailed marks the InstallationSucceeded status as false with the given
+// message.
+func (tps *ManualApprovalGateStatus) MarkInstallFailed(msg string) {
+	tps.MarkInstallFailedWithReason(ReasonError, msg)
+}
+
+// MarkInstallFailedWithReason marks the InstallationSucceeded status as false
+// with the given reason and message.
+func (tps *ManualApprovalGateStatus) MarkInstallFailedWithReason(reason, msg string) {
+	magCondSet.Manage(tps).MarkFalse(
+		InstallSucceeded,
+		reason,
+		"Install failed with message: %s", msg)
+}
+
+// MarkInstallReconciling marks the InstallSucceeded status as unknown while
+// a generation of the spec not observed yet is being installed.
+func (tps *ManualApprovalGateStatus) MarkInstallReconciling() {
+	magCondSet.Manage(tps).MarkUnknown(
+		InstallSucceeded,
+		ReasonReconciling,
+		"Installing the latest generation of the spec")
+}
+
+// MarkDeploymentsAvailable marks the DeploymentsAvailable status as true.
+func (tps *ManualApprovalGateStatus) MarkDeploymentsAvailable() {
+	magCondSet.Manage(tps).MarkTrue(DeploymentsAvailable)
+}
+
+// MarkDeploymentsNotReady marks the DeploymentsAvailable status as false and calls out
+// it's waiting for deployments.
+func (tps *ManualApprovalGateStatus) MarkDeploymentsNotReady() {
+	magCondSet.Manage(tps).MarkFalse(
+		DeploymentsAvailable,
+		"NotReady",
+		"Waiting on deployments")
+}
+
+// MarkDeploymentsTimedOut marks the DeploymentsAvailable status as false as
+// the deployments didn't become available in time.
+func (tps *ManualApprovalGateStatus) MarkDeploymentsTimedOut(msg string) {
+	magCondSet.Manage(tps).MarkFalse(
+		DeploymentsAvailable,
+		ReasonTimeoutWaitingReady,
+		"Deployments not available in time: %s", msg)
+}
+
+// MarkDependenciesInstalled marks the DependenciesInstalled status as true.
+func (tps *ManualApprovalGateStatus) MarkDependenciesInstalled() {
+	magCondSet.Manage(tps).MarkTrue(DependenciesInstalled)
+}
+
+// MarkDependencyInstalling marks the DependenciesInstalled status as false with the
+// given message.
+func (tps *ManualApprovalGateStatus) MarkDependencyInstalling(msg string) {
+	magCondSet.Manage(tps).MarkFalse(
+		DependenciesInstalled,
+		"Installing",
+		"Dependency installing: %s", msg)
+}
+
+// MarkDependencyMissing marks the DependenciesInstalled status as false with the
+// given message.
+func (tps *ManualApprovalGateStatus) MarkDependencyMissing(msg string) {
+	magCondSet.Manage(tps).MarkFalse(
+		DependenciesInstalled,
+		ReasonDependencyMissing,
+		"Dependency missing: %s", msg)
+}
+
+// MarkDeprecatedFieldsUnset marks the DeprecatedFieldsUnset status as true.
+func (tps *ManualApprovalGateStatus) MarkDeprecatedFieldsUnset() {
+	magCondSet.Manage(tps).MarkTrue(DeprecatedFieldsUnset)
+}
+
+// MarkDeprecatedFieldsSet marks the DeprecatedFieldsUnset status as false
+// with the given message.
+func (tps *ManualApprovalGateStatus) MarkDeprecatedFieldsSet(msg string) {
+	magCondSet.Manage(tps).MarkFalse(
+		DeprecatedFieldsUnset,
+		ReasonDeprecatedFields,
+		"Deprecated fields set: %s", msg)
+}
+
+// MarkDowngradeBlocked sets the DowngradeBlocked status to true with the
+// given message.
+func (tps *ManualApprovalGateStatus) MarkDowngradeBlocked(msg string) {
+	magCondSet.Manage(tps).MarkTrueWithReason(
+		DowngradeBlocked,
+		ReasonDowngradeBlocked,
+		"Downgrade blocked: %s", msg)
+}
+
+// ClearDowngradeBlocked removes the DowngradeBlocked status.
+func (tps *ManualApprovalGateStatus) ClearDowngradeBlocked() {
+	_ = magCondSet.Manage(tps).ClearCondition(DowngradeBlocked)
+}
+
+// GetVersion gets the currently installed version of the component.
+func (tps *ManualApprovalGateStatus) GetVersion() string {
+	return tps.Version
+}
+
+// SetVersion sets the currently installed version of the component.
+func (tps *ManualApprovalGateStatus) SetVersion(version string) {
+	tps.Version = version
+}
+
+// GetManifests gets the url links of the manifests.
+func (tps *ManualApprovalGateStatus) GetManifests() []string {
+	return tps.Manifests
+}
+
+// GetObservedGeneration gets the generation of the spec installed last.
+func (tps *ManualApprovalGateStatus) GetObservedGeneration() int64 {
+	return tps.ObservedGeneration
+}
+
+// SetObservedGeneration sets the generation of the spec installed last.
+func (tps *ManualApprovalGateStatus) SetObservedGeneration(generation int64) {
+	tps.ObservedGeneration = generation
+}
+
+// SetVersion sets the url links of the manifests.
+func (tps *ManualApprovalGateStatus) SetManifests(manifests []string) {
+	tps.Manifests = manifests
+}
+
+// GetPinnedImages gets the digest references the images were pinned to.
+func (tps *ManualApprovalGateStatus) GetPinnedImages() map[string]string {
+	return tps.PinnedImages
+}
+
+// SetPinnedImages sets the digest references the images were pinned to.
+func (tps *ManualApprovalGateStatus) SetPinnedImages(pinned map[string]string) {
+	tps.PinnedImages = pinned
+}
+
+// GetAPIVersions gets the API versions the installed CRDs serve.
+func (tps *ManualApprovalGateStatus) GetAPIVersions() []string {
+	return tps.APIVersions
+}
+
+// SetAPIVersions sets the API versions the installed CRDs serve.
+func (tps *ManualApprovalGateStatus) SetAPIVersions(versions []string) {
+	tps.APIVersions = versions
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+var (
+	_ TektonComponent     = (*ManualApprovalGate)(nil)
+	_ TektonComponentSpec = (*ManualApprovalGateSpec)(nil)
+)
+
+// ManualApprovalGate is the Schema for the manualapprovalgates API
+// +genclient
+// +genreconciler:krshapedlogic=false
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+type ManualApprovalGate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ManualApprovalGateSpec   `json:"spec,omitempty"`
+	Status ManualApprovalGateStatus `json:"status,omitempty"`
+}
+
+// GetSpec implements TektonComponent
+func (tp *ManualApprovalGate) GetSpec() TektonComponentSpec {
+	return &tp.Spec
+}
+
+// GetStatus implements TektonComponent
+func (tp *ManualApprovalGate) GetStatus() TektonComponentStatus {
+	return &tp.Status
+}
+
+// ManualApprovalGateSpec defines the desired state of ManualApprovalGate
+type ManualApprovalGateSpec struct {
+	CommonSpec `json:",inline"`
+
+	// Images overrides the images of the payload by container, step or
+	// argument name, taking precedence over the operator's overrides
+	// +optional
+	Images map[string]string `json:"images,omitempty"`
+}
+
+// ManualApprovalGateStatus defines the observed state of ManualApprovalGate
+type ManualApprovalGateStatus struct {
+	duckv1.Status `json:",inline"`
+
+	// The version of the installed release
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The url links of the manifests, separated by comma
+	// +optional
+	Manifests []string `json:"manifests,omitempty"`
+
+	// The digest references the payload images were pinned to, by image
+	// +optional
+	PinnedImages map[string]string `json:"pinnedImages,omitempty"`
+
+	// The Tekton API versions the installed custom resource definitions
+	// serve, e.g. tekton.dev/v1
+	// +optional
+	APIVersions []string `json:"apiVersions,omitempty"`
+}
+
+// ManualApprovalGateList contains a list of ManualApprovalGate
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ManualApprovalGateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ManualApprovalGate `json:"items"`
+}
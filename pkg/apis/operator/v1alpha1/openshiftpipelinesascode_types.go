@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+var (
+	_ TektonComponent     = (*OpenShiftPipelinesAsCode)(nil)
+	_ TektonComponentSpec = (*OpenShiftPipelinesAsCodeSpec)(nil)
+)
+
+// OpenShiftPipelinesAsCode is the Schema for the openshiftpipelinesascodes API
+// +genclient
+// +genreconciler:krshapedlogic=false
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+type OpenShiftPipelinesAsCode struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenShiftPipelinesAsCodeSpec   `json:"spec,omitempty"`
+	Status OpenShiftPipelinesAsCodeStatus `json:"status,omitempty"`
+}
+
+// GetSpec implements TektonComponent
+func (tp *OpenShiftPipelinesAsCode) GetSpec() TektonComponentSpec {
+	return &tp.Spec
+}
+
+// GetStatus implements TektonComponent
+func (tp *OpenShiftPipelinesAsCode) GetStatus() TektonComponentStatus {
+	return &tp.Status
+}
+
+// OpenShiftPipelinesAsCodeSpec defines the desired state of OpenShiftPipelinesAsCode
+type OpenShiftPipelinesAsCodeSpec struct {
+	CommonSpec `json:",inline"`
+
+	// Images overrides the images of the payload by container, step or
+	// argument name, taking precedence over the operator's overrides
+	// +optional
+	Images map[string]string `json:"images,omitempty"`
+
+	PipelinesAsCodeProperties `json:",inline"`
+}
+
+// PipelinesAsCodeProperties are the settings of Pipelines as Code, shared
+// by OpenShiftPipelinesAsCode and spec.pipelinesAsCode of TektonConfig
+type PipelinesAsCodeProperties struct {
+	// Settings are written into the pipelines-as-code ConfigMap of the
+	// payload, e.g. application-name or hub-url, replacing the values of
+	// the payload
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+}
+
+// OpenShiftPipelinesAsCodeStatus defines the observed state of OpenShiftPipelinesAsCode
+type OpenShiftPipelinesAsCodeStatus struct {
+	duckv1.Status `json:",inline"`
+
+	// The version of the installed release
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The url links of the manifests, separated by comma
+	// +optional
+	Manifests []string `json:"manifests,omitempty"`
+
+	// The digest references the payload images were pinned to, by image
+	// +optional
+	PinnedImages map[string]string `json:"pinnedImages,omitempty"`
+
+	// The Tekton API versions the installed custom resource definitions
+	// serve, e.g. tekton.dev/v1
+	// +optional
+	APIVersions []string `json:"apiVersions,omitempty"`
+}
+
+// OpenShiftPipelinesAsCodeList contains a list of OpenShiftPipelinesAsCode
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type OpenShiftPipelinesAsCodeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenShiftPipelinesAsCode `json:"items"`
+}
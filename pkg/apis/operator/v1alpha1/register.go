@@ -43,6 +43,24 @@ const (
 
 	// KindTektonConfig is the Kind of Tekton Config in a GVK context.
 	KindTektonConfig = "TektonConfig"
+
+	// KindTektonResult is the Kind of Tekton Result in a GVK context.
+	KindTektonResult = "TektonResult"
+
+	// KindTektonChain is the Kind of Tekton Chain in a GVK context.
+	KindTektonChain = "TektonChain"
+
+	// KindTektonHub is the Kind of Tekton Hub in a GVK context.
+	KindTektonHub = "TektonHub"
+
+	// KindOpenShiftPipelinesAsCode is the Kind of Pipelines as Code in a GVK context.
+	KindOpenShiftPipelinesAsCode = "OpenShiftPipelinesAsCode"
+
+	// KindManualApprovalGate is the Kind of Manual Approval Gate in a GVK context.
+	KindManualApprovalGate = "ManualApprovalGate"
+
+	// KindTektonOverride is the Kind of Tekton Override in a GVK context.
+	KindTektonOverride = "TektonOverride"
 )
 
 // Resource takes an unqualified resource and returns a Group qualified GroupResource
@@ -64,6 +82,20 @@ func addKnownTypes(s *runtime.Scheme) error {
 		&TektonAddonList{},
 		&TektonConfig{},
 		&TektonConfigList{},
+		&TektonOverride{},
+		&TektonOverrideList{},
+		&TektonResult{},
+		&TektonResultList{},
+		&TektonChain{},
+		&TektonChainList{},
+		&TektonHub{},
+		&TektonHubList{},
+		&OpenShiftPipelinesAsCode{},
+		&OpenShiftPipelinesAsCodeList{},
+		&ManualApprovalGate{},
+		&ManualApprovalGateList{},
+		&TektonInstallerSet{},
+		&TektonInstallerSetList{},
 	)
 	metav1.AddToGroupVersion(s, SchemeGroupVersion)
 	return nil
@@ -30,6 +30,16 @@ var (
 	)
 )
 
+// The conditions of the groups of resources of TektonAddon, telling which
+// part of the addon failed to install. They don't affect the readiness of
+// the addon on their own, a group failing to install fails InstallSucceeded
+// as well.
+const (
+	ClusterTasksReady      apis.ConditionType = "ClusterTasksReady"
+	PipelineTemplatesReady apis.ConditionType = "PipelineTemplatesReady"
+	TriggersResourcesReady apis.ConditionType = "TriggersResourcesReady"
+)
+
 // GroupVersionKind returns SchemeGroupVersion of a TektonAddon
 func (tp *TektonAddon) GroupVersionKind() schema.GroupVersionKind {
 	return SchemeGroupVersion.WithKind(KindTektonAddon)
@@ -62,9 +72,38 @@ func (tps *TektonAddonStatus) MarkInstallSucceeded() {
 // MarkInstallFailed marks the InstallationSucceeded status as false with the given
 // message.
 func (tps *TektonAddonStatus) MarkInstallFailed(msg string) {
+	tps.MarkInstallFailedWithReason(ReasonError, msg)
+}
+
+// MarkInstallFailedWithReason marks the InstallationSucceeded status as false
+// with the given reason and message.
+func (tps *TektonAddonStatus) MarkInstallFailedWithReason(reason, msg string) {
 	addonsCondSet.Manage(tps).MarkFalse(
 		InstallSucceeded,
-		"Error",
+		reason,
+		"Install failed with message: %s", msg)
+}
+
+// MarkInstallReconciling marks the InstallSucceeded status as unknown while
+// a generation of the spec not observed yet is being installed.
+func (tps *TektonAddonStatus) MarkInstallReconciling() {
+	addonsCondSet.Manage(tps).MarkUnknown(
+		InstallSucceeded,
+		ReasonReconciling,
+		"Installing the latest generation of the spec")
+}
+
+// MarkGroupReady marks the condition of a group of resources as true.
+func (tps *TektonAddonStatus) MarkGroupReady(group apis.ConditionType) {
+	addonsCondSet.Manage(tps).MarkTrue(group)
+}
+
+// MarkGroupFailed marks the condition of a group of resources as false
+// with the given reason and message.
+func (tps *TektonAddonStatus) MarkGroupFailed(group apis.ConditionType, reason, msg string) {
+	addonsCondSet.Manage(tps).MarkFalse(
+		group,
+		reason,
 		"Install failed with message: %s", msg)
 }
 
@@ -82,6 +121,15 @@ func (tps *TektonAddonStatus) MarkDeploymentsNotReady() {
 		"Waiting on deployments")
 }
 
+// MarkDeploymentsTimedOut marks the DeploymentsAvailable status as false as
+// the deployments didn't become available in time.
+func (tps *TektonAddonStatus) MarkDeploymentsTimedOut(msg string) {
+	addonsCondSet.Manage(tps).MarkFalse(
+		DeploymentsAvailable,
+		ReasonTimeoutWaitingReady,
+		"Deployments not available in time: %s", msg)
+}
+
 // MarkDependenciesInstalled marks the DependenciesInstalled status as true.
 func (tps *TektonAddonStatus) MarkDependenciesInstalled() {
 	addonsCondSet.Manage(tps).MarkTrue(DependenciesInstalled)
@@ -101,10 +149,38 @@ func (tps *TektonAddonStatus) MarkDependencyInstalling(msg string) {
 func (tps *TektonAddonStatus) MarkDependencyMissing(msg string) {
 	addonsCondSet.Manage(tps).MarkFalse(
 		DependenciesInstalled,
-		"Error",
+		ReasonDependencyMissing,
 		"Dependency missing: %s", msg)
 }
 
+// MarkDeprecatedFieldsUnset marks the DeprecatedFieldsUnset status as true.
+func (tps *TektonAddonStatus) MarkDeprecatedFieldsUnset() {
+	addonsCondSet.Manage(tps).MarkTrue(DeprecatedFieldsUnset)
+}
+
+// MarkDeprecatedFieldsSet marks the DeprecatedFieldsUnset status as false
+// with the given message.
+func (tps *TektonAddonStatus) MarkDeprecatedFieldsSet(msg string) {
+	addonsCondSet.Manage(tps).MarkFalse(
+		DeprecatedFieldsUnset,
+		ReasonDeprecatedFields,
+		"Deprecated fields set: %s", msg)
+}
+
+// MarkDowngradeBlocked sets the DowngradeBlocked status to true with the
+// given message.
+func (tps *TektonAddonStatus) MarkDowngradeBlocked(msg string) {
+	addonsCondSet.Manage(tps).MarkTrueWithReason(
+		DowngradeBlocked,
+		ReasonDowngradeBlocked,
+		"Downgrade blocked: %s", msg)
+}
+
+// ClearDowngradeBlocked removes the DowngradeBlocked status.
+func (tps *TektonAddonStatus) ClearDowngradeBlocked() {
+	_ = addonsCondSet.Manage(tps).ClearCondition(DowngradeBlocked)
+}
+
 // GetVersion gets the currently installed version of the component.
 func (tps *TektonAddonStatus) GetVersion() string {
 	return tps.Version
@@ -120,7 +196,37 @@ func (tps *TektonAddonStatus) GetManifests() []string {
 	return tps.Manifests
 }
 
+// GetObservedGeneration gets the generation of the spec installed last.
+func (tps *TektonAddonStatus) GetObservedGeneration() int64 {
+	return tps.ObservedGeneration
+}
+
+// SetObservedGeneration sets the generation of the spec installed last.
+func (tps *TektonAddonStatus) SetObservedGeneration(generation int64) {
+	tps.ObservedGeneration = generation
+}
+
 // SetVersion sets the url links of the manifests.
 func (tps *TektonAddonStatus) SetManifests(manifests []string) {
 	tps.Manifests = manifests
 }
+
+// GetPinnedImages gets the digest references the images were pinned to.
+func (tps *TektonAddonStatus) GetPinnedImages() map[string]string {
+	return tps.PinnedImages
+}
+
+// SetPinnedImages sets the digest references the images were pinned to.
+func (tps *TektonAddonStatus) SetPinnedImages(pinned map[string]string) {
+	tps.PinnedImages = pinned
+}
+
+// GetAPIVersions gets the API versions the installed CRDs serve.
+func (tps *TektonAddonStatus) GetAPIVersions() []string {
+	return tps.APIVersions
+}
+
+// SetAPIVersions sets the API versions the installed CRDs serve.
+func (tps *TektonAddonStatus) SetAPIVersions(versions []string) {
+	tps.APIVersions = versions
+}
@@ -136,3 +136,32 @@ func TestTektonAddonExternalDependency(t *testing.T) {
 	apistest.CheckConditionOngoing(tt, DeploymentsAvailable, t)
 	apistest.CheckConditionSucceeded(tt, InstallSucceeded, t)
 }
+
+func TestTektonAddonGroups(t *testing.T) {
+	tt := &TektonAddonStatus{}
+	tt.InitializeConditions()
+	tt.MarkDependenciesInstalled()
+	tt.MarkDeploymentsAvailable()
+
+	// A group fails along with the install.
+	tt.MarkGroupReady(ClusterTasksReady)
+	tt.MarkGroupFailed(PipelineTemplatesReady, ReasonApplyConflict, "test")
+	tt.MarkInstallFailed("test")
+	apistest.CheckConditionSucceeded(tt, ClusterTasksReady, t)
+	apistest.CheckConditionFailed(tt, PipelineTemplatesReady, t)
+	apistest.CheckConditionFailed(tt, InstallSucceeded, t)
+	if reason := tt.GetCondition(PipelineTemplatesReady).Reason; reason != ReasonApplyConflict {
+		t.Errorf("PipelineTemplatesReady reason = %q, want %q", reason, ReasonApplyConflict)
+	}
+	if ready := tt.IsReady(); ready {
+		t.Errorf("tt.IsReady() = %v, want false", ready)
+	}
+
+	// The group installs on retry.
+	tt.MarkGroupReady(PipelineTemplatesReady)
+	tt.MarkInstallSucceeded()
+	apistest.CheckConditionSucceeded(tt, PipelineTemplatesReady, t)
+	if ready := tt.IsReady(); !ready {
+		t.Errorf("tt.IsReady() = %v, want true", ready)
+	}
+}
@@ -52,8 +52,59 @@ func (tp *TektonAddon) GetStatus() TektonComponentStatus {
 // TektonAddonSpec defines the desired state of TektonAddon
 type TektonAddonSpec struct {
 	CommonSpec `json:",inline"`
+
+	// Images overrides the images of the payload by container, step or
+	// argument name, taking precedence over the operator's overrides
+	// +optional
+	Images map[string]string `json:"images,omitempty"`
+
+	// CommunityTasks pins the catalog version of community tasks, by task
+	// name, e.g. maven: "0.2". Tasks which are not part of the default set
+	// are installed as well.
+	// +optional
+	CommunityTasks map[string]string `json:"communityTasks,omitempty"`
+
+	// Pause halts the sync of sub-payloads of the addon, leaving their
+	// resources as they are in the cluster until they are resumed. Unknown
+	// sub-payloads fail the install.
+	// +optional
+	Pause []string `json:"pause,omitempty"`
+
+	// Params enable or disable groups of resources of the addon, with the
+	// values "true" or "false", all enabled by default. The resources of a
+	// disabled group are deleted.
+	// +optional
+	Params []Param `json:"params,omitempty"`
+}
+
+// Param is a name and a value
+type Param struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
+// The params of TektonAddon
+const (
+	// ClusterTasksParam enables the ClusterTasks of the addon
+	ClusterTasksParam = "clusterTasks"
+	// PipelineTemplatesParam enables the Pipelines of the addon
+	PipelineTemplatesParam = "pipelineTemplates"
+	// CommunityClusterTasksParam enables the ClusterTasks of the community
+	// catalog
+	CommunityClusterTasksParam = "communityClusterTasks"
+)
+
+// The sub-payloads of TektonAddon which can be paused
+const (
+	AddonClusterTriggerBindings = "clusterTriggerBindings"
+	AddonClusterTasks           = "clusterTasks"
+	AddonPipelines              = "pipelines"
+	AddonConsoleCLIDownload     = "consoleCLIDownload"
+	AddonOptional               = "optional"
+	AddonPipelineTemplates      = "pipelineTemplates"
+	AddonCommunityTasks         = "communityTasks"
+)
+
 // TektonAddonStatus defines the observed state of TektonAddon
 type TektonAddonStatus struct {
 	duckv1.Status `json:",inline"`
@@ -65,6 +116,38 @@ type TektonAddonStatus struct {
 	// The url links of the manifests, separated by comma
 	// +optional
 	Manifests []string `json:"manifests,omitempty"`
+
+	// The digest references the payload images were pinned to, by image
+	// +optional
+	PinnedImages map[string]string `json:"pinnedImages,omitempty"`
+
+	// The Tekton API versions the installed custom resource definitions
+	// serve, e.g. tekton.dev/v1
+	// +optional
+	APIVersions []string `json:"apiVersions,omitempty"`
+
+	// The resolution results of the community tasks
+	// +optional
+	CommunityTasks []CommunityTaskStatus `json:"communityTasks,omitempty"`
+
+	// The sub-payloads whose sync is paused
+	// +optional
+	Paused []string `json:"paused,omitempty"`
+}
+
+// CommunityTaskStatus reports the catalog version a community task was
+// resolved to
+type CommunityTaskStatus struct {
+	// Name of the task in the catalog
+	Name string `json:"name"`
+	// Version of the task in the catalog
+	Version string `json:"version"`
+	// The url of the resolved manifest
+	// +optional
+	URL string `json:"url,omitempty"`
+	// The reason the task could not be resolved, if any
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // TektonAddonsList contains a list of TektonAddon
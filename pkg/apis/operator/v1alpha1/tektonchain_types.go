@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+var (
+	_ TektonComponent     = (*TektonChain)(nil)
+	_ TektonComponentSpec = (*TektonChainSpec)(nil)
+)
+
+// TektonChain is the Schema for the tektonchains API
+// +genclient
+// +genreconciler:krshapedlogic=false
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+type TektonChain struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TektonChainSpec   `json:"spec,omitempty"`
+	Status TektonChainStatus `json:"status,omitempty"`
+}
+
+// GetSpec implements TektonComponent
+func (tp *TektonChain) GetSpec() TektonComponentSpec {
+	return &tp.Spec
+}
+
+// GetStatus implements TektonComponent
+func (tp *TektonChain) GetStatus() TektonComponentStatus {
+	return &tp.Status
+}
+
+// TektonChainSpec defines the desired state of TektonChain
+type TektonChainSpec struct {
+	CommonSpec `json:",inline"`
+
+	// Images overrides the images of the payload by container, step or
+	// argument name, taking precedence over the operator's overrides
+	// +optional
+	Images map[string]string `json:"images,omitempty"`
+
+	ChainProperties `json:",inline"`
+}
+
+// ChainProperties are the settings of Tekton Chains, shared by TektonChain
+// and spec.chain of TektonConfig
+type ChainProperties struct {
+	// ChainsConfig is written into the chains-config ConfigMap of the
+	// payload, e.g. artifacts.taskrun.storage or signers.kms.kmsref,
+	// replacing the values of the payload
+	// +optional
+	ChainsConfig map[string]string `json:"chainsConfig,omitempty"`
+
+	// SigningSecret names a Secret of the operator namespace whose data,
+	// e.g. cosign.key and cosign.password, is copied into the
+	// signing-secrets Secret of the payload. Without it, the keys written
+	// into signing-secrets are left alone.
+	// +optional
+	SigningSecret string `json:"signingSecret,omitempty"`
+}
+
+// TektonChainStatus defines the observed state of TektonChain
+type TektonChainStatus struct {
+	duckv1.Status `json:",inline"`
+
+	// The version of the installed release
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The url links of the manifests, separated by comma
+	// +optional
+	Manifests []string `json:"manifests,omitempty"`
+
+	// The digest references the payload images were pinned to, by image
+	// +optional
+	PinnedImages map[string]string `json:"pinnedImages,omitempty"`
+
+	// The Tekton API versions the installed custom resource definitions
+	// serve, e.g. tekton.dev/v1
+	// +optional
+	APIVersions []string `json:"apiVersions,omitempty"`
+}
+
+// TektonChainList contains a list of TektonChain
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TektonChainList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TektonChain `json:"items"`
+}
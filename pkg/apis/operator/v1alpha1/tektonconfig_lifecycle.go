@@ -63,12 +63,27 @@ func (tps *TektonConfigStatus) MarkInstallSucceeded() {
 // MarkInstallFailed marks the InstallationSucceeded status as false with the given
 // message.
 func (tps *TektonConfigStatus) MarkInstallFailed(msg string) {
+	tps.MarkInstallFailedWithReason(ReasonError, msg)
+}
+
+// MarkInstallFailedWithReason marks the InstallationSucceeded status as false
+// with the given reason and message.
+func (tps *TektonConfigStatus) MarkInstallFailedWithReason(reason, msg string) {
 	configCondSet.Manage(tps).MarkFalse(
 		InstallSucceeded,
-		"Error",
+		reason,
 		"Install failed with message: %s", msg)
 }
 
+// MarkInstallReconciling marks the InstallSucceeded status as unknown while
+// a generation of the spec not observed yet is being installed.
+func (tps *TektonConfigStatus) MarkInstallReconciling() {
+	configCondSet.Manage(tps).MarkUnknown(
+		InstallSucceeded,
+		ReasonReconciling,
+		"Installing the latest generation of the spec")
+}
+
 // MarkDeploymentsAvailable marks the DeploymentsAvailable status as true.
 func (tps *TektonConfigStatus) MarkDeploymentsAvailable() {
 	configCondSet.Manage(tps).MarkTrue(DeploymentsAvailable)
@@ -83,6 +98,15 @@ func (tps *TektonConfigStatus) MarkDeploymentsNotReady() {
 		"Waiting on deployments")
 }
 
+// MarkDeploymentsTimedOut marks the DeploymentsAvailable status as false as
+// the deployments didn't become available in time.
+func (tps *TektonConfigStatus) MarkDeploymentsTimedOut(msg string) {
+	configCondSet.Manage(tps).MarkFalse(
+		DeploymentsAvailable,
+		ReasonTimeoutWaitingReady,
+		"Deployments not available in time: %s", msg)
+}
+
 // MarkDependenciesInstalled marks the DependenciesInstalled status as true.
 func (tps *TektonConfigStatus) MarkDependenciesInstalled() {
 	configCondSet.Manage(tps).MarkTrue(DependenciesInstalled)
@@ -102,10 +126,38 @@ func (tps *TektonConfigStatus) MarkDependencyInstalling(msg string) {
 func (tps *TektonConfigStatus) MarkDependencyMissing(msg string) {
 	configCondSet.Manage(tps).MarkFalse(
 		DependenciesInstalled,
-		"Error",
+		ReasonDependencyMissing,
 		"Dependency missing: %s", msg)
 }
 
+// MarkDeprecatedFieldsUnset marks the DeprecatedFieldsUnset status as true.
+func (tps *TektonConfigStatus) MarkDeprecatedFieldsUnset() {
+	configCondSet.Manage(tps).MarkTrue(DeprecatedFieldsUnset)
+}
+
+// MarkDeprecatedFieldsSet marks the DeprecatedFieldsUnset status as false
+// with the given message.
+func (tps *TektonConfigStatus) MarkDeprecatedFieldsSet(msg string) {
+	configCondSet.Manage(tps).MarkFalse(
+		DeprecatedFieldsUnset,
+		ReasonDeprecatedFields,
+		"Deprecated fields set: %s", msg)
+}
+
+// MarkDowngradeBlocked sets the DowngradeBlocked status to true with the
+// given message.
+func (tps *TektonConfigStatus) MarkDowngradeBlocked(msg string) {
+	configCondSet.Manage(tps).MarkTrueWithReason(
+		DowngradeBlocked,
+		ReasonDowngradeBlocked,
+		"Downgrade blocked: %s", msg)
+}
+
+// ClearDowngradeBlocked removes the DowngradeBlocked status.
+func (tps *TektonConfigStatus) ClearDowngradeBlocked() {
+	_ = configCondSet.Manage(tps).ClearCondition(DowngradeBlocked)
+}
+
 // GetVersion gets the currently installed version of the component.
 func (tps *TektonConfigStatus) GetVersion() string {
 	return tps.Version
@@ -121,7 +173,37 @@ func (tps *TektonConfigStatus) GetManifests() []string {
 	return tps.Manifests
 }
 
+// GetObservedGeneration gets the generation of the spec installed last.
+func (tps *TektonConfigStatus) GetObservedGeneration() int64 {
+	return tps.ObservedGeneration
+}
+
+// SetObservedGeneration sets the generation of the spec installed last.
+func (tps *TektonConfigStatus) SetObservedGeneration(generation int64) {
+	tps.ObservedGeneration = generation
+}
+
 // SetVersion sets the url links of the manifests.
 func (tps *TektonConfigStatus) SetManifests(manifests []string) {
 	tps.Manifests = manifests
 }
+
+// GetPinnedImages gets the digest references the images were pinned to.
+func (tps *TektonConfigStatus) GetPinnedImages() map[string]string {
+	return tps.PinnedImages
+}
+
+// SetPinnedImages sets the digest references the images were pinned to.
+func (tps *TektonConfigStatus) SetPinnedImages(pinned map[string]string) {
+	tps.PinnedImages = pinned
+}
+
+// GetAPIVersions gets the API versions the installed CRDs serve.
+func (tps *TektonConfigStatus) GetAPIVersions() []string {
+	return tps.APIVersions
+}
+
+// SetAPIVersions sets the API versions the installed CRDs serve.
+func (tps *TektonConfigStatus) SetAPIVersions(versions []string) {
+	tps.APIVersions = versions
+}
@@ -51,8 +51,93 @@ func (tp *TektonConfig) GetStatus() TektonComponentStatus {
 
 // TektonConfigSpec defines the desired state of TektonConfig
 type TektonConfigSpec struct {
-	Profile    string `json:"profile,omitempty"`
+	// Profile selects the components installed: lite installs
+	// TektonPipeline only, basic and default add TektonTrigger, all adds
+	// the components of the platform, e.g. TektonDashboard
+	// +optional
+	Profile string `json:"profile,omitempty"`
+	// CommonSpec is passed on whole to the components the TektonConfig
+	// creates, not only the target namespace: its labels, podAnnotations,
+	// config and every other field apply to them as if set on each.
+	// The TektonPipeline and TektonTrigger it controls follow later
+	// changes; ones it doesn't control, e.g. created by hand, are left
+	// as they are.
 	CommonSpec `json:",inline"`
+
+	// ExcludedNamespaces are never touched by the automation of the
+	// operator in user namespaces, e.g. RBAC provisioning or pruning.
+	// Entries may be glob patterns, e.g. kube-*
+	// +optional
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+
+	// TrustBundle replicates a CA bundle into user namespaces, for the pods
+	// of TaskRuns to mount it
+	// +optional
+	TrustBundle *TrustBundle `json:"trustBundle,omitempty"`
+
+	// Chain installs Tekton Chains with these settings, the TektonChain
+	// being deleted once unset
+	// +optional
+	Chain *ChainProperties `json:"chain,omitempty"`
+
+	// PipelinesAsCode installs Pipelines as Code on OpenShift with these
+	// settings, the OpenShiftPipelinesAsCode being deleted once unset
+	// +optional
+	PipelinesAsCode *PipelinesAsCodeProperties `json:"pipelinesAsCode,omitempty"`
+
+	// Pruner deletes the old PipelineRuns and TaskRuns of every namespace
+	// but spec.excludedNamespaces on a schedule, its CronJob being deleted
+	// once unset
+	// +optional
+	Pruner *Pruner `json:"pruner,omitempty"`
+
+	// StatusAggregationSeconds batches the status changes of the child
+	// components for that long before the TektonConfig reports them, 10
+	// seconds by default, 0 reporting every change right away
+	// +optional
+	StatusAggregationSeconds *int64 `json:"statusAggregationSeconds,omitempty"`
+}
+
+// Pruner configures the periodic deletion of old runs
+type Pruner struct {
+	// Schedule of the pruning, in cron format, e.g. "0 8 * * *"
+	Schedule string `json:"schedule"`
+	// Keep is the number of the most recent runs of each resource kept in
+	// each namespace
+	Keep uint `json:"keep"`
+	// Resources are the kinds of runs pruned, pipelinerun and taskrun
+	Resources []string `json:"resources"`
+}
+
+// The resources a Pruner prunes
+const (
+	PrunePipelineRuns = "pipelinerun"
+	PruneTaskRuns     = "taskrun"
+)
+
+// DefaultTrustBundleName is the name of the replicas of the trust bundle
+// without spec.trustBundle.name
+const DefaultTrustBundleName = "config-trusted-cabundle"
+
+// TrustBundle configures the replication of a CA bundle ConfigMap
+type TrustBundle struct {
+	// ConfigMap of the operator namespace holding the CA bundle
+	ConfigMap string `json:"configMap"`
+	// Name of the replicas, config-trusted-cabundle by default
+	// +optional
+	Name string `json:"name,omitempty"`
+	// NamespaceSelector selects the namespaces the bundle is replicated
+	// to, all of them by default. spec.excludedNamespaces never get one.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// GetName returns the name of the replicas of the trust bundle
+func (tb *TrustBundle) GetName() string {
+	if tb.Name == "" {
+		return DefaultTrustBundleName
+	}
+	return tb.Name
 }
 
 // TektonConfigStatus defines the observed state of TektonConfig
@@ -66,6 +151,15 @@ type TektonConfigStatus struct {
 	// The url links of the manifests, separated by comma
 	// +optional
 	Manifests []string `json:"manifests,omitempty"`
+
+	// The digest references the payload images were pinned to, by image
+	// +optional
+	PinnedImages map[string]string `json:"pinnedImages,omitempty"`
+
+	// The Tekton API versions the installed custom resource definitions
+	// serve, e.g. tekton.dev/v1
+	// +optional
+	APIVersions []string `json:"apiVersions,omitempty"`
 }
 
 // TektonConfigList contains a list of TektonConfig
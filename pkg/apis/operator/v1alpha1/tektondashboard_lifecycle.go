@@ -63,12 +63,27 @@ func (tps *TektonDashboardStatus) MarkInstallSucceeded() {
 // MarkInstallFailed marks the InstallationSucceeded status as false with the given
 // message.
 func (tps *TektonDashboardStatus) MarkInstallFailed(msg string) {
+	tps.MarkInstallFailedWithReason(ReasonError, msg)
+}
+
+// MarkInstallFailedWithReason marks the InstallationSucceeded status as false
+// with the given reason and message.
+func (tps *TektonDashboardStatus) MarkInstallFailedWithReason(reason, msg string) {
 	dashboardCondSet.Manage(tps).MarkFalse(
 		InstallSucceeded,
-		"Error",
+		reason,
 		"Install failed with message: %s", msg)
 }
 
+// MarkInstallReconciling marks the InstallSucceeded status as unknown while
+// a generation of the spec not observed yet is being installed.
+func (tps *TektonDashboardStatus) MarkInstallReconciling() {
+	dashboardCondSet.Manage(tps).MarkUnknown(
+		InstallSucceeded,
+		ReasonReconciling,
+		"Installing the latest generation of the spec")
+}
+
 // MarkDeploymentsAvailable marks the DeploymentsAvailable status as true.
 func (tps *TektonDashboardStatus) MarkDeploymentsAvailable() {
 	dashboardCondSet.Manage(tps).MarkTrue(DeploymentsAvailable)
@@ -83,6 +98,15 @@ func (tps *TektonDashboardStatus) MarkDeploymentsNotReady() {
 		"Waiting on deployments")
 }
 
+// MarkDeploymentsTimedOut marks the DeploymentsAvailable status as false as
+// the deployments didn't become available in time.
+func (tps *TektonDashboardStatus) MarkDeploymentsTimedOut(msg string) {
+	dashboardCondSet.Manage(tps).MarkFalse(
+		DeploymentsAvailable,
+		ReasonTimeoutWaitingReady,
+		"Deployments not available in time: %s", msg)
+}
+
 // MarkDependenciesInstalled marks the DependenciesInstalled status as true.
 func (tps *TektonDashboardStatus) MarkDependenciesInstalled() {
 	dashboardCondSet.Manage(tps).MarkTrue(DependenciesInstalled)
@@ -102,10 +126,38 @@ func (tps *TektonDashboardStatus) MarkDependencyInstalling(msg string) {
 func (tps *TektonDashboardStatus) MarkDependencyMissing(msg string) {
 	dashboardCondSet.Manage(tps).MarkFalse(
 		DependenciesInstalled,
-		"Error",
+		ReasonDependencyMissing,
 		"Dependency missing: %s", msg)
 }
 
+// MarkDeprecatedFieldsUnset marks the DeprecatedFieldsUnset status as true.
+func (tps *TektonDashboardStatus) MarkDeprecatedFieldsUnset() {
+	dashboardCondSet.Manage(tps).MarkTrue(DeprecatedFieldsUnset)
+}
+
+// MarkDeprecatedFieldsSet marks the DeprecatedFieldsUnset status as false
+// with the given message.
+func (tps *TektonDashboardStatus) MarkDeprecatedFieldsSet(msg string) {
+	dashboardCondSet.Manage(tps).MarkFalse(
+		DeprecatedFieldsUnset,
+		ReasonDeprecatedFields,
+		"Deprecated fields set: %s", msg)
+}
+
+// MarkDowngradeBlocked sets the DowngradeBlocked status to true with the
+// given message.
+func (tps *TektonDashboardStatus) MarkDowngradeBlocked(msg string) {
+	dashboardCondSet.Manage(tps).MarkTrueWithReason(
+		DowngradeBlocked,
+		ReasonDowngradeBlocked,
+		"Downgrade blocked: %s", msg)
+}
+
+// ClearDowngradeBlocked removes the DowngradeBlocked status.
+func (tps *TektonDashboardStatus) ClearDowngradeBlocked() {
+	_ = dashboardCondSet.Manage(tps).ClearCondition(DowngradeBlocked)
+}
+
 // GetVersion gets the currently installed version of the component.
 func (tps *TektonDashboardStatus) GetVersion() string {
 	return tps.Version
@@ -121,7 +173,37 @@ func (tps *TektonDashboardStatus) GetManifests() []string {
 	return tps.Manifests
 }
 
+// GetObservedGeneration gets the generation of the spec installed last.
+func (tps *TektonDashboardStatus) GetObservedGeneration() int64 {
+	return tps.ObservedGeneration
+}
+
+// SetObservedGeneration sets the generation of the spec installed last.
+func (tps *TektonDashboardStatus) SetObservedGeneration(generation int64) {
+	tps.ObservedGeneration = generation
+}
+
 // SetVersion sets the url links of the manifests.
 func (tps *TektonDashboardStatus) SetManifests(manifests []string) {
 	tps.Manifests = manifests
 }
+
+// GetPinnedImages gets the digest references the images were pinned to.
+func (tps *TektonDashboardStatus) GetPinnedImages() map[string]string {
+	return tps.PinnedImages
+}
+
+// SetPinnedImages sets the digest references the images were pinned to.
+func (tps *TektonDashboardStatus) SetPinnedImages(pinned map[string]string) {
+	tps.PinnedImages = pinned
+}
+
+// GetAPIVersions gets the API versions the installed CRDs serve.
+func (tps *TektonDashboardStatus) GetAPIVersions() []string {
+	return tps.APIVersions
+}
+
+// SetAPIVersions sets the API versions the installed CRDs serve.
+func (tps *TektonDashboardStatus) SetAPIVersions(versions []string) {
+	tps.APIVersions = versions
+}
@@ -52,6 +52,16 @@ func (tp *TektonDashboard) GetStatus() TektonComponentStatus {
 // TektonDashboardSpec defines the desired state of TektonDashboard
 type TektonDashboardSpec struct {
 	CommonSpec `json:",inline"`
+
+	// Readonly installs the dashboard without the permissions to create,
+	// update or delete resources, hiding the actions doing so
+	// +optional
+	Readonly bool `json:"readonly,omitempty"`
+
+	// Expose creates an Ingress or an OpenShift Route for the dashboard,
+	// owned by the operator and following the Service across upgrades
+	// +optional
+	Expose *ServiceExpose `json:"expose,omitempty"`
 }
 
 // TektonDashboardStatus defines the observed state of TektonDashboard
@@ -65,6 +75,15 @@ type TektonDashboardStatus struct {
 	// The url links of the manifests, separated by comma
 	// +optional
 	Manifests []string `json:"manifests,omitempty"`
+
+	// The digest references the payload images were pinned to, by image
+	// +optional
+	PinnedImages map[string]string `json:"pinnedImages,omitempty"`
+
+	// The Tekton API versions the installed custom resource definitions
+	// serve, e.g. tekton.dev/v1
+	// +optional
+	APIVersions []string `json:"apiVersions,omitempty"`
 }
 
 // TektonDashboardsList contains a list of TektonDashboard
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+var (
+	_ TektonComponent     = (*TektonHub)(nil)
+	_ TektonComponentSpec = (*TektonHubSpec)(nil)
+)
+
+// TektonHub is the Schema for the tektonhubs API
+// +genclient
+// +genreconciler:krshapedlogic=false
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+type TektonHub struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TektonHubSpec   `json:"spec,omitempty"`
+	Status TektonHubStatus `json:"status,omitempty"`
+}
+
+// GetSpec implements TektonComponent
+func (tp *TektonHub) GetSpec() TektonComponentSpec {
+	return &tp.Spec
+}
+
+// GetStatus implements TektonComponent
+func (tp *TektonHub) GetStatus() TektonComponentStatus {
+	return &tp.Status
+}
+
+// TektonHubSpec defines the desired state of TektonHub
+type TektonHubSpec struct {
+	CommonSpec `json:",inline"`
+
+	// Images overrides the images of the payload by container, step or
+	// argument name, taking precedence over the operator's overrides
+	// +optional
+	Images map[string]string `json:"images,omitempty"`
+
+	// Db configures the database of the API
+	// +optional
+	Db HubDb `json:"db,omitempty"`
+
+	// Api configures the API server
+	// +optional
+	Api HubApi `json:"api,omitempty"`
+
+	// UI configures the web UI
+	// +optional
+	UI HubUI `json:"ui,omitempty"`
+}
+
+// HubDb configures the database of the hub API.
+type HubDb struct {
+	// Secret names a Secret of the target namespace holding the
+	// POSTGRES_HOST, POSTGRES_PORT, POSTGRES_DB, POSTGRES_USER and
+	// POSTGRES_PASSWORD keys of an external PostgreSQL database, the
+	// database of the payload not being installed then
+	// +optional
+	Secret string `json:"secret,omitempty"`
+}
+
+// HubApi configures the hub API server.
+type HubApi struct {
+	// Secret names a Secret of the target namespace holding the
+	// GH_CLIENT_ID, GH_CLIENT_SECRET, JWT_SIGNING_KEY,
+	// ACCESS_JWT_EXPIRES_IN and REFRESH_JWT_EXPIRES_IN keys of the API,
+	// defaults to tekton-hub-api
+	// +optional
+	Secret string `json:"secret,omitempty"`
+	// HubConfigUrl is the URL of the hub config file, listing the
+	// catalogs, categories and scopes of the hub
+	// +optional
+	HubConfigUrl string `json:"hubConfigUrl,omitempty"`
+	// Expose creates an Ingress or an OpenShift Route for the API
+	// +optional
+	Expose *ServiceExpose `json:"expose,omitempty"`
+}
+
+// HubUI configures the hub web UI.
+type HubUI struct {
+	// Expose creates an Ingress or an OpenShift Route for the UI
+	// +optional
+	Expose *ServiceExpose `json:"expose,omitempty"`
+}
+
+// TektonHubStatus defines the observed state of TektonHub
+type TektonHubStatus struct {
+	duckv1.Status `json:",inline"`
+
+	// The version of the installed release
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The url links of the manifests, separated by comma
+	// +optional
+	Manifests []string `json:"manifests,omitempty"`
+
+	// The digest references the payload images were pinned to, by image
+	// +optional
+	PinnedImages map[string]string `json:"pinnedImages,omitempty"`
+
+	// The Tekton API versions the installed custom resource definitions
+	// serve, e.g. tekton.dev/v1
+	// +optional
+	APIVersions []string `json:"apiVersions,omitempty"`
+}
+
+// TektonHubList contains a list of TektonHub
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TektonHubList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TektonHub `json:"items"`
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+)
+
+var installerSetCondSet = apis.NewLivingConditionSet(InstallSucceeded)
+
+// GroupVersionKind returns SchemeGroupVersion of a TektonInstallerSet
+func (tis *TektonInstallerSet) GroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind(KindTektonInstallerSet)
+}
+
+// GetCondition returns the current condition of a given condition type
+func (tiss *TektonInstallerSetStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return installerSetCondSet.Manage(tiss).GetCondition(t)
+}
+
+// InitializeConditions initializes conditions of an TektonInstallerSetStatus
+func (tiss *TektonInstallerSetStatus) InitializeConditions() {
+	installerSetCondSet.Manage(tiss).InitializeConditions()
+}
+
+// IsReady looks at the conditions returns true if they are all true.
+func (tiss *TektonInstallerSetStatus) IsReady() bool {
+	return installerSetCondSet.Manage(tiss).IsHappy()
+}
+
+// MarkInstallSucceeded marks the InstallationSucceeded status as true.
+func (tiss *TektonInstallerSetStatus) MarkInstallSucceeded() {
+	installerSetCondSet.Manage(tiss).MarkTrue(InstallSucceeded)
+}
+
+// MarkInstallFailedWithReason marks the InstallationSucceeded status as false
+// with the given reason and message, which the component of the installer
+// set reports as is.
+func (tiss *TektonInstallerSetStatus) MarkInstallFailedWithReason(reason, msg string) {
+	installerSetCondSet.Manage(tiss).MarkFalse(InstallSucceeded, reason, "%s", msg)
+}
+
+// MarkInstallReconciling marks the InstallSucceeded status as unknown while
+// a generation of the spec not observed yet is being applied.
+func (tiss *TektonInstallerSetStatus) MarkInstallReconciling() {
+	installerSetCondSet.Manage(tiss).MarkUnknown(
+		InstallSucceeded,
+		ReasonReconciling,
+		"Applying the latest generation of the spec")
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+const (
+	// KindTektonInstallerSet is the Kind of Tekton Installer Set in a GVK context.
+	KindTektonInstallerSet = "TektonInstallerSet"
+
+	// CreatedByKey labels an installer set with the kind of the component
+	// which created it
+	CreatedByKey = "operator.tekton.dev/created-by"
+
+	// ReleaseVersionKey labels an installer set with the version of the
+	// payload it holds
+	ReleaseVersionKey = "operator.tekton.dev/release-version"
+)
+
+// TektonInstallerSet records the exact resources applied for a component
+// and applies them, so that what a component installed can be told from
+// the cluster. The installer sets are internal: the reconciler of each
+// component creates, updates and owns its own.
+// +genclient
+// +genreconciler:krshapedlogic=false
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+type TektonInstallerSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TektonInstallerSetSpec `json:"spec,omitempty"`
+	// +optional
+	Status TektonInstallerSetStatus `json:"status,omitempty"`
+}
+
+// TektonInstallerSetSpec defines the resources of an installer set
+type TektonInstallerSetSpec struct {
+	// Manifests are the resources applied, transformed already
+	// +optional
+	Manifests []unstructured.Unstructured `json:"manifests,omitempty"`
+	// ServerSideApply applies the manifests server side, see
+	// spec.config.serverSideApply of the components
+	// +optional
+	ServerSideApply bool `json:"serverSideApply,omitempty"`
+	// Retained are resources applied before which the manifests no longer
+	// hold but which are kept as they are in the cluster rather than
+	// deleted as orphans, e.g. those of a paused sub-payload
+	// +optional
+	Retained []InstalledResource `json:"retained,omitempty"`
+}
+
+// TektonInstallerSetStatus defines the observed state of an installer set
+type TektonInstallerSetStatus struct {
+	duckv1.Status `json:",inline"`
+
+	// Resources are the resources applied last, those no longer part of
+	// the manifests being deleted once the manifests are applied
+	// +optional
+	Resources []InstalledResource `json:"resources,omitempty"`
+
+	// AppliedHash is the hash of the manifests applied last, along with
+	// the last reapply asked for, the installer set not being applied again
+	// until it changes
+	// +optional
+	AppliedHash string `json:"appliedHash,omitempty"`
+}
+
+// InstalledResource references a resource applied by an installer set
+type InstalledResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// TektonInstallerSetList contains a list of TektonInstallerSet
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TektonInstallerSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TektonInstallerSet `json:"items"`
+}
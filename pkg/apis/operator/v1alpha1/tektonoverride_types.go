@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TektonOverride layers labels, annotations, scheduling and images on top
+// of the spec of some components, e.g. those a TektonConfig installs. Only
+// the overrides of the operator namespace apply, the components being
+// cluster wide
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TektonOverride struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TektonOverrideSpec `json:"spec,omitempty"`
+}
+
+// TektonOverrideSpec defines the overrides of the components
+type TektonOverrideSpec struct {
+	// Components the override applies to, by kind, e.g. TektonPipeline
+	Components []string `json:"components"`
+
+	// Priority of the override. Overrides of higher priority take
+	// precedence, those of the same priority are ordered by name, the last
+	// one taking precedence.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// Labels added to every resource of the components, taking precedence
+	// over those of the components' spec but not over those of the payload
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations added to every resource of the components, taking
+	// precedence over those of the components' spec but not over those of
+	// the payload
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Scheduling of the pods of the payload deployments
+	// +optional
+	Scheduling *PodScheduling `json:"scheduling,omitempty"`
+
+	// Images overrides the images of the payload like spec.images of the
+	// components, taking precedence over it
+	// +optional
+	Images map[string]string `json:"images,omitempty"`
+}
+
+// PodScheduling places the pods of the payload deployments
+type PodScheduling struct {
+	// NodeSelector merged into the node selector of the pods
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations added to the pods
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// Targets returns true if the override applies to components of the given
+// kind
+func (to *TektonOverride) Targets(kind string) bool {
+	for _, component := range to.Spec.Components {
+		if component == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// TektonOverrideList contains a list of TektonOverride
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TektonOverrideList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TektonOverride `json:"items"`
+}
@@ -63,12 +63,27 @@ func (tps *TektonPipelineStatus) MarkInstallSucceeded() {
 // MarkInstallFailed marks the InstallationSucceeded status as false with the given
 // message.
 func (tps *TektonPipelineStatus) MarkInstallFailed(msg string) {
+	tps.MarkInstallFailedWithReason(ReasonError, msg)
+}
+
+// MarkInstallFailedWithReason marks the InstallationSucceeded status as false
+// with the given reason and message.
+func (tps *TektonPipelineStatus) MarkInstallFailedWithReason(reason, msg string) {
 	pipelineCondSet.Manage(tps).MarkFalse(
 		InstallSucceeded,
-		"Error",
+		reason,
 		"Install failed with message: %s", msg)
 }
 
+// MarkInstallReconciling marks the InstallSucceeded status as unknown while
+// a generation of the spec not observed yet is being installed.
+func (tps *TektonPipelineStatus) MarkInstallReconciling() {
+	pipelineCondSet.Manage(tps).MarkUnknown(
+		InstallSucceeded,
+		ReasonReconciling,
+		"Installing the latest generation of the spec")
+}
+
 // MarkDeploymentsAvailable marks the DeploymentsAvailable status as true.
 func (tps *TektonPipelineStatus) MarkDeploymentsAvailable() {
 	pipelineCondSet.Manage(tps).MarkTrue(DeploymentsAvailable)
@@ -83,6 +98,15 @@ func (tps *TektonPipelineStatus) MarkDeploymentsNotReady() {
 		"Waiting on deployments")
 }
 
+// MarkDeploymentsTimedOut marks the DeploymentsAvailable status as false as
+// the deployments didn't become available in time.
+func (tps *TektonPipelineStatus) MarkDeploymentsTimedOut(msg string) {
+	pipelineCondSet.Manage(tps).MarkFalse(
+		DeploymentsAvailable,
+		ReasonTimeoutWaitingReady,
+		"Deployments not available in time: %s", msg)
+}
+
 // MarkDependenciesInstalled marks the DependenciesInstalled status as true.
 func (tps *TektonPipelineStatus) MarkDependenciesInstalled() {
 	pipelineCondSet.Manage(tps).MarkTrue(DependenciesInstalled)
@@ -102,10 +126,38 @@ func (tps *TektonPipelineStatus) MarkDependencyInstalling(msg string) {
 func (tps *TektonPipelineStatus) MarkDependencyMissing(msg string) {
 	pipelineCondSet.Manage(tps).MarkFalse(
 		DependenciesInstalled,
-		"Error",
+		ReasonDependencyMissing,
 		"Dependency missing: %s", msg)
 }
 
+// MarkDeprecatedFieldsUnset marks the DeprecatedFieldsUnset status as true.
+func (tps *TektonPipelineStatus) MarkDeprecatedFieldsUnset() {
+	pipelineCondSet.Manage(tps).MarkTrue(DeprecatedFieldsUnset)
+}
+
+// MarkDeprecatedFieldsSet marks the DeprecatedFieldsUnset status as false
+// with the given message.
+func (tps *TektonPipelineStatus) MarkDeprecatedFieldsSet(msg string) {
+	pipelineCondSet.Manage(tps).MarkFalse(
+		DeprecatedFieldsUnset,
+		ReasonDeprecatedFields,
+		"Deprecated fields set: %s", msg)
+}
+
+// MarkDowngradeBlocked sets the DowngradeBlocked status to true with the
+// given message.
+func (tps *TektonPipelineStatus) MarkDowngradeBlocked(msg string) {
+	pipelineCondSet.Manage(tps).MarkTrueWithReason(
+		DowngradeBlocked,
+		ReasonDowngradeBlocked,
+		"Downgrade blocked: %s", msg)
+}
+
+// ClearDowngradeBlocked removes the DowngradeBlocked status.
+func (tps *TektonPipelineStatus) ClearDowngradeBlocked() {
+	_ = pipelineCondSet.Manage(tps).ClearCondition(DowngradeBlocked)
+}
+
 // GetVersion gets the currently installed version of the component.
 func (tps *TektonPipelineStatus) GetVersion() string {
 	return tps.Version
@@ -121,7 +173,37 @@ func (tps *TektonPipelineStatus) GetManifests() []string {
 	return tps.Manifests
 }
 
+// GetObservedGeneration gets the generation of the spec installed last.
+func (tps *TektonPipelineStatus) GetObservedGeneration() int64 {
+	return tps.ObservedGeneration
+}
+
+// SetObservedGeneration sets the generation of the spec installed last.
+func (tps *TektonPipelineStatus) SetObservedGeneration(generation int64) {
+	tps.ObservedGeneration = generation
+}
+
 // SetVersion sets the url links of the manifests.
 func (tps *TektonPipelineStatus) SetManifests(manifests []string) {
 	tps.Manifests = manifests
 }
+
+// GetPinnedImages gets the digest references the images were pinned to.
+func (tps *TektonPipelineStatus) GetPinnedImages() map[string]string {
+	return tps.PinnedImages
+}
+
+// SetPinnedImages sets the digest references the images were pinned to.
+func (tps *TektonPipelineStatus) SetPinnedImages(pinned map[string]string) {
+	tps.PinnedImages = pinned
+}
+
+// GetAPIVersions gets the API versions the installed CRDs serve.
+func (tps *TektonPipelineStatus) GetAPIVersions() []string {
+	return tps.APIVersions
+}
+
+// SetAPIVersions sets the API versions the installed CRDs serve.
+func (tps *TektonPipelineStatus) SetAPIVersions(versions []string) {
+	tps.APIVersions = versions
+}
@@ -136,3 +136,43 @@ func TestTektonPipelineExternalDependency(t *testing.T) {
 	apistest.CheckConditionOngoing(tp, DeploymentsAvailable, t)
 	apistest.CheckConditionSucceeded(tp, InstallSucceeded, t)
 }
+
+func TestTektonPipelineDeprecatedFields(t *testing.T) {
+	tp := &TektonPipelineStatus{}
+	tp.InitializeConditions()
+	tp.MarkInstallSucceeded()
+	tp.MarkDeploymentsAvailable()
+
+	// Deprecated fields don't affect readiness.
+	tp.MarkDeprecatedFieldsSet("test")
+	apistest.CheckConditionFailed(tp, DeprecatedFieldsUnset, t)
+	if ready := tp.IsReady(); !ready {
+		t.Errorf("tp.IsReady() = %v, want true", ready)
+	}
+
+	tp.MarkDeprecatedFieldsUnset()
+	apistest.CheckConditionSucceeded(tp, DeprecatedFieldsUnset, t)
+	if ready := tp.IsReady(); !ready {
+		t.Errorf("tp.IsReady() = %v, want true", ready)
+	}
+}
+
+func TestTektonPipelineDowngradeBlocked(t *testing.T) {
+	tp := &TektonPipelineStatus{}
+	tp.InitializeConditions()
+	tp.MarkInstallSucceeded()
+	tp.MarkDeploymentsAvailable()
+
+	tp.MarkDowngradeBlocked("test")
+	tp.MarkInstallFailedWithReason(ReasonDowngradeBlocked, "test")
+	apistest.CheckConditionSucceeded(tp, DowngradeBlocked, t)
+	apistest.CheckConditionFailed(tp, InstallSucceeded, t)
+	if ready := tp.IsReady(); ready {
+		t.Errorf("tp.IsReady() = %v, want false", ready)
+	}
+
+	tp.ClearDowngradeBlocked()
+	if c := tp.GetCondition(DowngradeBlocked); c != nil {
+		t.Errorf("DowngradeBlocked = %v, want it cleared", c)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
@@ -52,6 +53,166 @@ func (tp *TektonPipeline) GetStatus() TektonComponentStatus {
 // TektonPipelineSpec defines the desired state of TektonPipeline
 type TektonPipelineSpec struct {
 	CommonSpec `json:",inline"`
+
+	// Images overrides the images of the payload by container, step or
+	// argument name, taking precedence over the operator's overrides
+	// +optional
+	Images map[string]string `json:"images,omitempty"`
+
+	// GitResolver configures the git resolver of remote tasks and
+	// pipelines, when the payload has one. Without secrets, repositories
+	// are cloned anonymously.
+	// +optional
+	GitResolver *GitResolver `json:"gitResolver,omitempty"`
+
+	// FeatureFlags are written into the feature-flags ConfigMap of the
+	// payload, taking precedence over spec.options.configMaps. Flags left
+	// unset keep the value of the payload.
+	// +optional
+	FeatureFlags *PipelineFeatureFlags `json:"featureFlags,omitempty"`
+
+	// Defaults are written into the config-defaults ConfigMap of the
+	// payload, taking precedence over spec.options.configMaps. Defaults left
+	// unset keep the value of the payload.
+	// +optional
+	Defaults *PipelineDefaults `json:"defaults,omitempty"`
+
+	// CloudEvents sends the events of TaskRuns and PipelineRuns to a
+	// CloudEvents sink, setting default-cloud-events-sink of the
+	// config-defaults ConfigMap
+	// +optional
+	CloudEvents *CloudEvents `json:"cloudEvents,omitempty"`
+}
+
+// CloudEvents configures the sink the events of the runs are sent to
+type CloudEvents struct {
+	// Sink is the http or https URL the events are sent to
+	Sink string `json:"sink"`
+
+	// AuthSecret holds the bearer token the events are sent to the sink
+	// with, in the target namespace. The controller can't authenticate,
+	// setting it implies the listener.
+	// +optional
+	AuthSecret *SecretKeyReference `json:"authSecret,omitempty"`
+
+	// Listener deploys a service of the target namespace the controller
+	// sends the events to, relaying them to the sink
+	// +optional
+	Listener bool `json:"listener,omitempty"`
+}
+
+// ListenerEnabled returns true if the events are relayed by the listener
+func (c *CloudEvents) ListenerEnabled() bool {
+	return c != nil && (c.Listener || c.AuthSecret != nil)
+}
+
+// PipelineDefaults are the defaults of the runs of Tekton Pipelines
+type PipelineDefaults struct {
+	// DefaultTimeoutMinutes of TaskRuns and PipelineRuns without timeout,
+	// 0 for no timeout
+	// +optional
+	DefaultTimeoutMinutes *int32 `json:"defaultTimeoutMinutes,omitempty"`
+	// DefaultServiceAccount of TaskRuns and PipelineRuns without service
+	// account
+	// +optional
+	DefaultServiceAccount string `json:"defaultServiceAccount,omitempty"`
+	// DefaultPodTemplate of the pods of TaskRuns without pod template
+	// +optional
+	DefaultPodTemplate *PodTemplate `json:"defaultPodTemplate,omitempty"`
+	// DefaultManagedByLabelValue is the app.kubernetes.io/managed-by label
+	// of the pods of TaskRuns without one
+	// +optional
+	DefaultManagedByLabelValue string `json:"defaultManagedByLabelValue,omitempty"`
+}
+
+// PodTemplate holds the fields of the pods of TaskRuns a Tekton pod
+// template sets
+type PodTemplate struct {
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// +optional
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+	// +optional
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// +optional
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+}
+
+// PipelineFeatureFlags are the feature flags of Tekton Pipelines
+type PipelineFeatureFlags struct {
+	// EnableAPIFields is the stability level of the API fields accepted,
+	// stable, beta or alpha
+	// +optional
+	EnableAPIFields string `json:"enableAPIFields,omitempty"`
+	// DisableAffinityAssistant stops scheduling the pods of a PipelineRun
+	// sharing a workspace on the same node
+	// +optional
+	DisableAffinityAssistant *bool `json:"disableAffinityAssistant,omitempty"`
+	// EnableCustomTasks lets pipelines reference tasks of other kinds,
+	// run through Run resources
+	// +optional
+	EnableCustomTasks *bool `json:"enableCustomTasks,omitempty"`
+	// RunningInEnvironmentWithInjectedSidecars makes TaskRuns wait for
+	// sidecars injected by admission controllers before starting
+	// +optional
+	RunningInEnvironmentWithInjectedSidecars *bool `json:"runningInEnvironmentWithInjectedSidecars,omitempty"`
+	// DisableCredsInit stops initializing credentials from the Secrets of
+	// the ServiceAccount of TaskRuns
+	// +optional
+	DisableCredsInit *bool `json:"disableCredsInit,omitempty"`
+	// DisableHomeEnvOverwrite stops setting $HOME to /tekton/home
+	// +optional
+	DisableHomeEnvOverwrite *bool `json:"disableHomeEnvOverwrite,omitempty"`
+	// DisableWorkingDirOverwrite stops setting the working directory of
+	// steps to /workspace
+	// +optional
+	DisableWorkingDirOverwrite *bool `json:"disableWorkingDirOverwrite,omitempty"`
+	// EnableTektonOCIBundles lets tasks and pipelines be referenced from
+	// OCI bundles
+	// +optional
+	EnableTektonOCIBundles *bool `json:"enableTektonOCIBundles,omitempty"`
+	// RequireGitSSHSecretKnownHosts requires git SSH Secrets to include
+	// known_hosts
+	// +optional
+	RequireGitSSHSecretKnownHosts *bool `json:"requireGitSSHSecretKnownHosts,omitempty"`
+}
+
+// GitResolver configures the access of the git resolver to repositories
+type GitResolver struct {
+	// ServerURL of the SCM API used with the API token, e.g.
+	// https://github.example.com, defaults to the public service
+	// +optional
+	ServerURL string `json:"serverURL,omitempty"`
+
+	// SCMType of the server, e.g. github or gitlab
+	// +optional
+	SCMType string `json:"scmType,omitempty"`
+
+	// APITokenSecret holds the token the resolver fetches files from the
+	// SCM API with, in the namespace of the resolver
+	// +optional
+	APITokenSecret *SecretKeyReference `json:"apiTokenSecret,omitempty"`
+
+	// SSHKeySecret holds the private key the resolver clones repositories
+	// over SSH with, in the namespace of the resolver
+	// +optional
+	SSHKeySecret *SecretKeyReference `json:"sshKeySecret,omitempty"`
+}
+
+// SecretKeyReference points to a key of a Secret
+type SecretKeyReference struct {
+	// Name of the secret
+	Name string `json:"name"`
+	// Key of the value within the secret
+	Key string `json:"key"`
 }
 
 // TektonPipelineStatus defines the observed state of TektonPipeline
@@ -65,6 +226,15 @@ type TektonPipelineStatus struct {
 	// The url links of the manifests, separated by comma
 	// +optional
 	Manifests []string `json:"manifests,omitempty"`
+
+	// The digest references the payload images were pinned to, by image
+	// +optional
+	PinnedImages map[string]string `json:"pinnedImages,omitempty"`
+
+	// The Tekton API versions the installed custom resource definitions
+	// serve, e.g. tekton.dev/v1
+	// +optional
+	APIVersions []string `json:"apiVersions,omitempty"`
 }
 
 // TektonPipelineList contains a list of TektonPipeline
@@ -63,12 +63,27 @@ func (tps *TektonTriggerStatus) MarkInstallSucceeded() {
 // MarkInstallFailed marks the InstallationSucceeded status as false with the given
 // message.
 func (tps *TektonTriggerStatus) MarkInstallFailed(msg string) {
+	tps.MarkInstallFailedWithReason(ReasonError, msg)
+}
+
+// MarkInstallFailedWithReason marks the InstallationSucceeded status as false
+// with the given reason and message.
+func (tps *TektonTriggerStatus) MarkInstallFailedWithReason(reason, msg string) {
 	triggersCondSet.Manage(tps).MarkFalse(
 		InstallSucceeded,
-		"Error",
+		reason,
 		"Install failed with message: %s", msg)
 }
 
+// MarkInstallReconciling marks the InstallSucceeded status as unknown while
+// a generation of the spec not observed yet is being installed.
+func (tps *TektonTriggerStatus) MarkInstallReconciling() {
+	triggersCondSet.Manage(tps).MarkUnknown(
+		InstallSucceeded,
+		ReasonReconciling,
+		"Installing the latest generation of the spec")
+}
+
 // MarkDeploymentsAvailable marks the DeploymentsAvailable status as true.
 func (tps *TektonTriggerStatus) MarkDeploymentsAvailable() {
 	triggersCondSet.Manage(tps).MarkTrue(DeploymentsAvailable)
@@ -83,6 +98,15 @@ func (tps *TektonTriggerStatus) MarkDeploymentsNotReady() {
 		"Waiting on deployments")
 }
 
+// MarkDeploymentsTimedOut marks the DeploymentsAvailable status as false as
+// the deployments didn't become available in time.
+func (tps *TektonTriggerStatus) MarkDeploymentsTimedOut(msg string) {
+	triggersCondSet.Manage(tps).MarkFalse(
+		DeploymentsAvailable,
+		ReasonTimeoutWaitingReady,
+		"Deployments not available in time: %s", msg)
+}
+
 // MarkDependenciesInstalled marks the DependenciesInstalled status as true.
 func (tps *TektonTriggerStatus) MarkDependenciesInstalled() {
 	triggersCondSet.Manage(tps).MarkTrue(DependenciesInstalled)
@@ -102,10 +126,38 @@ func (tps *TektonTriggerStatus) MarkDependencyInstalling(msg string) {
 func (tps *TektonTriggerStatus) MarkDependencyMissing(msg string) {
 	triggersCondSet.Manage(tps).MarkFalse(
 		DependenciesInstalled,
-		"Error",
+		ReasonDependencyMissing,
 		"Dependency missing: %s", msg)
 }
 
+// MarkDeprecatedFieldsUnset marks the DeprecatedFieldsUnset status as true.
+func (tps *TektonTriggerStatus) MarkDeprecatedFieldsUnset() {
+	triggersCondSet.Manage(tps).MarkTrue(DeprecatedFieldsUnset)
+}
+
+// MarkDeprecatedFieldsSet marks the DeprecatedFieldsUnset status as false
+// with the given message.
+func (tps *TektonTriggerStatus) MarkDeprecatedFieldsSet(msg string) {
+	triggersCondSet.Manage(tps).MarkFalse(
+		DeprecatedFieldsUnset,
+		ReasonDeprecatedFields,
+		"Deprecated fields set: %s", msg)
+}
+
+// MarkDowngradeBlocked sets the DowngradeBlocked status to true with the
+// given message.
+func (tps *TektonTriggerStatus) MarkDowngradeBlocked(msg string) {
+	triggersCondSet.Manage(tps).MarkTrueWithReason(
+		DowngradeBlocked,
+		ReasonDowngradeBlocked,
+		"Downgrade blocked: %s", msg)
+}
+
+// ClearDowngradeBlocked removes the DowngradeBlocked status.
+func (tps *TektonTriggerStatus) ClearDowngradeBlocked() {
+	_ = triggersCondSet.Manage(tps).ClearCondition(DowngradeBlocked)
+}
+
 // GetVersion gets the currently installed version of the component.
 func (tps *TektonTriggerStatus) GetVersion() string {
 	return tps.Version
@@ -121,7 +173,37 @@ func (tps *TektonTriggerStatus) GetManifests() []string {
 	return tps.Manifests
 }
 
+// GetObservedGeneration gets the generation of the spec installed last.
+func (tps *TektonTriggerStatus) GetObservedGeneration() int64 {
+	return tps.ObservedGeneration
+}
+
+// SetObservedGeneration sets the generation of the spec installed last.
+func (tps *TektonTriggerStatus) SetObservedGeneration(generation int64) {
+	tps.ObservedGeneration = generation
+}
+
 // SetVersion sets the url links of the manifests.
 func (tps *TektonTriggerStatus) SetManifests(manifests []string) {
 	tps.Manifests = manifests
 }
+
+// GetPinnedImages gets the digest references the images were pinned to.
+func (tps *TektonTriggerStatus) GetPinnedImages() map[string]string {
+	return tps.PinnedImages
+}
+
+// SetPinnedImages sets the digest references the images were pinned to.
+func (tps *TektonTriggerStatus) SetPinnedImages(pinned map[string]string) {
+	tps.PinnedImages = pinned
+}
+
+// GetAPIVersions gets the API versions the installed CRDs serve.
+func (tps *TektonTriggerStatus) GetAPIVersions() []string {
+	return tps.APIVersions
+}
+
+// SetAPIVersions sets the API versions the installed CRDs serve.
+func (tps *TektonTriggerStatus) SetAPIVersions(versions []string) {
+	tps.APIVersions = versions
+}
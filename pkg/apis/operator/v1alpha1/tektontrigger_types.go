@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
@@ -52,6 +53,86 @@ func (tp *TektonTrigger) GetStatus() TektonComponentStatus {
 // TektonTriggerSpec defines the desired state of TektonTrigger
 type TektonTriggerSpec struct {
 	CommonSpec `json:",inline"`
+
+	// Images overrides the images of the payload by container, step or
+	// argument name, taking precedence over the operator's overrides
+	// +optional
+	Images map[string]string `json:"images,omitempty"`
+
+	// EventListenerDefaults are the scheduling constraints and the labels
+	// of the pods of the EventListeners which don't set their own
+	// +optional
+	EventListenerDefaults *EventListenerDefaults `json:"eventListenerDefaults,omitempty"`
+
+	// FeatureFlags are written into the feature-flags-triggers ConfigMap of
+	// the payload, taking precedence over spec.options.configMaps. Flags
+	// left unset keep the value of the payload.
+	// +optional
+	FeatureFlags *TriggerFeatureFlags `json:"featureFlags,omitempty"`
+
+	// Defaults are written into the config-defaults-triggers ConfigMap of
+	// the payload, taking precedence over spec.options.configMaps. Defaults
+	// left unset keep the value of the payload.
+	// +optional
+	Defaults *TriggerDefaults `json:"defaults,omitempty"`
+
+	// InterceptorTLS is how the serving certificate of the core
+	// interceptors is managed
+	// +optional
+	InterceptorTLS *InterceptorTLS `json:"interceptorTLS,omitempty"`
+}
+
+// InterceptorTLS is how the serving certificate of the core interceptors,
+// which the ClusterInterceptors trust through their caBundle, is managed.
+type InterceptorTLS struct {
+	// Managed has the operator generate the certificate into the Secret of
+	// the core interceptors, rotate it ahead of its expiry and set its CA
+	// on the ClusterInterceptors, rather than the interceptors do it
+	// themselves on start
+	// +optional
+	Managed bool `json:"managed,omitempty"`
+	// Validity of the certificates the operator generates, a year by
+	// default. They are rotated once less than a fifth of it is left.
+	// +optional
+	Validity *metav1.Duration `json:"validity,omitempty"`
+}
+
+// TriggerFeatureFlags are the feature flags of Tekton Triggers
+type TriggerFeatureFlags struct {
+	// EnableAPIFields is the stability level of the API fields accepted,
+	// stable or alpha
+	// +optional
+	EnableAPIFields string `json:"enableAPIFields,omitempty"`
+}
+
+// TriggerDefaults are the defaults of the EventListeners of Tekton Triggers
+type TriggerDefaults struct {
+	// DefaultServiceAccount of the triggers without service account
+	// +optional
+	DefaultServiceAccount string `json:"defaultServiceAccount,omitempty"`
+	// EventListenerEvents controls whether EventListeners send CloudEvents
+	// about the events they process
+	// +optional
+	EventListenerEvents *bool `json:"eventListenerEvents,omitempty"`
+	// EventListenerSecurityContext controls whether the pods of the
+	// EventListeners run with a restricted security context
+	// +optional
+	EventListenerSecurityContext *bool `json:"eventListenerSecurityContext,omitempty"`
+}
+
+// EventListenerDefaults configures the pods the triggers controller creates
+// for EventListeners.
+type EventListenerDefaults struct {
+	// NodeSelector of the EventListener pods
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations of the EventListener pods
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Labels of the EventListeners, which the triggers controller passes on
+	// to their deployments and pods. Labels the EventListeners set are kept.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // TektonTriggerStatus defines the observed state of TektonTrigger
@@ -65,6 +146,15 @@ type TektonTriggerStatus struct {
 	// The url links of the manifests, separated by comma
 	// +optional
 	Manifests []string `json:"manifests,omitempty"`
+
+	// The digest references the payload images were pinned to, by image
+	// +optional
+	PinnedImages map[string]string `json:"pinnedImages,omitempty"`
+
+	// The Tekton API versions the installed custom resource definitions
+	// serve, e.g. tekton.dev/v1
+	// +optional
+	APIVersions []string `json:"apiVersions,omitempty"`
 }
 
 // TektonTriggersList contains a list of TektonTrigger
@@ -28,10 +28,22 @@ type FakeOperatorV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeOperatorV1alpha1) ManualApprovalGates() v1alpha1.ManualApprovalGateInterface {
+	return &FakeManualApprovalGates{c}
+}
+
+func (c *FakeOperatorV1alpha1) OpenShiftPipelinesAsCodes() v1alpha1.OpenShiftPipelinesAsCodeInterface {
+	return &FakeOpenShiftPipelinesAsCodes{c}
+}
+
 func (c *FakeOperatorV1alpha1) TektonAddons() v1alpha1.TektonAddonInterface {
 	return &FakeTektonAddons{c}
 }
 
+func (c *FakeOperatorV1alpha1) TektonChains() v1alpha1.TektonChainInterface {
+	return &FakeTektonChains{c}
+}
+
 func (c *FakeOperatorV1alpha1) TektonConfigs() v1alpha1.TektonConfigInterface {
 	return &FakeTektonConfigs{c}
 }
@@ -40,10 +52,26 @@ func (c *FakeOperatorV1alpha1) TektonDashboards() v1alpha1.TektonDashboardInterf
 	return &FakeTektonDashboards{c}
 }
 
+func (c *FakeOperatorV1alpha1) TektonHubs() v1alpha1.TektonHubInterface {
+	return &FakeTektonHubs{c}
+}
+
+func (c *FakeOperatorV1alpha1) TektonInstallerSets() v1alpha1.TektonInstallerSetInterface {
+	return &FakeTektonInstallerSets{c}
+}
+
+func (c *FakeOperatorV1alpha1) TektonOverrides(namespace string) v1alpha1.TektonOverrideInterface {
+	return &FakeTektonOverrides{c, namespace}
+}
+
 func (c *FakeOperatorV1alpha1) TektonPipelines() v1alpha1.TektonPipelineInterface {
 	return &FakeTektonPipelines{c}
 }
 
+func (c *FakeOperatorV1alpha1) TektonResults() v1alpha1.TektonResultInterface {
+	return &FakeTektonResults{c}
+}
+
 func (c *FakeOperatorV1alpha1) TektonTriggers() v1alpha1.TektonTriggerInterface {
 	return &FakeTektonTriggers{c}
 }
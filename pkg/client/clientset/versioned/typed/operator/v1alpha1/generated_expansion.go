@@ -18,12 +18,26 @@ limitations under the License.
 
 package v1alpha1
 
+type ManualApprovalGateExpansion interface{}
+
+type OpenShiftPipelinesAsCodeExpansion interface{}
+
 type TektonAddonExpansion interface{}
 
+type TektonChainExpansion interface{}
+
 type TektonConfigExpansion interface{}
 
 type TektonDashboardExpansion interface{}
 
+type TektonHubExpansion interface{}
+
+type TektonInstallerSetExpansion interface{}
+
+type TektonOverrideExpansion interface{}
+
 type TektonPipelineExpansion interface{}
 
+type TektonResultExpansion interface{}
+
 type TektonTriggerExpansion interface{}
@@ -26,10 +26,17 @@ import (
 
 type OperatorV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	ManualApprovalGatesGetter
+	OpenShiftPipelinesAsCodesGetter
 	TektonAddonsGetter
+	TektonChainsGetter
 	TektonConfigsGetter
 	TektonDashboardsGetter
+	TektonHubsGetter
+	TektonInstallerSetsGetter
+	TektonOverridesGetter
 	TektonPipelinesGetter
+	TektonResultsGetter
 	TektonTriggersGetter
 }
 
@@ -38,10 +45,22 @@ type OperatorV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *OperatorV1alpha1Client) ManualApprovalGates() ManualApprovalGateInterface {
+	return newManualApprovalGates(c)
+}
+
+func (c *OperatorV1alpha1Client) OpenShiftPipelinesAsCodes() OpenShiftPipelinesAsCodeInterface {
+	return newOpenShiftPipelinesAsCodes(c)
+}
+
 func (c *OperatorV1alpha1Client) TektonAddons() TektonAddonInterface {
 	return newTektonAddons(c)
 }
 
+func (c *OperatorV1alpha1Client) TektonChains() TektonChainInterface {
+	return newTektonChains(c)
+}
+
 func (c *OperatorV1alpha1Client) TektonConfigs() TektonConfigInterface {
 	return newTektonConfigs(c)
 }
@@ -50,10 +69,26 @@ func (c *OperatorV1alpha1Client) TektonDashboards() TektonDashboardInterface {
 	return newTektonDashboards(c)
 }
 
+func (c *OperatorV1alpha1Client) TektonHubs() TektonHubInterface {
+	return newTektonHubs(c)
+}
+
+func (c *OperatorV1alpha1Client) TektonInstallerSets() TektonInstallerSetInterface {
+	return newTektonInstallerSets(c)
+}
+
+func (c *OperatorV1alpha1Client) TektonOverrides(namespace string) TektonOverrideInterface {
+	return newTektonOverrides(c, namespace)
+}
+
 func (c *OperatorV1alpha1Client) TektonPipelines() TektonPipelineInterface {
 	return newTektonPipelines(c)
 }
 
+func (c *OperatorV1alpha1Client) TektonResults() TektonResultInterface {
+	return newTektonResults(c)
+}
+
 func (c *OperatorV1alpha1Client) TektonTriggers() TektonTriggerInterface {
 	return newTektonTriggers(c)
 }
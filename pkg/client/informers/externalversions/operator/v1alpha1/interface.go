@@ -24,14 +24,28 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ManualApprovalGates returns a ManualApprovalGateInformer.
+	ManualApprovalGates() ManualApprovalGateInformer
+	// OpenShiftPipelinesAsCodes returns a OpenShiftPipelinesAsCodeInformer.
+	OpenShiftPipelinesAsCodes() OpenShiftPipelinesAsCodeInformer
 	// TektonAddons returns a TektonAddonInformer.
 	TektonAddons() TektonAddonInformer
+	// TektonChains returns a TektonChainInformer.
+	TektonChains() TektonChainInformer
 	// TektonConfigs returns a TektonConfigInformer.
 	TektonConfigs() TektonConfigInformer
 	// TektonDashboards returns a TektonDashboardInformer.
 	TektonDashboards() TektonDashboardInformer
+	// TektonHubs returns a TektonHubInformer.
+	TektonHubs() TektonHubInformer
+	// TektonInstallerSets returns a TektonInstallerSetInformer.
+	TektonInstallerSets() TektonInstallerSetInformer
+	// TektonOverrides returns a TektonOverrideInformer.
+	TektonOverrides() TektonOverrideInformer
 	// TektonPipelines returns a TektonPipelineInformer.
 	TektonPipelines() TektonPipelineInformer
+	// TektonResults returns a TektonResultInformer.
+	TektonResults() TektonResultInformer
 	// TektonTriggers returns a TektonTriggerInformer.
 	TektonTriggers() TektonTriggerInformer
 }
@@ -47,11 +61,26 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ManualApprovalGates returns a ManualApprovalGateInformer.
+func (v *version) ManualApprovalGates() ManualApprovalGateInformer {
+	return &manualApprovalGateInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// OpenShiftPipelinesAsCodes returns a OpenShiftPipelinesAsCodeInformer.
+func (v *version) OpenShiftPipelinesAsCodes() OpenShiftPipelinesAsCodeInformer {
+	return &openShiftPipelinesAsCodeInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // TektonAddons returns a TektonAddonInformer.
 func (v *version) TektonAddons() TektonAddonInformer {
 	return &tektonAddonInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// TektonChains returns a TektonChainInformer.
+func (v *version) TektonChains() TektonChainInformer {
+	return &tektonChainInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // TektonConfigs returns a TektonConfigInformer.
 func (v *version) TektonConfigs() TektonConfigInformer {
 	return &tektonConfigInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -62,11 +91,31 @@ func (v *version) TektonDashboards() TektonDashboardInformer {
 	return &tektonDashboardInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// TektonHubs returns a TektonHubInformer.
+func (v *version) TektonHubs() TektonHubInformer {
+	return &tektonHubInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// TektonInstallerSets returns a TektonInstallerSetInformer.
+func (v *version) TektonInstallerSets() TektonInstallerSetInformer {
+	return &tektonInstallerSetInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// TektonOverrides returns a TektonOverrideInformer.
+func (v *version) TektonOverrides() TektonOverrideInformer {
+	return &tektonOverrideInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TektonPipelines returns a TektonPipelineInformer.
 func (v *version) TektonPipelines() TektonPipelineInformer {
 	return &tektonPipelineInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// TektonResults returns a TektonResultInformer.
+func (v *version) TektonResults() TektonResultInformer {
+	return &tektonResultInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // TektonTriggers returns a TektonTriggerInformer.
 func (v *version) TektonTriggers() TektonTriggerInformer {
 	return &tektonTriggerInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -18,10 +18,22 @@ limitations under the License.
 
 package v1alpha1
 
+// ManualApprovalGateListerExpansion allows custom methods to be added to
+// ManualApprovalGateLister.
+type ManualApprovalGateListerExpansion interface{}
+
+// OpenShiftPipelinesAsCodeListerExpansion allows custom methods to be added to
+// OpenShiftPipelinesAsCodeLister.
+type OpenShiftPipelinesAsCodeListerExpansion interface{}
+
 // TektonAddonListerExpansion allows custom methods to be added to
 // TektonAddonLister.
 type TektonAddonListerExpansion interface{}
 
+// TektonChainListerExpansion allows custom methods to be added to
+// TektonChainLister.
+type TektonChainListerExpansion interface{}
+
 // TektonConfigListerExpansion allows custom methods to be added to
 // TektonConfigLister.
 type TektonConfigListerExpansion interface{}
@@ -30,10 +42,30 @@ type TektonConfigListerExpansion interface{}
 // TektonDashboardLister.
 type TektonDashboardListerExpansion interface{}
 
+// TektonHubListerExpansion allows custom methods to be added to
+// TektonHubLister.
+type TektonHubListerExpansion interface{}
+
+// TektonInstallerSetListerExpansion allows custom methods to be added to
+// TektonInstallerSetLister.
+type TektonInstallerSetListerExpansion interface{}
+
+// TektonOverrideListerExpansion allows custom methods to be added to
+// TektonOverrideLister.
+type TektonOverrideListerExpansion interface{}
+
+// TektonOverrideNamespaceListerExpansion allows custom methods to be added to
+// TektonOverrideNamespaceLister.
+type TektonOverrideNamespaceListerExpansion interface{}
+
 // TektonPipelineListerExpansion allows custom methods to be added to
 // TektonPipelineLister.
 type TektonPipelineListerExpansion interface{}
 
+// TektonResultListerExpansion allows custom methods to be added to
+// TektonResultLister.
+type TektonResultListerExpansion interface{}
+
 // TektonTriggerListerExpansion allows custom methods to be added to
 // TektonTriggerLister.
 type TektonTriggerListerExpansion interface{}
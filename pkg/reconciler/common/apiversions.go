@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// RecordAPIVersions is a Stage recording in the status the API versions the
+// CRDs of the manifest installed serve, for CI tooling to gate on the APIs
+// available without probing the discovery.
+func RecordAPIVersions(_ context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	instance.GetStatus().SetAPIVersions(ServedAPIVersions(manifest.Filter(mf.CRDs).Resources()))
+	return nil
+}
+
+// ServedAPIVersions returns the sorted API versions the CRDs serve, e.g.
+// tekton.dev/v1 and tekton.dev/v1beta1, of the v1 or v1beta1 shape.
+func ServedAPIVersions(crds []unstructured.Unstructured) []string {
+	served := sets.NewString()
+	for _, crd := range crds {
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		for _, v := range versions {
+			if v, ok := v.(map[string]interface{}); ok {
+				if name, _ := v["name"].(string); name != "" && v["served"] == true {
+					served.Insert(group + "/" + name)
+				}
+			}
+		}
+		if single, _, _ := unstructured.NestedString(crd.Object, "spec", "version"); len(versions) == 0 && single != "" {
+			served.Insert(group + "/" + single)
+		}
+	}
+	if served.Len() == 0 {
+		return nil
+	}
+	return served.List()
+}
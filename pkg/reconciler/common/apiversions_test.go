@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRecordAPIVersions(t *testing.T) {
+	v1beta1CRD := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1beta1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "eventlisteners.triggers.tekton.dev"},
+		"spec": map[string]interface{}{
+			"group":   "triggers.tekton.dev",
+			"version": "v1alpha1",
+		},
+	}}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
+		interceptorCRD("v1beta1", true),
+		interceptorCRD("v1alpha1", false),
+		v1beta1CRD,
+		namespacedResource("apps/v1", "Deployment", "tekton-pipelines", "controller"),
+	}))
+	util.AssertNoError(t, err)
+	tp := &v1alpha1.TektonPipeline{}
+	util.AssertNoError(t, RecordAPIVersions(context.Background(), &manifest, tp))
+	util.AssertDeepEqual(t, tp.Status.APIVersions, []string{"triggers.tekton.dev/v1alpha1", "triggers.tekton.dev/v1beta1"})
+
+	// payloads without CRDs serve none
+	empty, _ := mf.ManifestFrom(mf.Slice{})
+	util.AssertNoError(t, RecordAPIVersions(context.Background(), &empty, tp))
+	util.AssertEqual(t, len(tp.Status.APIVersions), 0)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// knownFlags are the flags spec.options.deployments[].args may set or
+// remove, by deployment. Flags of images are left to the image overrides.
+var knownFlags = map[string]sets.String{
+	"tekton-pipelines-controller": sets.NewString(
+		"namespace", "threads-per-controller", "kube-api-qps", "kube-api-burst", "disable-ha"),
+	"tekton-pipelines-webhook": sets.NewString("disable-ha"),
+	"tekton-triggers-controller": sets.NewString(
+		"el-port", "el-readtimeout", "el-writetimeout", "el-idletimeout", "el-timeouthandler",
+		"period-seconds", "failure-threshold", "stderrthreshold"),
+	"tekton-triggers-webhook": sets.NewString("disable-ha"),
+	"tekton-dashboard": sets.NewString(
+		"namespace", "pipelines-namespace", "triggers-namespace", "read-only", "logout-url",
+		"log-level", "log-format", "stream-logs", "external-logs"),
+}
+
+// DeploymentArgs adds and removes flags of the first container of the
+// deployments named in options. Adding a flag replaces its value in the
+// payload. It fails on deployments without known flags and on flags which
+// aren't known for the deployment.
+func DeploymentArgs(options []v1alpha1.DeploymentOptions) mf.Transformer {
+	byName := map[string]v1alpha1.ContainerArgs{}
+	for _, o := range options {
+		byName[o.Name] = o.Args
+	}
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Deployment" {
+			return nil
+		}
+		args, ok := byName[u.GetName()]
+		if !ok || (len(args.Add) == 0 && len(args.Remove) == 0) {
+			return nil
+		}
+		if err := validateArgs(u.GetName(), args); err != nil {
+			return err
+		}
+
+		containers, found, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "containers")
+		if err != nil || !found || len(containers) == 0 {
+			return err
+		}
+		container := containers[0].(map[string]interface{})
+		current, _, err := unstructured.NestedStringSlice(container, "args")
+		if err != nil {
+			return err
+		}
+		for _, flag := range args.Remove {
+			current = removeFlag(current, flagName(flag))
+		}
+		for _, arg := range args.Add {
+			current = append(removeFlag(current, flagName(arg)), arg)
+		}
+		if err := unstructured.SetNestedStringSlice(container, current, "args"); err != nil {
+			return err
+		}
+		return unstructured.SetNestedSlice(u.Object, containers, "spec", "template", "spec", "containers")
+	}
+}
+
+func validateArgs(deployment string, args v1alpha1.ContainerArgs) error {
+	known, ok := knownFlags[deployment]
+	if !ok {
+		return fmt.Errorf("deployment %s has no flags the operator can change", deployment)
+	}
+	for _, arg := range append(append([]string{}, args.Add...), args.Remove...) {
+		if name := flagName(arg); !strings.HasPrefix(arg, "-") || !known.Has(name) {
+			return fmt.Errorf("unknown flag %q of deployment %s, expected one of %s",
+				arg, deployment, strings.Join(known.List(), ", "))
+		}
+	}
+	return nil
+}
+
+// flagName returns the name of the flag of an argument such as -name,
+// --name or -name=value.
+func flagName(arg string) string {
+	return strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0]
+}
+
+// removeFlag removes the flag from args, along with its value when it is
+// passed as the next argument.
+func removeFlag(args []string, name string) []string {
+	kept := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || flagName(arg) != name {
+			kept = append(kept, arg)
+			continue
+		}
+		if !strings.Contains(arg, "=") && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			i++
+		}
+	}
+	return kept
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDeploymentArgs(t *testing.T) {
+	controller := util.MakeUnstructured(t, util.MakeDeployment("tekton-pipelines-controller", corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:  "tekton-pipelines-controller",
+			Image: "gcr.io/controller:v1",
+			Args:  []string{"-git-image", "gcr.io/git:v1", "-namespace", "default", "-disable-ha=false"},
+		}},
+	}))
+	transformer := DeploymentArgs([]v1alpha1.DeploymentOptions{{
+		Name: "tekton-pipelines-controller",
+		Args: v1alpha1.ContainerArgs{
+			Add:    []string{"-namespace=ci", "-threads-per-controller=4"},
+			Remove: []string{"--disable-ha"},
+		},
+	}})
+	util.AssertNoError(t, transformer(&controller))
+	containers, _, _ := unstructured.NestedSlice(controller.Object, "spec", "template", "spec", "containers")
+	util.AssertDeepEqual(t, containers[0].(map[string]interface{})["args"], []interface{}{"-git-image", "gcr.io/git:v1", "-namespace=ci", "-threads-per-controller=4"})
+
+	// other deployments are left alone
+	dashboard := util.MakeUnstructured(t, util.MakeDeployment("tekton-dashboard", corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "dashboard", Image: "gcr.io/dashboard:v1"}},
+	}))
+	unchanged := dashboard.DeepCopy()
+	util.AssertNoError(t, transformer(&dashboard))
+	util.AssertDeepEqual(t, &dashboard, unchanged)
+
+	tests := []struct {
+		name    string
+		options v1alpha1.DeploymentOptions
+		err     string
+	}{{
+		name: "unknown flag",
+		options: v1alpha1.DeploymentOptions{
+			Name: "tekton-pipelines-controller",
+			Args: v1alpha1.ContainerArgs{Add: []string{"-git-image=gcr.io/git:v2"}},
+		},
+		err: `unknown flag "-git-image=gcr.io/git:v2" of deployment tekton-pipelines-controller, expected one of disable-ha, kube-api-burst, kube-api-qps, namespace, threads-per-controller`,
+	}, {
+		name: "not a flag",
+		options: v1alpha1.DeploymentOptions{
+			Name: "tekton-pipelines-controller",
+			Args: v1alpha1.ContainerArgs{Remove: []string{"namespace"}},
+		},
+		err: `unknown flag "namespace" of deployment tekton-pipelines-controller, expected one of disable-ha, kube-api-burst, kube-api-qps, namespace, threads-per-controller`,
+	}, {
+		name: "unknown deployment",
+		options: v1alpha1.DeploymentOptions{
+			Name: "tekton-pipelines-remote-resolvers",
+			Args: v1alpha1.ContainerArgs{Add: []string{"-namespace=ci"}},
+		},
+		err: "deployment tekton-pipelines-remote-resolvers has no flags the operator can change",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deployment := util.MakeUnstructured(t, util.MakeDeployment(test.options.Name, corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "controller", Image: "gcr.io/controller:v1"}},
+			}))
+			err := DeploymentArgs([]v1alpha1.DeploymentOptions{test.options})(&deployment)
+			if err == nil || err.Error() != test.err {
+				t.Errorf("DeploymentArgs() = %v, wanted %s", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const defaultTargetCPUUtilization = 80
+
+// HorizontalPodAutoscalers is a Stage configuring a HorizontalPodAutoscaler
+// for the webhook and dashboard deployments when spec.config.autoscaling is
+// set, and dropping their replicas for the autoscaler to own them. The
+// autoscalers the payload ships, e.g. the one of tekton-pipelines-webhook,
+// are configured in place, the others are generated. When it is unset, the
+// autoscalers the operator generated before are deleted.
+func HorizontalPodAutoscalers(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	autoscaling := instance.GetSpec().GetConfig().Autoscaling
+	shipped := autoscalerTargets(*manifest)
+	if autoscaling == nil {
+		return deleteGeneratedAutoscalers(manifest, shipped)
+	}
+	m, err := manifest.Transform(func(u *unstructured.Unstructured) error {
+		switch {
+		case u.GetKind() == "Deployment" && autoscaled(u.GetName()):
+			unstructured.RemoveNestedField(u.Object, "spec", "replicas")
+		case u.GetKind() == "HorizontalPodAutoscaler":
+			kind, _, _ := unstructured.NestedString(u.Object, "spec", "scaleTargetRef", "kind")
+			name, _, _ := unstructured.NestedString(u.Object, "spec", "scaleTargetRef", "name")
+			if kind == "Deployment" && autoscaled(name) {
+				return configureAutoscaler(u, autoscaling)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	*manifest = m
+	return appendGenerated(ctx, manifest, instance, func(deployment *appsv1.Deployment) runtime.Object {
+		if !autoscaled(deployment.Name) || shipped[autoscalerTarget(deployment.Namespace, "Deployment", deployment.Name)] {
+			return nil
+		}
+		return horizontalPodAutoscaler(deployment, autoscaling)
+	})
+}
+
+// autoscalerTargets returns the workloads the HorizontalPodAutoscalers of
+// the manifest scale, see autoscalerTarget.
+func autoscalerTargets(manifest mf.Manifest) map[string]bool {
+	targets := map[string]bool{}
+	for _, hpa := range manifest.Filter(mf.ByKind("HorizontalPodAutoscaler")).Resources() {
+		kind, _, _ := unstructured.NestedString(hpa.Object, "spec", "scaleTargetRef", "kind")
+		name, _, _ := unstructured.NestedString(hpa.Object, "spec", "scaleTargetRef", "name")
+		targets[autoscalerTarget(hpa.GetNamespace(), kind, name)] = true
+	}
+	return targets
+}
+
+// autoscalerTarget returns the key of the autoscaled workload
+func autoscalerTarget(namespace, kind, name string) string {
+	return namespace + "/" + kind + "/" + name
+}
+
+// configureAutoscaler sets the replicas and the CPU utilization target of
+// the HorizontalPodAutoscaler of any API version.
+func configureAutoscaler(u *unstructured.Unstructured, autoscaling *v1alpha1.Autoscaling) error {
+	minReplicas := int64(1)
+	if autoscaling.MinReplicas != nil {
+		minReplicas = int64(*autoscaling.MinReplicas)
+	}
+	target := int64(defaultTargetCPUUtilization)
+	if autoscaling.TargetCPUUtilizationPercentage != nil {
+		target = int64(*autoscaling.TargetCPUUtilizationPercentage)
+	}
+	if err := unstructured.SetNestedField(u.Object, minReplicas, "spec", "minReplicas"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(u.Object, int64(autoscaling.MaxReplicas), "spec", "maxReplicas"); err != nil {
+		return err
+	}
+	if u.GroupVersionKind().Version == "v1" {
+		return unstructured.SetNestedField(u.Object, target, "spec", "targetCPUUtilizationPercentage")
+	}
+	metrics, _, err := unstructured.NestedSlice(u.Object, "spec", "metrics")
+	if err != nil {
+		return err
+	}
+	cpu := map[string]interface{}{"name": "cpu"}
+	if u.GroupVersionKind().Version == "v2beta1" {
+		cpu["targetAverageUtilization"] = target
+	} else {
+		cpu["target"] = map[string]interface{}{"type": "Utilization", "averageUtilization": target}
+	}
+	var kept []interface{}
+	for _, metric := range metrics {
+		if name, _, _ := unstructured.NestedString(metric.(map[string]interface{}), "resource", "name"); name != "cpu" {
+			kept = append(kept, metric)
+		}
+	}
+	kept = append(kept, map[string]interface{}{"type": "Resource", "resource": cpu})
+	return unstructured.SetNestedSlice(u.Object, kept, "spec", "metrics")
+}
+
+// autoscaled returns true for the deployments which scale horizontally
+func autoscaled(deployment string) bool {
+	return strings.Contains(deployment, "webhook") || strings.Contains(deployment, "dashboard")
+}
+
+func horizontalPodAutoscaler(deployment *appsv1.Deployment, autoscaling *v1alpha1.Autoscaling) *autoscalingv1.HorizontalPodAutoscaler {
+	target := int32(defaultTargetCPUUtilization)
+	if autoscaling.TargetCPUUtilizationPercentage != nil {
+		target = *autoscaling.TargetCPUUtilizationPercentage
+	}
+	return &autoscalingv1.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{APIVersion: "autoscaling/v1", Kind: "HorizontalPodAutoscaler"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.Name,
+			Namespace: deployment.Namespace,
+			Labels:    deployment.Labels,
+		},
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       deployment.Name,
+			},
+			MinReplicas:                    autoscaling.MinReplicas,
+			MaxReplicas:                    autoscaling.MaxReplicas,
+			TargetCPUUtilizationPercentage: &target,
+		},
+	}
+}
+
+// deleteGeneratedAutoscalers deletes the HorizontalPodAutoscalers the
+// operator generated for the deployments of the manifest, but for the
+// deployments the payload ships an autoscaler of the same name for.
+func deleteGeneratedAutoscalers(manifest *mf.Manifest, shipped map[string]bool) error {
+	for _, u := range manifest.Filter(mf.ByKind("Deployment")).Resources() {
+		if !autoscaled(u.GetName()) || shipped[autoscalerTarget(u.GetNamespace(), "Deployment", u.GetName())] {
+			continue
+		}
+		hpa := &unstructured.Unstructured{}
+		hpa.SetAPIVersion("autoscaling/v1")
+		hpa.SetKind("HorizontalPodAutoscaler")
+		hpa.SetNamespace(u.GetNamespace())
+		hpa.SetName(u.GetName())
+		live, err := manifest.Client.Get(hpa)
+		if apierrors.IsNotFound(err) || (err == nil && live == nil) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if live.GetLabels()[LabelGenerated] != "true" {
+			continue
+		}
+		if err := manifest.Client.Delete(live); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete HorizontalPodAutoscaler %s/%s: %w", live.GetNamespace(), live.GetName(), err)
+		}
+	}
+	return nil
+}
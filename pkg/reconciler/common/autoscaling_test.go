@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// generatedClient holds a generated resource of every name
+type generatedClient struct {
+	*util.FakeClient
+}
+
+func (c *generatedClient) Get(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	live := obj.DeepCopy()
+	live.SetLabels(map[string]string{LabelGenerated: "true"})
+	return live, nil
+}
+
+func TestHorizontalPodAutoscalers(t *testing.T) {
+	var resources []unstructured.Unstructured
+	for _, name := range []string{"tekton-pipelines-controller", "tekton-pipelines-webhook"} {
+		resources = append(resources, util.MakeUnstructured(t, util.MakeDeployment(name, corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main", Image: "gcr.io/main:v1"}},
+		})))
+		util.AssertNoError(t, unstructured.SetNestedField(resources[len(resources)-1].Object, int64(2), "spec", "replicas"))
+	}
+	client := &generatedClient{util.NewFakeClient()}
+	manifest, err := mf.ManifestFrom(mf.Slice(resources), mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	// generated autoscalers are deleted while autoscaling is off
+	instance := &v1alpha1.TektonPipeline{}
+	util.AssertNoError(t, HorizontalPodAutoscalers(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(manifest.Resources()), 2)
+	util.AssertEqual(t, len(client.Deletes), 1)
+	util.AssertEqual(t, client.Deletes[0].GetName(), "tekton-pipelines-webhook")
+
+	instance.Spec.Config.Autoscaling = &v1alpha1.Autoscaling{MaxReplicas: 5}
+	util.AssertNoError(t, HorizontalPodAutoscalers(context.Background(), &manifest, instance))
+	hpas := manifest.Filter(mf.ByKind("HorizontalPodAutoscaler")).Resources()
+	util.AssertEqual(t, len(hpas), 1)
+	hpa := &autoscalingv1.HorizontalPodAutoscaler{}
+	util.AssertNoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(hpas[0].Object, hpa))
+	util.AssertEqual(t, hpa.Spec.ScaleTargetRef.Name, "tekton-pipelines-webhook")
+	util.AssertEqual(t, hpa.Spec.MaxReplicas, int32(5))
+	util.AssertEqual(t, *hpa.Spec.TargetCPUUtilizationPercentage, int32(80))
+	util.AssertEqual(t, hpa.Labels[LabelGenerated], "true")
+
+	for _, u := range manifest.Filter(mf.ByKind("Deployment")).Resources() {
+		_, found, _ := unstructured.NestedInt64(u.Object, "spec", "replicas")
+		util.AssertEqual(t, found, u.GetName() == "tekton-pipelines-controller")
+	}
+}
+
+func TestHorizontalPodAutoscalersShipped(t *testing.T) {
+	webhook := util.MakeUnstructured(t, util.MakeDeployment("tekton-pipelines-webhook", corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "main", Image: "gcr.io/main:v1"}},
+	}))
+	shipped := namespacedResource("autoscaling/v2beta1", "HorizontalPodAutoscaler", "", "tekton-pipelines-webhook")
+	shipped.Object["spec"] = map[string]interface{}{
+		"minReplicas": int64(1),
+		"maxReplicas": int64(5),
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": "apps/v1", "kind": "Deployment", "name": "tekton-pipelines-webhook",
+		},
+		"metrics": []interface{}{map[string]interface{}{
+			"type":     "Resource",
+			"resource": map[string]interface{}{"name": "cpu", "targetAverageUtilization": int64(100)},
+		}},
+	}
+	client := &generatedClient{util.NewFakeClient()}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{webhook, shipped}), mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	// the autoscaler of the payload is never deleted
+	instance := &v1alpha1.TektonPipeline{}
+	util.AssertNoError(t, HorizontalPodAutoscalers(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(client.Deletes), 0)
+
+	// but configured rather than doubled by a generated one
+	minReplicas := int32(2)
+	instance.Spec.Config.Autoscaling = &v1alpha1.Autoscaling{MinReplicas: &minReplicas, MaxReplicas: 8}
+	util.AssertNoError(t, HorizontalPodAutoscalers(context.Background(), &manifest, instance))
+	hpas := manifest.Filter(mf.ByKind("HorizontalPodAutoscaler")).Resources()
+	util.AssertEqual(t, len(hpas), 1)
+	util.AssertEqual(t, hpas[0].GetLabels()[LabelGenerated], "")
+	spec := hpas[0].Object["spec"].(map[string]interface{})
+	util.AssertEqual(t, spec["minReplicas"], int64(2))
+	util.AssertEqual(t, spec["maxReplicas"], int64(8))
+	util.AssertDeepEqual(t, spec["metrics"], []interface{}{map[string]interface{}{
+		"type":     "Resource",
+		"resource": map[string]interface{}{"name": "cpu", "targetAverageUtilization": int64(80)},
+	}})
+}
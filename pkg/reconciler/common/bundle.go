@@ -0,0 +1,186 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// AnnotationDebugBundle requests a debug bundle of the annotated
+	// component. The bundle is collected once per value of the annotation.
+	AnnotationDebugBundle = "operator.tekton.dev/debug-bundle"
+
+	// OperatorLabelSelector selects the pods of the operator
+	OperatorLabelSelector = "name=tekton-operator"
+
+	// debugBundleEntryLimit keeps the bundle below the size limit of a
+	// ConfigMap
+	debugBundleEntryLimit = 200 * 1024
+	operatorLogLines      = int64(500)
+)
+
+// DebugBundle returns a Stage which, when the component is annotated with
+// AnnotationDebugBundle, collects its status, its rendered manifest, the
+// state of its deployments and the operator logs into the ConfigMap
+// <name>-debug-bundle of the operator namespace, to be attached to bug
+// reports. Failures to collect the bundle are logged and don't affect the
+// install.
+func DebugBundle(kubeClient kubernetes.Interface) Stage {
+	return func(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+		request, ok := instance.GetAnnotations()[AnnotationDebugBundle]
+		if !ok {
+			return nil
+		}
+		if err := writeDebugBundle(ctx, kubeClient, *manifest, instance, request); err != nil {
+			logging.FromContext(ctx).Error("Failed to write the debug bundle", err)
+		}
+		return nil
+	}
+}
+
+// DebugBundleName returns the name of the ConfigMap holding the debug
+// bundle of the component.
+func DebugBundleName(instance v1alpha1.TektonComponent) string {
+	return instance.GetName() + "-debug-bundle"
+}
+
+func writeDebugBundle(ctx context.Context, kubeClient kubernetes.Interface, manifest mf.Manifest, instance v1alpha1.TektonComponent, request string) error {
+	configMaps := kubeClient.CoreV1().ConfigMaps(system.Namespace())
+	existing, err := configMaps.Get(ctx, DebugBundleName(instance), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && existing.Annotations[AnnotationDebugBundle] == request {
+		return nil
+	}
+
+	logging.FromContext(ctx).Infow("Collecting debug bundle", "request", request)
+	data := debugBundleData(manifest, instance)
+	data["operator.log"] = truncateEntry(operatorLogs(ctx, kubeClient), true)
+	bundle := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        DebugBundleName(instance),
+			Namespace:   system.Namespace(),
+			Annotations: map[string]string{AnnotationDebugBundle: request},
+		},
+		Data: data,
+	}
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, bundle, metav1.CreateOptions{})
+		return err
+	}
+	bundle.ResourceVersion = existing.ResourceVersion
+	_, err = configMaps.Update(ctx, bundle, metav1.UpdateOptions{})
+	return err
+}
+
+// debugBundleData collects the status of the component, its rendered
+// manifest, with the data of Secrets redacted, and the live status of the
+// deployments of the manifest.
+func debugBundleData(manifest mf.Manifest, instance v1alpha1.TektonComponent) map[string]string {
+	data := map[string]string{}
+
+	status, err := json.MarshalIndent(instance.GetStatus(), "", "  ")
+	if err != nil {
+		status = []byte(err.Error())
+	}
+	data["status.json"] = string(status)
+
+	var rendered []interface{}
+	for _, u := range manifest.Resources() {
+		if u.GetKind() == "Secret" {
+			for _, field := range []string{"data", "stringData"} {
+				if _, ok := u.Object[field]; ok {
+					u.Object[field] = "REDACTED"
+				}
+			}
+		}
+		rendered = append(rendered, u.Object)
+	}
+	out, err := json.MarshalIndent(rendered, "", "  ")
+	if err != nil {
+		out = []byte(err.Error())
+	}
+	data["manifest.json"] = truncateEntry(string(out), false)
+
+	deployments := map[string]interface{}{}
+	for _, u := range manifest.Filter(mf.ByKind("Deployment")).Resources() {
+		key := u.GetNamespace() + "/" + u.GetName()
+		live, err := manifest.Client.Get(&u)
+		if err != nil {
+			deployments[key] = err.Error()
+			continue
+		}
+		deployments[key] = live.Object["status"]
+	}
+	out, err = json.MarshalIndent(deployments, "", "  ")
+	if err != nil {
+		out = []byte(err.Error())
+	}
+	data["deployments.json"] = truncateEntry(string(out), false)
+	return data
+}
+
+// operatorLogs returns the latest lines of the logs of the operator pods.
+func operatorLogs(ctx context.Context, kubeClient kubernetes.Interface) string {
+	pods, err := kubeClient.CoreV1().Pods(system.Namespace()).List(ctx, metav1.ListOptions{LabelSelector: OperatorLabelSelector})
+	if err != nil {
+		return err.Error()
+	}
+	var logs strings.Builder
+	lines := operatorLogLines
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			fmt.Fprintf(&logs, "==> %s/%s <==\n", pod.Name, container.Name)
+			out, err := kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+				Container: container.Name,
+				TailLines: &lines,
+			}).DoRaw(ctx)
+			if err != nil {
+				out = []byte(err.Error() + "\n")
+			}
+			logs.Write(out)
+		}
+	}
+	return logs.String()
+}
+
+// truncateEntry cuts an entry of the bundle to debugBundleEntryLimit,
+// keeping its end if keepTail is true and its beginning otherwise.
+func truncateEntry(entry string, keepTail bool) string {
+	const marker = "... truncated ...\n"
+	if len(entry) <= debugBundleEntryLimit {
+		return entry
+	}
+	if keepTail {
+		return marker + entry[len(entry)-debugBundleEntryLimit:]
+	}
+	return entry[:debugBundleEntryLimit] + marker
+}
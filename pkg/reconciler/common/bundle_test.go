@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDebugBundleData(t *testing.T) {
+	deployment := util.MakeUnstructured(t, util.MakeDeployment("controller", corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "controller", Image: "gcr.io/controller:v1"}},
+	}))
+	deployment.SetNamespace("tekton-pipelines")
+	secret := util.MakeUnstructured(t, &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-certs", Namespace: "tekton-pipelines"},
+		StringData: map[string]string{"token": "s3cr3t"},
+	})
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment, secret}))
+	util.AssertNoError(t, err)
+	manifest.Client = &util.FakeClient{Err: errors.New("deployment not found")}
+
+	instance := &v1alpha1.TektonPipeline{}
+	instance.Status.InitializeConditions()
+	instance.Status.MarkInstallFailed("broken")
+
+	data := debugBundleData(manifest, instance)
+	if !strings.Contains(data["status.json"], "Install failed with message: broken") {
+		t.Errorf("status.json = %s, wanted the conditions of the component", data["status.json"])
+	}
+	if !strings.Contains(data["manifest.json"], "gcr.io/controller:v1") {
+		t.Errorf("manifest.json = %s, wanted the rendered deployment", data["manifest.json"])
+	}
+	if strings.Contains(data["manifest.json"], "s3cr3t") {
+		t.Errorf("manifest.json = %s, wanted the secret data redacted", data["manifest.json"])
+	}
+	util.AssertEqual(t, data["deployments.json"], "{\n  \"tekton-pipelines/controller\": \"deployment not found\"\n}")
+}
+
+func TestTruncateEntry(t *testing.T) {
+	util.AssertEqual(t, truncateEntry("short", true), "short")
+
+	entry := strings.Repeat("a", debugBundleEntryLimit) + "end"
+	tail := truncateEntry(entry, true)
+	util.AssertEqual(t, strings.HasSuffix(tail, "end"), true)
+	util.AssertEqual(t, strings.HasPrefix(tail, "... truncated ..."), true)
+	head := truncateEntry(entry, false)
+	util.AssertEqual(t, strings.HasSuffix(head, "... truncated ...\n"), true)
+	util.AssertEqual(t, len(head), debugBundleEntryLimit+len("... truncated ...\n"))
+}
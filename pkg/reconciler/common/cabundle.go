@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// CABundleVolume is the volume of the ConfigMap of spec.config.caBundleConfigMap
+	CABundleVolume = "operator-ca-bundle"
+	// CABundleMountPath is where the CA bundle is mounted and added to SSL_CERT_DIR
+	CABundleMountPath = "/etc/operator-ca-bundle"
+
+	sslCertDirEnv     = "SSL_CERT_DIR"
+	defaultSSLCertDir = "/etc/ssl/certs"
+)
+
+// CABundle returns a transformer mounting the given ConfigMap of the target
+// namespace into every container of the payload deployments and adding it
+// to SSL_CERT_DIR, so that the certificates it holds are trusted next to the
+// system ones. It is a no-op when configMap is empty.
+func CABundle(configMap string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if configMap == "" || u.GetKind() != "Deployment" {
+			return nil
+		}
+		template, found, err := unstructured.NestedMap(u.Object, "spec", "template", "spec")
+		if err != nil || !found {
+			return err
+		}
+		podSpec := &corev1.PodSpec{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template, podSpec); err != nil {
+			return err
+		}
+
+		podSpec.Volumes = append(removeVolume(podSpec.Volumes, CABundleVolume), corev1.Volume{
+			Name: CABundleVolume,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: configMap},
+				},
+			},
+		})
+		for _, containers := range [][]corev1.Container{podSpec.Containers, podSpec.InitContainers} {
+			for i := range containers {
+				trustCABundle(&containers[i])
+			}
+		}
+
+		unstrObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(podSpec)
+		if err != nil {
+			return err
+		}
+		return unstructured.SetNestedMap(u.Object, unstrObj, "spec", "template", "spec")
+	}
+}
+
+// trustCABundle mounts the CA bundle into the container and appends it to
+// its SSL_CERT_DIR, keeping the directories already trusted.
+func trustCABundle(container *corev1.Container) {
+	mounted := false
+	for _, mount := range container.VolumeMounts {
+		mounted = mounted || mount.Name == CABundleVolume
+	}
+	if !mounted {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      CABundleVolume,
+			MountPath: CABundleMountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	for i, env := range container.Env {
+		if env.Name != sslCertDirEnv {
+			continue
+		}
+		if env.ValueFrom == nil && !strings.Contains(env.Value, CABundleMountPath) {
+			container.Env[i].Value = env.Value + ":" + CABundleMountPath
+		}
+		return
+	}
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  sslCertDirEnv,
+		Value: defaultSSLCertDir + ":" + CABundleMountPath,
+	})
+}
+
+func removeVolume(volumes []corev1.Volume, name string) []corev1.Volume {
+	result := volumes[:0]
+	for _, volume := range volumes {
+		if volume.Name != name {
+			result = append(result, volume)
+		}
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestCABundle(t *testing.T) {
+	deployment := util.MakeUnstructured(t, util.MakeDeployment("controller", corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:  "controller",
+			Image: "gcr.io/controller:v1",
+			Env:   []corev1.EnvVar{{Name: "SSL_CERT_DIR", Value: "/etc/ssl/certs"}},
+		}, {
+			Name:  "sidecar",
+			Image: "gcr.io/sidecar:v1",
+		}},
+	}))
+	unchanged := deployment.DeepCopy()
+	util.AssertNoError(t, CABundle("")(&deployment))
+	util.AssertDeepEqual(t, &deployment, unchanged)
+
+	// applying twice doesn't mount the bundle twice
+	util.AssertNoError(t, CABundle("corp-ca")(&deployment))
+	util.AssertNoError(t, CABundle("corp-ca")(&deployment))
+
+	result := &appsv1.Deployment{}
+	util.AssertNoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(deployment.Object, result))
+	podSpec := result.Spec.Template.Spec
+	util.AssertDeepEqual(t, podSpec.Volumes, []corev1.Volume{{
+		Name: CABundleVolume,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "corp-ca"}},
+		},
+	}})
+	mounts := []corev1.VolumeMount{{Name: CABundleVolume, MountPath: CABundleMountPath, ReadOnly: true}}
+	util.AssertDeepEqual(t, podSpec.Containers[0].VolumeMounts, mounts)
+	util.AssertDeepEqual(t, podSpec.Containers[0].Env, []corev1.EnvVar{{Name: "SSL_CERT_DIR", Value: "/etc/ssl/certs:/etc/operator-ca-bundle"}})
+	util.AssertDeepEqual(t, podSpec.Containers[1].VolumeMounts, mounts)
+	util.AssertDeepEqual(t, podSpec.Containers[1].Env, []corev1.EnvVar{{Name: "SSL_CERT_DIR", Value: "/etc/ssl/certs:/etc/operator-ca-bundle"}})
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// kindOrder is the order of the kinds of the resources of the installer
+// sets, the resources others depend on first, e.g. namespaces, custom
+// resource definitions and service accounts. Kinds missing, e.g. custom
+// resources, follow, and webhook configurations are last not to gate the
+// resources before on webhooks which aren't running yet.
+var kindOrder = []string{
+	"Namespace",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodSecurityPolicy",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"CustomResourceDefinition",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"Ingress",
+	"APIService",
+}
+
+// kindRank returns the rank of the kind in kindOrder, the kinds missing
+// ranking after the ones listed, and webhook configurations last
+func kindRank(kind string) int {
+	switch kind {
+	case "MutatingWebhookConfiguration", "ValidatingWebhookConfiguration":
+		return len(kindOrder) + 1
+	}
+	for i, k := range kindOrder {
+		if k == kind {
+			return i
+		}
+	}
+	return len(kindOrder)
+}
+
+// CanonicalResources returns the resources in a stable order, by kind as
+// per kindOrder then by API version, namespace and name, and without the
+// null fields, e.g. the creationTimestamp of resources converted from
+// typed objects, so that the same payload always serializes the same way
+// whatever the order it was read or generated in.
+func CanonicalResources(resources []unstructured.Unstructured) []unstructured.Unstructured {
+	result := make([]unstructured.Unstructured, len(resources))
+	for i := range resources {
+		result[i] = *resources[i].DeepCopy()
+		dropNulls(result[i].Object)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := &result[i], &result[j]
+		if ra, rb := kindRank(a.GetKind()), kindRank(b.GetKind()); ra != rb {
+			return ra < rb
+		}
+		if a.GetKind() != b.GetKind() {
+			return a.GetKind() < b.GetKind()
+		}
+		if a.GetAPIVersion() != b.GetAPIVersion() {
+			return a.GetAPIVersion() < b.GetAPIVersion()
+		}
+		if a.GetNamespace() != b.GetNamespace() {
+			return a.GetNamespace() < b.GetNamespace()
+		}
+		return a.GetName() < b.GetName()
+	})
+	return result
+}
+
+// dropNulls removes the null fields of the object, recursively
+func dropNulls(object map[string]interface{}) {
+	for key, value := range object {
+		switch value := value.(type) {
+		case nil:
+			delete(object, key)
+		case map[string]interface{}:
+			dropNulls(value)
+		case []interface{}:
+			for _, item := range value {
+				if item, ok := item.(map[string]interface{}); ok {
+					dropNulls(item)
+				}
+			}
+		}
+	}
+}
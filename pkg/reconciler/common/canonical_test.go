@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCanonicalResources(t *testing.T) {
+	deployment := namespacedResource("apps/v1", "Deployment", "tekton-pipelines", "controller")
+	deployment.Object["metadata"].(map[string]interface{})["creationTimestamp"] = nil
+	resources := []unstructured.Unstructured{
+		clusterScopedResource("admissionregistration.k8s.io/v1", "ValidatingWebhookConfiguration", "validation"),
+		namespacedResource("tekton.dev/v1", "Task", "tekton-pipelines", "git-clone"),
+		deployment,
+		namespacedResource("apps/v1", "Deployment", "tekton-pipelines", "a-webhook"),
+		namespacedResource("v1", "ServiceAccount", "tekton-pipelines", "controller"),
+		clusterScopedResource("apiextensions.k8s.io/v1", "CustomResourceDefinition", "tasks.tekton.dev"),
+		clusterScopedResource("v1", "Namespace", "tekton-pipelines"),
+	}
+	want := []string{
+		"Namespace/tekton-pipelines",
+		"ServiceAccount/controller",
+		"CustomResourceDefinition/tasks.tekton.dev",
+		"Deployment/a-webhook",
+		"Deployment/controller",
+		"Task/git-clone",
+		"ValidatingWebhookConfiguration/validation",
+	}
+	canonical := CanonicalResources(resources)
+	got := make([]string, len(canonical))
+	for i, r := range canonical {
+		got[i] = r.GetKind() + "/" + r.GetName()
+	}
+	util.AssertDeepEqual(t, got, want)
+	_, found := canonical[4].Object["metadata"].(map[string]interface{})["creationTimestamp"]
+	util.AssertEqual(t, found, false)
+	// the resources passed are left alone
+	util.AssertEqual(t, resources[0].GetKind(), "ValidatingWebhookConfiguration")
+
+	// the hash doesn't depend on the order of the resources
+	reversed := make([]unstructured.Unstructured, len(resources))
+	for i := range resources {
+		reversed[len(resources)-1-i] = resources[i]
+	}
+	h1, err := specHash(CanonicalResources(resources), nil)
+	util.AssertNoError(t, err)
+	h2, err := specHash(CanonicalResources(reversed), nil)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, h1, h2)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+type clockKey struct{}
+
+// WithClock attaches to the context the clock the stages time themselves
+// with, e.g. a fake clock stepped by a test.
+func WithClock(ctx context.Context, c clock.Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, c)
+}
+
+// ClockFrom returns the clock of the context, or the wall clock.
+func ClockFrom(ctx context.Context) clock.Clock {
+	if c, ok := ctx.Value(clockKey{}).(clock.Clock); ok {
+		return c
+	}
+	return clock.RealClock{}
+}
+
+// Requeue schedules another reconcile of the component being reconciled
+// after the given delay, e.g. with the EnqueueAfter of its controller.
+type Requeue func(after time.Duration)
+
+type requeueKey struct{}
+
+// WithRequeue attaches to the context how to schedule another reconcile of
+// the component, for long running stages to hand over the rest of their
+// work to a later reconcile rather than hold the workqueue.
+func WithRequeue(ctx context.Context, requeue Requeue) context.Context {
+	return context.WithValue(ctx, requeueKey{}, requeue)
+}
+
+// requeueAfter schedules another reconcile of the component after the
+// delay, returning false if the context can't schedule one.
+func requeueAfter(ctx context.Context, after time.Duration) bool {
+	requeue, _ := ctx.Value(requeueKey{}).(Requeue)
+	if requeue == nil {
+		return false
+	}
+	requeue(after)
+	return true
+}
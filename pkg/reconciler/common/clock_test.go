@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+func TestClockFrom(t *testing.T) {
+	util.AssertEqual(t, ClockFrom(context.Background()), clock.Clock(clock.RealClock{}))
+
+	fake := clock.NewFakeClock(time.Unix(0, 0))
+	ctx := WithClock(context.Background(), fake)
+	fake.Step(time.Minute)
+	util.AssertEqual(t, ClockFrom(ctx).Since(time.Unix(0, 0)), time.Minute)
+}
+
+func TestRequeueAfter(t *testing.T) {
+	util.AssertEqual(t, requeueAfter(context.Background(), time.Second), false)
+
+	var scheduled []time.Duration
+	ctx := WithRequeue(context.Background(), func(after time.Duration) {
+		scheduled = append(scheduled, after)
+	})
+	util.AssertEqual(t, requeueAfter(ctx, time.Second), true)
+	util.AssertDeepEqual(t, scheduled, []time.Duration{time.Second})
+}
@@ -24,6 +24,8 @@ import (
 	informer "github.com/tektoncd/operator/pkg/client/informers/externalversions/operator/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	clientcache "k8s.io/client-go/tools/cache"
 )
 
 var (
@@ -32,6 +34,12 @@ var (
 	DashboardResourceName = "dashboard"
 	AddonResourceName     = "addon"
 	ConfigResourceName    = "config"
+	ResultResourceName    = "result"
+	ChainResourceName     = "chain"
+	HubResourceName       = "hub"
+	PACResourceName       = "pipelines-as-code"
+	MAGResourceName       = "manual-approval-gate"
+	ProfileLite           = "lite"
 	ProfileBasic          = "basic"
 	ProfileDefault        = "default"
 	ProfileAll            = "all"
@@ -89,3 +97,30 @@ func getTriggerRes(informer informer.TektonTriggerInformer) (*v1alpha1.TektonTri
 	res, err := informer.Lister().Get(TriggerResourceName)
 	return res, err
 }
+
+// EnqueueOnReady returns an event handler for the informer of a dependency,
+// e.g. TektonPipeline, enqueuing the dependent component of the given name
+// as soon as the dependency becomes ready, rather than once the backoff of
+// its failed dependency check expires.
+func EnqueueOnReady(enqueueKey func(types.NamespacedName), name string) clientcache.ResourceEventHandler {
+	enqueue := func() {
+		enqueueKey(types.NamespacedName{Name: name})
+	}
+	return clientcache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if isReady(obj) {
+				enqueue()
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if !isReady(oldObj) && isReady(newObj) {
+				enqueue()
+			}
+		},
+	}
+}
+
+func isReady(obj interface{}) bool {
+	component, ok := obj.(v1alpha1.TektonComponent)
+	return ok && component.GetStatus().IsReady()
+}
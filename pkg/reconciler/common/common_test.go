@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestEnqueueOnReady(t *testing.T) {
+	var enqueued []types.NamespacedName
+	handler := EnqueueOnReady(func(key types.NamespacedName) {
+		enqueued = append(enqueued, key)
+	}, TriggerResourceName)
+
+	installing := &v1alpha1.TektonPipeline{}
+	installing.Status.InitializeConditions()
+	ready := installing.DeepCopy()
+	ready.Status.MarkDependenciesInstalled()
+	ready.Status.MarkInstallSucceeded()
+	ready.Status.MarkDeploymentsAvailable()
+
+	handler.OnAdd(installing)
+	handler.OnUpdate(installing, installing)
+	util.AssertEqual(t, len(enqueued), 0)
+
+	handler.OnUpdate(installing, ready)
+	util.AssertDeepEqual(t, enqueued, []types.NamespacedName{{Name: TriggerResourceName}})
+
+	// resyncs of a ready dependency don't enqueue again
+	handler.OnUpdate(ready, ready)
+	util.AssertEqual(t, len(enqueued), 1)
+
+	// a dependency found ready when the informer starts does
+	handler.OnAdd(ready)
+	util.AssertEqual(t, len(enqueued), 2)
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ConfigMapData merges the given data into the payload ConfigMaps of the
+// same name, e.g. config-defaults or feature-flags, replacing the values
+// the payload ships. Other ConfigMaps are left alone.
+func ConfigMapData(configMaps map[string]map[string]string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "ConfigMap" {
+			return nil
+		}
+		overrides, ok := configMaps[u.GetName()]
+		if !ok || len(overrides) == 0 {
+			return nil
+		}
+		data, _, err := unstructured.NestedStringMap(u.Object, "data")
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			data = map[string]string{}
+		}
+		for k, v := range overrides {
+			data[k] = v
+		}
+		return unstructured.SetNestedStringMap(u.Object, data, "data")
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestConfigMapData(t *testing.T) {
+	featureFlags := namespacedResource("v1", "ConfigMap", "tekton-pipelines", "feature-flags")
+	featureFlags.Object["data"] = map[string]interface{}{
+		"disable-affinity-assistant": "false",
+		"enable-api-fields":          "stable",
+	}
+	defaults := namespacedResource("v1", "ConfigMap", "tekton-pipelines", "config-defaults")
+	logging := namespacedResource("v1", "ConfigMap", "tekton-pipelines", "config-logging")
+	logging.Object["data"] = map[string]interface{}{"loglevel.controller": "info"}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{featureFlags, defaults, logging}))
+	util.AssertNoError(t, err)
+
+	manifest, err = manifest.Transform(ConfigMapData(map[string]map[string]string{
+		"feature-flags":   {"enable-api-fields": "alpha"},
+		"config-defaults": {"default-timeout-minutes": "20"},
+		"config-missing":  {"key": "value"},
+	}))
+	util.AssertNoError(t, err)
+	resources := manifest.Resources()
+	util.AssertDeepEqual(t, resources[0].Object["data"], map[string]interface{}{
+		"disable-affinity-assistant": "false",
+		"enable-api-fields":          "alpha",
+	})
+	util.AssertDeepEqual(t, resources[1].Object["data"], map[string]interface{}{"default-timeout-minutes": "20"})
+	util.AssertDeepEqual(t, resources[2].Object, logging.Object)
+}
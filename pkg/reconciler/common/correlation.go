@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// AnnotationCorrelationID holds the correlation ID of the reconcile which
+	// last created or updated a resource
+	AnnotationCorrelationID = "operator.tekton.dev/correlation-id"
+	// correlationIDKey is the logger key of the correlation ID
+	correlationIDKey = "correlationID"
+)
+
+type correlationIDKeyType struct{}
+
+// WithCorrelationID returns a context carrying a new correlation ID, with
+// the ID attached to its logger, and the start of the reconcile, for
+// ObserveGeneration to report its duration. Reconcilers call it once per
+// reconcile.
+func WithCorrelationID(ctx context.Context) context.Context {
+	id := string(uuid.NewUUID())
+	ctx = context.WithValue(ctx, correlationIDKeyType{}, id)
+	ctx = context.WithValue(ctx, reconcileStartKey{}, time.Now())
+	return logging.WithLogger(ctx, logging.FromContext(ctx).With(correlationIDKey, id))
+}
+
+// CorrelationID returns the correlation ID of the context, or "" if there
+// is none.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKeyType{}).(string)
+	return id
+}
+
+// correlate adds the correlation ID of the context to the error, which
+// ends up in the event recorded for it.
+func correlate(ctx context.Context, err error) error {
+	id := CorrelationID(ctx)
+	if err == nil || id == "" {
+		return err
+	}
+	return fmt.Errorf("%w (correlation id %s)", err, id)
+}
+
+// correlatedClient annotates the resources it creates or updates with a
+// correlation ID. Unchanged resources aren't written by manifestival, so
+// they keep the ID of the reconcile which last changed them.
+type correlatedClient struct {
+	mf.Client
+	id string
+}
+
+// withCorrelatedClient makes the manifest annotate the resources it writes
+// with the correlation ID of the context.
+func withCorrelatedClient(ctx context.Context, manifest *mf.Manifest) {
+	id := CorrelationID(ctx)
+	if id == "" || manifest.Client == nil {
+		return
+	}
+	if c, ok := manifest.Client.(correlatedClient); ok {
+		manifest.Client = c.Client
+	}
+	manifest.Client = correlatedClient{Client: manifest.Client, id: id}
+}
+
+func (c correlatedClient) Create(obj *unstructured.Unstructured, options ...mf.ApplyOption) error {
+	c.annotate(obj)
+	return c.Client.Create(obj, options...)
+}
+
+func (c correlatedClient) Update(obj *unstructured.Unstructured, options ...mf.ApplyOption) error {
+	c.annotate(obj)
+	return c.Client.Update(obj, options...)
+}
+
+func (c correlatedClient) annotate(obj *unstructured.Unstructured) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationCorrelationID] = c.id
+	obj.SetAnnotations(annotations)
+}
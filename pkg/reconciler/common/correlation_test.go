@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type recordingClient struct {
+	mf.Client
+	written []*unstructured.Unstructured
+}
+
+func (c *recordingClient) Create(obj *unstructured.Unstructured, _ ...mf.ApplyOption) error {
+	c.written = append(c.written, obj)
+	return nil
+}
+
+func (c *recordingClient) Update(obj *unstructured.Unstructured, _ ...mf.ApplyOption) error {
+	c.written = append(c.written, obj)
+	return nil
+}
+
+func TestCorrelationID(t *testing.T) {
+	util.AssertEqual(t, CorrelationID(context.Background()), "")
+
+	ctx := WithCorrelationID(context.Background())
+	id := CorrelationID(ctx)
+	if id == "" {
+		t.Fatal("CorrelationID() = \"\", want an ID")
+	}
+	if CorrelationID(WithCorrelationID(ctx)) == id {
+		t.Fatal("WithCorrelationID() reused the ID of the parent context")
+	}
+}
+
+func TestExecuteCorrelation(t *testing.T) {
+	ctx := WithCorrelationID(context.Background())
+	id := CorrelationID(ctx)
+	client := &recordingClient{}
+	manifest, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	failure := errors.New("failed")
+	write := func(ctx context.Context, manifest *mf.Manifest, _ v1alpha1.TektonComponent) error {
+		u := namespacedResource("v1", "ConfigMap", "test", "test")
+		return manifest.Client.Create(&u)
+	}
+	fail := func(context.Context, *mf.Manifest, v1alpha1.TektonComponent) error {
+		return failure
+	}
+
+	err = Stages{write, fail}.Execute(ctx, &manifest, &v1alpha1.TektonPipeline{})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Execute() = %v, want wrapped %v", err, failure)
+	}
+	util.AssertEqual(t, err.Error(), "failed (correlation id "+id+")")
+	util.AssertEqual(t, len(client.written), 1)
+	util.AssertEqual(t, client.written[0].GetAnnotations()[AnnotationCorrelationID], id)
+
+	// Executing again doesn't stack the clients
+	util.AssertNoError(t, Stages{write}.Execute(WithCorrelationID(ctx), &manifest, &v1alpha1.TektonPipeline{}))
+	if _, ok := manifest.Client.(correlatedClient).Client.(*recordingClient); !ok {
+		t.Fatalf("Execute() stacked correlated clients: %T", manifest.Client.(correlatedClient).Client)
+	}
+}
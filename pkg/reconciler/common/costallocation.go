@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// CostAllocationLabels returns the cost allocation labels of the
+// component, spec.costAllocation.labels merged with the labels of its kind.
+func CostAllocationLabels(instance v1alpha1.TektonComponent) map[string]string {
+	return instance.GetSpec().GetCostAllocation().LabelsFor(ComponentKind(instance))
+}
+
+// ComponentKind returns the kind of the component, which typed objects
+// don't carry in their TypeMeta.
+func ComponentKind(instance v1alpha1.TektonComponent) string {
+	switch instance.(type) {
+	case *v1alpha1.TektonPipeline:
+		return v1alpha1.KindTektonPipeline
+	case *v1alpha1.TektonTrigger:
+		return v1alpha1.KindTektonTrigger
+	case *v1alpha1.TektonDashboard:
+		return v1alpha1.KindTektonDashboard
+	case *v1alpha1.TektonAddon:
+		return v1alpha1.KindTektonAddon
+	case *v1alpha1.TektonConfig:
+		return v1alpha1.KindTektonConfig
+	case *v1alpha1.TektonResult:
+		return v1alpha1.KindTektonResult
+	case *v1alpha1.TektonChain:
+		return v1alpha1.KindTektonChain
+	case *v1alpha1.TektonHub:
+		return v1alpha1.KindTektonHub
+	case *v1alpha1.OpenShiftPipelinesAsCode:
+		return v1alpha1.KindOpenShiftPipelinesAsCode
+	case *v1alpha1.ManualApprovalGate:
+		return v1alpha1.KindManualApprovalGate
+	}
+	return instance.GroupVersionKind().Kind
+}
+
+// CostLabels sets the given labels on the payload workloads and their pod
+// templates, replacing the values of the payload so that every pod of a
+// component reports the same. Labels of the workload selector are left
+// alone, pods would no longer match it otherwise.
+func CostLabels(labels map[string]string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if len(labels) == 0 || !podTemplateKinds.Has(u.GetKind()) {
+			return nil
+		}
+		selector, _, err := unstructured.NestedStringMap(u.Object, "spec", "selector", "matchLabels")
+		if err != nil {
+			return err
+		}
+		current, _, err := unstructured.NestedStringMap(u.Object, "spec", "template", "metadata", "labels")
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			current = map[string]string{}
+		}
+		metadata := u.GetLabels()
+		if metadata == nil {
+			metadata = map[string]string{}
+		}
+		for k, v := range labels {
+			if _, ok := selector[k]; ok {
+				continue
+			}
+			current[k] = v
+			metadata[k] = v
+		}
+		u.SetLabels(metadata)
+		return unstructured.SetNestedStringMap(u.Object, current, "spec", "template", "metadata", "labels")
+	}
+}
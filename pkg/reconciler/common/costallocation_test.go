@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCostAllocationLabels(t *testing.T) {
+	costAllocation := &v1alpha1.CostAllocation{
+		Labels: map[string]string{"cost-center": "platform", "team": "ci"},
+		Components: map[string]map[string]string{
+			v1alpha1.KindTektonTrigger: {"team": "webhooks"},
+		},
+	}
+	pipeline := &v1alpha1.TektonPipeline{
+		Spec: v1alpha1.TektonPipelineSpec{CommonSpec: v1alpha1.CommonSpec{CostAllocation: costAllocation}},
+	}
+	trigger := &v1alpha1.TektonTrigger{
+		Spec: v1alpha1.TektonTriggerSpec{CommonSpec: v1alpha1.CommonSpec{CostAllocation: costAllocation}},
+	}
+	util.AssertDeepEqual(t, CostAllocationLabels(pipeline), map[string]string{"cost-center": "platform", "team": "ci"})
+	util.AssertDeepEqual(t, CostAllocationLabels(trigger), map[string]string{"cost-center": "platform", "team": "webhooks"})
+	if labels := CostAllocationLabels(&v1alpha1.TektonDashboard{}); labels != nil {
+		t.Errorf("expected no labels, got %v", labels)
+	}
+}
+
+func TestCostLabels(t *testing.T) {
+	deployment := util.MakeDeployment("controller", corev1.PodSpec{})
+	deployment.Labels = map[string]string{"team": "payload"}
+	deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "controller"}}
+	deployment.Spec.Template.Labels = map[string]string{"app": "controller", "team": "payload"}
+	u := util.MakeUnstructured(t, deployment)
+
+	labels := map[string]string{"team": "ci", "app": "cost"}
+	assertNoEror(t, CostLabels(labels)(&u))
+	util.AssertDeepEqual(t, u.GetLabels(), map[string]string{"team": "ci"})
+	got, _, err := unstructured.NestedStringMap(u.Object, "spec", "template", "metadata", "labels")
+	assertNoEror(t, err)
+	util.AssertDeepEqual(t, got, map[string]string{"app": "controller", "team": "ci"})
+
+	cm := namespacedResource("v1", "ConfigMap", "ns", "config")
+	assertNoEror(t, CostLabels(labels)(&cm))
+	util.AssertEqual(t, len(cm.GetLabels()), 0)
+}
@@ -19,9 +19,11 @@ package common
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	mf "github.com/manifestival/manifestival"
 	v1alpha1 "github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -42,6 +44,11 @@ func CheckDeployments(ctx context.Context, manifest *mf.Manifest, instance v1alp
 			return err
 		}
 		if !isDeploymentAvailable(deployment) {
+			if progressDeadlineExceeded(deployment) {
+				err := &operrors.TimeoutWaitingReady{Err: fmt.Errorf("deployment %s exceeded its progress deadline", deployment.Name)}
+				operrors.MarkFailed(status, err)
+				return err
+			}
 			status.MarkDeploymentsNotReady()
 			return errors.New("deployment not available")
 		}
@@ -50,6 +57,17 @@ func CheckDeployments(ctx context.Context, manifest *mf.Manifest, instance v1alp
 	return nil
 }
 
+// progressDeadlineExceeded returns true if the rollout of the deployment
+// stopped progressing
+func progressDeadlineExceeded(d *appsv1.Deployment) bool {
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse && c.Reason == "ProgressDeadlineExceeded" {
+			return true
+		}
+	}
+	return false
+}
+
 func isDeploymentAvailable(d *appsv1.Deployment) bool {
 	for _, c := range d.Status.Conditions {
 		if c.Type == appsv1.DeploymentAvailable && c.Status == corev1.ConditionTrue {
@@ -57,12 +57,21 @@ func TestCheckDeployments(t *testing.T) {
 		},
 	}
 
+	stuckDeployment := notReadyDeployment.DeepCopy()
+	stuckDeployment.Name = "stuck"
+	stuckDeployment.Status.Conditions = append(stuckDeployment.Status.Conditions, appsv1.DeploymentCondition{
+		Type:   appsv1.DeploymentProgressing,
+		Status: corev1.ConditionFalse,
+		Reason: "ProgressDeadlineExceeded",
+	})
+
 	tests := []struct {
 		name       string
 		inManifest []unstructured.Unstructured
 		inAPI      []runtime.Object
 		wantError  bool
 		wantStatus corev1.ConditionStatus
+		wantReason string
 	}{{
 		name: "ready deployment",
 		inManifest: []unstructured.Unstructured{
@@ -79,6 +88,16 @@ func TestCheckDeployments(t *testing.T) {
 		inAPI:      []runtime.Object{notReadyDeployment},
 		wantError:  true,
 		wantStatus: corev1.ConditionFalse,
+		wantReason: "NotReady",
+	}, {
+		name: "deployment past its progress deadline",
+		inManifest: []unstructured.Unstructured{
+			namespacedResource("apps/v1", "Deployment", "test", "stuck"),
+		},
+		inAPI:      []runtime.Object{stuckDeployment},
+		wantError:  true,
+		wantStatus: corev1.ConditionFalse,
+		wantReason: v1alpha1.ReasonTimeoutWaitingReady,
 	}, {
 		name: "ready and not ready deployment",
 		inManifest: []unstructured.Unstructured{
@@ -117,6 +136,9 @@ func TestCheckDeployments(t *testing.T) {
 			if condition == nil || condition.Status != test.wantStatus {
 				t.Fatalf("DeploymentAvailable = %v, want %v", condition, test.wantStatus)
 			}
+			if test.wantReason != "" && condition.Reason != test.wantReason {
+				t.Fatalf("DeploymentAvailable reason = %s, want %s", condition.Reason, test.wantReason)
+			}
 		})
 	}
 }
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+)
+
+// DeprecatedFieldReason is the reason of the warning events recorded for
+// deprecated fields set in the spec of a component.
+const DeprecatedFieldReason = "DeprecatedField"
+
+// Deprecation is a field of the spec slated for removal, along with the
+// field replacing it.
+type Deprecation struct {
+	// Field is the path of the deprecated field, e.g. spec.options.configMaps
+	Field string
+	// Replacement is the path of the field to set instead
+	Replacement string
+}
+
+func (d Deprecation) String() string {
+	return fmt.Sprintf("%s is deprecated, use %s instead", d.Field, d.Replacement)
+}
+
+// DeprecationCheck returns the deprecated fields set in the spec of a
+// component.
+type DeprecationCheck func(v1alpha1.TektonComponent) []Deprecation
+
+// ReportDeprecations returns a Stage reporting the deprecated fields the
+// checks find in the spec, in a warning event and a log for each of them
+// and in the DeprecatedFieldsUnset condition. Deprecated fields keep
+// working until they are removed, so the install goes on either way.
+func ReportDeprecations(checks ...DeprecationCheck) Stage {
+	return func(ctx context.Context, _ *mf.Manifest, instance v1alpha1.TektonComponent) error {
+		var deprecations []Deprecation
+		for _, check := range checks {
+			deprecations = append(deprecations, check(instance)...)
+		}
+		if len(deprecations) == 0 {
+			instance.GetStatus().MarkDeprecatedFieldsUnset()
+			return nil
+		}
+		sort.Slice(deprecations, func(i, j int) bool {
+			return deprecations[i].Field < deprecations[j].Field
+		})
+
+		logger := logging.FromContext(ctx)
+		recorder := controller.GetEventRecorder(ctx)
+		messages := make([]string, len(deprecations))
+		for i, d := range deprecations {
+			messages[i] = d.String()
+			logger.Warnw("Deprecated field set", "field", d.Field, "replacement", d.Replacement)
+			if object, ok := instance.(runtime.Object); ok && recorder != nil {
+				recorder.Event(object, corev1.EventTypeWarning, DeprecatedFieldReason, messages[i])
+			}
+		}
+		instance.GetStatus().MarkDeprecatedFieldsSet(strings.Join(messages, "; "))
+		return nil
+	}
+}
+
+// DeprecatedConfigMapKeys returns a DeprecationCheck for the keys of
+// spec.options.configMaps replaced by typed fields of the spec, given by
+// ConfigMap name and key.
+func DeprecatedConfigMapKeys(replacements map[string]map[string]string) DeprecationCheck {
+	return func(instance v1alpha1.TektonComponent) []Deprecation {
+		var deprecations []Deprecation
+		for name, data := range instance.GetSpec().GetOptions().ConfigMaps {
+			for key := range data {
+				if replacement, ok := replacements[name][key]; ok {
+					deprecations = append(deprecations, Deprecation{
+						Field:       fmt.Sprintf("spec.options.configMaps.%s.%s", name, key),
+						Replacement: replacement,
+					})
+				}
+			}
+		}
+		return deprecations
+	}
+}
+
+// DeprecatedDisruptionFields is a DeprecationCheck for
+// spec.config.podDisruptionBudget and spec.config.priorityClassName,
+// replaced by spec.disruptionPolicy, which takes precedence over them.
+func DeprecatedDisruptionFields(instance v1alpha1.TektonComponent) []Deprecation {
+	config := instance.GetSpec().GetConfig()
+	var deprecations []Deprecation
+	if config.PodDisruptionBudget != nil {
+		deprecations = append(deprecations, Deprecation{
+			Field:       "spec.config.podDisruptionBudget",
+			Replacement: "spec.disruptionPolicy.minAvailable",
+		})
+	}
+	if config.PriorityClassName != "" {
+		deprecations = append(deprecations, Deprecation{
+			Field:       "spec.config.priorityClassName",
+			Replacement: "spec.disruptionPolicy.priorityClassName",
+		})
+	}
+	return deprecations
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/controller"
+)
+
+func TestReportDeprecations(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	ctx := controller.WithEventRecorder(context.Background(), recorder)
+	report := ReportDeprecations(DeprecatedConfigMapKeys(map[string]map[string]string{
+		"feature-flags": {"enable-api-fields": "spec.featureFlags.enableAPIFields"},
+	}))
+
+	instance := &v1alpha1.TektonPipeline{}
+	instance.Status.InitializeConditions()
+	instance.Spec.Options.ConfigMaps = map[string]map[string]string{
+		"feature-flags":   {"enable-api-fields": "alpha", "some-new-flag": "true"},
+		"config-defaults": {"enable-api-fields": "alpha"},
+	}
+	util.AssertNoError(t, report(ctx, nil, instance))
+	util.AssertEqual(t, len(recorder.Events), 1)
+	util.AssertEqual(t, <-recorder.Events,
+		"Warning DeprecatedField spec.options.configMaps.feature-flags.enable-api-fields is deprecated, use spec.featureFlags.enableAPIFields instead")
+	condition := instance.Status.GetCondition(v1alpha1.DeprecatedFieldsUnset)
+	util.AssertEqual(t, condition.IsFalse(), true)
+	util.AssertEqual(t, condition.Reason, v1alpha1.ReasonDeprecatedFields)
+
+	// the condition clears once the deprecated fields are unset
+	delete(instance.Spec.Options.ConfigMaps["feature-flags"], "enable-api-fields")
+	util.AssertNoError(t, report(ctx, nil, instance))
+	util.AssertEqual(t, len(recorder.Events), 0)
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.DeprecatedFieldsUnset).IsTrue(), true)
+}
+
+func TestDeprecatedDisruptionFields(t *testing.T) {
+	instance := &v1alpha1.TektonPipeline{}
+	util.AssertEqual(t, len(DeprecatedDisruptionFields(instance)), 0)
+
+	instance.Spec.Config.PodDisruptionBudget = &v1alpha1.PodDisruptionBudget{}
+	instance.Spec.Config.PriorityClassName = "high"
+	util.AssertDeepEqual(t, DeprecatedDisruptionFields(instance), []Deprecation{{
+		Field:       "spec.config.podDisruptionBudget",
+		Replacement: "spec.disruptionPolicy.minAvailable",
+	}, {
+		Field:       "spec.config.priorityClassName",
+		Replacement: "spec.disruptionPolicy.priorityClassName",
+	}})
+}
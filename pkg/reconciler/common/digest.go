@@ -0,0 +1,299 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const dockerHubRegistry = "registry-1.docker.io"
+
+var (
+	// manifestMediaTypes are the manifests accepted when resolving digests,
+	// multi-arch indexes first so that the digest is valid on every node
+	manifestMediaTypes = strings.Join([]string{
+		"application/vnd.docker.distribution.manifest.list.v2+json",
+		"application/vnd.oci.image.index.v1+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+		"application/vnd.oci.image.manifest.v1+json",
+	}, ",")
+
+	challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+	// registryClient talks to the registries, replaced in tests
+	registryClient = &http.Client{Timeout: 30 * time.Second}
+	// resolveDigest resolves the digest of an image, replaced in tests
+	resolveDigest = registryDigest
+)
+
+// PinImageDigests replaces the tag of the payload images by their digest
+// when spec.registry.pinDigests is set, and adds the pins to the status.
+// The registries are reached like the image pulls are checked, see
+// PrecheckImagePulls, and the install fails naming every image which
+// couldn't be pinned.
+// Pins in the status are reused, so that the install doesn't change when a
+// tag is moved, and dropped on upgrades.
+func PinImageDigests(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	status := instance.GetStatus()
+	if !instance.GetSpec().GetRegistry().PinDigests {
+		status.SetPinnedImages(nil)
+		return nil
+	}
+
+	pinned := map[string]string{}
+	if status.GetVersion() == TargetVersion(instance) {
+		for image, ref := range status.GetPinnedImages() {
+			pinned[image] = ref
+		}
+	}
+
+	images, err := manifestImages(*manifest)
+	if err != nil {
+		return err
+	}
+	registries, err := payloadRegistries(manifest, instance)
+	if err != nil {
+		return err
+	}
+	var failures []string
+	for _, image := range images {
+		if _, ok := pinned[image]; ok || strings.Contains(image, "@") {
+			continue
+		}
+		registry, _, _ := parseImage(image)
+		digest, err := resolveDigest(ctx, registries.access(registry), image)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", image, err))
+			continue
+		}
+		pinned[image] = image + "@" + digest
+	}
+	if len(failures) != 0 {
+		err := &operrors.ImagePinFailed{Err: fmt.Errorf("Failed to pin %d images: %s", len(failures), strings.Join(failures, "; "))}
+		operrors.MarkFailed(status, err)
+		return err
+	}
+
+	transformed, err := manifest.Transform(imagesTransformer(func(image string) string {
+		if ref, ok := pinned[image]; ok {
+			return ref
+		}
+		return image
+	}))
+	if err != nil {
+		return err
+	}
+	*manifest = transformed
+	status.SetPinnedImages(pinned)
+	return nil
+}
+
+// manifestImages returns the payload images of the manifest, see
+// imagesTransformer.
+func manifestImages(manifest mf.Manifest) ([]string, error) {
+	images := sets.NewString()
+	_, err := manifest.Transform(imagesTransformer(func(image string) string {
+		images.Insert(image)
+		return image
+	}))
+	return images.List(), err
+}
+
+// registryDigest asks the registry of the image for the digest of its
+// manifest, through the access to the registry.
+func registryDigest(ctx context.Context, access *registryAccess, image string) (string, error) {
+	registry, repository, tag := parseImage(image)
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", registry, repository, tag)
+
+	resp, err := access.do(ctx, http.MethodHead, manifestURL, manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s returned %s", manifestURL, resp.Status)
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("%s returned no digest", manifestURL)
+	}
+	return digest, nil
+}
+
+// registryAccess is how the registry API is reached: the client sending
+// the requests, registryClient when nil, and the credentials of the
+// registry, anonymous when empty.
+type registryAccess struct {
+	client   *http.Client
+	username string
+	password string
+}
+
+// registries reach the registries of the payload images the way the
+// kubelet pulls them: through spec.proxy, with the credentials of
+// spec.registry.imagePullSecrets.
+type registries struct {
+	client      *http.Client
+	credentials map[string]registryAccess
+}
+
+// payloadRegistries returns the access to the registries of the payload of
+// the instance.
+func payloadRegistries(manifest *mf.Manifest, instance v1alpha1.TektonComponent) (*registries, error) {
+	spec := instance.GetSpec()
+	credentials, err := pullSecretCredentials(manifest, spec.GetTargetNamespace(), spec.GetRegistry().ImagePullSecrets)
+	if err != nil {
+		return nil, err
+	}
+	return &registries{client: registryClientWithProxy(spec.GetProxy()), credentials: credentials}, nil
+}
+
+// access returns the access to the registry, anonymous without
+// credentials.
+func (r *registries) access(registry string) *registryAccess {
+	access := r.credentials[registry]
+	access.client = r.client
+	return &access
+}
+
+// do sends a request to the registry API, authenticating with the
+// credentials of the access if the registry requires it. The caller closes
+// the body of the response.
+func (a *registryAccess) do(ctx context.Context, method, apiURL, accept string) (*http.Response, error) {
+	resp, err := a.request(ctx, method, apiURL, accept, "")
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	resp.Body.Close()
+	challenge := resp.Header.Get("WWW-Authenticate")
+	if strings.HasPrefix(challenge, "Basic ") && a.username != "" {
+		return a.request(ctx, method, apiURL, accept, "Basic "+a.basicAuth())
+	}
+	token, err := a.token(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+	return a.request(ctx, method, apiURL, accept, "Bearer "+token)
+}
+
+func (a *registryAccess) request(ctx context.Context, method, apiURL, accept, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return a.httpClient().Do(req)
+}
+
+func (a *registryAccess) httpClient() *http.Client {
+	if a.client != nil {
+		return a.client
+	}
+	return registryClient
+}
+
+func (a *registryAccess) basicAuth() string {
+	return base64.StdEncoding.EncodeToString([]byte(a.username + ":" + a.password))
+}
+
+// token gets a pull token from the realm of the bearer challenge of a
+// registry, with the credentials of the access or anonymously.
+func (a *registryAccess) token(ctx context.Context, challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported registry authentication %q", challenge)
+	}
+	params := map[string]string{}
+	for _, match := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid registry authentication realm in %q", challenge)
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if a.username != "" {
+		req.SetBasicAuth(a.username, a.password)
+	}
+	resp, err := a.httpClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s returned %s", realm.Host, resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// parseImage splits an image reference into the host of its registry, its
+// repository and its tag, defaulting to Docker Hub and latest.
+func parseImage(image string) (registry, repository, tag string) {
+	registry, repository = dockerHubRegistry, image
+	if parts := strings.SplitN(image, "/", 2); len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		registry, repository = parts[0], parts[1]
+	}
+	if registry == "docker.io" || registry == "index.docker.io" {
+		registry = dockerHubRegistry
+	}
+	if registry == dockerHubRegistry && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	tag = "latest"
+	if i := strings.LastIndex(repository, ":"); i != -1 {
+		repository, tag = repository[:i], repository[i+1:]
+	}
+	return registry, repository, tag
+}
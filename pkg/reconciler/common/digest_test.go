@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseImage(t *testing.T) {
+	tests := []struct {
+		image, registry, repository, tag string
+	}{
+		{"busybox", "registry-1.docker.io", "library/busybox", "latest"},
+		{"docker.io/tektoncd/git-init:v1", "registry-1.docker.io", "tektoncd/git-init", "v1"},
+		{"gcr.io/tekton-releases/controller:v0.20.0", "gcr.io", "tekton-releases/controller", "v0.20.0"},
+		{"localhost:5000/registry", "localhost:5000", "registry", "latest"},
+	}
+	for _, test := range tests {
+		registry, repository, tag := parseImage(test.image)
+		util.AssertEqual(t, registry, test.registry)
+		util.AssertEqual(t, repository, test.repository)
+		util.AssertEqual(t, tag, test.tag)
+	}
+}
+
+func TestRegistryDigest(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if r.URL.Query().Get("scope") != "repository:tekton/controller:pull" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"token": "secret"}`)
+		case "/v2/tekton/controller/manifests/v1":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+					`Bearer realm="%s/token",service="registry",scope="repository:tekton/controller:pull"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", "sha256:abc")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	defer func(client *http.Client) { registryClient = client }(registryClient)
+	registryClient = server.Client()
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	digest, err := registryDigest(context.Background(), &registryAccess{}, host+"/tekton/controller:v1")
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, digest, "sha256:abc")
+
+	if _, err := registryDigest(context.Background(), &registryAccess{}, host+"/tekton/webhook:v1"); err == nil {
+		t.Fatal("registryDigest() = nil, wanted an error for a missing image")
+	}
+}
+
+func TestPinImageDigests(t *testing.T) {
+	os.Setenv(KoEnvKey, "testdata/kodata")
+	defer os.Unsetenv(KoEnvKey)
+	defer func(resolve func(context.Context, *registryAccess, string) (string, error)) { resolveDigest = resolve }(resolveDigest)
+	resolved := 0
+	resolveDigest = func(_ context.Context, _ *registryAccess, image string) (string, error) {
+		resolved++
+		return "sha256:" + image, nil
+	}
+
+	deployment := util.MakeUnstructured(t, util.MakeDeployment("controller", corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: "controller", Image: "gcr.io/controller:v1"},
+			{Name: "sidecar", Image: "gcr.io/sidecar@sha256:def"},
+		},
+	}))
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment}))
+	util.AssertNoError(t, err)
+
+	instance := &v1alpha1.TektonPipeline{}
+	util.AssertNoError(t, PinImageDigests(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(instance.Status.PinnedImages), 0)
+
+	instance.Spec.Registry.PinDigests = true
+	instance.Status.Version = TargetVersion(instance)
+	instance.Status.PinnedImages = map[string]string{"gcr.io/controller:v1": "gcr.io/controller:v1@sha256:old"}
+	pinned := manifest.Append()
+	util.AssertNoError(t, PinImageDigests(context.Background(), &pinned, instance))
+	util.AssertEqual(t, resolved, 0)
+	images := func(m mf.Manifest) []string {
+		containers, _, _ := unstructured.NestedSlice(m.Resources()[0].Object, "spec", "template", "spec", "containers")
+		return []string{
+			containers[0].(map[string]interface{})["image"].(string),
+			containers[1].(map[string]interface{})["image"].(string),
+		}
+	}
+	util.AssertDeepEqual(t, images(pinned), []string{"gcr.io/controller:v1@sha256:old", "gcr.io/sidecar@sha256:def"})
+
+	// Pins of another version are dropped
+	instance.Status.Version = "0.0.1"
+	pinned = manifest.Append()
+	util.AssertNoError(t, PinImageDigests(context.Background(), &pinned, instance))
+	util.AssertEqual(t, resolved, 1)
+	util.AssertDeepEqual(t, images(pinned), []string{"gcr.io/controller:v1@sha256:gcr.io/controller:v1", "gcr.io/sidecar@sha256:def"})
+	util.AssertDeepEqual(t, instance.Status.PinnedImages, map[string]string{
+		"gcr.io/controller:v1": "gcr.io/controller:v1@sha256:gcr.io/controller:v1",
+	})
+
+	// every image which can't be pinned is reported
+	resolveDigest = func(_ context.Context, _ *registryAccess, image string) (string, error) {
+		return "", fmt.Errorf("not found")
+	}
+	deployment = util.MakeUnstructured(t, util.MakeDeployment("controller", corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: "controller", Image: "gcr.io/controller:v2"},
+			{Name: "webhook", Image: "gcr.io/webhook:v2"},
+		},
+	}))
+	manifest, err = mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment}))
+	util.AssertNoError(t, err)
+	instance.Status.InitializeConditions()
+	err = PinImageDigests(context.Background(), &manifest, instance)
+	util.AssertEqual(t, err.Error(), "Failed to pin 2 images: gcr.io/controller:v2: not found; gcr.io/webhook:v2: not found")
+}
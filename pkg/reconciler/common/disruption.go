@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"knative.dev/pkg/logging"
+)
+
+// annotationSafeToEvict tells the cluster autoscaler whether it may evict
+// a pod when scaling down its node
+const annotationSafeToEvict = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+
+// disruption are the settings of a component for node drains
+type disruption struct {
+	// minAvailable or maxUnavailable of the PodDisruptionBudgets, none
+	// being generated when both are nil
+	minAvailable   *intstr.IntOrString
+	maxUnavailable *intstr.IntOrString
+	// priorityClassName of the payload pods, left alone when empty
+	priorityClassName string
+	// safeToEvict is the value of the safe-to-evict annotation of the pods
+	// of the controllers and webhooks, left alone when empty
+	safeToEvict string
+}
+
+// disruptionSettings returns the settings of spec.disruptionPolicy, or of
+// spec.config.podDisruptionBudget and spec.config.priorityClassName when
+// it is unset.
+func disruptionSettings(spec v1alpha1.TektonComponentSpec) disruption {
+	one := intstr.FromInt(1)
+	policy := spec.GetDisruptionPolicy()
+	if policy == nil {
+		config := spec.GetConfig()
+		settings := disruption{priorityClassName: config.PriorityClassName}
+		if config.PodDisruptionBudget != nil {
+			settings.minAvailable = &one
+			if config.PodDisruptionBudget.MinAvailable != nil {
+				settings.minAvailable = config.PodDisruptionBudget.MinAvailable
+			}
+		}
+		return settings
+	}
+	// without minAvailable, one pod of each deployment may be evicted at a
+	// time, whatever its replicas, so that drains never block
+	settings := disruption{
+		minAvailable:      policy.MinAvailable,
+		priorityClassName: policy.PriorityClassName,
+		safeToEvict:       "true",
+	}
+	if settings.minAvailable == nil {
+		settings.maxUnavailable = &one
+	}
+	if policy.SafeToEvict != nil && !*policy.SafeToEvict {
+		settings.safeToEvict = "false"
+	}
+	return settings
+}
+
+// disruptable returns whether the deployment is one of the controllers and
+// webhooks the disruption settings protect
+func disruptable(name string) bool {
+	return strings.Contains(name, "controller") || strings.Contains(name, "webhook")
+}
+
+// PodDisruptionBudgets is a Stage appending a PodDisruptionBudget for every
+// controller and webhook deployment when spec.disruptionPolicy or
+// spec.config.podDisruptionBudget is set, keeping minAvailable of their
+// pods during node drains, or evicting them one at a time.
+func PodDisruptionBudgets(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	settings := disruptionSettings(instance.GetSpec())
+	if settings.minAvailable == nil && settings.maxUnavailable == nil {
+		return nil
+	}
+	return appendGenerated(ctx, manifest, instance, func(deployment *appsv1.Deployment) runtime.Object {
+		if !disruptable(deployment.Name) {
+			return nil
+		}
+		if settings.minAvailable != nil {
+			warnBlockedDrains(ctx, deployment, *settings.minAvailable)
+		}
+		return podDisruptionBudget(deployment, settings)
+	})
+}
+
+// warnBlockedDrains logs the deployments whose PodDisruptionBudget never
+// allows an eviction, blocking node drains and the scale down of their
+// nodes, as minAvailable isn't below their replicas.
+func warnBlockedDrains(ctx context.Context, deployment *appsv1.Deployment, minAvailable intstr.IntOrString) {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	available, err := intstr.GetValueFromIntOrPercent(&minAvailable, int(replicas), true)
+	if err != nil || int32(available) < replicas {
+		return
+	}
+	logging.FromContext(ctx).Warnw("PodDisruptionBudget blocks node drains, minAvailable isn't below the replicas",
+		"deployment", deployment.Name, "replicas", replicas, "minAvailable", minAvailable.String())
+}
+
+// SafeToEvict sets the cluster autoscaler safe-to-evict annotation of the
+// pods of the controller and webhook deployments. It is a no-op when value
+// is empty.
+func SafeToEvict(value string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if value == "" || u.GetKind() != "Deployment" || !disruptable(u.GetName()) {
+			return nil
+		}
+		return PodAnnotations(map[string]string{annotationSafeToEvict: value})(u)
+	}
+}
+
+// podDisruptionBudget returns the PodDisruptionBudget of the pods of a
+// deployment
+func podDisruptionBudget(deployment *appsv1.Deployment, settings disruption) *policyv1beta1.PodDisruptionBudget {
+	return &policyv1beta1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.Name,
+			Namespace: deployment.Namespace,
+			Labels:    deployment.Labels,
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable:   settings.minAvailable,
+			MaxUnavailable: settings.maxUnavailable,
+			Selector:       deployment.Spec.Selector,
+		},
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestPodDisruptionBudgets(t *testing.T) {
+	var resources []unstructured.Unstructured
+	for _, name := range []string{"tekton-pipelines-controller", "tekton-pipelines-webhook", "tekton-dashboard"} {
+		resources = append(resources, util.MakeUnstructured(t, util.MakeDeployment(name, corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main", Image: "gcr.io/main:v1"}},
+		})))
+	}
+	manifest, err := mf.ManifestFrom(mf.Slice(resources))
+	util.AssertNoError(t, err)
+
+	instance := &v1alpha1.TektonPipeline{}
+	util.AssertNoError(t, PodDisruptionBudgets(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(manifest.Resources()), 3)
+
+	half := intstr.FromString("50%")
+	instance.Spec.Config.PodDisruptionBudget = &v1alpha1.PodDisruptionBudget{MinAvailable: &half}
+	util.AssertNoError(t, PodDisruptionBudgets(context.Background(), &manifest, instance))
+	pdbs := manifest.Filter(mf.ByKind("PodDisruptionBudget")).Resources()
+	util.AssertEqual(t, len(pdbs), 2)
+	for _, u := range pdbs {
+		pdb := &policyv1beta1.PodDisruptionBudget{}
+		util.AssertNoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, pdb))
+		util.AssertEqual(t, pdb.Spec.MinAvailable.String(), "50%")
+	}
+}
+
+func TestDisruptionSettings(t *testing.T) {
+	spec := &v1alpha1.CommonSpec{}
+	spec.Config.PriorityClassName = "tekton"
+	util.AssertDeepEqual(t, disruptionSettings(spec), disruption{priorityClassName: "tekton"})
+
+	spec.Config.PodDisruptionBudget = &v1alpha1.PodDisruptionBudget{}
+	settings := disruptionSettings(spec)
+	util.AssertEqual(t, settings.minAvailable.String(), "1")
+	util.AssertEqual(t, settings.safeToEvict, "")
+
+	two := intstr.FromInt(2)
+	spec.DisruptionPolicy = &v1alpha1.DisruptionPolicy{MinAvailable: &two, PriorityClassName: "system-cluster-critical"}
+	settings = disruptionSettings(spec)
+	util.AssertEqual(t, settings.minAvailable.String(), "2")
+	util.AssertEqual(t, settings.priorityClassName, "system-cluster-critical")
+	util.AssertEqual(t, settings.safeToEvict, "true")
+
+	safeToEvict := false
+	spec.DisruptionPolicy = &v1alpha1.DisruptionPolicy{SafeToEvict: &safeToEvict}
+	settings = disruptionSettings(spec)
+	util.AssertEqual(t, settings.minAvailable == nil, true)
+	util.AssertEqual(t, settings.maxUnavailable.String(), "1")
+	util.AssertEqual(t, settings.priorityClassName, "")
+	util.AssertEqual(t, settings.safeToEvict, "false")
+}
+
+func TestSafeToEvict(t *testing.T) {
+	controller := util.MakeUnstructured(t, util.MakeDeployment("tekton-pipelines-controller", corev1.PodSpec{}))
+	dashboard := util.MakeUnstructured(t, util.MakeDeployment("tekton-dashboard", corev1.PodSpec{}))
+	for _, u := range []*unstructured.Unstructured{&controller, &dashboard} {
+		util.AssertNoError(t, SafeToEvict("true")(u))
+	}
+	got, _, _ := unstructured.NestedStringMap(controller.Object, "spec", "template", "metadata", "annotations")
+	util.AssertDeepEqual(t, got, map[string]string{annotationSafeToEvict: "true"})
+	_, found, _ := unstructured.NestedStringMap(dashboard.Object, "spec", "template", "metadata", "annotations")
+	util.AssertEqual(t, found, false)
+
+	util.AssertNoError(t, SafeToEvict("")(&controller))
+	got, _, _ = unstructured.NestedStringMap(controller.Object, "spec", "template", "metadata", "annotations")
+	util.AssertDeepEqual(t, got, map[string]string{annotationSafeToEvict: "true"})
+}
+
+func TestPodDisruptionBudgetsOfPolicy(t *testing.T) {
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
+		util.MakeUnstructured(t, util.MakeDeployment("tekton-pipelines-controller", corev1.PodSpec{})),
+	}))
+	util.AssertNoError(t, err)
+
+	instance := &v1alpha1.TektonPipeline{}
+	instance.Spec.DisruptionPolicy = &v1alpha1.DisruptionPolicy{}
+	util.AssertNoError(t, PodDisruptionBudgets(context.Background(), &manifest, instance))
+	pdbs := manifest.Filter(mf.ByKind("PodDisruptionBudget")).Resources()
+	util.AssertEqual(t, len(pdbs), 1)
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	util.AssertNoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(pdbs[0].Object, pdb))
+	util.AssertEqual(t, pdb.Spec.MinAvailable == nil, true)
+	util.AssertEqual(t, pdb.Spec.MaxUnavailable.String(), "1")
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"golang.org/x/mod/semver"
+	"knative.dev/pkg/logging"
+)
+
+// BlockDowngrades stops the reconcile before anything is installed when
+// the target payload is older than the installed one, e.g. once the
+// operator was rolled back, as the older payload may not serve the stored
+// versions of its resources. The install fails with the DowngradeBlocked
+// condition set, unless spec.allowDowngrade is set. Versions which aren't
+// semantic, e.g. devel, are never considered older.
+func BlockDowngrades(ctx context.Context, _ *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	status := instance.GetStatus()
+	installed, target := status.GetVersion(), TargetVersion(instance)
+	if !isDowngrade(installed, target) {
+		status.ClearDowngradeBlocked()
+		return nil
+	}
+	if instance.GetSpec().GetAllowDowngrade() {
+		logging.FromContext(ctx).Warnw("Downgrading the payload", "installed", installed, "target", target)
+		status.ClearDowngradeBlocked()
+		return nil
+	}
+	msg := fmt.Sprintf("the payload %s is older than the installed %s, set spec.allowDowngrade to install it", target, installed)
+	status.MarkDowngradeBlocked(msg)
+	status.MarkInstallFailedWithReason(v1alpha1.ReasonDowngradeBlocked, msg)
+	return fmt.Errorf("downgrade blocked: %s", msg)
+}
+
+// isDowngrade tells whether the target version is older than the installed
+// one, both being semantic versions with or without their v prefix.
+func isDowngrade(installed, target string) bool {
+	canonical := func(version string) string {
+		if !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+		return version
+	}
+	installed, target = canonical(installed), canonical(target)
+	if !semver.IsValid(installed) || !semver.IsValid(target) {
+		return false
+	}
+	return semver.Compare(target, installed) < 0
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+)
+
+func TestIsDowngrade(t *testing.T) {
+	for _, test := range []struct {
+		installed, target string
+		downgrade         bool
+	}{
+		{"0.22.0", "0.21.0", true},
+		{"v0.22.0", "0.21.3", true},
+		{"0.22.0", "0.22.0", false},
+		{"0.21.0", "0.22.0", false},
+		{"", "0.22.0", false},
+		{"devel", "0.22.0", false},
+		{"0.22.0", "devel", false},
+	} {
+		util.AssertEqual(t, isDowngrade(test.installed, test.target), test.downgrade)
+	}
+}
+
+func TestBlockDowngrades(t *testing.T) {
+	os.Setenv(KoEnvKey, "testdata/kodata")
+	defer os.Unsetenv(KoEnvKey)
+	instance := &v1alpha1.TektonPipeline{}
+	instance.Status.InitializeConditions()
+	target := TargetVersion(instance)
+
+	// the payload isn't older
+	instance.Status.SetVersion(target)
+	util.AssertNoError(t, BlockDowngrades(context.Background(), nil, instance))
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.DowngradeBlocked) == nil, true)
+
+	instance.Status.SetVersion("99.0.0")
+	if err := BlockDowngrades(context.Background(), nil, instance); err == nil {
+		t.Error("expected the downgrade to be blocked")
+	}
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.DowngradeBlocked).IsTrue(), true)
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).Reason, v1alpha1.ReasonDowngradeBlocked)
+	util.AssertEqual(t, instance.Status.IsReady(), false)
+
+	// allowing the downgrade lifts the block
+	instance.Spec.AllowDowngrade = true
+	util.AssertNoError(t, BlockDowngrades(context.Background(), nil, instance))
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.DowngradeBlocked) == nil, true)
+}
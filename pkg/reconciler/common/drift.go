@@ -0,0 +1,48 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common/managed"
+	clientcache "k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/controller"
+)
+
+// EnqueueOwned returns an event handler enqueuing the component of the
+// given kind controlling the resource changed.
+func EnqueueOwned(enqueueControllerOf func(interface{}), kind string) clientcache.ResourceEventHandler {
+	return clientcache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterControllerGVK(v1alpha1.SchemeGroupVersion.WithKind(kind)),
+		Handler:    controller.HandleAll(enqueueControllerOf),
+	}
+}
+
+// WatchOwned enqueues the component of the given kind whenever one of the
+// payload resources it controls, beside its deployments, is edited or
+// deleted out of band, for SkipUnchanged to find the drift and apply the
+// manifest again. The informers, see package managed, are shared by all
+// the components and started by the injection. Resources of remote
+// clusters have no owner and aren't watched.
+func WatchOwned(ctx context.Context, kind string, enqueueControllerOf func(interface{})) {
+	handler := EnqueueOwned(enqueueControllerOf, kind)
+	for _, informer := range managed.Informers(ctx) {
+		informer.AddEventHandler(handler)
+	}
+}
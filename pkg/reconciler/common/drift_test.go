@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEnqueueOwned(t *testing.T) {
+	var enqueued []string
+	handler := EnqueueOwned(func(obj interface{}) {
+		enqueued = append(enqueued, obj.(metav1.Object).GetName())
+	}, v1alpha1.KindTektonPipeline)
+
+	pipeline := &v1alpha1.TektonPipeline{ObjectMeta: metav1.ObjectMeta{Name: "pipeline"}}
+	trigger := &v1alpha1.TektonTrigger{ObjectMeta: metav1.ObjectMeta{Name: "trigger"}}
+	owned := func(name string, owner metav1.Object, kind string) *corev1.Service {
+		service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if owner != nil {
+			service.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(owner, v1alpha1.SchemeGroupVersion.WithKind(kind))}
+		}
+		return service
+	}
+
+	handler.OnDelete(owned("tekton-pipelines-webhook", pipeline, v1alpha1.KindTektonPipeline))
+	handler.OnUpdate(owned("tekton-pipelines-controller", pipeline, v1alpha1.KindTektonPipeline), owned("tekton-pipelines-controller", pipeline, v1alpha1.KindTektonPipeline))
+	handler.OnDelete(owned("tekton-triggers-webhook", trigger, v1alpha1.KindTektonTrigger))
+	handler.OnDelete(owned("kubernetes", nil, ""))
+	util.AssertDeepEqual(t, enqueued, []string{"tekton-pipelines-webhook", "tekton-pipelines-controller"})
+}
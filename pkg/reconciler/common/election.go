@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
+	knleaderelection "knative.dev/pkg/leaderelection"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// LeaseNamespaceEnvKey is the environment variable of the operator
+	// setting the namespace of the Lease electing its leader, the namespace
+	// of the operator by default.
+	LeaseNamespaceEnvKey = "LEADER_ELECTION_NAMESPACE"
+	// LeaseNameEnvKey is the environment variable of the operator setting
+	// the name of the Lease electing its leader.
+	LeaseNameEnvKey = "LEADER_ELECTION_NAME"
+	// LeaseDurationEnvKey is the environment variable of the operator
+	// setting how long the replicas wait for the leader to renew the Lease
+	// before taking it over.
+	LeaseDurationEnvKey = "LEADER_ELECTION_LEASE_DURATION"
+	// RenewDeadlineEnvKey is the environment variable of the operator
+	// setting how long the leader retries renewing the Lease before
+	// stepping down.
+	RenewDeadlineEnvKey = "LEADER_ELECTION_RENEW_DEADLINE"
+	// RetryPeriodEnvKey is the environment variable of the operator setting
+	// the period of the attempts to acquire or renew the Lease.
+	RetryPeriodEnvKey = "LEADER_ELECTION_RETRY_PERIOD"
+
+	defaultLeaseName = "tekton-operator-lock"
+	// the defaults are those of knative
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewDeadline = 10 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+)
+
+// OperatorElection is the leader election of the replicas of the operator,
+// only the leader reconciling while the others stand by, ready to take over
+// the Lease once the leader stops renewing it.
+type OperatorElection struct {
+	Namespace     string
+	Name          string
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// OperatorElectionFromEnv reads the OperatorElection of the operator from
+// its environment, the variables which aren't set keeping the defaults.
+func OperatorElectionFromEnv() (OperatorElection, error) {
+	return operatorElectionFrom(os.Getenv)
+}
+
+func operatorElectionFrom(getenv func(string) string) (OperatorElection, error) {
+	election := OperatorElection{
+		Namespace:     getenv(LeaseNamespaceEnvKey),
+		Name:          getenv(LeaseNameEnvKey),
+		LeaseDuration: defaultLeaseDuration,
+		RenewDeadline: defaultRenewDeadline,
+		RetryPeriod:   defaultRetryPeriod,
+	}
+	if election.Namespace == "" {
+		election.Namespace = getenv(system.NamespaceEnvKey)
+	}
+	if election.Namespace == "" {
+		return OperatorElection{}, fmt.Errorf("neither %s nor %s is set", LeaseNamespaceEnvKey, system.NamespaceEnvKey)
+	}
+	if election.Name == "" {
+		election.Name = defaultLeaseName
+	}
+	if err := durationsFrom(getenv, map[string]*time.Duration{
+		LeaseDurationEnvKey: &election.LeaseDuration,
+		RenewDeadlineEnvKey: &election.RenewDeadline,
+		RetryPeriodEnvKey:   &election.RetryPeriod,
+	}); err != nil {
+		return OperatorElection{}, err
+	}
+	if election.LeaseDuration <= election.RenewDeadline || election.RenewDeadline <= election.RetryPeriod {
+		return OperatorElection{}, fmt.Errorf("%s %s, %s %s and %s %s must be decreasing",
+			LeaseDurationEnvKey, election.LeaseDuration, RenewDeadlineEnvKey, election.RenewDeadline,
+			RetryPeriodEnvKey, election.RetryPeriod)
+	}
+	return election, nil
+}
+
+// Run campaigns for the Lease of e once the caches of the controllers on
+// standby synced, the controllers reconciling while the replica holds it.
+// A replica losing the Lease stands by again, its caches still warm, and
+// campaigns for it again. The leadership transitions are recorded as
+// events of the Lease. Run returns once ctx is done, releasing the Lease.
+func (e OperatorElection) Run(ctx context.Context, cfg *rest.Config, standby *Standby) error {
+	logger := logging.FromContext(ctx)
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	identity, err := knleaderelection.UniqueID()
+	if err != nil {
+		return err
+	}
+	broadcaster := record.NewBroadcaster()
+	defer broadcaster.Shutdown()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events(e.Namespace)})
+	lock, err := resourcelock.New(resourcelock.LeasesResourceLock, e.Namespace, e.Name,
+		kubeClient.CoreV1(), kubeClient.CoordinationV1(), resourcelock.ResourceLockConfig{
+			Identity:      identity,
+			EventRecorder: broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "tekton-operator"}),
+		})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-standby.Ready():
+	case <-ctx.Done():
+		return nil
+	}
+	for ctx.Err() == nil {
+		leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			Name:            e.Name,
+			LeaseDuration:   e.LeaseDuration,
+			RenewDeadline:   e.RenewDeadline,
+			RetryPeriod:     e.RetryPeriod,
+			ReleaseOnCancel: true,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: standby.Lead,
+				OnStoppedLeading: func() { standby.StandBy(ctx) },
+			},
+		})
+		if ctx.Err() == nil {
+			logger.Warnw("Lost the Lease, standing by", "lease", e.Namespace+"/"+e.Name)
+		}
+	}
+	return nil
+}
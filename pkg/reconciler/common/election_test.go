@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"knative.dev/pkg/system"
+)
+
+func TestOperatorElectionFrom(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	election, err := operatorElectionFrom(env(map[string]string{system.NamespaceEnvKey: "tekton-operator"}))
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, election, OperatorElection{
+		Namespace:     "tekton-operator",
+		Name:          "tekton-operator-lock",
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   2 * time.Second,
+	})
+
+	election, err = operatorElectionFrom(env(map[string]string{
+		system.NamespaceEnvKey: "tekton-operator",
+		LeaseNamespaceEnvKey:   "kube-system",
+		LeaseNameEnvKey:        "tekton-operator-leader",
+		LeaseDurationEnvKey:    "30s",
+		RenewDeadlineEnvKey:    "20s",
+		RetryPeriodEnvKey:      "5s",
+	}))
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, election, OperatorElection{
+		Namespace:     "kube-system",
+		Name:          "tekton-operator-leader",
+		LeaseDuration: 30 * time.Second,
+		RenewDeadline: 20 * time.Second,
+		RetryPeriod:   5 * time.Second,
+	})
+
+	for _, vars := range []map[string]string{
+		{},
+		{system.NamespaceEnvKey: "tekton-operator", LeaseDurationEnvKey: "soon"},
+		{system.NamespaceEnvKey: "tekton-operator", LeaseDurationEnvKey: "10s"},
+		{system.NamespaceEnvKey: "tekton-operator", RetryPeriodEnvKey: "10s"},
+	} {
+		if _, err := operatorElectionFrom(env(vars)); err == nil {
+			t.Errorf("expected an error for %v", vars)
+		}
+	}
+}
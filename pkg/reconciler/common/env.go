@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ContainerEnv returns a transformer setting the environment variables of
+// the overrides on the containers of the payload deployments they name,
+// replacing the variables of the same name, including the proxy ones.
+func ContainerEnv(overrides []v1alpha1.EnvOverride) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if len(overrides) == 0 || u.GetKind() != "Deployment" {
+			return nil
+		}
+		containers, found, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "containers")
+		if err != nil || !found {
+			return err
+		}
+		changed := false
+		for _, override := range overrides {
+			if override.Deployment != u.GetName() {
+				continue
+			}
+			for _, c := range containers {
+				container := c.(map[string]interface{})
+				if override.Container != "" && container["name"] != override.Container {
+					continue
+				}
+				envs, err := extractEnvs(container)
+				if err != nil {
+					return err
+				}
+				for i := range override.Env {
+					env, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&override.Env[i])
+					if err != nil {
+						return err
+					}
+					envs[override.Env[i].Name] = env
+				}
+				if err := unstructured.SetNestedSlice(container, toUnstructured(envs), "env"); err != nil {
+					return err
+				}
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+		return unstructured.SetNestedSlice(u.Object, containers, "spec", "template", "spec", "containers")
+	}
+}
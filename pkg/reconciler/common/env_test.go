@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestContainerEnv(t *testing.T) {
+	u := util.MakeUnstructured(t, util.MakeDeployment("tekton-pipelines-controller", corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:  "controller",
+			Image: "gcr.io/controller:v1",
+			Env:   []corev1.EnvVar{{Name: "SYSTEM_NAMESPACE", Value: "tekton-pipelines"}, {Name: "GODEBUG", Value: "x509ignoreCN=0"}},
+		}, {
+			Name:  "sidecar",
+			Image: "gcr.io/sidecar:v1",
+		}},
+	}))
+	credentials := corev1.EnvVar{
+		Name: "AWS_ACCESS_KEY_ID",
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "aws"},
+			Key:                  "id",
+		}},
+	}
+	transformer := ContainerEnv([]v1alpha1.EnvOverride{{
+		Deployment: "tekton-pipelines-controller",
+		Container:  "controller",
+		Env:        []corev1.EnvVar{{Name: "GODEBUG", Value: "http2client=0"}, credentials},
+	}, {
+		Deployment: "tekton-pipelines-controller",
+		Env:        []corev1.EnvVar{{Name: "FEATURE", Value: "on"}},
+	}, {
+		Deployment: "tekton-pipelines-webhook",
+		Env:        []corev1.EnvVar{{Name: "WEBHOOK", Value: "on"}},
+	}})
+	util.AssertNoError(t, transformer(&u))
+
+	deployment := &appsv1.Deployment{}
+	util.AssertNoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, deployment))
+	containers := deployment.Spec.Template.Spec.Containers
+	util.AssertDeepEqual(t, containers[0].Env, []corev1.EnvVar{
+		credentials,
+		{Name: "FEATURE", Value: "on"},
+		{Name: "GODEBUG", Value: "http2client=0"},
+		{Name: "SYSTEM_NAMESPACE", Value: "tekton-pipelines"},
+	})
+	util.AssertDeepEqual(t, containers[1].Env, []corev1.EnvVar{{Name: "FEATURE", Value: "on"}})
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
+)
+
+// ComponentFailedReason is the reason of the events recorded on a parent
+// for a failing child component of the given kind, e.g.
+// TektonPipelineFailed.
+func ComponentFailedReason(kind string) string {
+	return kind + "Failed"
+}
+
+// recordedFailures holds the failures last recorded on a parent for a
+// child kind, keyed by the UID of the parent and the kind, so that the
+// reconciles of a parent whose child keeps failing the same way don't
+// record the same events over and over. Parents are reconciled
+// concurrently, hence the lock.
+var recordedFailures = struct {
+	sync.Mutex
+	byKey map[string]string
+}{byKey: map[string]string{}}
+
+// RecordComponentFailures records a warning event on the parent for each
+// failed condition of a child component, so that watching the parent is
+// enough to catch the failures of its children. The failures recorded last
+// for the kind aren't recorded again until they change. It is a no-op
+// without an event recorder in the context.
+func RecordComponentFailures(ctx context.Context, parent runtime.Object, kind string, conditions duckv1.Conditions) {
+	recorder := controller.GetEventRecorder(ctx)
+	if recorder == nil {
+		return
+	}
+	var failures []string
+	for _, c := range conditions {
+		// Ready only summarizes the other conditions
+		if c.Type == apis.ConditionReady || !c.IsFalse() {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("%s: %s %s", c.Type, c.Reason, c.Message))
+	}
+	if !failuresChanged(parent, kind, failures) {
+		return
+	}
+	for _, failure := range failures {
+		recorder.Event(parent, corev1.EventTypeWarning, ComponentFailedReason(kind), failure)
+	}
+}
+
+// failuresChanged records the failures of the kind on the parent, returning
+// false if they are the ones recorded last.
+func failuresChanged(parent runtime.Object, kind string, failures []string) bool {
+	key := kind
+	if accessor, err := meta.Accessor(parent); err == nil {
+		key = string(accessor.GetUID()) + "/" + kind
+	}
+	joined := strings.Join(failures, "\n")
+	recordedFailures.Lock()
+	defer recordedFailures.Unlock()
+	if recordedFailures.byKey[key] == joined {
+		return false
+	}
+	if joined == "" {
+		delete(recordedFailures.byKey, key)
+	} else {
+		recordedFailures.byKey[key] = joined
+	}
+	return true
+}
+
+// ForgetComponentFailures drops the failures recorded on the parent for all
+// its child kinds, once the parent is deleted.
+func ForgetComponentFailures(parent runtime.Object) {
+	accessor, err := meta.Accessor(parent)
+	if err != nil {
+		return
+	}
+	prefix := string(accessor.GetUID()) + "/"
+	recordedFailures.Lock()
+	defer recordedFailures.Unlock()
+	for key := range recordedFailures.byKey {
+		if strings.HasPrefix(key, prefix) {
+			delete(recordedFailures.byKey, key)
+		}
+	}
+}
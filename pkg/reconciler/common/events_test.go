@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/controller"
+)
+
+func TestRecordComponentFailures(t *testing.T) {
+	tp := &v1alpha1.TektonPipeline{}
+	tp.Status.InitializeConditions()
+	tp.Status.MarkInstallFailed("webhook unreachable")
+
+	// No recorder, nothing to do
+	RecordComponentFailures(context.Background(), &v1alpha1.TektonConfig{}, "TektonPipeline", tp.Status.Conditions)
+
+	recorder := record.NewFakeRecorder(10)
+	ctx := controller.WithEventRecorder(context.Background(), recorder)
+	RecordComponentFailures(ctx, &v1alpha1.TektonConfig{}, "TektonPipeline", tp.Status.Conditions)
+
+	util.AssertEqual(t, len(recorder.Events), 1)
+	util.AssertEqual(t, <-recorder.Events, "Warning TektonPipelineFailed InstallSucceeded: Error Install failed with message: webhook unreachable")
+}
+
+func TestRecordComponentFailuresOnce(t *testing.T) {
+	tp := &v1alpha1.TektonPipeline{}
+	tp.Status.InitializeConditions()
+	tp.Status.MarkInstallFailed("webhook unreachable")
+	tc := &v1alpha1.TektonConfig{}
+	tc.SetUID("config-uid")
+
+	recorder := record.NewFakeRecorder(10)
+	ctx := controller.WithEventRecorder(context.Background(), recorder)
+	RecordComponentFailures(ctx, tc, "TektonPipeline", tp.Status.Conditions)
+	RecordComponentFailures(ctx, tc, "TektonPipeline", tp.Status.Conditions)
+	util.AssertEqual(t, len(recorder.Events), 1)
+	<-recorder.Events
+
+	// recorded again once it changed, or failed again after recovering
+	tp.Status.MarkInstallFailed("webhook timed out")
+	RecordComponentFailures(ctx, tc, "TektonPipeline", tp.Status.Conditions)
+	util.AssertEqual(t, <-recorder.Events, "Warning TektonPipelineFailed InstallSucceeded: Error Install failed with message: webhook timed out")
+	tp.Status.MarkInstallSucceeded()
+	RecordComponentFailures(ctx, tc, "TektonPipeline", tp.Status.Conditions)
+	tp.Status.MarkInstallFailed("webhook timed out")
+	RecordComponentFailures(ctx, tc, "TektonPipeline", tp.Status.Conditions)
+	util.AssertEqual(t, len(recorder.Events), 1)
+}
+
+func TestForgetComponentFailures(t *testing.T) {
+	tp := &v1alpha1.TektonPipeline{}
+	tp.Status.InitializeConditions()
+	tp.Status.MarkInstallFailed("webhook unreachable")
+	tc := &v1alpha1.TektonConfig{}
+	tc.SetUID("deleted-uid")
+	other := &v1alpha1.TektonConfig{}
+	other.SetUID("other-uid")
+
+	recorder := record.NewFakeRecorder(10)
+	ctx := controller.WithEventRecorder(context.Background(), recorder)
+	RecordComponentFailures(ctx, tc, "TektonPipeline", tp.Status.Conditions)
+	RecordComponentFailures(ctx, tc, "TektonTrigger", tp.Status.Conditions)
+	RecordComponentFailures(ctx, other, "TektonPipeline", tp.Status.Conditions)
+	ForgetComponentFailures(tc)
+
+	recordedFailures.Lock()
+	defer recordedFailures.Unlock()
+	for key := range recordedFailures.byKey {
+		if strings.HasPrefix(key, "deleted-uid/") {
+			t.Errorf("failures of %s weren't forgotten", key)
+		}
+	}
+	if _, ok := recordedFailures.byKey["other-uid/TektonPipeline"]; !ok {
+		t.Error("failures of another parent were forgotten")
+	}
+}
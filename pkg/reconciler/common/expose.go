@@ -0,0 +1,324 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common/managed"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// External-dns annotations of the objects exposing a service
+const (
+	externalDNSHostname = "external-dns.alpha.kubernetes.io/hostname"
+	externalDNSTTL      = "external-dns.alpha.kubernetes.io/ttl"
+	externalDNSTarget   = "external-dns.alpha.kubernetes.io/target"
+)
+
+// ExposeService appends the Ingress or the Route of expose, set in the
+// field of the spec, pointing at the Service of the manifest called name.
+// The object goes through the common transformers for it to be namespaced
+// and owned like the payload. The object of the other kind, or both when
+// expose is nil, are deleted if the operator generated them, for
+// external-dns to remove their records.
+func ExposeService(ctx context.Context, kubeClient kubernetes.Interface, manifest *mf.Manifest, instance v1alpha1.TektonComponent,
+	expose *v1alpha1.ServiceExpose, name, field string) error {
+	services := manifest.Filter(mf.ByKind("Service"), mf.ByName(name)).Resources()
+	if expose == nil {
+		if len(services) == 0 {
+			return nil
+		}
+		return deleteExposed(ctx, manifest, instance, services[0].GetNamespace(), name, "")
+	}
+	if len(services) == 0 {
+		err := &operrors.TransformError{Err: fmt.Errorf("the payload has no %s Service to expose", name)}
+		operrors.MarkFailed(instance.GetStatus(), err)
+		return err
+	}
+	service := &corev1.Service{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(services[0].Object, service); err != nil {
+		return err
+	}
+	if len(service.Spec.Ports) == 0 {
+		err := &operrors.TransformError{Err: fmt.Errorf("the %s Service has no port to expose", name)}
+		operrors.MarkFailed(instance.GetStatus(), err)
+		return err
+	}
+
+	host, err := ExposedHost(expose, service.Name, service.Namespace)
+	if err != nil {
+		err = &operrors.TransformError{Err: fmt.Errorf("invalid %s.hostTemplate: %w", field, err)}
+		operrors.MarkFailed(instance.GetStatus(), err)
+		return err
+	}
+	if expose.ExternalDNS != nil && host == "" {
+		err := &operrors.TransformError{Err: fmt.Errorf("%s.externalDNS requires a host or a hostTemplate", field)}
+		operrors.MarkFailed(instance.GetStatus(), err)
+		return err
+	}
+
+	var object unstructured.Unstructured
+	switch expose.Type {
+	case v1alpha1.ExposeIngress:
+		object, err = exposeIngress(expose, host, service)
+	case v1alpha1.ExposeRoute:
+		var tls *corev1.Secret
+		if expose.TLSSecret != "" {
+			tls, err = kubeClient.CoreV1().Secrets(service.Namespace).Get(ctx, expose.TLSSecret, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				err = &operrors.DependencyMissing{Err: fmt.Errorf("secret %s/%s of %s.tlsSecret not found", service.Namespace, expose.TLSSecret, field)}
+				operrors.MarkFailed(instance.GetStatus(), err)
+				return err
+			}
+			if err != nil {
+				return err
+			}
+		}
+		object = exposeRoute(expose, host, service, tls)
+	default:
+		err = &operrors.TransformError{Err: fmt.Errorf("invalid %s.type %q, expected %s or %s",
+			field, expose.Type, v1alpha1.ExposeIngress, v1alpha1.ExposeRoute)}
+		operrors.MarkFailed(instance.GetStatus(), err)
+	}
+	if err != nil {
+		return err
+	}
+	if err := deleteExposed(ctx, manifest, instance, service.Namespace, service.Name, expose.Type); err != nil {
+		return err
+	}
+
+	exposed, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{object}))
+	if err != nil {
+		return err
+	}
+	if err := Transform(ctx, &exposed, instance); err != nil {
+		return err
+	}
+	*manifest = manifest.Append(exposed)
+	return nil
+}
+
+// exposedLabels returns the labels of the object exposing the service:
+// generated, and part of the same application as the service
+func exposedLabels(service *corev1.Service) map[string]string {
+	labels := map[string]string{LabelGenerated: "true"}
+	if partOf, ok := service.Labels["app.kubernetes.io/part-of"]; ok {
+		labels["app.kubernetes.io/part-of"] = partOf
+	}
+	return labels
+}
+
+// ExposedHost returns the host expose serves the service of the namespace
+// called name at, empty when neither a host nor a template is set.
+func ExposedHost(expose *v1alpha1.ServiceExpose, name, namespace string) (string, error) {
+	if expose.Host != "" || expose.HostTemplate == "" {
+		return expose.Host, nil
+	}
+	tmpl, err := template.New("host").Option("missingkey=error").Parse(expose.HostTemplate)
+	if err != nil {
+		return "", err
+	}
+	var host strings.Builder
+	if err := tmpl.Execute(&host, struct{ Name, Namespace string }{name, namespace}); err != nil {
+		return "", err
+	}
+	return host.String(), nil
+}
+
+// exposedMeta returns the metadata of the object exposing the service at
+// host, annotated for external-dns when enabled
+func exposedMeta(expose *v1alpha1.ServiceExpose, host string, service *corev1.Service) metav1.ObjectMeta {
+	annotations := expose.Annotations
+	if dns := expose.ExternalDNS; dns != nil {
+		annotations = map[string]string{}
+		for k, v := range expose.Annotations {
+			annotations[k] = v
+		}
+		annotations[externalDNSHostname] = host
+		if dns.TTL != nil {
+			annotations[externalDNSTTL] = strconv.FormatInt(*dns.TTL, 10)
+		}
+		if dns.Target != "" {
+			annotations[externalDNSTarget] = dns.Target
+		}
+	}
+	return metav1.ObjectMeta{
+		Name:        service.Name,
+		Namespace:   service.Namespace,
+		Labels:      exposedLabels(service),
+		Annotations: annotations,
+	}
+}
+
+// exposeIngress returns the Ingress of the service, with TLS when a
+// secret is given.
+func exposeIngress(expose *v1alpha1.ServiceExpose, host string, service *corev1.Service) (unstructured.Unstructured, error) {
+	ingress := &networkingv1beta1.Ingress{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1beta1", Kind: "Ingress"},
+		ObjectMeta: exposedMeta(expose, host, service),
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{{
+				Host: host,
+				IngressRuleValue: networkingv1beta1.IngressRuleValue{
+					HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+						Paths: []networkingv1beta1.HTTPIngressPath{{
+							Path: "/",
+							Backend: networkingv1beta1.IngressBackend{
+								ServiceName: service.Name,
+								ServicePort: intstr.FromInt(int(service.Spec.Ports[0].Port)),
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+	if expose.TLSSecret != "" {
+		tls := networkingv1beta1.IngressTLS{SecretName: expose.TLSSecret}
+		if host != "" {
+			tls.Hosts = []string{host}
+		}
+		ingress.Spec.TLS = []networkingv1beta1.IngressTLS{tls}
+	}
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ingress)
+	if err != nil {
+		return unstructured.Unstructured{}, err
+	}
+	u := unstructured.Unstructured{Object: object}
+	// the converter sets the empty status and creation timestamp
+	unstructured.RemoveNestedField(u.Object, "status")
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	return u, nil
+}
+
+// exposeRoute returns the edge terminated Route of the service, with the
+// certificate of the secret when given.
+func exposeRoute(expose *v1alpha1.ServiceExpose, host string, service *corev1.Service, secret *corev1.Secret) unstructured.Unstructured {
+	objectMeta := exposedMeta(expose, host, service)
+	u := unstructured.Unstructured{}
+	u.SetAPIVersion("route.openshift.io/v1")
+	u.SetKind("Route")
+	u.SetName(objectMeta.Name)
+	u.SetNamespace(objectMeta.Namespace)
+	u.SetLabels(objectMeta.Labels)
+	u.SetAnnotations(objectMeta.Annotations)
+
+	port := service.Spec.Ports[0]
+	targetPort := interface{}(port.Name)
+	if port.Name == "" {
+		targetPort = int64(port.Port)
+	}
+	tls := map[string]interface{}{
+		"termination":                   "edge",
+		"insecureEdgeTerminationPolicy": "Redirect",
+	}
+	if secret != nil {
+		tls["certificate"] = string(secret.Data[corev1.TLSCertKey])
+		tls["key"] = string(secret.Data[corev1.TLSPrivateKeyKey])
+	}
+	spec := map[string]interface{}{
+		"to": map[string]interface{}{
+			"kind":   "Service",
+			"name":   service.Name,
+			"weight": int64(100),
+		},
+		"port": map[string]interface{}{"targetPort": targetPort},
+		"tls":  tls,
+	}
+	if host != "" {
+		spec["host"] = host
+	}
+	u.Object["spec"] = spec
+	return u
+}
+
+// exposedResources are the resources of the kinds of objects exposing a
+// service
+var exposedResources = map[string]schema.GroupVersionResource{
+	v1alpha1.ExposeIngress: {Group: "networking.k8s.io", Version: "v1beta1", Resource: "ingresses"},
+	v1alpha1.ExposeRoute:   {Group: "route.openshift.io", Version: "v1", Resource: "routes"},
+}
+
+// deleteExposed deletes the Ingress and the Route the operator generated
+// for the service of the namespace called name, but the one of kind keep.
+// Kinds the cluster doesn't serve, e.g. Routes out of OpenShift, are
+// skipped.
+func deleteExposed(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent, namespace, name, keep string) error {
+	if manifest.Client == nil {
+		return nil
+	}
+	for kind, gvr := range exposedResources {
+		if kind == keep {
+			continue
+		}
+		live, err := getExposed(ctx, manifest, instance, gvr, kind, namespace, name)
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) || (err == nil && live == nil) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if live.GetLabels()[LabelGenerated] != "true" {
+			continue
+		}
+		if err := manifest.Client.Delete(live); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete %s %s/%s: %w", kind, namespace, name, err)
+		}
+	}
+	return nil
+}
+
+// getExposed returns the object of the kind exposing the service, from the
+// informer of the labeled resources on the local cluster, or else from the
+// client of the manifest. It returns nil if the cluster doesn't serve the
+// kind.
+func getExposed(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent,
+	gvr schema.GroupVersionResource, kind, namespace, name string) (*unstructured.Unstructured, error) {
+	if !isRemote(instance) {
+		lister, ok, err := managed.Lister(ctx, gvr)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			object, err := lister.ByNamespace(namespace).Get(name)
+			if err != nil {
+				return nil, err
+			}
+			live, _ := object.(*unstructured.Unstructured)
+			return live.DeepCopy(), nil
+		}
+	}
+	u := namespacedResource(gvr.GroupVersion().String(), kind, namespace, name)
+	return manifest.Client.Get(&u)
+}
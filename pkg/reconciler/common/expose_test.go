@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	fake "github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestExposeRoute(t *testing.T) {
+	expose := &v1alpha1.ServiceExpose{Type: v1alpha1.ExposeRoute}
+	secret := &corev1.Secret{Data: map[string][]byte{
+		corev1.TLSCertKey:       []byte("cert"),
+		corev1.TLSPrivateKeyKey: []byte("key"),
+	}}
+	route := exposeRoute(expose, "", &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "tekton-dashboard", Namespace: "tekton-pipelines"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 9097}}},
+	}, secret)
+	util.AssertEqual(t, route.GetKind(), "Route")
+	spec, _, _ := unstructured.NestedMap(route.Object, "spec")
+	util.AssertDeepEqual(t, spec, map[string]interface{}{
+		"to":   map[string]interface{}{"kind": "Service", "name": "tekton-dashboard", "weight": int64(100)},
+		"port": map[string]interface{}{"targetPort": "http"},
+		"tls": map[string]interface{}{
+			"termination":                   "edge",
+			"insecureEdgeTerminationPolicy": "Redirect",
+			"certificate":                   "cert",
+			"key":                           "key",
+		},
+	})
+}
+
+func TestExposedHost(t *testing.T) {
+	expose := &v1alpha1.ServiceExpose{Type: v1alpha1.ExposeIngress}
+	host, err := ExposedHost(expose, "tekton-dashboard", "tekton-pipelines")
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, host, "")
+
+	expose.HostTemplate = "{{.Name}}.{{.Namespace}}.apps.example.com"
+	host, err = ExposedHost(expose, "tekton-dashboard", "tekton-pipelines")
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, host, "tekton-dashboard.tekton-pipelines.apps.example.com")
+
+	// the host takes precedence over the template
+	expose.Host = "dashboard.example.com"
+	host, err = ExposedHost(expose, "tekton-dashboard", "tekton-pipelines")
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, host, "dashboard.example.com")
+
+	expose.Host = ""
+	expose.HostTemplate = "{{.Cluster}}.example.com"
+	if _, err := ExposedHost(expose, "tekton-dashboard", "tekton-pipelines"); err == nil {
+		t.Error("expected an error for a template with an unknown field")
+	}
+}
+
+func TestExposeServiceExternalDNS(t *testing.T) {
+	service, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Name: "tekton-dashboard", Namespace: "tekton-pipelines"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 9097}}},
+	})
+	util.AssertNoError(t, err)
+	route := namespacedResource("route.openshift.io/v1", "Route", "tekton-pipelines", "tekton-dashboard")
+	route.SetLabels(map[string]string{LabelGenerated: "true"})
+	client := fake.New(&route)
+	payload, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{{Object: service}}), mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	ttl := int64(60)
+	instance := &v1alpha1.TektonDashboard{}
+	instance.Spec.TargetNamespace = "tekton-pipelines"
+	expose := &v1alpha1.ServiceExpose{
+		Type:         v1alpha1.ExposeIngress,
+		HostTemplate: "{{.Name}}.apps.example.com",
+		Annotations:  map[string]string{"kubernetes.io/ingress.class": "nginx"},
+		ExternalDNS:  &v1alpha1.ExternalDNS{TTL: &ttl},
+	}
+	manifest := payload.Append()
+	util.AssertNoError(t, ExposeService(context.Background(), nil, &manifest, instance, expose, "tekton-dashboard", "spec.expose"))
+	ingresses := manifest.Filter(mf.ByKind("Ingress")).Resources()
+	util.AssertEqual(t, len(ingresses), 1)
+	util.AssertDeepEqual(t, ingresses[0].GetAnnotations(), map[string]string{
+		"kubernetes.io/ingress.class":               "nginx",
+		"external-dns.alpha.kubernetes.io/hostname": "tekton-dashboard.apps.example.com",
+		"external-dns.alpha.kubernetes.io/ttl":      "60",
+	})
+	// the Route generated before the switch to an Ingress is deleted
+	if _, err := client.Get(&route); err == nil {
+		t.Error("expected the generated Route to be deleted")
+	}
+
+	// without a host, there is no record to create
+	expose.HostTemplate = ""
+	instance.Status.InitializeConditions()
+	manifest = payload.Append()
+	if err := ExposeService(context.Background(), nil, &manifest, instance, expose, "tekton-dashboard", "spec.expose"); err == nil {
+		t.Error("expected an error for spec.expose.externalDNS without host")
+	}
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).Reason, v1alpha1.ReasonTransformError)
+
+	// the generated Ingress is deleted once spec.expose is unset, but not
+	// one of the user
+	util.AssertNoError(t, client.Create(&ingresses[0]))
+	manifest = payload.Append()
+	util.AssertNoError(t, ExposeService(context.Background(), nil, &manifest, instance, nil, "tekton-dashboard", "spec.expose"))
+	if _, err := client.Get(&ingresses[0]); err == nil {
+		t.Error("expected the generated Ingress to be deleted")
+	}
+	ingresses[0].SetLabels(nil)
+	util.AssertNoError(t, client.Create(&ingresses[0]))
+	util.AssertNoError(t, ExposeService(context.Background(), nil, &manifest, instance, nil, "tekton-dashboard", "spec.expose"))
+	_, err = client.Get(&ingresses[0])
+	util.AssertNoError(t, err)
+}
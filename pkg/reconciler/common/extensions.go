@@ -25,6 +25,8 @@ import (
 
 // Extension enables platform-specific features
 type Extension interface {
+	// Transformers are run by Transform one at a time, even over a pool
+	// of workers, see Serialized.
 	Transformers(v1alpha1.TektonComponent) []mf.Transformer
 	PreReconcile(context.Context, v1alpha1.TektonComponent) error
 	PostReconcile(context.Context, v1alpha1.TektonComponent) error
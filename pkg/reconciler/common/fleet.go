@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"knative.dev/pkg/system"
+)
+
+// DefaultKubeconfigKey is the key holding the kubeconfig in the Secret
+// referenced by spec.kubeconfigSecret, unless another one is given.
+const DefaultKubeconfigKey = "kubeconfig"
+
+// isRemote returns true if the instance is installed to a remote cluster.
+func isRemote(instance v1alpha1.TektonComponent) bool {
+	return instance.GetSpec().GetKubeconfigSecret() != nil
+}
+
+// TargetCluster rebinds the manifest to the cluster the instance is
+// installed to and returns the rest.Config of that cluster. It returns a
+// nil config, leaving the manifest untouched, if the instance targets the
+// cluster the operator runs in. A missing secret is a missing dependency,
+// an invalid kubeconfig an invalid spec.
+func TargetCluster(ctx context.Context, kubeClient kubernetes.Interface, manifest *mf.Manifest, instance v1alpha1.TektonComponent) (*rest.Config, error) {
+	ref := instance.GetSpec().GetKubeconfigSecret()
+	if ref == nil {
+		return nil, nil
+	}
+	key := ref.Key
+	if key == "" {
+		key = DefaultKubeconfigKey
+	}
+	secret, err := kubeClient.CoreV1().Secrets(system.Namespace()).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, &operrors.DependencyMissing{Err: fmt.Errorf("failed to get kubeconfig secret %s: %w", ref.Name, err)}
+	}
+	kubeconfig, ok := secret.Data[key]
+	if !ok {
+		return nil, &operrors.TransformError{Err: fmt.Errorf("kubeconfig secret %s has no key %s", ref.Name, key)}
+	}
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, &operrors.TransformError{Err: fmt.Errorf("invalid kubeconfig in secret %s: %w", ref.Name, err)}
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, &operrors.TransformError{Err: fmt.Errorf("invalid kubeconfig in secret %s: %w", ref.Name, err)}
+	}
+	manifest.Client = client
+	return cfg, nil
+}
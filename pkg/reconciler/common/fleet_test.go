@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTargetClusterLocal(t *testing.T) {
+	component := &v1alpha1.TektonPipeline{}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{}))
+	util.AssertNoError(t, err)
+	client := manifest.Client
+
+	cfg, err := TargetCluster(context.Background(), nil, &manifest, component)
+	util.AssertNoError(t, err)
+	if cfg != nil {
+		t.Fatalf("TargetCluster() = %v, want nil", cfg)
+	}
+	util.AssertEqual(t, manifest.Client, client)
+}
+
+func TestTransformRemoteSkipsOwner(t *testing.T) {
+	component := &v1alpha1.TektonPipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-name",
+		},
+		Spec: v1alpha1.TektonPipelineSpec{
+			CommonSpec: v1alpha1.CommonSpec{
+				TargetNamespace: "test-ns",
+				KubeconfigSecret: &v1alpha1.KubeconfigSecretReference{
+					Name: "spoke",
+				},
+			},
+		},
+	}
+	in := []unstructured.Unstructured{namespacedResource("test/v1", "TestCR", "another-ns", "test-resource")}
+	manifest, err := mf.ManifestFrom(mf.Slice(in))
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, Transform(context.Background(), &manifest, component))
+
+	resource := manifest.Resources()[0]
+	util.AssertEqual(t, resource.GetNamespace(), "test-ns")
+	util.AssertEqual(t, len(resource.GetOwnerReferences()), 0)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
+)
+
+// ErrSuperseded is returned by Stages.Execute when the spec of the
+// component changed while its stages executed, as the remaining stages
+// would install a stale spec.
+var ErrSuperseded = errors.New("the spec changed during the reconcile")
+
+// LatestGeneration returns the latest generation of the named component,
+// e.g. from the informer of its kind.
+type LatestGeneration func(name string) (int64, error)
+
+type latestGenerationKey struct{}
+
+// WithLatestGeneration attaches to the context how to get the latest
+// generation of the component being reconciled, for Stages.Execute to
+// stop installing a generation superseded mid-apply.
+func WithLatestGeneration(ctx context.Context, latest LatestGeneration) context.Context {
+	return context.WithValue(ctx, latestGenerationKey{}, latest)
+}
+
+// superseded returns true if the context knows of a newer generation of
+// the component than the one being reconciled.
+func superseded(ctx context.Context, instance v1alpha1.TektonComponent) bool {
+	latest, _ := ctx.Value(latestGenerationKey{}).(LatestGeneration)
+	if latest == nil {
+		return false
+	}
+	generation, err := latest(instance.GetName())
+	return err == nil && generation > instance.GetGeneration()
+}
+
+// ObserveGeneration records the generation of the component as observed
+// once its stages executed without error, or once its spec was found
+// invalid, retrying being useless until the spec changes. A reconcile
+// superseded by a newer generation, or waiting for its installer set,
+// ends without error, the informers enqueueing the component again. The
+// duration and outcome of the reconcile are recorded when the context
+// carries its start, see WithCorrelationID, a reconcile ending on an
+// invalid spec as failed, and the time of the success when the component
+// is ready.
+func ObserveGeneration(ctx context.Context, instance v1alpha1.TektonComponent, err error) error {
+	if errors.Is(err, ErrSuperseded) || errors.Is(err, ErrInstallerSetPending) {
+		reportReconcile(ctx, instance.GetName(), reconcileRequeued)
+		return nil
+	}
+	if err != nil {
+		reportReconcile(ctx, instance.GetName(), reconcileFailed)
+		return err
+	}
+	if installFailed(instance.GetStatus()) {
+		reportReconcile(ctx, instance.GetName(), reconcileFailed)
+	} else {
+		reportReconcile(ctx, instance.GetName(), reconcileSucceeded)
+	}
+	instance.GetStatus().SetObservedGeneration(instance.GetGeneration())
+	if instance.GetStatus().IsReady() {
+		// on every reconcile, skipped installs of a healthy component and
+		// the first reconcile after a restart included
+		reportInstalled(ctx, instance.GetName())
+	}
+	return nil
+}
+
+// installFailed returns true if the status reports its install failed,
+// as it does when the spec was found invalid.
+func installFailed(status v1alpha1.TektonComponentStatus) bool {
+	conditions, ok := status.(interface {
+		GetCondition(apis.ConditionType) *apis.Condition
+	})
+	if !ok {
+		return false
+	}
+	condition := conditions.GetCondition(v1alpha1.InstallSucceeded)
+	return condition != nil && condition.IsFalse()
+}
+
+// SkipUnchanged returns a Stage executing stage, typically Install, only
+// when the component needs it: the generation of its spec wasn't
+// installed yet, it isn't ready or the live resources drifted from the
+// manifest, e.g. after an image override or an out-of-band edit.
+func SkipUnchanged(stage Stage) Stage {
+	return func(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+		status := instance.GetStatus()
+		if status.GetObservedGeneration() != instance.GetGeneration() || !status.IsReady() {
+			return stage(ctx, manifest, instance)
+		}
+		logger := logging.FromContext(ctx)
+		drifted, err := drift(manifest, instance)
+		if err != nil {
+			logger.Warnw("Unable to detect drift, applying the manifest", "error", err)
+			return stage(ctx, manifest, instance)
+		}
+		if drifted != 0 {
+			logger.Infow("Live resources drifted from the manifest", "resources", drifted)
+			return stage(ctx, manifest, instance)
+		}
+		logger.Debug("Generation installed already and no drift, skipping")
+		return nil
+	}
+}
+
+// drift returns the number of live resources which drifted from the
+// manifest, compared the way the component applies them: a dry run of the
+// server side apply, or the last-applied annotation of the client side
+// one.
+func drift(manifest *mf.Manifest, instance v1alpha1.TektonComponent) (int, error) {
+	if ssa, ok := manifest.Client.(ServerSideApplier); ok && instance.GetSpec().GetConfig().ServerSideApply {
+		m, err := withoutAutoscaledReplicas(*manifest)
+		if err != nil {
+			return 0, err
+		}
+		return serverSideDrift(ssa, m)
+	}
+	patches, err := manifest.DryRun()
+	return len(patches), err
+}
+
+// InitializeStatus initializes the conditions of the component and marks
+// the install of a generation not observed yet in progress, so that Ready
+// never reports on a previous generation of the spec.
+func InitializeStatus(instance v1alpha1.TektonComponent) {
+	status := instance.GetStatus()
+	status.InitializeConditions()
+	if status.GetObservedGeneration() != instance.GetGeneration() {
+		status.MarkInstallReconciling()
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	fake "github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"knative.dev/pkg/apis"
+)
+
+func TestExecuteSuperseded(t *testing.T) {
+	instance := &v1alpha1.TektonPipeline{}
+	instance.SetName("pipeline")
+	instance.SetGeneration(1)
+	latest := int64(1)
+	ctx := WithLatestGeneration(context.Background(), func(name string) (int64, error) {
+		util.AssertEqual(t, name, "pipeline")
+		return latest, nil
+	})
+
+	executed := 0
+	stage := func(context.Context, *mf.Manifest, v1alpha1.TektonComponent) error {
+		executed++
+		// the spec changes while the first stage executes
+		latest = 2
+		return nil
+	}
+	manifest, _ := mf.ManifestFrom(mf.Slice{})
+	err := Stages{stage, stage}.Execute(ctx, &manifest, instance)
+	if !errors.Is(err, ErrSuperseded) {
+		t.Errorf("Execute() = %v, wanted ErrSuperseded", err)
+	}
+	util.AssertEqual(t, executed, 1)
+
+	// the superseded generation isn't observed, and ends the reconcile
+	util.AssertNoError(t, ObserveGeneration(context.Background(), instance, err))
+	util.AssertEqual(t, instance.Status.ObservedGeneration, int64(0))
+	util.AssertNoError(t, ObserveGeneration(context.Background(), instance, nil))
+	util.AssertEqual(t, instance.Status.ObservedGeneration, int64(1))
+}
+
+func TestSkipUnchanged(t *testing.T) {
+	client := fake.New()
+	cm := namespacedResource("v1", "ConfigMap", "tekton-pipelines", "config")
+	cm.Object["data"] = map[string]interface{}{"key": "value"}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{cm}), mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	installs := 0
+	install := SkipUnchanged(func(_ context.Context, manifest *mf.Manifest, _ v1alpha1.TektonComponent) error {
+		installs++
+		return manifest.Apply()
+	})
+	instance := &v1alpha1.TektonPipeline{}
+	instance.SetGeneration(1)
+	instance.Status.InitializeConditions()
+
+	// a generation not installed yet
+	util.AssertNoError(t, install(context.Background(), &manifest, instance))
+	util.AssertEqual(t, installs, 1)
+
+	// installed and ready, without drift
+	instance.Status.MarkInstallSucceeded()
+	instance.Status.MarkDeploymentsAvailable()
+	instance.Status.SetObservedGeneration(1)
+	util.AssertNoError(t, install(context.Background(), &manifest, instance))
+	util.AssertEqual(t, installs, 1)
+
+	// an out-of-band edit
+	live, err := client.Get(&cm)
+	util.AssertNoError(t, err)
+	live.Object["data"] = map[string]interface{}{"key": "edited"}
+	util.AssertNoError(t, client.Update(live))
+	util.AssertNoError(t, install(context.Background(), &manifest, instance))
+	util.AssertEqual(t, installs, 2)
+
+	// a new generation
+	instance.SetGeneration(2)
+	util.AssertNoError(t, install(context.Background(), &manifest, instance))
+	util.AssertEqual(t, installs, 3)
+}
+
+func TestInitializeStatus(t *testing.T) {
+	instance := &v1alpha1.TektonPipeline{}
+	instance.SetGeneration(1)
+	InitializeStatus(instance)
+	instance.Status.MarkInstallSucceeded()
+	instance.Status.MarkDeploymentsAvailable()
+	util.AssertNoError(t, ObserveGeneration(context.Background(), instance, nil))
+	util.AssertEqual(t, instance.Status.IsReady(), true)
+
+	// the observed generation stays ready
+	InitializeStatus(instance)
+	util.AssertEqual(t, instance.Status.IsReady(), true)
+
+	// a new generation isn't ready until installed
+	instance.SetGeneration(2)
+	InitializeStatus(instance)
+	ready := instance.Status.GetCondition(apis.ConditionReady)
+	util.AssertEqual(t, ready.IsUnknown(), true)
+	util.AssertEqual(t, ready.Reason, v1alpha1.ReasonReconciling)
+	instance.Status.MarkInstallSucceeded()
+	util.AssertNoError(t, ObserveGeneration(context.Background(), instance, nil))
+	util.AssertEqual(t, instance.Status.IsReady(), true)
+
+	// a failure of the previous generation stays reported
+	instance.Status.MarkDeploymentsTimedOut("tekton-pipelines-controller")
+	instance.SetGeneration(3)
+	InitializeStatus(instance)
+	util.AssertEqual(t, instance.Status.GetCondition(apis.ConditionReady).IsFalse(), true)
+}
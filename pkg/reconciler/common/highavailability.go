@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	defaultHAReplicas = int32(2)
+	// controllerComponent is the app.kubernetes.io/component label of the
+	// payload controllers, the Deployments ha sets the replicas of
+	controllerComponent = "controller"
+)
+
+// HighAvailability returns a transformer running the payload controllers,
+// the Deployments labelled as the controller component, with the replicas
+// of ha, and spreading the pods of those, and of every
+// other payload Deployment running more than one replica, with a pod
+// anti-affinity replacing the one the payload may ship: preferred across
+// zones then nodes for the zone spread, the default, required across nodes
+// for the node spread. It is a no-op when ha is nil, and fails on fewer
+// than one replica.
+func HighAvailability(ha *v1alpha1.HighAvailability) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if ha == nil || u.GetKind() != "Deployment" {
+			return nil
+		}
+		switch ha.Spread {
+		case "", v1alpha1.SpreadZone, v1alpha1.SpreadNode, v1alpha1.SpreadNone:
+		default:
+			return fmt.Errorf("unknown high availability spread %q, expected one of %s, %s or %s",
+				ha.Spread, v1alpha1.SpreadZone, v1alpha1.SpreadNode, v1alpha1.SpreadNone)
+		}
+
+		if ha.Replicas != nil && *ha.Replicas < 1 {
+			return fmt.Errorf("high availability replicas must be at least 1, got %d", *ha.Replicas)
+		}
+
+		if u.GetLabels()["app.kubernetes.io/component"] == controllerComponent {
+			replicas := defaultHAReplicas
+			if ha.Replicas != nil {
+				replicas = *ha.Replicas
+			}
+			if err := unstructured.SetNestedField(u.Object, int64(replicas), "spec", "replicas"); err != nil {
+				return err
+			}
+		}
+		replicas, found, err := unstructured.NestedInt64(u.Object, "spec", "replicas")
+		if err != nil {
+			return err
+		}
+		if ha.Spread == v1alpha1.SpreadNone || !found || replicas < 2 {
+			return nil
+		}
+
+		selector, found, err := unstructured.NestedFieldCopy(u.Object, "spec", "selector")
+		if err != nil || !found {
+			return err
+		}
+		term := func(key string) map[string]interface{} {
+			return map[string]interface{}{"topologyKey": key, "labelSelector": selector}
+		}
+		affinity := map[string]interface{}{
+			"requiredDuringSchedulingIgnoredDuringExecution": []interface{}{term(corev1.LabelHostname)},
+		}
+		if ha.Spread != v1alpha1.SpreadNode {
+			affinity = map[string]interface{}{
+				"preferredDuringSchedulingIgnoredDuringExecution": []interface{}{
+					map[string]interface{}{"weight": int64(100), "podAffinityTerm": term(defaultTopologyKey)},
+					map[string]interface{}{"weight": int64(50), "podAffinityTerm": term(corev1.LabelHostname)},
+				},
+			}
+		}
+		return unstructured.SetNestedMap(u.Object, affinity, "spec", "template", "spec", "affinity", "podAntiAffinity")
+	}
+}
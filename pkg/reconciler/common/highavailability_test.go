@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestHighAvailability(t *testing.T) {
+	deployment := func(name string, replicas int32) unstructured.Unstructured {
+		d := util.MakeDeployment(name, corev1.PodSpec{
+			Containers: []corev1.Container{{Name: name, Image: "gcr.io/" + name + ":v1"}},
+			Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "rack"}},
+			}},
+		})
+		d.Labels = map[string]string{"app.kubernetes.io/component": name}
+		d.Spec.Replicas = &replicas
+		d.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}}
+		return util.MakeUnstructured(t, d)
+	}
+	antiAffinity := func(u unstructured.Unstructured) map[string]interface{} {
+		affinity, _, _ := unstructured.NestedMap(u.Object, "spec", "template", "spec", "affinity", "podAntiAffinity")
+		return affinity
+	}
+	replicas := func(u unstructured.Unstructured) int64 {
+		replicas, _, _ := unstructured.NestedInt64(u.Object, "spec", "replicas")
+		return replicas
+	}
+	selector := map[string]interface{}{"matchLabels": map[string]interface{}{"app": "controller"}}
+
+	controller := deployment("controller", 1)
+	unchanged := controller.DeepCopy()
+	util.AssertNoError(t, HighAvailability(nil)(&controller))
+	util.AssertDeepEqual(t, &controller, unchanged)
+
+	// the zone spread prefers distinct zones, then distinct nodes
+	util.AssertNoError(t, HighAvailability(&v1alpha1.HighAvailability{})(&controller))
+	util.AssertEqual(t, replicas(controller), int64(2))
+	util.AssertDeepEqual(t, antiAffinity(controller), map[string]interface{}{
+		"preferredDuringSchedulingIgnoredDuringExecution": []interface{}{
+			map[string]interface{}{"weight": int64(100), "podAffinityTerm": map[string]interface{}{
+				"topologyKey": "topology.kubernetes.io/zone", "labelSelector": selector,
+			}},
+			map[string]interface{}{"weight": int64(50), "podAffinityTerm": map[string]interface{}{
+				"topologyKey": "kubernetes.io/hostname", "labelSelector": selector,
+			}},
+		},
+	})
+
+	// the node spread requires distinct nodes
+	controller = *unchanged.DeepCopy()
+	three := int32(3)
+	util.AssertNoError(t, HighAvailability(&v1alpha1.HighAvailability{Replicas: &three, Spread: v1alpha1.SpreadNode})(&controller))
+	util.AssertEqual(t, replicas(controller), int64(3))
+	util.AssertDeepEqual(t, antiAffinity(controller), map[string]interface{}{
+		"requiredDuringSchedulingIgnoredDuringExecution": []interface{}{
+			map[string]interface{}{"topologyKey": "kubernetes.io/hostname", "labelSelector": selector},
+		},
+	})
+
+	// no spread keeps the anti-affinity of the payload
+	controller = *unchanged.DeepCopy()
+	util.AssertNoError(t, HighAvailability(&v1alpha1.HighAvailability{Spread: v1alpha1.SpreadNone})(&controller))
+	util.AssertEqual(t, replicas(controller), int64(2))
+	util.AssertDeepEqual(t, antiAffinity(controller), antiAffinity(*unchanged))
+
+	// other deployments are only spread when running several replicas
+	webhook := deployment("webhook", 1)
+	unchanged = webhook.DeepCopy()
+	util.AssertNoError(t, HighAvailability(&v1alpha1.HighAvailability{})(&webhook))
+	util.AssertDeepEqual(t, &webhook, unchanged)
+	webhook = deployment("webhook", 2)
+	util.AssertNoError(t, HighAvailability(&v1alpha1.HighAvailability{Spread: v1alpha1.SpreadNode})(&webhook))
+	util.AssertEqual(t, replicas(webhook), int64(2))
+	util.AssertEqual(t, len(antiAffinity(webhook)["requiredDuringSchedulingIgnoredDuringExecution"].([]interface{})), 1)
+
+	if err := HighAvailability(&v1alpha1.HighAvailability{Spread: "region"})(&webhook); err == nil {
+		t.Error("expected an error for an unknown spread")
+	}
+
+	// the controllers are picked by their component label, not their name
+	other := deployment("controller", 1)
+	other.SetLabels(map[string]string{"app.kubernetes.io/component": "dashboard"})
+	util.AssertNoError(t, HighAvailability(&v1alpha1.HighAvailability{})(&other))
+	util.AssertEqual(t, replicas(other), int64(1))
+
+	zero, negative := int32(0), int32(-1)
+	for _, r := range []*int32{&zero, &negative} {
+		controller = deployment("controller", 1)
+		if err := HighAvailability(&v1alpha1.HighAvailability{Replicas: r})(&controller); err == nil {
+			t.Errorf("expected an error for %d replicas", *r)
+		}
+	}
+}
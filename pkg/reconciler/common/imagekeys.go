@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ImageKeys returns the image override keys the manifest accepts, in the
+// form of the lower cased keys without prefix the image transformers
+// match: container names of workloads, arg_ keys of their flags, step
+// names of Tasks and ClusterTasks, StepAction names and param_ keys of
+// their params.
+func ImageKeys(manifest mf.Manifest) sets.String {
+	return sets.StringKeySet(imageKeyNames(manifest))
+}
+
+// PayloadKeyCollisions returns the image override keys matched by several
+// distinct names of the manifest, e.g. the containers tekton-controller
+// and tekton_controller. An override of such a key replaces the images of
+// all of them.
+func PayloadKeyCollisions(manifest mf.Manifest) []KeyCollision {
+	var collisions []KeyCollision
+	for key, names := range imageKeyNames(manifest) {
+		if names.Len() > 1 {
+			collisions = append(collisions, KeyCollision{Key: key, Names: names.List()})
+		}
+	}
+	sortCollisions(collisions)
+	return collisions
+}
+
+// imageKeyNames returns the names of the manifest matched by each image
+// override key.
+func imageKeyNames(manifest mf.Manifest) map[string]sets.String {
+	keys := map[string]sets.String{}
+	add := func(prefix, name string) {
+		key := NormalizeKey(prefix, name)
+		if _, ok := keys[key]; !ok {
+			keys[key] = sets.NewString()
+		}
+		keys[key].Insert(prefix + name)
+	}
+	workloadKinds := sets.NewString("Deployment", "DaemonSet", "ReplicaSet")
+	for _, u := range manifest.Resources() {
+		switch {
+		case workloadKinds.Has(u.GetKind()):
+			for _, field := range []string{"containers", "initContainers"} {
+				containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "template", "spec", field)
+				for _, c := range containers {
+					container, _ := c.(map[string]interface{})
+					if name, ok := container["name"].(string); ok {
+						add("", name)
+					}
+					args, _, _ := unstructured.NestedStringSlice(container, "args")
+					for _, flag := range imageFlags(args) {
+						add(ArgPrefix, flag)
+					}
+				}
+			}
+		case TaskKinds(&u):
+			steps, _, _ := unstructured.NestedSlice(u.Object, "spec", "steps")
+			for _, name := range itemNames(steps) {
+				add("", name)
+			}
+			params, _, _ := unstructured.NestedSlice(u.Object, "spec", "params")
+			for _, name := range itemNames(params) {
+				add(ParamPrefix, name)
+			}
+		case byGroupKind(TektonGroup, "StepAction")(&u):
+			add("", u.GetName())
+			params, _, _ := unstructured.NestedSlice(u.Object, "spec", "params")
+			for _, name := range itemNames(params) {
+				add(ParamPrefix, name)
+			}
+		}
+	}
+	return keys
+}
+
+// imageFlags returns the flags of args with a value, either -flag=value or
+// -flag value.
+func imageFlags(args []string) []string {
+	var flags []string
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if values, hasValue := splitsByEqual(arg); hasValue {
+			flags = append(flags, values[0])
+		} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			flags = append(flags, arg)
+		}
+	}
+	return flags
+}
+
+func itemNames(items []interface{}) []string {
+	var names []string
+	for _, i := range items {
+		item, _ := i.(map[string]interface{})
+		if name, ok := item["name"].(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
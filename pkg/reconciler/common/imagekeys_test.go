@@ -0,0 +1,49 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestImageKeys(t *testing.T) {
+	deployment := util.MakeUnstructured(t, util.MakeDeployment("controller", corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:  "tekton-pipelines-controller",
+			Image: "gcr.io/controller:v1",
+			Args:  []string{"-git-image", "gcr.io/git:v1", "--port=9097", "-disable-ha", "-verbose"},
+		}},
+		InitContainers: []corev1.Container{{Name: "init", Image: "gcr.io/init:v1"}},
+	}))
+	task := clusterScopedResource("tekton.dev/v1beta1", "ClusterTask", "buildah")
+	task.Object["spec"] = map[string]interface{}{
+		"steps":  []interface{}{map[string]interface{}{"name": "build", "image": "buildah"}},
+		"params": []interface{}{map[string]interface{}{"name": "BUILDER_IMAGE"}},
+	}
+	stepAction := clusterScopedResource("tekton.dev/v1beta1", "StepAction", "git-clone")
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment, task, stepAction}))
+	util.AssertNoError(t, err)
+
+	util.AssertDeepEqual(t, ImageKeys(manifest).List(), []string{
+		"arg___port", "arg__git_image", "build", "git_clone", "init", "param_builder_image", "tekton_pipelines_controller",
+	})
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	clientcache "k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/system"
+)
+
+// imagesSecretResync is the resync period of the images Secret informer.
+const imagesSecretResync = 10 * time.Hour
+
+// ImagesConfigMapName is the ConfigMap in the operator namespace holding
+// image overrides. Its keys are named like the environment variables, e.g.
+// IMAGE_PIPELINES_CONTROLLER, and take precedence over them.
+const ImagesConfigMapName = "tekton-operator-images"
+
+// ImagesSecretName is the Secret in the operator namespace holding image
+// overrides, for references which shouldn't be readable by everyone who
+// can read ConfigMaps. Its keys are named like the ones of the ConfigMap
+// and take precedence over them.
+const ImagesSecretName = "tekton-operator-images"
+
+// ImageStore keeps the image overrides of the images ConfigMap and Secret.
+type ImageStore struct {
+	m      sync.RWMutex
+	data   map[string]string
+	secret map[string]string
+}
+
+// NewImageStore returns an empty ImageStore.
+func NewImageStore() *ImageStore {
+	return &ImageStore{data: map[string]string{}}
+}
+
+// OnConfigMapChanged replaces the overrides with the data of the ConfigMap.
+func (s *ImageStore) OnConfigMapChanged(cm *corev1.ConfigMap) {
+	data := make(map[string]string, len(cm.Data))
+	for k, v := range cm.Data {
+		data[k] = v
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.data = data
+}
+
+// OnSecretChanged replaces the overrides of the Secret with its data, or
+// drops them when secret is nil.
+func (s *ImageStore) OnSecretChanged(secret *corev1.Secret) {
+	data := map[string]string{}
+	if secret != nil {
+		for k, v := range secret.Data {
+			data[k] = string(v)
+		}
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.secret = data
+}
+
+// Images returns the images with the given prefix, from the environment
+// merged with the ConfigMap, then the Secret overrides. A nil store only
+// reads the environment.
+func (s *ImageStore) Images(prefix string) map[string]string {
+	images := ImagesFromEnv(prefix)
+	if s == nil {
+		return images
+	}
+	s.m.RLock()
+	defer s.m.RUnlock()
+	for _, overrides := range []map[string]string{s.data, s.secret} {
+		for k, v := range overrides {
+			if strings.HasPrefix(k, prefix) && v != "" {
+				images[strings.TrimPrefix(k, prefix)] = v
+			}
+		}
+	}
+	return images
+}
+
+// WatchImages keeps the store in sync with the images ConfigMap, calling
+// onChange after each update. The ConfigMap is optional when the watcher
+// supports defaults.
+func WatchImages(cmw configmap.Watcher, store *ImageStore, onChange func()) {
+	observer := func(cm *corev1.ConfigMap) {
+		store.OnConfigMapChanged(cm)
+		onChange()
+	}
+	if dw, ok := cmw.(configmap.DefaultingWatcher); ok {
+		dw.WatchWithDefault(corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ImagesConfigMapName,
+				Namespace: system.Namespace(),
+			},
+		}, observer)
+		return
+	}
+	cmw.Watch(ImagesConfigMapName, observer)
+}
+
+// WatchImagesSecret keeps the store in sync with the images Secret, which
+// is optional, calling onChange after each update, until ctx is done.
+// Only the Secret of that name is watched.
+func WatchImagesSecret(ctx context.Context, kubeClient kubernetes.Interface, store *ImageStore, onChange func()) {
+	lw := clientcache.NewListWatchFromClient(kubeClient.CoreV1().RESTClient(), "secrets", system.Namespace(),
+		fields.OneTermEqualSelector("metadata.name", ImagesSecretName))
+	watchImagesSecret(ctx, lw, store, onChange)
+}
+
+func watchImagesSecret(ctx context.Context, lw clientcache.ListerWatcher, store *ImageStore, onChange func()) {
+	informer := clientcache.NewSharedInformer(lw, &corev1.Secret{}, imagesSecretResync)
+	update := func(obj interface{}) {
+		secret, _ := obj.(*corev1.Secret)
+		store.OnSecretChanged(secret)
+		onChange()
+	}
+	informer.AddEventHandler(clientcache.ResourceEventHandlerFuncs{
+		AddFunc:    update,
+		UpdateFunc: func(_, obj interface{}) { update(obj) },
+		DeleteFunc: func(interface{}) { update(nil) },
+	})
+	go informer.Run(ctx.Done())
+}
+
+// SpecImages keys the images of a spec.images field like the overrides of
+// the operator, e.g. tekton-pipelines-controller becomes
+// tekton_pipelines_controller.
+func SpecImages(images map[string]string) map[string]string {
+	result := make(map[string]string, len(images))
+	for k, v := range images {
+		result[NormalizeKey("", k)] = v
+	}
+	return result
+}
+
+type imageStoreKey struct{}
+
+// WithImageStore attaches the store to the context, for the extensions
+// generated from it.
+func WithImageStore(ctx context.Context, store *ImageStore) context.Context {
+	return context.WithValue(ctx, imageStoreKey{}, store)
+}
+
+// ImageStoreFromContext returns the store attached to the context, or nil.
+func ImageStoreFromContext(ctx context.Context) *ImageStore {
+	store, _ := ctx.Value(imageStoreKey{}).(*ImageStore)
+	return store
+}
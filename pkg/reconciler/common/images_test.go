@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	clientcache "k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/configmap"
+)
+
+func TestImageStore(t *testing.T) {
+	os.Setenv("IMAGE_PIPELINES_CONTROLLER", "env/controller")
+	os.Setenv("IMAGE_PIPELINES_WEBHOOK", "env/webhook")
+	defer os.Unsetenv("IMAGE_PIPELINES_CONTROLLER")
+	defer os.Unsetenv("IMAGE_PIPELINES_WEBHOOK")
+
+	var nilStore *ImageStore
+	util.AssertDeepEqual(t, nilStore.Images(PipelinesImagePrefix), map[string]string{
+		"CONTROLLER": "env/controller",
+		"WEBHOOK":    "env/webhook",
+	})
+
+	store := NewImageStore()
+	store.OnConfigMapChanged(&corev1.ConfigMap{
+		Data: map[string]string{
+			"IMAGE_PIPELINES_CONTROLLER": "cm/controller",
+			"IMAGE_PIPELINES_WEBHOOK":    "",
+			"IMAGE_TRIGGERS_CONTROLLER":  "cm/triggers",
+		},
+	})
+	util.AssertDeepEqual(t, store.Images(PipelinesImagePrefix), map[string]string{
+		"CONTROLLER": "cm/controller",
+		"WEBHOOK":    "env/webhook",
+	})
+	util.AssertDeepEqual(t, store.Images(TriggersImagePrefix), map[string]string{
+		"CONTROLLER": "cm/triggers",
+	})
+
+	// the Secret takes precedence over the ConfigMap, until deleted
+	store.OnSecretChanged(&corev1.Secret{
+		Data: map[string][]byte{"IMAGE_PIPELINES_CONTROLLER": []byte("secret/controller")},
+	})
+	util.AssertEqual(t, store.Images(PipelinesImagePrefix)["CONTROLLER"], "secret/controller")
+	store.OnSecretChanged(nil)
+	util.AssertEqual(t, store.Images(PipelinesImagePrefix)["CONTROLLER"], "cm/controller")
+}
+
+func TestWatchImages(t *testing.T) {
+	cmw := configmap.NewStaticWatcher(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ImagesConfigMapName},
+		Data:       map[string]string{"IMAGE_ADDONS_PUSH": "cm/push"},
+	})
+	store := NewImageStore()
+	changes := 0
+	WatchImages(cmw, store, func() { changes++ })
+	util.AssertNoError(t, cmw.Start(nil))
+
+	util.AssertEqual(t, changes, 1)
+	util.AssertEqual(t, store.Images(AddonsImagePrefix)["PUSH"], "cm/push")
+}
+
+func TestWatchImagesSecret(t *testing.T) {
+	watcher := watch.NewFake()
+	lw := &clientcache.ListWatch{
+		ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+			return &corev1.SecretList{}, nil
+		},
+		WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+			return watcher, nil
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := NewImageStore()
+	changes := make(chan struct{}, 10)
+	watchImagesSecret(ctx, lw, store, func() { changes <- struct{}{} })
+	waitForChange := func() {
+		t.Helper()
+		select {
+		case <-changes:
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the images Secret")
+		}
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: ImagesSecretName, Namespace: "tekton-operator"},
+		Data:       map[string][]byte{"IMAGE_TRIGGERS_WEBHOOK": []byte("secret/webhook")},
+	}
+	watcher.Add(secret)
+	waitForChange()
+	util.AssertEqual(t, store.Images(TriggersImagePrefix)["WEBHOOK"], "secret/webhook")
+
+	watcher.Delete(secret)
+	waitForChange()
+	util.AssertEqual(t, len(store.Images(TriggersImagePrefix)), 0)
+}
+
+func TestImageStoreFromContext(t *testing.T) {
+	if ImageStoreFromContext(context.Background()) != nil {
+		t.Fatal("ImageStoreFromContext() = non-nil store, want nil")
+	}
+	store := NewImageStore()
+	if ImageStoreFromContext(WithImageStore(context.Background(), store)) != store {
+		t.Fatal("ImageStoreFromContext() did not return the attached store")
+	}
+}
+
+func TestSpecImages(t *testing.T) {
+	util.AssertDeepEqual(t, SpecImages(map[string]string{
+		"Tekton-Pipelines-Controller": "spec/controller",
+		"arg_bash-image":              "spec/bash",
+	}), map[string]string{
+		"tekton_pipelines_controller": "spec/controller",
+		"arg_bash_image":              "spec/bash",
+	})
+}
@@ -22,6 +22,9 @@ import (
 
 	mf "github.com/manifestival/manifestival"
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"knative.dev/pkg/logging"
 )
 
@@ -31,54 +34,146 @@ var (
 	rolebinding           mf.Predicate = mf.Any(mf.ByKind("ClusterRoleBinding"), mf.ByKind("RoleBinding"))
 	consoleCLIDownload    mf.Predicate = mf.Any(mf.ByKind("ConsoleCLIDownload"))
 	clusterTriggerBinding mf.Predicate = mf.Any(mf.ByKind("ClusterTriggerBinding"))
+	webhookConfiguration  mf.Predicate = mf.Any(mf.ByKind("MutatingWebhookConfiguration"), mf.ByKind("ValidatingWebhookConfiguration"))
 )
 
 // Install applies the manifest resources for the given version and updates the given
-// status accordingly.
+// status accordingly. When the context carries installer sets and the
+// component is installed to the cluster the operator runs in, the manifest
+// is recorded in the installer set of the component instead, which applies
+// it.
 func Install(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	return InstallPart(ctx, "", manifest, instance)
+}
+
+// InstallPart is Install for a part of the manifest of the component, e.g.
+// a group of resources reported on its own, recorded in an installer set
+// of its own named after the part.
+func InstallPart(ctx context.Context, part string, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	if err := releaseUnmanagedNamespace(manifest.Client, instance); err != nil {
+		operrors.MarkFailed(instance.GetStatus(), err)
+		return err
+	}
+	if sets := installerSets(ctx); sets != nil && !isRemote(instance) {
+		return installSet(ctx, sets, part, manifest, instance)
+	}
+	status := instance.GetStatus()
+	ctx = WithServerSideApply(ctx, instance.GetSpec().GetConfig().ServerSideApply)
+	if err := Apply(ctx, manifest, instance.GetName()); err != nil {
+		operrors.MarkFailed(status, err)
+		return err
+	}
+	status.MarkInstallSucceeded()
+	status.SetVersion(installedVersion(instance))
+	return nil
+}
+
+// Apply applies the manifest resources in order, recording the apply
+// counters of the named component. Resources are applied server side when
+// the context asks for it, see WithServerSideApply.
+func Apply(ctx context.Context, manifest *mf.Manifest, component string) (err error) {
 	logger := logging.FromContext(ctx)
 	logger.Debug("Installing manifest")
-	status := instance.GetStatus()
+	recorder := newApplyRecorder(manifest.Client)
+	defer func() {
+		recorder.report(ctx, component)
+		if err != nil {
+			reportError(ctx, applyErrorCountStat, component)
+		}
+	}()
+	apply := applier(ctx, manifest.Client, recorder)
+	applied := *manifest
+	if serverSideApply(ctx) {
+		// the replicas of the autoscaled workloads are the autoscaler's
+		if applied, err = withoutAutoscaledReplicas(applied); err != nil {
+			return &operrors.TransformError{Err: err}
+		}
+	}
+	applied.Client = recorder
+	manifest = &applied
 	// The Operator needs a higher level of permissions if it 'bind's non-existent roles.
 	// To avoid this, we strictly order the manifest application as (Cluster)Roles, then
 	// (Cluster)RoleBindings, then the rest of the manifest.
-	if err := manifest.Filter(namespace).Apply(); err != nil {
-		status.MarkInstallFailed(err.Error())
+	if err := applyError(apply(manifest.Filter(namespace))); err != nil {
 		return fmt.Errorf("failed to apply namespaces: %w", err)
 	}
-	if err := manifest.Filter(role).Apply(); err != nil {
-		status.MarkInstallFailed(err.Error())
+	if err := applyError(apply(manifest.Filter(role))); err != nil {
 		return fmt.Errorf("failed to apply (cluster)roles: %w", err)
 	}
-	if err := manifest.Filter(rolebinding).Apply(); err != nil {
-		status.MarkInstallFailed(err.Error())
+	if err := applyError(apply(manifest.Filter(rolebinding))); err != nil {
 		return fmt.Errorf("failed to apply (cluster)rolebindings: %w", err)
 	}
-	if err := manifest.Filter(consoleCLIDownload).Apply(); err != nil {
-		status.MarkInstallFailed(err.Error())
+	if err := applyError(apply(manifest.Filter(consoleCLIDownload))); err != nil {
 		return fmt.Errorf("failed to apply consoleCLIdownload: %w", err)
 	}
-	if err := manifest.Filter(clusterTriggerBinding).Apply(); err != nil {
-		status.MarkInstallFailed(err.Error())
+	if err := applyError(apply(manifest.Filter(clusterTriggerBinding))); err != nil {
 		return fmt.Errorf("failed to apply clusterTriggerBinding: %w", err)
 	}
-	if err := manifest.Filter(mf.Not(mf.Any(role, rolebinding))).Apply(); err != nil {
-		status.MarkInstallFailed(err.Error())
+	if err := applyError(apply(manifest.Filter(mf.Not(mf.Any(role, rolebinding))))); err != nil {
 		return fmt.Errorf("failed to apply non rbac manifest: %w", err)
 	}
-	status.MarkInstallSucceeded()
-	status.SetVersion(TargetVersion(instance))
 	return nil
 }
 
-// Uninstall removes all resources
-func Uninstall(ctx context.Context, manifest *mf.Manifest) error {
-	if err := manifest.Filter(mf.Not(mf.Any(role, rolebinding))).Delete(); err != nil {
-		return fmt.Errorf("failed to remove non-crd/non-rbac resources: %w", err)
+// applyError types the error of applying resources for the condition it
+// marks false.
+func applyError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case apierrors.IsConflict(err):
+		return &operrors.ApplyConflict{Err: err}
+	default:
+		return &operrors.ApplyFailed{Err: err}
 	}
-	// Delete Roles last, as they may be useful for human operators to clean up.
-	if err := manifest.Filter(mf.Any(role, rolebinding)).Delete(); err != nil {
-		return fmt.Errorf("failed to remove rbac: %w", err)
+}
+
+// Uninstall removes all resources, group by group in deletionOrder
+func Uninstall(ctx context.Context, manifest *mf.Manifest) error {
+	for _, group := range deletionOrder(*manifest) {
+		if err := manifest.Filter(group.match).Delete(); err != nil {
+			return &operrors.ApplyFailed{Err: fmt.Errorf("failed to remove %s: %w", group.name, err)}
+		}
 	}
 	return nil
 }
+
+type deletionGroup struct {
+	name  string
+	match mf.Predicate
+}
+
+// deletionOrder groups the resources of the manifest in the order Uninstall
+// deletes them: custom resources while their controllers and CRDs are
+// still around, then webhook configurations, while the webhooks are still
+// running. Once a webhook's deployment or namespace is being deleted, its
+// configurations would block any further deletion, including the one of
+// its own namespace. Workloads and the rest follow, then RBAC, as it may
+// be useful for human operators to clean up, and lastly CRDs and
+// namespaces.
+func deletionOrder(manifest mf.Manifest) []deletionGroup {
+	customResource := customResources(manifest)
+	rbac := mf.Any(role, rolebinding)
+	return []deletionGroup{
+		{"custom resources", customResource},
+		{"webhook configurations", webhookConfiguration},
+		{"non-crd/non-rbac resources", mf.Not(mf.Any(customResource, webhookConfiguration, rbac, mf.CRDs, namespace))},
+		{"rbac", rbac},
+		{"crds", mf.CRDs},
+		{"namespaces", namespace},
+	}
+}
+
+// customResources matches the resources whose group is defined by a CRD of
+// the manifest.
+func customResources(manifest mf.Manifest) mf.Predicate {
+	groups := map[string]bool{}
+	for _, crd := range manifest.Filter(mf.CRDs).Resources() {
+		if group, _, _ := unstructured.NestedString(crd.Object, "spec", "group"); group != "" {
+			groups[group] = true
+		}
+	}
+	return func(u *unstructured.Unstructured) bool {
+		return !mf.CRDs(u) && groups[u.GroupVersionKind().Group]
+	}
+}
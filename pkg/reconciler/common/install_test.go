@@ -25,6 +25,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	mf "github.com/manifestival/manifestival"
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -49,7 +50,7 @@ func TestInstall(t *testing.T) {
 	// Expect things to be applied in order.
 	want := []unstructured.Unstructured{role, clusterRole, roleBinding, clusterRoleBinding, deployment}
 
-	client := &fakeClient{}
+	client := util.NewFakeClient()
 	manifest, err := mf.ManifestFrom(mf.Slice(in), mf.UseClient(client))
 	if err != nil {
 		t.Fatalf("Failed to generate manifest: %v", err)
@@ -66,8 +67,11 @@ func TestInstall(t *testing.T) {
 		t.Fatalf("Install() = %v, want no error", err)
 	}
 
-	if !cmp.Equal(client.creates, want) {
-		t.Fatalf("Unexpected creates: %s", cmp.Diff(client.creates, want))
+	for i := range client.Creates {
+		client.Creates[i].SetAnnotations(nil) // Irrelevant for the test.
+	}
+	if !cmp.Equal(client.Creates, want) {
+		t.Fatalf("Unexpected creates: %s", cmp.Diff(client.Creates, want))
 	}
 
 	condition := instance.Status.GetCondition(v1alpha1.InstallSucceeded)
@@ -82,7 +86,7 @@ func TestInstallError(t *testing.T) {
 	os.Setenv(KoEnvKey, koPath)
 	defer os.Unsetenv(KoEnvKey)
 
-	client := &fakeClient{err: errors.New("test")}
+	client := &util.FakeClient{Err: errors.New("test")}
 	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
 		namespacedResource("apps/v1", "Deployment", "test", "test-deployment"),
 	}), mf.UseClient(client))
@@ -141,10 +145,10 @@ func TestUninstall(t *testing.T) {
 
 	// Deliberately mixing the order in the manifest.
 	in := []unstructured.Unstructured{crd, deployment, role, roleBinding, clusterRole, clusterRoleBinding}
-	// Expect things to be deleted, non-rbac resources first and then in reversed order.
-	want := []unstructured.Unstructured{deployment, crd, clusterRoleBinding, clusterRole, roleBinding, role}
+	// Expect things to be deleted, non-rbac resources first, then rbac in reversed order and CRDs last.
+	want := []unstructured.Unstructured{deployment, clusterRoleBinding, clusterRole, roleBinding, role, crd}
 
-	client := &fakeClient{resourcesExist: true}
+	client := util.NewFakeClient(in...)
 	manifest, err := mf.ManifestFrom(mf.Slice(in), mf.UseClient(client))
 	if err != nil {
 		t.Fatalf("Failed to generate manifest: %v", err)
@@ -154,37 +158,36 @@ func TestUninstall(t *testing.T) {
 		t.Fatalf("Uninstall() = %v, want no error", err)
 	}
 
-	if !cmp.Equal(client.deletes, want) {
-		t.Fatalf("Unexpected deletes: %s", cmp.Diff(client.deletes, want))
+	if !cmp.Equal(client.Deletes, want) {
+		t.Fatalf("Unexpected deletes: %s", cmp.Diff(client.Deletes, want))
 	}
 }
 
-type fakeClient struct {
-	err            error
-	resourcesExist bool
-	creates        []unstructured.Unstructured
-	deletes        []unstructured.Unstructured
-}
-
-func (f *fakeClient) Get(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-	var resource *unstructured.Unstructured
-	if f.resourcesExist {
-		resource = &unstructured.Unstructured{}
+func TestUninstallOrder(t *testing.T) {
+	crd := clusterScopedResource("apiextensions.k8s.io/v1", "CustomResourceDefinition", "tasks.tekton.dev")
+	crd.Object["spec"] = map[string]interface{}{"group": "tekton.dev"}
+	ns := clusterScopedResource("v1", "Namespace", "tekton-pipelines")
+	ns.SetAnnotations(map[string]string{"manifestival": "new"})
+	webhook := clusterScopedResource("admissionregistration.k8s.io/v1", "ValidatingWebhookConfiguration", "validation.webhook.pipeline.tekton.dev")
+	deployment := namespacedResource("apps/v1", "Deployment", "tekton-pipelines", "tekton-pipelines-webhook")
+	clusterRole := clusterScopedResource("rbac.authorization.k8s.io/v1", "ClusterRole", "tekton-pipelines-webhook")
+	clusterTask := clusterScopedResource("tekton.dev/v1beta1", "ClusterTask", "buildah")
+
+	// Ordered like a release, dependencies first.
+	in := []unstructured.Unstructured{ns, clusterRole, crd, deployment, webhook, clusterTask}
+	want := []unstructured.Unstructured{clusterTask, webhook, deployment, clusterRole, crd, ns}
+
+	client := util.NewFakeClient(in...)
+	manifest, err := mf.ManifestFrom(mf.Slice(in), mf.UseClient(client))
+	if err != nil {
+		t.Fatalf("Failed to generate manifest: %v", err)
 	}
-	return resource, f.err
-}
 
-func (f *fakeClient) Delete(obj *unstructured.Unstructured, options ...mf.DeleteOption) error {
-	f.deletes = append(f.deletes, *obj)
-	return f.err
-}
-
-func (f *fakeClient) Create(obj *unstructured.Unstructured, options ...mf.ApplyOption) error {
-	obj.SetAnnotations(nil) // Deleting the extra annotation. Irrelevant for the test.
-	f.creates = append(f.creates, *obj)
-	return f.err
-}
+	if err := Uninstall(context.TODO(), &manifest); err != nil {
+		t.Fatalf("Uninstall() = %v, want no error", err)
+	}
 
-func (f *fakeClient) Update(obj *unstructured.Unstructured, options ...mf.ApplyOption) error {
-	return f.err
+	if !cmp.Equal(client.Deletes, want) {
+		t.Fatalf("Unexpected deletes: %s", cmp.Diff(client.Deletes, want))
+	}
 }
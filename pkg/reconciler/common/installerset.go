@@ -0,0 +1,285 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorv1alpha1 "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clientcache "k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// manifestsHashKey annotates an installer set with the hash of its
+	// manifests, telling whether they changed without comparing them
+	manifestsHashKey = "operator.tekton.dev/manifests-hash"
+
+	// reappliedAtKey annotates an installer set with the last time its
+	// component asked for the manifests to be applied again, e.g. after an
+	// out-of-band edit of the resources
+	reappliedAtKey = "operator.tekton.dev/reapplied-at"
+
+	// reapplyInterval is the least time between two reapplies of an
+	// installer set, so that a component fighting over a resource with
+	// someone else doesn't update its installer set in a loop
+	reapplyInterval = 30 * time.Second
+)
+
+// ErrInstallerSetPending is returned by Install while the installer set of
+// the component hasn't applied the latest manifest yet. The component is
+// enqueued again once the status of the installer set changes.
+var ErrInstallerSetPending = errors.New("waiting for the installer set to apply the manifest")
+
+type installerSetsKey struct{}
+
+// WithInstallerSets attaches to the context the client of the installer
+// sets Install records the manifests of the component in, rather than
+// applying them itself.
+func WithInstallerSets(ctx context.Context, sets operatorv1alpha1.TektonInstallerSetInterface) context.Context {
+	return context.WithValue(ctx, installerSetsKey{}, sets)
+}
+
+// installerSets returns the installer sets of the context, nil if the
+// manifests are applied by the stages.
+func installerSets(ctx context.Context) operatorv1alpha1.TektonInstallerSetInterface {
+	sets, _ := ctx.Value(installerSetsKey{}).(operatorv1alpha1.TektonInstallerSetInterface)
+	return sets
+}
+
+type retainedKey struct{}
+
+// WithRetainedResources attaches to the context the resources the
+// installer sets keep as they are in the cluster rather than deleting them
+// once the manifests no longer hold them, e.g. those of a paused
+// sub-payload.
+func WithRetainedResources(ctx context.Context, refs []v1alpha1.InstalledResource) context.Context {
+	return context.WithValue(ctx, retainedKey{}, refs)
+}
+
+// retainedResources returns the resources of the context the installer set
+// applied before, which it keeps.
+func retainedResources(ctx context.Context, live *v1alpha1.TektonInstallerSet) []v1alpha1.InstalledResource {
+	refs, _ := ctx.Value(retainedKey{}).([]v1alpha1.InstalledResource)
+	held := map[v1alpha1.InstalledResource]bool{}
+	for _, ref := range append(live.Status.Resources, live.Spec.Retained...) {
+		held[ref] = true
+	}
+	var retained []v1alpha1.InstalledResource
+	for _, ref := range refs {
+		if held[ref] {
+			retained = append(retained, ref)
+		}
+	}
+	return retained
+}
+
+// EnqueueOnInstallerSet returns an event handler for the
+// TektonInstallerSet informer, enqueuing the component of the given kind
+// owning the installer set changed, e.g. once it applied the manifest.
+func EnqueueOnInstallerSet(enqueueControllerOf func(interface{}), kind string) clientcache.ResourceEventHandler {
+	return EnqueueOwned(enqueueControllerOf, kind)
+}
+
+// InstallerSetName returns the name of the installer set holding the part
+// of the manifest of the component, the whole manifest for an empty part.
+func InstallerSetName(instance v1alpha1.TektonComponent, part string) string {
+	name := strings.ToLower(instance.GroupVersionKind().Kind) + "-" + instance.GetName()
+	if part != "" {
+		name += "-" + part
+	}
+	return name
+}
+
+// installSet records the manifest in the installer set of the part, owned
+// by the component, and reports the outcome of applying it once the
+// installer set observed it. An empty manifest deletes the installer set.
+func installSet(ctx context.Context, sets operatorv1alpha1.TektonInstallerSetInterface, part string, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	logger := logging.FromContext(ctx)
+	status := instance.GetStatus()
+	name := InstallerSetName(instance, part)
+	if len(manifest.Resources()) == 0 {
+		if err := sets.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		status.MarkInstallSucceeded()
+		status.SetVersion(installedVersion(instance))
+		return nil
+	}
+
+	live, err := sets.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		desired, err := makeInstallerSet(name, manifest, instance, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := sets.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+			err = applyError(err)
+			operrors.MarkFailed(status, err)
+			return err
+		}
+		logger.Infow("Created the installer set", "name", name)
+		return installSetPending(status)
+	}
+	if err != nil {
+		return err
+	}
+	desired, err := makeInstallerSet(name, manifest, instance, retainedResources(ctx, live))
+	if err != nil {
+		return err
+	}
+	if live.Annotations[manifestsHashKey] != desired.Annotations[manifestsHashKey] ||
+		live.Labels[v1alpha1.ReleaseVersionKey] != desired.Labels[v1alpha1.ReleaseVersionKey] ||
+		live.Spec.ServerSideApply != desired.Spec.ServerSideApply {
+		updated := live.DeepCopy()
+		updated.Labels = desired.Labels
+		updated.Annotations = desired.Annotations
+		updated.Spec = desired.Spec
+		if _, err := sets.Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+			err = applyError(err)
+			operrors.MarkFailed(status, err)
+			return err
+		}
+		logger.Infow("Updated the installer set", "name", name)
+		return installSetPending(status)
+	}
+
+	if live.Status.ObservedGeneration != live.Generation {
+		return installSetPending(status)
+	}
+	condition := live.Status.GetCondition(v1alpha1.InstallSucceeded)
+	switch {
+	case condition.IsTrue():
+		if status.IsReady() && status.GetObservedGeneration() == instance.GetGeneration() {
+			// SkipUnchanged installs a ready component only once its
+			// resources drifted, which the unchanged installer set would
+			// not apply again until its next resync
+			if err := reapplySet(ctx, sets, live); err != nil {
+				return err
+			}
+		}
+		status.MarkInstallSucceeded()
+		status.SetVersion(installedVersion(instance))
+		return nil
+	case condition.IsFalse():
+		err := fmt.Errorf("installer set %s failed: %s", name, condition.Message)
+		status.MarkInstallFailedWithReason(condition.Reason, err.Error())
+		return err
+	}
+	return installSetPending(status)
+}
+
+// installSetPending marks the install in progress while the installer set
+// applies the manifest.
+func installSetPending(status v1alpha1.TektonComponentStatus) error {
+	status.MarkInstallReconciling()
+	return ErrInstallerSetPending
+}
+
+// reapplySet annotates the installer set for its reconciler to apply its
+// manifests again, or enqueues the component again for when the interval
+// since the last reapply elapsed.
+func reapplySet(ctx context.Context, sets operatorv1alpha1.TektonInstallerSetInterface, live *v1alpha1.TektonInstallerSet) error {
+	now := ClockFrom(ctx).Now()
+	if last, err := time.Parse(time.RFC3339, live.Annotations[reappliedAtKey]); err == nil && now.Sub(last) < reapplyInterval {
+		// the drift would otherwise wait for the next resync
+		requeueAfter(ctx, reapplyInterval-now.Sub(last))
+		return nil
+	}
+	updated := live.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	updated.Annotations[reappliedAtKey] = now.UTC().Format(time.RFC3339)
+	_, err := sets.Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+// makeInstallerSet returns the installer set of the manifest, labeled with
+// the kind of the component and the version of the payload, owned by the
+// component for the garbage collector to delete it along. Its resources
+// are canonical, see CanonicalResources.
+func makeInstallerSet(name string, manifest *mf.Manifest, instance v1alpha1.TektonComponent, retained []v1alpha1.InstalledResource) (*v1alpha1.TektonInstallerSet, error) {
+	resources := CanonicalResources(manifest.Resources())
+	hash, err := specHash(resources, retained)
+	if err != nil {
+		return nil, err
+	}
+	gvk := instance.GroupVersionKind()
+	return &v1alpha1.TektonInstallerSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				v1alpha1.CreatedByKey:      gvk.Kind,
+				v1alpha1.ReleaseVersionKey: installedVersion(instance),
+			},
+			Annotations: map[string]string{
+				manifestsHashKey: hash,
+			},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(instance, gvk)},
+		},
+		Spec: v1alpha1.TektonInstallerSetSpec{
+			Manifests:       resources,
+			ServerSideApply: instance.GetSpec().GetConfig().ServerSideApply,
+			Retained:        retained,
+		},
+	}, nil
+}
+
+// AppliedHash returns the hash of the manifests of the installer set and
+// of the last reapply its component asked for, which its reconciler
+// records once it applied them, so that it doesn't apply them again until
+// either changes.
+func AppliedHash(tis *v1alpha1.TektonInstallerSet) (string, error) {
+	hash, err := specHash(tis.Spec.Manifests, tis.Spec.Retained)
+	if err != nil {
+		return "", err
+	}
+	if reappliedAt := tis.Annotations[reappliedAtKey]; reappliedAt != "" {
+		hash += "/" + reappliedAt
+	}
+	return hash, nil
+}
+
+// specHash returns the hash of the JSON of the resources, whose keys are
+// sorted, and of the resources retained, if any.
+func specHash(resources []unstructured.Unstructured, retained []v1alpha1.InstalledResource) (string, error) {
+	data, err := json.Marshal(resources)
+	if err != nil {
+		return "", err
+	}
+	if len(retained) != 0 {
+		refs, err := json.Marshal(retained)
+		if err != nil {
+			return "", err
+		}
+		data = append(data, refs...)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+func configMapManifest(t *testing.T, value string) *mf.Manifest {
+	t.Helper()
+	cm := namespacedResource("v1", "ConfigMap", "tekton-pipelines", "config")
+	cm.Object["data"] = map[string]interface{}{"key": value}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{cm}))
+	util.AssertNoError(t, err)
+	return &manifest
+}
+
+func TestInstallSet(t *testing.T) {
+	os.Setenv(KoEnvKey, "testdata/kodata")
+	defer os.Unsetenv(KoEnvKey)
+
+	sets := fake.NewSimpleClientset().OperatorV1alpha1().TektonInstallerSets()
+	ctx := WithInstallerSets(context.Background(), sets)
+	instance := &v1alpha1.TektonPipeline{}
+	instance.SetName("pipeline")
+	instance.SetGeneration(1)
+	InitializeStatus(instance)
+
+	// applied by the installer set reconciler, bumping the generation of
+	// the installer set on spec changes like the API server does
+	reconcile := func(mark func(*v1alpha1.TektonInstallerSetStatus)) {
+		set, err := sets.Get(ctx, "tektonpipeline-pipeline", metav1.GetOptions{})
+		util.AssertNoError(t, err)
+		set.Generation++
+		set.Status.ObservedGeneration = set.Generation
+		mark(&set.Status)
+		_, err = sets.Update(ctx, set, metav1.UpdateOptions{})
+		util.AssertNoError(t, err)
+	}
+
+	err := Install(ctx, configMapManifest(t, "v1"), instance)
+	if !errors.Is(err, ErrInstallerSetPending) {
+		t.Fatalf("Install() = %v, wanted ErrInstallerSetPending", err)
+	}
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).IsUnknown(), true)
+	set, err := sets.Get(ctx, "tektonpipeline-pipeline", metav1.GetOptions{})
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(set.Spec.Manifests), 1)
+	util.AssertEqual(t, set.Labels[v1alpha1.CreatedByKey], v1alpha1.KindTektonPipeline)
+	util.AssertEqual(t, set.Labels[v1alpha1.ReleaseVersionKey], TargetVersion(instance))
+	util.AssertEqual(t, metav1.GetControllerOf(set).Name, "pipeline")
+	hash := set.Annotations[manifestsHashKey]
+
+	// waiting for the installer set to observe the manifest
+	err = Install(ctx, configMapManifest(t, "v1"), instance)
+	if !errors.Is(err, ErrInstallerSetPending) {
+		t.Fatalf("Install() = %v, wanted ErrInstallerSetPending", err)
+	}
+
+	reconcile((*v1alpha1.TektonInstallerSetStatus).MarkInstallSucceeded)
+	util.AssertNoError(t, Install(ctx, configMapManifest(t, "v1"), instance))
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).IsTrue(), true)
+	util.AssertEqual(t, instance.Status.GetVersion(), TargetVersion(instance))
+
+	// a changed manifest updates the installer set
+	err = Install(ctx, configMapManifest(t, "v2"), instance)
+	if !errors.Is(err, ErrInstallerSetPending) {
+		t.Fatalf("Install() = %v, wanted ErrInstallerSetPending", err)
+	}
+	set, err = sets.Get(ctx, "tektonpipeline-pipeline", metav1.GetOptions{})
+	util.AssertNoError(t, err)
+	if set.Annotations[manifestsHashKey] == hash {
+		t.Error("the hash of the installer set didn't change with its manifests")
+	}
+
+	// the failure of the installer set is the one of the component
+	reconcile(func(status *v1alpha1.TektonInstallerSetStatus) {
+		status.MarkInstallFailedWithReason(v1alpha1.ReasonApplyConflict, "boom")
+	})
+	if err := Install(ctx, configMapManifest(t, "v2"), instance); err == nil {
+		t.Fatal("Install() = nil, wanted the error of the installer set")
+	}
+	condition := instance.Status.GetCondition(v1alpha1.InstallSucceeded)
+	util.AssertEqual(t, condition.IsFalse(), true)
+	util.AssertEqual(t, condition.Reason, v1alpha1.ReasonApplyConflict)
+
+	// nothing to install deletes the installer set
+	empty, _ := mf.ManifestFrom(mf.Slice{})
+	util.AssertNoError(t, Install(ctx, &empty, instance))
+	_, err = sets.Get(ctx, "tektonpipeline-pipeline", metav1.GetOptions{})
+	util.AssertEqual(t, apierrors.IsNotFound(err), true)
+}
+
+func TestInstallSetRetained(t *testing.T) {
+	os.Setenv(KoEnvKey, "testdata/kodata")
+	defer os.Unsetenv(KoEnvKey)
+
+	sets := fake.NewSimpleClientset().OperatorV1alpha1().TektonInstallerSets()
+	instance := &v1alpha1.TektonAddon{}
+	instance.SetName("addon")
+	instance.Status.InitializeConditions()
+
+	paused := v1alpha1.InstalledResource{APIVersion: "v1", Kind: "ConfigMap", Namespace: "tekton-pipelines", Name: "paused"}
+	set, err := makeInstallerSet(InstallerSetName(instance, ""), configMapManifest(t, "v1"), instance, nil)
+	util.AssertNoError(t, err)
+	set.Status.Resources = []v1alpha1.InstalledResource{paused}
+	_, err = sets.Create(context.Background(), set, metav1.CreateOptions{})
+	util.AssertNoError(t, err)
+
+	// only the resources the installer set applied before are retained
+	elsewhere := v1alpha1.InstalledResource{APIVersion: "v1", Kind: "ConfigMap", Namespace: "tekton-pipelines", Name: "elsewhere"}
+	ctx := WithRetainedResources(WithInstallerSets(context.Background(), sets), []v1alpha1.InstalledResource{paused, elsewhere})
+	err = Install(ctx, configMapManifest(t, "v1"), instance)
+	if !errors.Is(err, ErrInstallerSetPending) {
+		t.Fatalf("Install() = %v, wanted ErrInstallerSetPending", err)
+	}
+	set, err = sets.Get(ctx, set.Name, metav1.GetOptions{})
+	util.AssertNoError(t, err)
+	util.AssertDeepEqual(t, set.Spec.Retained, []v1alpha1.InstalledResource{paused})
+}
+
+func TestInstallSetReapply(t *testing.T) {
+	os.Setenv(KoEnvKey, "testdata/kodata")
+	defer os.Unsetenv(KoEnvKey)
+
+	sets := fake.NewSimpleClientset().OperatorV1alpha1().TektonInstallerSets()
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFakeClock(now)
+	var requeued time.Duration
+	ctx := WithClock(WithInstallerSets(context.Background(), sets), fakeClock)
+	ctx = WithRequeue(ctx, func(after time.Duration) { requeued = after })
+	instance := &v1alpha1.TektonAddon{}
+	instance.SetName("addon")
+	instance.SetGeneration(1)
+	instance.Status.InitializeConditions()
+
+	manifest := configMapManifest(t, "v1")
+	set, err := makeInstallerSet(InstallerSetName(instance, "clustertasks"), manifest, instance, nil)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, set.Name, "tektonaddon-addon-clustertasks")
+	set.Status.MarkInstallSucceeded()
+	_, err = sets.Create(ctx, set, metav1.CreateOptions{})
+	util.AssertNoError(t, err)
+
+	// installing a ready component means its resources drifted
+	instance.Status.MarkInstallSucceeded()
+	instance.Status.MarkDeploymentsAvailable()
+	instance.Status.SetObservedGeneration(1)
+	util.AssertNoError(t, InstallPart(ctx, "clustertasks", manifest, instance))
+	set, err = sets.Get(ctx, set.Name, metav1.GetOptions{})
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, set.Annotations[reappliedAtKey], "2021-06-01T12:00:00Z")
+
+	// not again until the interval elapsed
+	fakeClock.Step(10 * time.Second)
+	util.AssertNoError(t, InstallPart(ctx, "clustertasks", manifest, instance))
+	set, err = sets.Get(ctx, set.Name, metav1.GetOptions{})
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, set.Annotations[reappliedAtKey], "2021-06-01T12:00:00Z")
+	util.AssertEqual(t, requeued, 20*time.Second)
+	fakeClock.Step(reapplyInterval)
+	util.AssertNoError(t, InstallPart(ctx, "clustertasks", manifest, instance))
+	set, err = sets.Get(ctx, set.Name, metav1.GetOptions{})
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, set.Annotations[reappliedAtKey], "2021-06-01T12:00:40Z")
+}
+
+func TestAppliedHash(t *testing.T) {
+	os.Setenv(KoEnvKey, "testdata/kodata")
+	defer os.Unsetenv(KoEnvKey)
+
+	set, err := makeInstallerSet("tektonpipeline-pipeline", configMapManifest(t, "v1"), &v1alpha1.TektonPipeline{}, nil)
+	util.AssertNoError(t, err)
+	hash, err := AppliedHash(set)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, hash, set.Annotations[manifestsHashKey])
+
+	set.Annotations[reappliedAtKey] = "2021-06-01T12:00:00Z"
+	reapplied, err := AppliedHash(set)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, reapplied, hash+"/2021-06-01T12:00:00Z")
+}
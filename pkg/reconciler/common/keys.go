@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"knative.dev/pkg/logging"
+)
+
+// NormalizeKey returns the image override key of a container, step,
+// StepAction, flag (with ArgPrefix) or param (with ParamPrefix) name: the
+// prefixed name, lower cased, with dashes turned into underscores. Keys of
+// the environment, e.g. ARG__GIT_IMAGE for -git-image, are normalized the
+// same way, so that they match regardless of case and dashes.
+func NormalizeKey(prefix, name string) string {
+	key := strings.ToLower(name)
+	if prefix != "" {
+		key = prefix + key
+	}
+	return strings.ReplaceAll(key, "-", "_")
+}
+
+// KeyCollision is a key several distinct names normalize to.
+type KeyCollision struct {
+	Key   string
+	Names []string
+}
+
+// NormalizeKeys normalizes the keys of image overrides with NormalizeKey.
+// When several keys normalize to the same key, the value of the last of
+// them in lexical order is kept and the collision is returned.
+func NormalizeKeys(keyValues map[string]string) (map[string]string, []KeyCollision) {
+	names := make([]string, 0, len(keyValues))
+	for k := range keyValues {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	normalized := make(map[string]string, len(keyValues))
+	byKey := map[string][]string{}
+	for _, name := range names {
+		key := NormalizeKey("", name)
+		normalized[key] = keyValues[name]
+		byKey[key] = append(byKey[key], name)
+	}
+	var collisions []KeyCollision
+	for key, names := range byKey {
+		if len(names) > 1 {
+			collisions = append(collisions, KeyCollision{Key: key, Names: names})
+		}
+	}
+	sortCollisions(collisions)
+	return normalized, collisions
+}
+
+// ToLowerCaseKeys normalizes the keys of image overrides with
+// NormalizeKeys, dropping the collisions.
+func ToLowerCaseKeys(keyValues map[string]string) map[string]string {
+	normalized, _ := NormalizeKeys(keyValues)
+	return normalized
+}
+
+func sortCollisions(collisions []KeyCollision) {
+	sort.Slice(collisions, func(i, j int) bool {
+		return collisions[i].Key < collisions[j].Key
+	})
+}
+
+// ReportKeyCollisions logs the image override keys which are ambiguous for
+// the component: keys of the environment with the given prefix which
+// normalize to the same key, and keys matching several names of the
+// latest payload of the component. It is meant to be called on startup,
+// as these lead to wrong images without any error.
+func ReportKeyCollisions(ctx context.Context, instance v1alpha1.TektonComponent, prefix string) {
+	logger := logging.FromContext(ctx)
+	if prefix != "" {
+		_, collisions := NormalizeKeys(ImagesFromEnv(prefix))
+		for _, c := range collisions {
+			variables := make([]string, 0, len(c.Names))
+			for _, name := range c.Names {
+				variables = append(variables, prefix+name)
+			}
+			logger.Errorw("Image overrides of the environment collide, only the last one applies",
+				"key", c.Key, "variables", variables)
+		}
+	}
+
+	releases, err := allReleases(instance)
+	if err != nil {
+		logger.Debugw("No payload to check image override keys against", "error", err)
+		return
+	}
+	manifest, err := mf.ManifestFrom(mf.Recursive(filepath.Join(ComponentDir(instance), releases[0])))
+	if err != nil {
+		logger.Errorw("Failed to read the payload to check image override keys", "error", err)
+		return
+	}
+	for _, c := range PayloadKeyCollisions(manifest) {
+		logger.Errorw("Image override key matches several names of the payload, overrides apply to all of them",
+			"key", c.Key, "names", c.Names)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"knative.dev/pkg/logging"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	for _, test := range []struct {
+		prefix, name, key string
+	}{
+		{"", "tekton-pipelines-controller", "tekton_pipelines_controller"},
+		{"", "TEKTON_PIPELINES_CONTROLLER", "tekton_pipelines_controller"},
+		{ArgPrefix, "-git-image", "arg__git_image"},
+		{"", "ARG__GIT_IMAGE", "arg__git_image"},
+		{ParamPrefix, "BUILDER_IMAGE", "param_builder_image"},
+	} {
+		util.AssertEqual(t, NormalizeKey(test.prefix, test.name), test.key)
+	}
+}
+
+func TestNormalizeKeys(t *testing.T) {
+	normalized, collisions := NormalizeKeys(map[string]string{
+		"WEBHOOK":           "env/webhook",
+		"tekton-controller": "cm/dashes",
+		"TEKTON_CONTROLLER": "env/underscores",
+	})
+	util.AssertDeepEqual(t, normalized, map[string]string{
+		"webhook":           "env/webhook",
+		"tekton_controller": "cm/dashes",
+	})
+	util.AssertDeepEqual(t, collisions, []KeyCollision{{
+		Key:   "tekton_controller",
+		Names: []string{"TEKTON_CONTROLLER", "tekton-controller"},
+	}})
+	util.AssertDeepEqual(t, ToLowerCaseKeys(map[string]string{"CONTROLLER": "docker.io/pipeline"}),
+		map[string]string{"controller": "docker.io/pipeline"})
+}
+
+func TestPayloadKeyCollisions(t *testing.T) {
+	deployment := util.MakeUnstructured(t, util.MakeDeployment("controller", corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: "tekton-controller", Image: "gcr.io/controller:v1", Args: []string{"-git-image", "gcr.io/git:v1"}},
+			{Name: "tekton_controller", Image: "gcr.io/sidecar:v1", Args: []string{"--git_image=gcr.io/git:v1"}},
+		},
+	}))
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment}))
+	util.AssertNoError(t, err)
+	util.AssertDeepEqual(t, PayloadKeyCollisions(manifest), []KeyCollision{{
+		Key:   "tekton_controller",
+		Names: []string{"tekton-controller", "tekton_controller"},
+	}})
+}
+
+func TestReportKeyCollisions(t *testing.T) {
+	os.Setenv("IMAGE_PIPELINES_ARG__GIT_IMAGE", "gcr.io/git:v1")
+	defer os.Unsetenv("IMAGE_PIPELINES_ARG__GIT_IMAGE")
+	os.Setenv("IMAGE_PIPELINES_arg__git-image", "gcr.io/git:v2")
+	defer os.Unsetenv("IMAGE_PIPELINES_arg__git-image")
+	defer os.Unsetenv(KoEnvKey)
+
+	// with and without payload, the collisions of the environment are
+	// reported and reporting never fails the startup
+	for _, dir := range []string{"testdata/kodata", "testdata/missing"} {
+		core, logs := observer.New(zap.ErrorLevel)
+		ctx := logging.WithLogger(context.Background(), zap.New(core).Sugar())
+		os.Setenv(KoEnvKey, dir)
+		ReportKeyCollisions(ctx, &v1alpha1.TektonPipeline{}, PipelinesImagePrefix)
+
+		entries := logs.All()
+		util.AssertEqual(t, len(entries), 1)
+		fields := entries[0].ContextMap()
+		util.AssertEqual(t, fields["key"], "arg__git_image")
+		util.AssertDeepEqual(t, fields["variables"], []interface{}{
+			"IMAGE_PIPELINES_ARG__GIT_IMAGE", "IMAGE_PIPELINES_arg__git-image",
+		})
+	}
+}
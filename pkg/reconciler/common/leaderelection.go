@@ -0,0 +1,262 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+)
+
+// CleanupLeaderElection returns a Stage deleting the leader election
+// records of the target namespace, Leases and ConfigMaps, held by payload
+// pods which no longer exist and past their renew deadline, e.g. those of a controller renamed by an
+// upgrade or of replicas dropped by a change of topology, so that they
+// don't delay the election of the new leaders. It's meant to run once the
+// deployments are available. The pods and records are read from the
+// informers of the cache, or listed when it's nil, e.g. for a remote target
+// cluster the operator has no informers of.
+func CleanupLeaderElection(kubeClient kubernetes.Interface, cache *LeaderElectionCache) Stage {
+	return func(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+		logger := logging.FromContext(ctx)
+		ns := instance.GetSpec().GetTargetNamespace()
+		list := listLeaderElection
+		if cache != nil {
+			list = cache.list
+		}
+		pods, leases, configMaps, err := list(ctx, kubeClient, ns)
+		if err != nil {
+			return err
+		}
+
+		stale := staleLeaderElection(ClockFrom(ctx).Now(), payloadDeployments(manifest), pods, leases, configMaps)
+		for _, name := range stale.leases {
+			logger.Infow("Deleting stale leader election lease", "namespace", ns, "name", name)
+			err := kubeClient.CoordinationV1().Leases(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+		for _, name := range stale.configMaps {
+			logger.Infow("Deleting stale leader election ConfigMap", "namespace", ns, "name", name)
+			err := kubeClient.CoreV1().ConfigMaps(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// LeaderElectionCache holds the informers of the pods, Leases and
+// ConfigMaps of the target namespaces, for CleanupLeaderElection not to
+// list them on every reconcile. The informers of a namespace are started on
+// its first cleanup, caching that namespace only, and stop with the
+// context of the cache.
+type LeaderElectionCache struct {
+	ctx    context.Context
+	client kubernetes.Interface
+
+	mu        sync.Mutex
+	factories map[string]informers.SharedInformerFactory
+}
+
+// NewLeaderElectionCache makes a LeaderElectionCache of the cluster of the
+// client, its informers running until the context is done.
+func NewLeaderElectionCache(ctx context.Context, client kubernetes.Interface) *LeaderElectionCache {
+	return &LeaderElectionCache{ctx: ctx, client: client, factories: map[string]informers.SharedInformerFactory{}}
+}
+
+// list returns the pods and leader election records of the namespace from
+// the informers, starting them if need be
+func (c *LeaderElectionCache) list(ctx context.Context, _ kubernetes.Interface, ns string) ([]*corev1.Pod, []*coordinationv1.Lease, []*corev1.ConfigMap, error) {
+	factory := c.factory(ns)
+	syncCtx, cancel := context.WithTimeout(ctx, leaderElectionSyncTimeout)
+	defer cancel()
+	for informer, synced := range factory.WaitForCacheSync(syncCtx.Done()) {
+		if !synced {
+			return nil, nil, nil, fmt.Errorf("failed to sync the informer of %v in %s", informer, ns)
+		}
+	}
+	pods, err := factory.Core().V1().Pods().Lister().Pods(ns).List(labels.Everything())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	leases, err := factory.Coordination().V1().Leases().Lister().Leases(ns).List(labels.Everything())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	configMaps, err := factory.Core().V1().ConfigMaps().Lister().ConfigMaps(ns).List(labels.Everything())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return pods, leases, configMaps, nil
+}
+
+// factory returns the informer factory of the namespace, started
+func (c *LeaderElectionCache) factory(ns string) informers.SharedInformerFactory {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if factory, ok := c.factories[ns]; ok {
+		return factory
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(c.client, controller.GetResyncPeriod(c.ctx), informers.WithNamespace(ns))
+	// the informers are only started once requested
+	factory.Core().V1().Pods().Informer()
+	factory.Coordination().V1().Leases().Informer()
+	factory.Core().V1().ConfigMaps().Informer()
+	factory.Start(c.ctx.Done())
+	c.factories[ns] = factory
+	return factory
+}
+
+// leaderElectionSyncTimeout bounds the wait for the informers of a
+// namespace to sync, e.g. when the operator isn't allowed to list them
+const leaderElectionSyncTimeout = time.Minute
+
+// listLeaderElection lists the pods and leader election records of the
+// namespace through the API
+func listLeaderElection(ctx context.Context, kubeClient kubernetes.Interface, ns string) ([]*corev1.Pod, []*coordinationv1.Lease, []*corev1.ConfigMap, error) {
+	pods, err := kubeClient.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	leases, err := kubeClient.CoordinationV1().Leases(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	configMaps, err := kubeClient.CoreV1().ConfigMaps(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var (
+		podRefs       []*corev1.Pod
+		leaseRefs     []*coordinationv1.Lease
+		configMapRefs []*corev1.ConfigMap
+	)
+	for i := range pods.Items {
+		podRefs = append(podRefs, &pods.Items[i])
+	}
+	for i := range leases.Items {
+		leaseRefs = append(leaseRefs, &leases.Items[i])
+	}
+	for i := range configMaps.Items {
+		configMapRefs = append(configMapRefs, &configMaps.Items[i])
+	}
+	return podRefs, leaseRefs, configMapRefs, nil
+}
+
+// leaderElectionRecords are the names of leader election records
+type leaderElectionRecords struct {
+	leases, configMaps []string
+}
+
+// payloadLease matches the names of the Leases of the payload controllers,
+// which knative names after the component, the reconciler and the bucket,
+// e.g. controller.taskrun.00-of-01
+var payloadLease = regexp.MustCompile(`^[a-z0-9-]+(\.[a-z0-9-]+)*\.[0-9]{2}-of-[0-9]{2}$`)
+
+// staleLeaderElection returns the leader election records held by pods
+// which no longer exist and which belonged to the payload: to one of its
+// deployments, or to a former deployment of a Tekton component. The Leases
+// are the ones of the payload controllers only, and the records are only
+// stale once past their renew deadline at now, their renew time plus the
+// lease duration, a holder missing from a stale cache still renewing them.
+func staleLeaderElection(now time.Time, deployments sets.String, pods []*corev1.Pod, leases []*coordinationv1.Lease, configMaps []*corev1.ConfigMap) leaderElectionRecords {
+	live := sets.NewString()
+	for _, pod := range pods {
+		live.Insert(pod.Name)
+	}
+	stale := func(holder string) bool {
+		// holder identities are the name of the pod followed by a unique
+		// suffix, e.g. tekton-pipelines-controller-6d5f9c8b7-x2x9z_0a1b2c
+		i := strings.LastIndex(holder, "_")
+		if i <= 0 {
+			return false
+		}
+		pod := holder[:i]
+		return !live.Has(pod) && payloadPod(deployments, pod)
+	}
+
+	expired := func(renewed time.Time, seconds int) bool {
+		return !renewed.IsZero() && seconds > 0 && now.After(renewed.Add(time.Duration(seconds)*time.Second))
+	}
+
+	var records leaderElectionRecords
+	for _, lease := range leases {
+		if !payloadLease.MatchString(lease.Name) || lease.Spec.HolderIdentity == nil ||
+			lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		if stale(*lease.Spec.HolderIdentity) && expired(lease.Spec.RenewTime.Time, int(*lease.Spec.LeaseDurationSeconds)) {
+			records.leases = append(records.leases, lease.Name)
+		}
+	}
+	for _, cm := range configMaps {
+		annotation, ok := cm.Annotations[resourcelock.LeaderElectionRecordAnnotationKey]
+		if !ok {
+			continue
+		}
+		record := resourcelock.LeaderElectionRecord{}
+		if err := json.Unmarshal([]byte(annotation), &record); err != nil {
+			continue
+		}
+		if stale(record.HolderIdentity) && expired(record.RenewTime.Time, record.LeaseDurationSeconds) {
+			records.configMaps = append(records.configMaps, cm.Name)
+		}
+	}
+	return records
+}
+
+// payloadPod returns true if the pod, named after its deployment followed
+// by the hashes of its ReplicaSet and its own, belongs to one of the
+// deployments or to a Tekton deployment
+func payloadPod(deployments sets.String, pod string) bool {
+	parts := strings.Split(pod, "-")
+	if len(parts) < 3 {
+		return false
+	}
+	deployment := strings.Join(parts[:len(parts)-2], "-")
+	return deployments.Has(deployment) || strings.HasPrefix(deployment, "tekton-")
+}
+
+// payloadDeployments returns the names of the deployments of the manifest
+func payloadDeployments(manifest *mf.Manifest) sets.String {
+	names := sets.NewString()
+	for _, u := range manifest.Filter(mf.ByKind("Deployment")).Resources() {
+		names.Insert(u.GetName())
+	}
+	return names
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"knative.dev/pkg/ptr"
+)
+
+func TestStaleLeaderElection(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	expired := metav1.NewMicroTime(now.Add(-time.Minute))
+	lease := func(name, holder string) *coordinationv1.Lease {
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				RenewTime:            &expired,
+				LeaseDurationSeconds: ptr.Int32(15),
+			},
+		}
+	}
+	renewed := lease("controller.taskrun.02-of-04", "tekton-pipelines-controller-5f6c8-fghij_8")
+	renewed.Spec.RenewTime = &metav1.MicroTime{Time: now.Add(-5 * time.Second)}
+	configMap := func(name, record string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{resourcelock.LeaderElectionRecordAnnotationKey: record},
+		}}
+	}
+	deployments := sets.NewString("tekton-pipelines-controller", "custom-webhook")
+	pods := []*corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "tekton-pipelines-controller-7b9d4-abcde"}}}
+	leases := []*coordinationv1.Lease{
+		// held by a running pod
+		lease("controller.pipelinerun.00-of-01", "tekton-pipelines-controller-7b9d4-abcde_1"),
+		// held by a pod of a former ReplicaSet
+		lease("controller.taskrun.00-of-01", "tekton-pipelines-controller-5f6c8-fghij_2"),
+		// held by a pod of a renamed Tekton controller
+		lease("controller.taskrun.01-of-02", "tekton-controller-5f6c8-fghij_3"),
+		// held by a gone pod of a deployment of the payload
+		lease("webhook.00-of-01", "custom-webhook-5f6c8-fghij_4"),
+		// held by a gone pod of another application
+		lease("other.00-of-01", "other-controller-5f6c8-fghij_5"),
+		// not a lease of the payload controllers
+		lease("tekton-other", "tekton-pipelines-controller-5f6c8-fghij_9"),
+		// still within its renew deadline, the pod missing from the cache
+		renewed,
+		// released
+		lease("released", ""),
+	}
+	configMaps := []*corev1.ConfigMap{
+		configMap("tekton-pipelines-controller-leader", `{"holderIdentity":"tekton-pipelines-controller-5f6c8-fghij_6","leaseDurationSeconds":15,"renewTime":"2021-03-01T11:59:00Z"}`),
+		configMap("current-leader", `{"holderIdentity":"tekton-pipelines-controller-7b9d4-abcde_7","leaseDurationSeconds":15,"renewTime":"2021-03-01T11:59:00Z"}`),
+		configMap("renewed-leader", `{"holderIdentity":"tekton-pipelines-controller-5f6c8-fghij_10","leaseDurationSeconds":15,"renewTime":"2021-03-01T11:59:55Z"}`),
+		configMap("invalid", `{`),
+		{ObjectMeta: metav1.ObjectMeta{Name: "config-logging"}},
+	}
+
+	stale := staleLeaderElection(now, deployments, pods, leases, configMaps)
+	util.AssertDeepEqual(t, stale.leases, []string{"controller.taskrun.00-of-01", "controller.taskrun.01-of-02", "webhook.00-of-01"})
+	util.AssertDeepEqual(t, stale.configMaps, []string{"tekton-pipelines-controller-leader"})
+}
+
+func TestCleanupLeaderElection(t *testing.T) {
+	holder := "tekton-pipelines-controller-5f6c8-fghij_1"
+	renewed := metav1.NewMicroTime(time.Now().Add(-time.Minute))
+	objects := func() []runtime.Object {
+		return []runtime.Object{
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "tekton-pipelines-controller-7b9d4-abcde", Namespace: "tekton-pipelines"}},
+			&coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: "controller.taskrun.00-of-01", Namespace: "tekton-pipelines"},
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity:       &holder,
+					RenewTime:            &renewed,
+					LeaseDurationSeconds: ptr.Int32(15),
+				},
+			},
+		}
+	}
+	instance := &v1alpha1.TektonPipeline{Spec: v1alpha1.TektonPipelineSpec{CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "tekton-pipelines"}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// read from the informers, or listed without them
+	informed := kubefake.NewSimpleClientset(objects()...)
+	listed := kubefake.NewSimpleClientset(objects()...)
+	for client, cache := range map[*kubefake.Clientset]*LeaderElectionCache{
+		informed: NewLeaderElectionCache(ctx, informed),
+		listed:   nil,
+	} {
+		manifest := mf.Manifest{}
+		util.AssertNoError(t, CleanupLeaderElection(client, cache)(ctx, &manifest, instance))
+		leases, err := client.CoordinationV1().Leases("tekton-pipelines").List(ctx, metav1.ListOptions{})
+		util.AssertNoError(t, err)
+		util.AssertEqual(t, len(leases.Items), 0)
+	}
+}
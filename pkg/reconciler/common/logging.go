@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// loggingConfig is the name, or the prefix of the name, of the logging
+	// ConfigMaps of the payloads, e.g. config-logging-triggers
+	loggingConfig = "config-logging"
+	// logLevelPrefix prefixes the component of the logger in the keys
+	// overriding its level
+	logLevelPrefix = "loglevel."
+)
+
+// LogLevels sets the loglevel.<component> keys of the logging ConfigMaps
+// of the payload to the levels given by component, e.g. controller or
+// webhook, the keys the payload ships being replaced. An invalid level
+// fails the transform.
+func LogLevels(levels map[string]string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if len(levels) == 0 || u.GetKind() != "ConfigMap" || !strings.HasPrefix(u.GetName(), loggingConfig) {
+			return nil
+		}
+		data, _, err := unstructured.NestedStringMap(u.Object, "data")
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			data = map[string]string{}
+		}
+		for component, level := range levels {
+			var l zapcore.Level
+			if err := l.UnmarshalText([]byte(level)); err != nil {
+				return fmt.Errorf("invalid log level %q of %s, expected one of debug, info, warn, error, dpanic, panic, fatal", level, component)
+			}
+			data[logLevelPrefix+component] = l.String()
+		}
+		return unstructured.SetNestedStringMap(u.Object, data, "data")
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestLogLevels(t *testing.T) {
+	logging := namespacedResource("v1", "ConfigMap", "tekton-pipelines", "config-logging")
+	logging.Object["data"] = map[string]interface{}{
+		"zap-logger-config":   "{}",
+		"loglevel.controller": "info",
+		"loglevel.webhook":    "info",
+	}
+	triggers := namespacedResource("v1", "ConfigMap", "tekton-pipelines", "config-logging-triggers")
+	defaults := namespacedResource("v1", "ConfigMap", "tekton-pipelines", "config-defaults")
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{logging, triggers, defaults}))
+	util.AssertNoError(t, err)
+
+	manifest, err = manifest.Transform(LogLevels(map[string]string{"controller": "DEBUG", "eventlistener": "warn"}))
+	util.AssertNoError(t, err)
+	resources := manifest.Resources()
+	util.AssertDeepEqual(t, resources[0].Object["data"], map[string]interface{}{
+		"zap-logger-config":      "{}",
+		"loglevel.controller":    "debug",
+		"loglevel.webhook":       "info",
+		"loglevel.eventlistener": "warn",
+	})
+	util.AssertDeepEqual(t, resources[1].Object["data"], map[string]interface{}{
+		"loglevel.controller":    "debug",
+		"loglevel.eventlistener": "warn",
+	})
+	util.AssertDeepEqual(t, resources[2].Object, defaults.Object)
+
+	_, err = manifest.Transform(LogLevels(map[string]string{"webhook": "verbose"}))
+	if err == nil {
+		t.Fatal("expected an error for an invalid level")
+	}
+	util.AssertEqual(t, err.Error(), `invalid log level "verbose" of webhook, expected one of debug, info, warn, error, dpanic, panic, fatal`)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	clientcache "k8s.io/client-go/tools/cache"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/injection/clients/dynamicclient"
+)
+
+func init() {
+	injection.Default.RegisterInformerFactory(withLazyInformers)
+}
+
+type lazyKey struct{}
+
+// lazyInformers holds the informers of the labeled resources of the kinds
+// the cluster may not serve, e.g. Routes, which are started on first use
+// rather than along the injected informers, whose sync would never end.
+type lazyInformers struct {
+	factory   dynamicinformer.DynamicSharedInformerFactory
+	discovery discovery.DiscoveryInterface
+	stop      <-chan struct{}
+
+	mu sync.Mutex
+	// listers are nil for the resources the cluster doesn't serve
+	listers map[schema.GroupVersionResource]clientcache.GenericLister
+}
+
+func withLazyInformers(ctx context.Context) context.Context {
+	namespace := metav1.NamespaceAll
+	if injection.HasNamespaceScope(ctx) {
+		namespace = injection.GetNamespaceScope(ctx)
+	}
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicclient.Get(ctx), controller.GetResyncPeriod(ctx), namespace,
+		func(opts *metav1.ListOptions) {
+			opts.LabelSelector = Selector.String()
+		})
+	return context.WithValue(ctx, lazyKey{}, &lazyInformers{
+		factory:   factory,
+		discovery: kubeclient.Get(ctx).Discovery(),
+		stop:      ctx.Done(),
+		listers:   map[schema.GroupVersionResource]clientcache.GenericLister{},
+	})
+}
+
+// Lister returns the lister of the labeled resources of gvr, starting and
+// syncing their informer on first use. It returns false without informers
+// in the context, e.g. in tests, or when the cluster doesn't serve the
+// resources, e.g. Routes out of OpenShift.
+func Lister(ctx context.Context, gvr schema.GroupVersionResource) (clientcache.GenericLister, bool, error) {
+	lazy, ok := ctx.Value(lazyKey{}).(*lazyInformers)
+	if !ok {
+		return nil, false, nil
+	}
+	lazy.mu.Lock()
+	defer lazy.mu.Unlock()
+	if lister, ok := lazy.listers[gvr]; ok {
+		return lister, lister != nil, nil
+	}
+
+	resources, err := lazy.discovery.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, false, fmt.Errorf("failed to discover %s: %w", gvr.GroupVersion(), err)
+	}
+	served := false
+	if err == nil {
+		for _, resource := range resources.APIResources {
+			served = served || resource.Name == gvr.Resource
+		}
+	}
+	if !served {
+		lazy.listers[gvr] = nil
+		return nil, false, nil
+	}
+
+	informer := lazy.factory.ForResource(gvr)
+	lazy.factory.Start(lazy.stop)
+	if !clientcache.WaitForCacheSync(ctx.Done(), informer.Informer().HasSynced) {
+		return nil, false, fmt.Errorf("failed to sync the informer of %s", gvr)
+	}
+	lazy.listers[gvr] = informer.Lister()
+	return informer.Lister(), true, nil
+}
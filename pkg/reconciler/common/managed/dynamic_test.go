@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clientcache "k8s.io/client-go/tools/cache"
+)
+
+func TestLister(t *testing.T) {
+	ingresses := schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1beta1", Resource: "ingresses"}
+	routes := schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
+	// without informers in the context
+	_, ok, err := Lister(context.Background(), ingresses)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, ok, false)
+
+	ingress := &unstructured.Unstructured{}
+	ingress.SetAPIVersion("networking.k8s.io/v1beta1")
+	ingress.SetKind("Ingress")
+	ingress.SetNamespace("tekton-pipelines")
+	ingress.SetName("tekton-dashboard")
+	ingress.SetLabels(map[string]string{v1alpha1.ManagedByKey: v1alpha1.ManagedByValue})
+	kubeClient := kubefake.NewSimpleClientset()
+	kubeClient.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "networking.k8s.io/v1beta1",
+		APIResources: []metav1.APIResource{{Name: "ingresses", Namespaced: true, Kind: "Ingress"}},
+	}, {
+		GroupVersion: "route.openshift.io/v1",
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = context.WithValue(ctx, lazyKey{}, &lazyInformers{
+		factory:   dynamicinformer.NewDynamicSharedInformerFactory(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), ingress), 0),
+		discovery: kubeClient.Discovery(),
+		stop:      ctx.Done(),
+		listers:   map[schema.GroupVersionResource]clientcache.GenericLister{},
+	})
+
+	lister, ok, err := Lister(ctx, ingresses)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, ok, true)
+	object, err := lister.ByNamespace("tekton-pipelines").Get("tekton-dashboard")
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, object.(*unstructured.Unstructured).GetName(), "tekton-dashboard")
+
+	// Routes out of OpenShift
+	_, ok, err = Lister(ctx, routes)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, ok, false)
+}
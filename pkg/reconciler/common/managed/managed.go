@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package managed injects the informers of the payload resources the
+// operator applies, labeled with v1alpha1.ManagedByKey, shared by all the
+// component controllers and started along the other injected informers.
+// Only the labeled resources are cached, rather than every ConfigMap,
+// ServiceAccount or RoleBinding of the cluster.
+package managed
+
+import (
+	"context"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	clientcache "k8s.io/client-go/tools/cache"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+)
+
+// resources are the informers of the kinds of payload resources watched
+var resources = []func(informers.SharedInformerFactory) clientcache.SharedIndexInformer{
+	func(f informers.SharedInformerFactory) clientcache.SharedIndexInformer {
+		return f.Core().V1().Services().Informer()
+	},
+	func(f informers.SharedInformerFactory) clientcache.SharedIndexInformer {
+		return f.Core().V1().ConfigMaps().Informer()
+	},
+	func(f informers.SharedInformerFactory) clientcache.SharedIndexInformer {
+		return f.Core().V1().ServiceAccounts().Informer()
+	},
+	func(f informers.SharedInformerFactory) clientcache.SharedIndexInformer {
+		return f.Rbac().V1().ClusterRoles().Informer()
+	},
+	func(f informers.SharedInformerFactory) clientcache.SharedIndexInformer {
+		return f.Rbac().V1().ClusterRoleBindings().Informer()
+	},
+	func(f informers.SharedInformerFactory) clientcache.SharedIndexInformer {
+		return f.Rbac().V1().Roles().Informer()
+	},
+	func(f informers.SharedInformerFactory) clientcache.SharedIndexInformer {
+		return f.Rbac().V1().RoleBindings().Informer()
+	},
+	func(f informers.SharedInformerFactory) clientcache.SharedIndexInformer {
+		return f.Admissionregistration().V1().ValidatingWebhookConfigurations().Informer()
+	},
+	func(f informers.SharedInformerFactory) clientcache.SharedIndexInformer {
+		return f.Admissionregistration().V1().MutatingWebhookConfigurations().Informer()
+	},
+}
+
+func init() {
+	injection.Default.RegisterInformerFactory(withInformerFactory)
+	for _, informer := range resources {
+		informer := informer
+		injection.Default.RegisterInformer(func(ctx context.Context) (context.Context, controller.Informer) {
+			return ctx, informer(Get(ctx))
+		})
+	}
+}
+
+// Key is used as the key for associating the factory with a context.Context.
+type Key struct{}
+
+// Selector selects the payload resources the operator applies
+var Selector = labels.SelectorFromSet(labels.Set{v1alpha1.ManagedByKey: v1alpha1.ManagedByValue})
+
+func withInformerFactory(ctx context.Context) context.Context {
+	opts := []informers.SharedInformerOption{
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = Selector.String()
+		}),
+	}
+	if injection.HasNamespaceScope(ctx) {
+		opts = append(opts, informers.WithNamespace(injection.GetNamespaceScope(ctx)))
+	}
+	return context.WithValue(ctx, Key{},
+		informers.NewSharedInformerFactoryWithOptions(kubeclient.Get(ctx), controller.GetResyncPeriod(ctx), opts...))
+}
+
+// Get extracts the informer factory of the payload resources from the
+// context.
+func Get(ctx context.Context) informers.SharedInformerFactory {
+	untyped := ctx.Value(Key{})
+	if untyped == nil {
+		logging.FromContext(ctx).Panic("Unable to fetch the informer factory of the managed resources from context.")
+	}
+	return untyped.(informers.SharedInformerFactory)
+}
+
+// Informers returns the informers of the payload resources of the
+// context, which the injection starts.
+func Informers(ctx context.Context) []clientcache.SharedIndexInformer {
+	factory := Get(ctx)
+	informers := make([]clientcache.SharedIndexInformer, 0, len(resources))
+	for _, informer := range resources {
+		informers = append(informers, informer(factory))
+	}
+	return informers
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"sync"
+
+	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// manifests holds the payload manifests parsed already, so that reconciles
+// don't read and parse the YAML of the ko data directory again. It lives
+// in memory only: a restart of the operator, e.g. on an upgrade shipping
+// new payloads, starts from scratch.
+var manifests = newManifestCache()
+
+// manifestKey identifies the payload of a component at a version. The
+// component is its ko data directory, and manifests fetched by path alone
+// have none.
+type manifestKey struct {
+	component, version string
+}
+
+type manifestCache struct {
+	mu        sync.RWMutex
+	manifests map[manifestKey]mf.Manifest
+}
+
+func newManifestCache() *manifestCache {
+	return &manifestCache{manifests: map[manifestKey]mf.Manifest{}}
+}
+
+// get returns the manifest of the key, reading it from the path the first
+// time only. Errors aren't cached, the next get reading the path again.
+func (c *manifestCache) get(key manifestKey, path string) (mf.Manifest, error) {
+	c.mu.RLock()
+	m, ok := c.manifests[key]
+	c.mu.RUnlock()
+	if ok {
+		return m, nil
+	}
+	m, err := mf.NewManifest(path)
+	if err != nil {
+		return m, err
+	}
+	c.mu.Lock()
+	c.manifests[key] = m
+	c.mu.Unlock()
+	return m, nil
+}
+
+// retain drops the manifests of the component but the ones of the
+// versions given, e.g. the ones it moved away from.
+func (c *manifestCache) retain(component string, versions ...string) {
+	keep := sets.NewString(versions...)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.manifests {
+		if key.component != component || keep.Has(key.version) {
+			continue
+		}
+		delete(c.manifests, key)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+)
+
+func TestManifestCache(t *testing.T) {
+	cache := newManifestCache()
+	dir := "testdata/kodata/tekton-pipeline"
+	latest := manifestKey{component: dir, version: "0.15.2"}
+	m, err := cache.get(latest, dir+"/0.15.2")
+	util.AssertNoError(t, err)
+	want := len(m.Resources())
+
+	// the manifest isn't read again
+	m, err = cache.get(latest, "testdata/missing")
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(m.Resources()), want)
+
+	// errors aren't cached
+	_, err = cache.get(manifestKey{component: dir, version: "0.0.1"}, "testdata/missing")
+	util.AssertEqual(t, err != nil, true)
+	util.AssertEqual(t, len(cache.manifests), 1)
+
+	_, err = cache.get(manifestKey{component: dir, version: "0.14.3"}, dir+"/0.14.3")
+	util.AssertNoError(t, err)
+	_, err = cache.get(manifestKey{component: dir, version: "0.13.2"}, dir+"/0.13.2")
+	util.AssertNoError(t, err)
+	_, err = cache.get(manifestKey{version: dir + "/0.13.2"}, dir+"/0.13.2")
+	util.AssertNoError(t, err)
+	cache.retain(dir, "0.15.2", "0.14.3")
+	util.AssertEqual(t, len(cache.manifests), 3)
+	_, ok := cache.manifests[manifestKey{component: dir, version: "0.13.2"}]
+	util.AssertEqual(t, ok, false)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/metrics"
+)
+
+// The outcomes of applying a payload resource
+const (
+	applyUnchanged = "unchanged"
+	applyUpdated   = "updated"
+	applyCreated   = "created"
+	applyFailed    = "failed"
+)
+
+// The outcomes of a reconcile of a component
+const (
+	reconcileSucceeded = "succeeded"
+	reconcileFailed    = "failed"
+	// superseded by a newer generation or waiting for its installer set
+	reconcileRequeued = "requeued"
+)
+
+var (
+	applyCountStat          = stats.Int64("payload_apply_count", "Number of payload resources applied", stats.UnitDimensionless)
+	applyErrorCountStat     = stats.Int64("payload_apply_error_count", "Number of failed applies of the payload", stats.UnitDimensionless)
+	transformErrorCountStat = stats.Int64("payload_transform_error_count", "Number of failed transforms of the payload", stats.UnitDimensionless)
+	reconcileDurationStat   = stats.Float64("component_reconcile_duration", "Duration of the reconciles of the components", stats.UnitMilliseconds)
+	installSuccessTimeStat  = stats.Int64("payload_install_success_timestamp_seconds", "Unix time of the last successful reconcile of the payload, ready", stats.UnitSeconds)
+
+	componentTagKey = tag.MustNewKey("component")
+	gvkTagKey       = tag.MustNewKey("gvk")
+	outcomeTagKey   = tag.MustNewKey("outcome")
+
+	// the precedence of the outcomes of a resource applied more than once
+	// during an install
+	applyPrecedence = map[string]int{applyUnchanged: 0, applyUpdated: 1, applyCreated: 2, applyFailed: 3}
+)
+
+func init() {
+	if err := view.Register(&view.View{
+		Description: "Number of payload resources applied, by component, kind and outcome",
+		Measure:     applyCountStat,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{componentTagKey, gvkTagKey, outcomeTagKey},
+	}, &view.View{
+		Description: "Number of failed applies of the payload, by component",
+		Measure:     applyErrorCountStat,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{componentTagKey},
+	}, &view.View{
+		Description: "Number of failed transforms of the payload, by component",
+		Measure:     transformErrorCountStat,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{componentTagKey},
+	}, &view.View{
+		Description: "Duration of the reconciles of the components, by component and outcome",
+		Measure:     reconcileDurationStat,
+		Aggregation: view.Distribution(10, 100, 1000, 5000, 10000, 30000, 60000, 300000),
+		TagKeys:     []tag.Key{componentTagKey, outcomeTagKey},
+	}, &view.View{
+		Description: "Unix time of the last successful install of the payload, by component",
+		Measure:     installSuccessTimeStat,
+		Aggregation: view.LastValue(),
+		TagKeys:     []tag.Key{componentTagKey},
+	}); err != nil {
+		panic(err)
+	}
+}
+
+type applyKey struct {
+	gvk             schema.GroupVersionKind
+	namespace, name string
+}
+
+// applyRecorder is a manifestival client recording the outcome of applying
+// each resource: unchanged once it's been read, then created, updated or
+// failed by the calls which follow.
+type applyRecorder struct {
+	mf.Client
+	outcomes map[applyKey]string
+}
+
+func newApplyRecorder(client mf.Client) *applyRecorder {
+	return &applyRecorder{Client: client, outcomes: map[applyKey]string{}}
+}
+
+func (r *applyRecorder) record(u *unstructured.Unstructured, outcome string) {
+	key := applyKey{gvk: u.GroupVersionKind(), namespace: u.GetNamespace(), name: u.GetName()}
+	if current, ok := r.outcomes[key]; !ok || applyPrecedence[outcome] > applyPrecedence[current] {
+		r.outcomes[key] = outcome
+	}
+}
+
+func (r *applyRecorder) Get(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	live, err := r.Client.Get(u)
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.record(u, applyFailed)
+	} else {
+		r.record(u, applyUnchanged)
+	}
+	return live, err
+}
+
+func (r *applyRecorder) Create(u *unstructured.Unstructured, opts ...mf.ApplyOption) error {
+	err := r.Client.Create(u, opts...)
+	r.recordChange(u, applyCreated, err)
+	return err
+}
+
+func (r *applyRecorder) Update(u *unstructured.Unstructured, opts ...mf.ApplyOption) error {
+	err := r.Client.Update(u, opts...)
+	r.recordChange(u, applyUpdated, err)
+	return err
+}
+
+func (r *applyRecorder) recordChange(u *unstructured.Unstructured, outcome string, err error) {
+	if err != nil {
+		outcome = applyFailed
+	}
+	r.record(u, outcome)
+}
+
+// counts returns the number of resources by kind and outcome
+func (r *applyRecorder) counts() map[schema.GroupVersionKind]map[string]int64 {
+	counts := map[schema.GroupVersionKind]map[string]int64{}
+	for key, outcome := range r.outcomes {
+		if counts[key.gvk] == nil {
+			counts[key.gvk] = map[string]int64{}
+		}
+		counts[key.gvk][outcome]++
+	}
+	return counts
+}
+
+// report records the apply counters of the named component
+func (r *applyRecorder) report(ctx context.Context, component string) {
+	logger := logging.FromContext(ctx)
+	for gvk, outcomes := range r.counts() {
+		for outcome, n := range outcomes {
+			ctx, err := tag.New(ctx,
+				tag.Insert(componentTagKey, component),
+				tag.Insert(gvkTagKey, gvk.GroupVersion().String()+"/"+gvk.Kind),
+				tag.Insert(outcomeTagKey, outcome))
+			if err != nil {
+				logger.Errorw("Failed to tag the apply counters", "error", err)
+				return
+			}
+			metrics.Record(ctx, applyCountStat.M(n))
+		}
+	}
+}
+
+type reconcileStartKey struct{}
+
+// reportReconcile records the duration of the reconcile of the named
+// component, started when the context got its correlation ID, with its
+// outcome.
+func reportReconcile(ctx context.Context, component, outcome string) {
+	start, ok := ctx.Value(reconcileStartKey{}).(time.Time)
+	if !ok {
+		return
+	}
+	ctx, err := tag.New(ctx, tag.Insert(componentTagKey, component), tag.Insert(outcomeTagKey, outcome))
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Failed to tag the reconcile duration", "error", err)
+		return
+	}
+	metrics.Record(ctx, reconcileDurationStat.M(float64(time.Since(start))/float64(time.Millisecond)))
+}
+
+// reportError counts an error of the named component with stat
+func reportError(ctx context.Context, stat *stats.Int64Measure, component string) {
+	ctx, err := tag.New(ctx, tag.Insert(componentTagKey, component))
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Failed to tag the error counter", "error", err)
+		return
+	}
+	metrics.Record(ctx, stat.M(1))
+}
+
+// reportInstalled records the time of the last successful reconcile of the
+// named component, ready
+func reportInstalled(ctx context.Context, component string) {
+	ctx, err := tag.New(ctx, tag.Insert(componentTagKey, component))
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Failed to tag the install timestamp", "error", err)
+		return
+	}
+	metrics.Record(ctx, installSuccessTimeStat.M(ClockFrom(ctx).Now().Unix()))
+}
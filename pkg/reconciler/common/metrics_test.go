@@ -0,0 +1,184 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+	"knative.dev/pkg/metrics"
+)
+
+func TestApplyRecorder(t *testing.T) {
+	namespace := schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+	deployment := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	resources := []unstructured.Unstructured{
+		clusterScopedResource("v1", "Namespace", "tekton-pipelines"),
+		namespacedResource("apps/v1", "Deployment", "tekton-pipelines", "controller"),
+		namespacedResource("apps/v1", "Deployment", "tekton-pipelines", "webhook"),
+	}
+	// resources live in the cluster already, differing from the manifest
+	var live []unstructured.Unstructured
+	for _, resource := range resources {
+		resource := *resource.DeepCopy()
+		resource.SetLabels(map[string]string{"version": "old"})
+		live = append(live, resource)
+	}
+	tests := []struct {
+		name   string
+		client *util.FakeClient
+		want   map[schema.GroupVersionKind]map[string]int64
+	}{{
+		name:   "created",
+		client: util.NewFakeClient(),
+		want: map[schema.GroupVersionKind]map[string]int64{
+			namespace:  {applyCreated: 1},
+			deployment: {applyCreated: 2},
+		},
+	}, {
+		name:   "updated",
+		client: util.NewFakeClient(live...),
+		want: map[schema.GroupVersionKind]map[string]int64{
+			namespace:  {applyUpdated: 1},
+			deployment: {applyUpdated: 2},
+		},
+	}, {
+		name:   "failed",
+		client: &util.FakeClient{Err: errors.New("test")},
+		want: map[schema.GroupVersionKind]map[string]int64{
+			namespace: {applyFailed: 1},
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := newApplyRecorder(test.client)
+			manifest, err := mf.ManifestFrom(mf.Slice(resources), mf.UseClient(recorder))
+			util.AssertNoError(t, err)
+			// resources applied again keep the outcome of their first apply
+			_ = manifest.Apply()
+			_ = manifest.Apply()
+			util.AssertDeepEqual(t, recorder.counts(), test.want)
+		})
+	}
+}
+
+// viewRow returns the row of the named view with the tags, nil if there is
+// none.
+func viewRow(t *testing.T, name string, tags ...tag.Tag) *view.Row {
+	t.Helper()
+	rows, err := view.RetrieveData(name)
+	util.AssertNoError(t, err)
+	for _, row := range rows {
+		if len(row.Tags) != len(tags) {
+			continue
+		}
+		matched := true
+		for i := range tags {
+			if row.Tags[i] != tags[i] {
+				matched = false
+			}
+		}
+		if matched {
+			return row
+		}
+	}
+	return nil
+}
+
+func TestReconcileMetrics(t *testing.T) {
+	metrics.InitForTesting()
+	component := tag.Tag{Key: componentTagKey, Value: "metrics"}
+	instance := &v1alpha1.TektonPipeline{ObjectMeta: metav1.ObjectMeta{Name: "metrics"}}
+
+	// reconciles without a start aren't recorded
+	util.AssertNoError(t, ObserveGeneration(context.Background(), instance, nil))
+	if row := viewRow(t, "component_reconcile_duration", component, tag.Tag{Key: outcomeTagKey, Value: reconcileSucceeded}); row != nil {
+		t.Errorf("unexpected reconcile duration %v", row)
+	}
+
+	ctx := WithCorrelationID(context.Background())
+	util.AssertNoError(t, ObserveGeneration(ctx, instance, nil))
+	util.AssertNoError(t, ObserveGeneration(ctx, instance, ErrSuperseded))
+	if ObserveGeneration(ctx, instance, errors.New("boom")) == nil {
+		t.Error("expected the error of the reconcile")
+	}
+	for _, outcome := range []string{reconcileSucceeded, reconcileRequeued, reconcileFailed} {
+		row := viewRow(t, "component_reconcile_duration", component, tag.Tag{Key: outcomeTagKey, Value: outcome})
+		if row == nil {
+			t.Fatalf("no reconcile duration for the %s outcome", outcome)
+		}
+		util.AssertEqual(t, row.Data.(*view.DistributionData).Count, int64(1))
+	}
+
+	// an invalid spec ends the reconcile without error, but failed
+	invalid := instance.DeepCopy()
+	invalid.Status.InitializeConditions()
+	operrors.MarkFailed(&invalid.Status, &operrors.TransformError{Err: errors.New("invalid spec")})
+	util.AssertNoError(t, ObserveGeneration(ctx, invalid, nil))
+	row := viewRow(t, "component_reconcile_duration", component, tag.Tag{Key: outcomeTagKey, Value: reconcileFailed})
+	util.AssertEqual(t, row.Data.(*view.DistributionData).Count, int64(2))
+	row = viewRow(t, "component_reconcile_duration", component, tag.Tag{Key: outcomeTagKey, Value: reconcileSucceeded})
+	util.AssertEqual(t, row.Data.(*view.DistributionData).Count, int64(1))
+
+	reportError(ctx, transformErrorCountStat, "metrics")
+	reportError(ctx, transformErrorCountStat, "metrics")
+	row = viewRow(t, "payload_transform_error_count", component)
+	util.AssertEqual(t, row.Data.(*view.SumData).Value, float64(2))
+
+	// recorded for ready components only, whether they installed anything
+	if row := viewRow(t, "payload_install_success_timestamp_seconds", component); row != nil {
+		t.Errorf("unexpected install timestamp %v", row.Data)
+	}
+	instance.Status.MarkDependenciesInstalled()
+	instance.Status.MarkInstallSucceeded()
+	instance.Status.MarkDeploymentsAvailable()
+	util.AssertNoError(t, ObserveGeneration(ctx, instance, nil))
+	row = viewRow(t, "payload_install_success_timestamp_seconds", component)
+	if row == nil || row.Data.(*view.LastValueData).Value <= 0 {
+		t.Errorf("unexpected install timestamp %v", row)
+	}
+}
+
+func TestWorkqueueMetrics(t *testing.T) {
+	metrics.InitForTesting()
+	// the controllers export the metrics of their workqueues, by name
+	queue := workqueue.NewNamed("metrics-test")
+	defer queue.ShutDown()
+	queue.Add("item")
+
+	for _, name := range []string{"workqueue_depth", "workqueue_queue_latency_seconds", "workqueue_work_duration_seconds", "workqueue_retries_total"} {
+		if view.Find(name) == nil {
+			t.Errorf("the view %s isn't registered", name)
+		}
+	}
+	row := viewRow(t, "workqueue_depth", tag.Tag{Key: tag.MustNewKey("name"), Value: "metrics-test"})
+	if row == nil {
+		t.Fatal("the depth of the workqueue wasn't recorded")
+	}
+	util.AssertEqual(t, row.Data.(*view.LastValueData).Value, 1.0)
+}
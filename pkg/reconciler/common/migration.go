@@ -0,0 +1,259 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// migrationPageSize is the number of custom resources listed per request
+	// while rewriting objects to the new storage version.
+	migrationPageSize = 500
+	// migrationBudget is how long a reconcile rewrites objects before
+	// handing over the rest of the migration to a later reconcile, when the
+	// context can schedule one.
+	migrationBudget = 30 * time.Second
+	// migrationRequeueDelay is the delay before the reconcile resuming an
+	// interrupted migration.
+	migrationRequeueDelay = 5 * time.Second
+)
+
+// migrationProgress remembers the page an interrupted migration resumes
+// from, by CRD. It is lost on restart, the migration then starting over:
+// rewriting an object twice is harmless.
+var migrationProgress = &progress{pages: map[string]page{}}
+
+type page struct {
+	storage string
+	token   string
+}
+
+type progress struct {
+	mu    sync.Mutex
+	pages map[string]page
+}
+
+// resume returns the continue token of the page the migration of the CRD
+// to the storage version stopped at, or an empty string to start over,
+// e.g. after a rollback changed the storage version.
+func (p *progress) resume(crd, storage string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if last, ok := p.pages[crd]; ok && last.storage == storage {
+		return last.token
+	}
+	return ""
+}
+
+func (p *progress) save(crd, storage, token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if token == "" {
+		delete(p.pages, crd)
+		return
+	}
+	p.pages[crd] = page{storage: storage, token: token}
+}
+
+var crdGVR = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// MigrateStorageVersions returns a Stage which, for every CRD in the
+// manifest whose live status.storedVersions still lists versions other
+// than the current storage version, rewrites all existing objects so
+// they get persisted in the storage version and then trims
+// status.storedVersions. This lets later releases drop the old versions
+// from the CRD without the API server refusing the update.
+//
+// A migration outlasting migrationBudget is interrupted and resumed by
+// another reconcile scheduled with the Requeue of the context, the
+// remaining CRDs waiting for it.
+func MigrateStorageVersions(client dynamic.Interface) Stage {
+	return func(ctx context.Context, manifest *mf.Manifest, _ v1alpha1.TektonComponent) error {
+		logger := logging.FromContext(ctx)
+		for _, u := range manifest.Filter(mf.CRDs).Resources() {
+			live, err := client.Resource(crdGVR).Get(ctx, u.GetName(), metav1.GetOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return err
+			}
+			migrate, err := needsMigration(live)
+			if err != nil {
+				return err
+			}
+			if !migrate {
+				continue
+			}
+			logger.Infow("Migrating storage version", "crd", live.GetName())
+			done, err := migrateCRD(ctx, client, live)
+			if err != nil {
+				return fmt.Errorf("failed to migrate storage version of %s: %w", live.GetName(), err)
+			}
+			if !done {
+				logger.Infow("Storage version migration resumes in a later reconcile", "crd", live.GetName(), "after", migrationRequeueDelay)
+				return nil
+			}
+		}
+		return nil
+	}
+}
+
+// storageVersion returns the name of the version flagged with storage: true,
+// or an empty string if there is none.
+func storageVersion(crd *unstructured.Unstructured) (string, error) {
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return "", err
+	}
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if storage, _ := version["storage"].(bool); storage {
+			name, _ := version["name"].(string)
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// needsMigration reports whether objects of the CRD may still be persisted
+// in a version other than the current storage version.
+func needsMigration(crd *unstructured.Unstructured) (bool, error) {
+	storage, err := storageVersion(crd)
+	if err != nil || storage == "" {
+		return false, err
+	}
+	stored, _, err := unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+	if err != nil {
+		return false, err
+	}
+	for _, v := range stored {
+		if v != storage {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// migrateCRD rewrites the objects of the CRD in its storage version and
+// trims its status.storedVersions, returning false if it ran out of budget
+// and a later reconcile resumes it.
+func migrateCRD(ctx context.Context, client dynamic.Interface, crd *unstructured.Unstructured) (bool, error) {
+	storage, err := storageVersion(crd)
+	if err != nil {
+		return false, err
+	}
+	group, _, err := unstructured.NestedString(crd.Object, "spec", "group")
+	if err != nil {
+		return false, err
+	}
+	plural, _, err := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	if err != nil {
+		return false, err
+	}
+	resource := client.Resource(schema.GroupVersionResource{
+		Group:    group,
+		Version:  storage,
+		Resource: plural,
+	})
+
+	clock := ClockFrom(ctx)
+	start := clock.Now()
+	opts := metav1.ListOptions{Limit: migrationPageSize, Continue: migrationProgress.resume(crd.GetName(), storage)}
+	for {
+		list, err := resource.List(ctx, opts)
+		if apierrors.IsResourceExpired(err) && opts.Continue != "" {
+			// the page to resume from is gone, start over
+			opts.Continue = ""
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		for i := range list.Items {
+			if err := rewriteObject(ctx, resource, &list.Items[i]); err != nil {
+				return false, err
+			}
+		}
+		opts.Continue = list.GetContinue()
+		migrationProgress.save(crd.GetName(), storage, opts.Continue)
+		if opts.Continue == "" {
+			break
+		}
+		if clock.Since(start) >= migrationBudget && requeueAfter(ctx, migrationRequeueDelay) {
+			return false, nil
+		}
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		live, err := client.Resource(crdGVR).Get(ctx, crd.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedStringSlice(live.Object, []string{storage}, "status", "storedVersions"); err != nil {
+			return err
+		}
+		_, err = client.Resource(crdGVR).UpdateStatus(ctx, live, metav1.UpdateOptions{})
+		return err
+	})
+	return err == nil, err
+}
+
+// rewriteObject issues a no-op update so the API server persists the
+// object in the current storage version.
+func rewriteObject(ctx context.Context, resource dynamic.NamespaceableResourceInterface, obj *unstructured.Unstructured) error {
+	ri := dynamic.ResourceInterface(resource)
+	if ns := obj.GetNamespace(); ns != "" {
+		ri = resource.Namespace(ns)
+	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, err := ri.Update(ctx, obj, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) {
+			latest, getErr := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+			obj = latest
+		}
+		return err
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/dynamic"
+)
+
+func crdWithVersions(storage string, stored ...string) *unstructured.Unstructured {
+	versions := []interface{}{}
+	for _, name := range []string{"v1alpha1", "v1beta1"} {
+		versions = append(versions, map[string]interface{}{
+			"name":    name,
+			"served":  true,
+			"storage": name == storage,
+		})
+	}
+	storedVersions := []interface{}{}
+	for _, v := range stored {
+		storedVersions = append(storedVersions, v)
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"spec": map[string]interface{}{
+			"versions": versions,
+		},
+		"status": map[string]interface{}{
+			"storedVersions": storedVersions,
+		},
+	}}
+}
+
+func TestStorageVersion(t *testing.T) {
+	version, err := storageVersion(crdWithVersions("v1beta1"))
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, version, "v1beta1")
+
+	version, err = storageVersion(crdWithVersions(""))
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, version, "")
+}
+
+func TestNeedsMigration(t *testing.T) {
+	tests := []struct {
+		name string
+		crd  *unstructured.Unstructured
+		want bool
+	}{{
+		name: "only storage version stored",
+		crd:  crdWithVersions("v1beta1", "v1beta1"),
+		want: false,
+	}, {
+		name: "old version still stored",
+		crd:  crdWithVersions("v1beta1", "v1alpha1", "v1beta1"),
+		want: true,
+	}, {
+		name: "no storage version",
+		crd:  crdWithVersions("", "v1alpha1"),
+		want: false,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := needsMigration(test.crd)
+			util.AssertNoError(t, err)
+			util.AssertEqual(t, got, test.want)
+		})
+	}
+}
+
+// cluster simulates the API server during a storage version migration: the
+// CRD, the version each of its objects is persisted in and the time listing
+// a page takes
+type cluster struct {
+	crd     *unstructured.Unstructured
+	objects map[string]string
+	clock   *clock.FakeClock
+	// listLatency is the time the clock is stepped by on each list
+	listLatency time.Duration
+	// failList fails the list of the given page, counting from 1
+	failList int
+	// expired fails the next list resuming from a continue token as expired
+	expired bool
+	lists   int
+}
+
+func newCluster(storage string, stored []string, objects int) *cluster {
+	crd := crdWithVersions(storage, stored...)
+	crd.SetName("tasks.tekton.dev")
+	unstructured.SetNestedField(crd.Object, "tekton.dev", "spec", "group")
+	unstructured.SetNestedField(crd.Object, "tasks", "spec", "names", "plural")
+	c := &cluster{crd: crd, objects: map[string]string{}, clock: clock.NewFakeClock(time.Now())}
+	for i := 0; i < objects; i++ {
+		c.objects["task-"+strconv.Itoa(1000+i)] = stored[0]
+	}
+	return c
+}
+
+func (c *cluster) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &clusterResource{cluster: c, gvr: gvr}
+}
+
+func (c *cluster) storedVersions() []string {
+	stored, _, _ := unstructured.NestedStringSlice(c.crd.Object, "status", "storedVersions")
+	return stored
+}
+
+// persisted returns the number of objects persisted in each version
+func (c *cluster) persisted() map[string]int {
+	versions := map[string]int{}
+	for _, v := range c.objects {
+		versions[v]++
+	}
+	return versions
+}
+
+type clusterResource struct {
+	dynamic.NamespaceableResourceInterface
+	cluster *cluster
+	gvr     schema.GroupVersionResource
+}
+
+func (r *clusterResource) Namespace(string) dynamic.ResourceInterface {
+	return r
+}
+
+func (r *clusterResource) Get(_ context.Context, name string, _ metav1.GetOptions, _ ...string) (*unstructured.Unstructured, error) {
+	if r.gvr == crdGVR {
+		return r.cluster.crd.DeepCopy(), nil
+	}
+	if _, ok := r.cluster.objects[name]; !ok {
+		return nil, apierrors.NewNotFound(r.gvr.GroupResource(), name)
+	}
+	u := &unstructured.Unstructured{}
+	u.SetName(name)
+	return u, nil
+}
+
+func (r *clusterResource) List(_ context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	c := r.cluster
+	c.lists++
+	c.clock.Step(c.listLatency)
+	if c.lists == c.failList {
+		return nil, errors.New("connection reset")
+	}
+	if c.expired && opts.Continue != "" {
+		c.expired = false
+		return nil, apierrors.NewResourceExpired("continue token expired")
+	}
+	names := make([]string, 0, len(c.objects))
+	for name := range c.objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	start, _ := strconv.Atoi(opts.Continue)
+	end := start + int(opts.Limit)
+	list := &unstructured.UnstructuredList{}
+	if end < len(names) {
+		list.SetContinue(strconv.Itoa(end))
+	} else {
+		end = len(names)
+	}
+	for _, name := range names[start:end] {
+		u := unstructured.Unstructured{}
+		u.SetName(name)
+		list.Items = append(list.Items, u)
+	}
+	return list, nil
+}
+
+func (r *clusterResource) Update(_ context.Context, obj *unstructured.Unstructured, _ metav1.UpdateOptions, _ ...string) (*unstructured.Unstructured, error) {
+	r.cluster.objects[obj.GetName()] = r.gvr.Version
+	return obj, nil
+}
+
+func (r *clusterResource) UpdateStatus(_ context.Context, obj *unstructured.Unstructured, _ metav1.UpdateOptions) (*unstructured.Unstructured, error) {
+	r.cluster.crd = obj.DeepCopy()
+	return obj, nil
+}
+
+// migrate runs the migration stage as a reconcile would, returning the
+// delays of the reconciles it scheduled
+func (c *cluster) migrate(t *testing.T) ([]time.Duration, error) {
+	t.Helper()
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{*c.crd}))
+	util.AssertNoError(t, err)
+	var requeues []time.Duration
+	ctx := WithClock(context.Background(), c.clock)
+	ctx = WithRequeue(ctx, func(after time.Duration) {
+		requeues = append(requeues, after)
+	})
+	err = MigrateStorageVersions(c)(ctx, &manifest, &v1alpha1.TektonPipeline{})
+	return requeues, err
+}
+
+func TestMigrateStorageVersionsMultiStep(t *testing.T) {
+	migrationProgress = &progress{pages: map[string]page{}}
+	c := newCluster("v1beta1", []string{"v1alpha1"}, 4*migrationPageSize+1)
+	c.listLatency = migrationBudget / 2
+
+	// every reconcile rewrites the pages listed within the budget
+	for step := 1; step <= 2; step++ {
+		requeues, err := c.migrate(t)
+		util.AssertNoError(t, err)
+		util.AssertDeepEqual(t, requeues, []time.Duration{migrationRequeueDelay})
+		util.AssertEqual(t, c.persisted()["v1beta1"], 2*step*migrationPageSize)
+		util.AssertDeepEqual(t, c.storedVersions(), []string{"v1alpha1"})
+	}
+	requeues, err := c.migrate(t)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(requeues), 0)
+	util.AssertDeepEqual(t, c.persisted(), map[string]int{"v1beta1": 4*migrationPageSize + 1})
+	util.AssertDeepEqual(t, c.storedVersions(), []string{"v1beta1"})
+	util.AssertEqual(t, c.lists, 5)
+
+	// nothing left to migrate
+	c.lists = 0
+	_, err = c.migrate(t)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, c.lists, 0)
+}
+
+func TestMigrateStorageVersionsWithoutRequeue(t *testing.T) {
+	migrationProgress = &progress{pages: map[string]page{}}
+	c := newCluster("v1beta1", []string{"v1alpha1"}, 3*migrationPageSize)
+	c.listLatency = migrationBudget
+
+	// without a way to resume, the migration runs to the end
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{*c.crd}))
+	util.AssertNoError(t, err)
+	ctx := WithClock(context.Background(), c.clock)
+	util.AssertNoError(t, MigrateStorageVersions(c)(ctx, &manifest, &v1alpha1.TektonPipeline{}))
+	util.AssertDeepEqual(t, c.persisted(), map[string]int{"v1beta1": 3 * migrationPageSize})
+	util.AssertDeepEqual(t, c.storedVersions(), []string{"v1beta1"})
+}
+
+func TestMigrateStorageVersionsInterrupted(t *testing.T) {
+	migrationProgress = &progress{pages: map[string]page{}}
+	c := newCluster("v1beta1", []string{"v1alpha1"}, 3*migrationPageSize)
+	c.failList = 2
+
+	_, err := c.migrate(t)
+	if err == nil {
+		t.Fatal("expected the migration to fail with the list")
+	}
+	util.AssertEqual(t, c.persisted()["v1beta1"], migrationPageSize)
+	util.AssertDeepEqual(t, c.storedVersions(), []string{"v1alpha1"})
+
+	// the next reconcile resumes from the page that failed
+	_, err = c.migrate(t)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, c.lists, 4)
+	util.AssertDeepEqual(t, c.persisted(), map[string]int{"v1beta1": 3 * migrationPageSize})
+	util.AssertDeepEqual(t, c.storedVersions(), []string{"v1beta1"})
+}
+
+func TestMigrateStorageVersionsExpiredPage(t *testing.T) {
+	migrationProgress = &progress{pages: map[string]page{}}
+	c := newCluster("v1beta1", []string{"v1alpha1"}, 2*migrationPageSize)
+	c.listLatency = migrationBudget
+
+	_, err := c.migrate(t)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, c.persisted()["v1beta1"], migrationPageSize)
+
+	// the continue token expired by the next reconcile, the migration
+	// starts over
+	c.expired = true
+	c.listLatency = 0
+	c.lists = 0
+	_, err = c.migrate(t)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, c.lists, 3)
+	util.AssertEqual(t, c.persisted()["v1beta1"], 2*migrationPageSize)
+}
+
+func TestMigrateStorageVersionsRollback(t *testing.T) {
+	migrationProgress = &progress{pages: map[string]page{}}
+	c := newCluster("v1beta1", []string{"v1alpha1"}, 3*migrationPageSize)
+	c.listLatency = migrationBudget
+
+	_, err := c.migrate(t)
+	util.AssertNoError(t, err)
+	util.AssertDeepEqual(t, c.persisted(), map[string]int{"v1alpha1": 2 * migrationPageSize, "v1beta1": migrationPageSize})
+
+	// a rollback restores v1alpha1 as the storage version, while objects are
+	// persisted in both versions: the migration starts over in v1alpha1
+	// rather than resuming the page of v1beta1
+	c.crd = crdWithVersions("v1alpha1", "v1alpha1", "v1beta1")
+	c.crd.SetName("tasks.tekton.dev")
+	unstructured.SetNestedField(c.crd.Object, "tekton.dev", "spec", "group")
+	unstructured.SetNestedField(c.crd.Object, "tasks", "spec", "names", "plural")
+	c.listLatency = 0
+	c.lists = 0
+	requeues, err := c.migrate(t)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(requeues), 0)
+	util.AssertEqual(t, c.lists, 3)
+	util.AssertDeepEqual(t, c.persisted(), map[string]int{"v1alpha1": 3 * migrationPageSize})
+	util.AssertDeepEqual(t, c.storedVersions(), []string{"v1alpha1"})
+}
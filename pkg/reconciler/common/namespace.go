@@ -0,0 +1,217 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	clientset "github.com/tektoncd/operator/pkg/client/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// LabelPartOf is the recommended label naming the application a resource belongs to
+	LabelPartOf = "app.kubernetes.io/part-of"
+	// tektonPartOfPrefix prefixes the part-of label value of all Tekton payload resources
+	tektonPartOfPrefix = "tekton"
+)
+
+// filterUnmanagedNamespace drops the Namespace resources from the manifest
+// when the component does not own its target namespace, so that a
+// pre-existing namespace is neither adopted on install nor deleted on
+// uninstall.
+func filterUnmanagedNamespace(manifest mf.Manifest, instance v1alpha1.TektonComponent) mf.Manifest {
+	if instance.GetSpec().IsTargetNamespaceManaged() {
+		return manifest
+	}
+	return manifest.Filter(mf.Not(namespace))
+}
+
+// releaseUnmanagedNamespace removes the owner reference to the instance
+// from its target namespace once the component no longer owns it, for the
+// garbage collector not to delete the namespace with the instance.
+func releaseUnmanagedNamespace(client mf.Client, instance v1alpha1.TektonComponent) error {
+	if client == nil || instance.GetSpec().IsTargetNamespaceManaged() {
+		return nil
+	}
+	name := instance.GetSpec().GetTargetNamespace()
+	ns := clusterScopedResource("v1", "Namespace", name)
+	live, err := client.Get(&ns)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	refs := []metav1.OwnerReference{}
+	for _, ref := range live.GetOwnerReferences() {
+		if ref.UID == instance.GetUID() && ref.Name == instance.GetName() {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	if len(refs) == len(live.GetOwnerReferences()) {
+		return nil
+	}
+	live.SetOwnerReferences(refs)
+	if err := client.Update(live); err != nil {
+		return fmt.Errorf("failed to release namespace %s: %w", name, err)
+	}
+	return nil
+}
+
+// ProtectTargetNamespace returns a Stage meant to run before Uninstall. If
+// a namespace in the manifest still holds workloads which are not part of
+// Tekton, it is dropped from the manifest and the owner reference to the
+// instance is removed, so that neither Uninstall nor the garbage collector
+// deletes it.
+func ProtectTargetNamespace(kubeClient kubernetes.Interface) Stage {
+	return func(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+		logger := logging.FromContext(ctx)
+		for _, u := range manifest.Filter(namespace).Resources() {
+			foreign, err := foreignResources(ctx, kubeClient, u.GetName())
+			if err != nil {
+				return err
+			}
+			if len(foreign) == 0 {
+				continue
+			}
+			logger.Warnw("Namespace contains non-Tekton resources and will not be deleted",
+				"namespace", u.GetName(), "resources", foreign)
+			if err := releaseNamespace(ctx, kubeClient, u.GetName(), instance); err != nil {
+				return err
+			}
+			*manifest = manifest.Filter(mf.Not(mf.All(namespace, mf.ByName(u.GetName()))))
+		}
+		return nil
+	}
+}
+
+// foreignResources lists the workloads and services in the namespace which
+// don't carry a Tekton part-of label, formatted as kind/name.
+func foreignResources(ctx context.Context, kubeClient kubernetes.Interface, ns string) ([]string, error) {
+	var foreign []string
+
+	deployments, err := kubeClient.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deployments.Items {
+		if !isTektonResource(d.ObjectMeta) {
+			foreign = append(foreign, "Deployment/"+d.Name)
+		}
+	}
+
+	statefulSets, err := kubeClient.AppsV1().StatefulSets(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range statefulSets.Items {
+		if !isTektonResource(s.ObjectMeta) {
+			foreign = append(foreign, "StatefulSet/"+s.Name)
+		}
+	}
+
+	daemonSets, err := kubeClient.AppsV1().DaemonSets(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range daemonSets.Items {
+		if !isTektonResource(d.ObjectMeta) {
+			foreign = append(foreign, "DaemonSet/"+d.Name)
+		}
+	}
+
+	services, err := kubeClient.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range services.Items {
+		if !isTektonResource(s.ObjectMeta) {
+			foreign = append(foreign, "Service/"+s.Name)
+		}
+	}
+
+	return foreign, nil
+}
+
+func isTektonResource(meta metav1.ObjectMeta) bool {
+	return strings.HasPrefix(meta.Labels[LabelPartOf], tektonPartOfPrefix)
+}
+
+// releaseNamespace removes the owner reference to the instance from the
+// namespace.
+func releaseNamespace(ctx context.Context, kubeClient kubernetes.Interface, name string, instance v1alpha1.TektonComponent) error {
+	ns, err := kubeClient.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	refs := []metav1.OwnerReference{}
+	for _, ref := range ns.OwnerReferences {
+		if ref.UID == instance.GetUID() && ref.Name == instance.GetName() {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	if len(refs) == len(ns.OwnerReferences) {
+		return nil
+	}
+	ns.OwnerReferences = refs
+	if _, err := kubeClient.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to release namespace %s: %w", name, err)
+	}
+	return nil
+}
+
+// ExcludedNamespaces returns spec.excludedNamespaces of the TektonConfig,
+// which the automation of user namespaces must leave alone. There are none
+// without a TektonConfig.
+func ExcludedNamespaces(ctx context.Context, operatorClient clientset.Interface) ([]string, error) {
+	config, err := operatorClient.OperatorV1alpha1().TektonConfigs().Get(ctx, ConfigResourceName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return config.Spec.ExcludedNamespaces, nil
+}
+
+// NamespaceExcluded returns true if the namespace is one of the excluded
+// namespaces or matches one of their glob patterns.
+func NamespaceExcluded(excluded []string, namespace string) bool {
+	for _, pattern := range excluded {
+		if pattern == namespace {
+			return true
+		}
+		if matched, err := path.Match(pattern, namespace); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
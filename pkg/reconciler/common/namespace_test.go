@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"knative.dev/pkg/ptr"
+)
+
+func TestTransformUnmanagedNamespace(t *testing.T) {
+	in := []unstructured.Unstructured{
+		clusterScopedResource("v1", "Namespace", "tekton-pipelines"),
+		namespacedResource("v1", "ConfigMap", "tekton-pipelines", "config-defaults"),
+	}
+	component := &v1alpha1.TektonPipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "pipeline"},
+		Spec: v1alpha1.TektonPipelineSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "tekton-pipelines"},
+		},
+	}
+
+	manifest, err := mf.ManifestFrom(mf.Slice(in))
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, Transform(context.Background(), &manifest, component))
+	util.AssertEqual(t, len(manifest.Filter(mf.ByKind("Namespace")).Resources()), 1)
+
+	component.Spec.ManageTargetNamespace = ptr.Bool(false)
+	manifest, err = mf.ManifestFrom(mf.Slice(in))
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, Transform(context.Background(), &manifest, component))
+	util.AssertEqual(t, len(manifest.Filter(mf.ByKind("Namespace")).Resources()), 0)
+	util.AssertEqual(t, len(manifest.Resources()), 1)
+}
+
+func TestReleaseUnmanagedNamespace(t *testing.T) {
+	component := &v1alpha1.TektonPipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "pipeline", UID: "pipeline-uid"},
+		Spec: v1alpha1.TektonPipelineSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "tekton-pipelines"},
+		},
+	}
+	ns := clusterScopedResource("v1", "Namespace", "tekton-pipelines")
+	ns.SetOwnerReferences([]metav1.OwnerReference{
+		{APIVersion: "v1alpha1", Kind: "TektonPipeline", Name: "pipeline", UID: "pipeline-uid"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid"},
+	})
+	client := util.NewFakeClient(ns)
+
+	// still managed: the namespace is the component's
+	util.AssertNoError(t, releaseUnmanagedNamespace(client, component))
+	util.AssertEqual(t, len(client.Updates), 0)
+
+	component.Spec.ManageTargetNamespace = ptr.Bool(false)
+	util.AssertNoError(t, releaseUnmanagedNamespace(client, component))
+	util.AssertEqual(t, len(client.Updates), 1)
+	live, err := client.Get(&ns)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(live.GetOwnerReferences()), 1)
+	util.AssertEqual(t, live.GetOwnerReferences()[0].Name, "other")
+
+	// released already
+	util.AssertNoError(t, releaseUnmanagedNamespace(client, component))
+	util.AssertEqual(t, len(client.Updates), 1)
+
+	// the namespace is gone
+	util.AssertNoError(t, releaseUnmanagedNamespace(util.NewFakeClient(), component))
+}
+
+func TestIsTektonResource(t *testing.T) {
+	util.AssertEqual(t, isTektonResource(metav1.ObjectMeta{
+		Labels: map[string]string{LabelPartOf: "tekton-pipelines"},
+	}), true)
+	util.AssertEqual(t, isTektonResource(metav1.ObjectMeta{
+		Labels: map[string]string{LabelPartOf: "my-app"},
+	}), false)
+	util.AssertEqual(t, isTektonResource(metav1.ObjectMeta{}), false)
+}
+
+func TestExcludedNamespaces(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	excluded, err := ExcludedNamespaces(context.Background(), client)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(excluded), 0)
+
+	client = fake.NewSimpleClientset(&v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigResourceName},
+		Spec:       v1alpha1.TektonConfigSpec{ExcludedNamespaces: []string{"default", "kube-*", "[bad"}},
+	})
+	excluded, err = ExcludedNamespaces(context.Background(), client)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, NamespaceExcluded(excluded, "default"), true)
+	util.AssertEqual(t, NamespaceExcluded(excluded, "kube-system"), true)
+	util.AssertEqual(t, NamespaceExcluded(excluded, "[bad"), true)
+	util.AssertEqual(t, NamespaceExcluded(excluded, "team-a"), false)
+}
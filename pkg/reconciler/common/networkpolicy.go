@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// apiServerPorts are the ports the API server commonly listens on, 443 also
+// being the one of the registries images are resolved from
+var apiServerPorts = []int32{443, 6443}
+
+// NetworkPolicies is a Stage appending a NetworkPolicy for every payload
+// deployment when spec.config.networkPolicy is set, so that they keep
+// working in namespaces denying traffic by default: webhooks accept calls
+// of the API server and dashboards of their users on their container ports,
+// and every deployment may reach the API server, DNS and the pods of its
+// namespace. The policies go through the common transformers, like the rest
+// of the payload.
+func NetworkPolicies(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	if !instance.GetSpec().GetConfig().NetworkPolicy {
+		return nil
+	}
+	return appendGenerated(ctx, manifest, instance, func(deployment *appsv1.Deployment) runtime.Object {
+		return networkPolicy(deployment)
+	})
+}
+
+// networkPolicy returns the NetworkPolicy of the pods of a deployment
+func networkPolicy(deployment *appsv1.Deployment) *networkingv1.NetworkPolicy {
+	tcp, udp := corev1.ProtocolTCP, corev1.ProtocolUDP
+	port := func(protocol corev1.Protocol, port int32) networkingv1.NetworkPolicyPort {
+		p := intstr.FromInt(int(port))
+		return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &p}
+	}
+
+	var apiServer []networkingv1.NetworkPolicyPort
+	for _, p := range apiServerPorts {
+		apiServer = append(apiServer, port(tcp, p))
+	}
+	policy := &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.Name,
+			Namespace: deployment.Namespace,
+			Labels:    deployment.Labels,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{Ports: apiServer},
+				{Ports: []networkingv1.NetworkPolicyPort{port(udp, 53), port(tcp, 53)}},
+				{To: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}},
+			},
+		},
+	}
+	if deployment.Spec.Selector != nil {
+		policy.Spec.PodSelector = *deployment.Spec.Selector
+	}
+
+	if strings.Contains(deployment.Name, "webhook") || strings.Contains(deployment.Name, "dashboard") {
+		var ports []networkingv1.NetworkPolicyPort
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			for _, p := range container.Ports {
+				protocol := p.Protocol
+				if protocol == "" {
+					protocol = tcp
+				}
+				ports = append(ports, port(protocol, p.ContainerPort))
+			}
+		}
+		policy.Spec.PolicyTypes = append([]networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes...)
+		policy.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{Ports: ports}}
+	}
+	return policy
+}
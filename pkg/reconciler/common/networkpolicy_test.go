@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestNetworkPolicies(t *testing.T) {
+	controller := util.MakeUnstructured(t, util.MakeDeployment("tekton-pipelines-controller", corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "controller", Image: "gcr.io/controller:v1"}},
+	}))
+	webhook := util.MakeUnstructured(t, util.MakeDeployment("tekton-pipelines-webhook", corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:  "webhook",
+			Image: "gcr.io/webhook:v1",
+			Ports: []corev1.ContainerPort{{Name: "https-webhook", ContainerPort: 8443}},
+		}},
+	}))
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{controller, webhook}))
+	util.AssertNoError(t, err)
+
+	instance := &v1alpha1.TektonPipeline{}
+	instance.Spec.TargetNamespace = "tekton-pipelines"
+	util.AssertNoError(t, NetworkPolicies(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(manifest.Resources()), 2)
+
+	instance.Spec.Config.NetworkPolicy = true
+	util.AssertNoError(t, NetworkPolicies(context.Background(), &manifest, instance))
+	policies := map[string]*networkingv1.NetworkPolicy{}
+	for _, u := range manifest.Filter(mf.ByKind("NetworkPolicy")).Resources() {
+		policy := &networkingv1.NetworkPolicy{}
+		util.AssertNoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, policy))
+		util.AssertEqual(t, policy.Namespace, "tekton-pipelines")
+		policies[policy.Name] = policy
+	}
+	util.AssertEqual(t, len(policies), 2)
+
+	policy := policies["tekton-pipelines-controller"]
+	util.AssertDeepEqual(t, policy.Spec.PolicyTypes, []networkingv1.PolicyType{networkingv1.PolicyTypeEgress})
+	util.AssertEqual(t, len(policy.Spec.Egress), 3)
+
+	policy = policies["tekton-pipelines-webhook"]
+	util.AssertDeepEqual(t, policy.Spec.PolicyTypes, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress})
+	util.AssertEqual(t, len(policy.Spec.Ingress), 1)
+	util.AssertEqual(t, policy.Spec.Ingress[0].Ports[0].Port.IntValue(), 8443)
+	util.AssertEqual(t, *policy.Spec.Ingress[0].Ports[0].Protocol, corev1.ProtocolTCP)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// observabilityDir is the directory of a payload version holding its
+	// monitoring bundle
+	observabilityDir = "observability"
+	// openTelemetryComponent is the app.kubernetes.io/component label of
+	// the OpenTelemetry collector configuration of a bundle
+	openTelemetryComponent = "opentelemetry-collector"
+)
+
+// monitorKinds are the Prometheus Operator kinds selecting the namespaces
+// they scrape
+var monitorKinds = sets.NewString("ServiceMonitor", "PodMonitor")
+
+// Observability is a Stage appending the monitoring bundle shipped with the
+// target payload version when spec.config.observability is set. The bundle
+// is deployed to the namespace of spec.config.observability, the target
+// namespace when empty, and scrapes the target namespace.
+func Observability(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	return appendObservability(ctx, manifest, instance, TargetVersion(instance))
+}
+
+// InstalledObservability is the Observability Stage of the installed
+// payload version, so that bundles of former versions are deleted with it.
+func InstalledObservability(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	version := instance.GetStatus().GetVersion()
+	if version == "" {
+		version = TargetVersion(instance)
+	}
+	return appendObservability(ctx, manifest, instance, version)
+}
+
+func appendObservability(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent, version string) error {
+	config := instance.GetSpec().GetConfig().Observability
+	if config == nil {
+		return nil
+	}
+	logger := logging.FromContext(ctx)
+	dir := filepath.Join(ComponentDir(instance), version, observabilityDir)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		logger.Infow("No observability bundle is shipped with the payload", "version", version)
+		return nil
+	}
+	bundle, err := Fetch(dir)
+	if err != nil {
+		return err
+	}
+	if !config.OpenTelemetry {
+		bundle = bundle.Filter(mf.Not(openTelemetry))
+	}
+
+	namespace := config.Namespace
+	if namespace == "" {
+		namespace = instance.GetSpec().GetTargetNamespace()
+	}
+	if config.CreateNamespace {
+		create, err := createsNamespace(manifest.Client, namespace)
+		if err != nil {
+			return err
+		}
+		if create {
+			ns := unstructured.Unstructured{}
+			ns.SetAPIVersion("v1")
+			ns.SetKind("Namespace")
+			ns.SetLabels(map[string]string{LabelGenerated: "true"})
+			namespaced, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{ns}))
+			if err != nil {
+				return err
+			}
+			bundle = namespaced.Append(bundle)
+		} else {
+			logger.Infow("Not adopting the existing observability namespace", "namespace", namespace)
+		}
+	}
+	transformers := []mf.Transformer{
+		mf.InjectNamespace(namespace),
+		monitorNamespaceSelector(instance.GetSpec().GetTargetNamespace()),
+		ResourceLabels(instance.GetSpec().GetLabels()),
+		ResourceAnnotations(instance.GetSpec().GetAnnotations()),
+	}
+	if !isRemote(instance) {
+		transformers = append([]mf.Transformer{mf.InjectOwner(instance)}, transformers...)
+	}
+	if bundle, err = bundle.Transform(transformers...); err != nil {
+		err = &operrors.TransformError{Err: err}
+		operrors.MarkFailed(instance.GetStatus(), err)
+		return err
+	}
+	*manifest = manifest.Append(bundle)
+	return nil
+}
+
+// createsNamespace returns true if the bundle holds the namespace called
+// name: it doesn't exist yet or the operator created it. A namespace
+// created otherwise is never adopted, as the owner reference of the bundle
+// would have it deleted with the component.
+func createsNamespace(client mf.Client, name string) (bool, error) {
+	if client == nil {
+		return true, nil
+	}
+	ns := clusterScopedResource("v1", "Namespace", name)
+	live, err := client.Get(&ns)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return live.GetLabels()[LabelGenerated] == "true", nil
+}
+
+// openTelemetry selects the OpenTelemetry collector configuration of a
+// bundle
+func openTelemetry(u *unstructured.Unstructured) bool {
+	return u.GetLabels()["app.kubernetes.io/component"] == openTelemetryComponent
+}
+
+// monitorNamespaceSelector makes ServiceMonitors and PodMonitors scrape the
+// given namespace only.
+func monitorNamespaceSelector(namespace string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if !monitorKinds.Has(u.GetKind()) {
+			return nil
+		}
+		return unstructured.SetNestedStringSlice(u.Object, []string{namespace}, "spec", "namespaceSelector", "matchNames")
+	}
+}
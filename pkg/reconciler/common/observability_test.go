@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestObservability(t *testing.T) {
+	os.Setenv(KoEnvKey, "testdata/kodata")
+	defer os.Unsetenv(KoEnvKey)
+
+	instance := &v1alpha1.TektonPipeline{
+		Spec: v1alpha1.TektonPipelineSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "tekton-pipelines"},
+		},
+	}
+	manifest, err := mf.ManifestFrom(mf.Slice{})
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, Observability(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(manifest.Resources()), 0)
+
+	instance.Spec.Config.Observability = &v1alpha1.Observability{Namespace: "monitoring", CreateNamespace: true}
+	util.AssertNoError(t, Observability(context.Background(), &manifest, instance))
+	resources := manifest.Resources()
+	util.AssertEqual(t, len(resources), 2)
+	util.AssertEqual(t, resources[0].GetKind(), "Namespace")
+	util.AssertEqual(t, resources[0].GetName(), "monitoring")
+	util.AssertEqual(t, resources[1].GetKind(), "ServiceMonitor")
+	util.AssertEqual(t, resources[1].GetNamespace(), "monitoring")
+	matchNames, _, _ := unstructured.NestedStringSlice(resources[1].Object, "spec", "namespaceSelector", "matchNames")
+	util.AssertDeepEqual(t, matchNames, []string{"tekton-pipelines"})
+	util.AssertEqual(t, len(resources[1].GetOwnerReferences()), 1)
+
+	// an existing namespace is never adopted, unless the operator created it
+	monitoring := clusterScopedResource("v1", "Namespace", "monitoring")
+	manifest, err = mf.ManifestFrom(mf.Slice{}, mf.UseClient(util.NewFakeClient(monitoring)))
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, Observability(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(manifest.Filter(mf.ByKind("Namespace")).Resources()), 0)
+	util.AssertEqual(t, len(manifest.Resources()), 1)
+
+	monitoring.SetLabels(map[string]string{LabelGenerated: "true"})
+	manifest, err = mf.ManifestFrom(mf.Slice{}, mf.UseClient(util.NewFakeClient(monitoring)))
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, Observability(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(manifest.Filter(mf.ByKind("Namespace")).Resources()), 1)
+
+	instance.Spec.Config.Observability = &v1alpha1.Observability{OpenTelemetry: true}
+	manifest, err = mf.ManifestFrom(mf.Slice{})
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, Observability(context.Background(), &manifest, instance))
+	resources = manifest.Resources()
+	util.AssertEqual(t, len(resources), 2)
+	for _, u := range resources {
+		util.AssertEqual(t, u.GetNamespace(), "tekton-pipelines")
+	}
+	util.AssertEqual(t, len(manifest.Filter(openTelemetry).Resources()), 1)
+}
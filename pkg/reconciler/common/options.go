@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	OptionsProfileDevelopment = "development"
+	OptionsProfileStaging     = "staging"
+	OptionsProfileProduction  = "production"
+)
+
+// optionsProfile bundles the recommended settings of the payload
+// deployments for an environment.
+type optionsProfile struct {
+	// webhookReplicas of the webhook deployments. Controllers elect a
+	// leader and keep the replicas of the release.
+	webhookReplicas int64
+	// resources of every container
+	resources corev1.ResourceRequirements
+	// timings of the existing liveness and readiness probes
+	probePeriodSeconds    int32
+	probeTimeoutSeconds   int32
+	probeFailureThreshold int32
+}
+
+var optionsProfiles = map[string]optionsProfile{
+	OptionsProfileDevelopment: {
+		webhookReplicas: 1,
+		resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+		},
+		probePeriodSeconds:    30,
+		probeTimeoutSeconds:   5,
+		probeFailureThreshold: 5,
+	},
+	OptionsProfileStaging: {
+		webhookReplicas: 1,
+		resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+		},
+		probePeriodSeconds:    15,
+		probeTimeoutSeconds:   5,
+		probeFailureThreshold: 3,
+	},
+	OptionsProfileProduction: {
+		webhookReplicas: 2,
+		resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("250m"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		},
+		probePeriodSeconds:    10,
+		probeTimeoutSeconds:   5,
+		probeFailureThreshold: 3,
+	},
+}
+
+// OptionsProfile returns a transformer applying the settings of the named
+// options profile to the payload deployments: the replicas of the webhooks,
+// the resources of every container and the timings of their probes. It is
+// a no-op when name is empty.
+func OptionsProfile(name string) mf.Transformer {
+	profile, ok := optionsProfiles[name]
+	return func(u *unstructured.Unstructured) error {
+		if name == "" || u.GetKind() != "Deployment" {
+			return nil
+		}
+		if !ok {
+			return fmt.Errorf("unknown options profile %q, expected one of %s, %s or %s",
+				name, OptionsProfileDevelopment, OptionsProfileStaging, OptionsProfileProduction)
+		}
+
+		if strings.Contains(u.GetName(), "webhook") {
+			if err := unstructured.SetNestedField(u.Object, profile.webhookReplicas, "spec", "replicas"); err != nil {
+				return err
+			}
+		}
+
+		template, found, err := unstructured.NestedMap(u.Object, "spec", "template", "spec")
+		if err != nil || !found {
+			return err
+		}
+		podSpec := &corev1.PodSpec{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template, podSpec); err != nil {
+			return err
+		}
+		for i := range podSpec.Containers {
+			container := &podSpec.Containers[i]
+			container.Resources = *profile.resources.DeepCopy()
+			for _, probe := range []*corev1.Probe{container.LivenessProbe, container.ReadinessProbe} {
+				if probe == nil {
+					continue
+				}
+				probe.PeriodSeconds = profile.probePeriodSeconds
+				probe.TimeoutSeconds = profile.probeTimeoutSeconds
+				probe.FailureThreshold = profile.probeFailureThreshold
+			}
+		}
+		unstrObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(podSpec)
+		if err != nil {
+			return err
+		}
+		return unstructured.SetNestedMap(u.Object, unstrObj, "spec", "template", "spec")
+	}
+}
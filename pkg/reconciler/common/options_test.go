@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestOptionsProfile(t *testing.T) {
+	podSpec := corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:          "webhook",
+			Image:         "gcr.io/webhook:v1",
+			LivenessProbe: &corev1.Probe{PeriodSeconds: 1},
+		}},
+	}
+	webhook := util.MakeUnstructured(t, util.MakeDeployment("tekton-pipelines-webhook", podSpec))
+	controller := util.MakeUnstructured(t, util.MakeDeployment("tekton-pipelines-controller", podSpec))
+
+	util.AssertNoError(t, OptionsProfile(OptionsProfileProduction)(&webhook))
+	util.AssertNoError(t, OptionsProfile(OptionsProfileProduction)(&controller))
+
+	deployment := &appsv1.Deployment{}
+	util.AssertNoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(webhook.Object, deployment))
+	util.AssertEqual(t, *deployment.Spec.Replicas, int32(2))
+	container := deployment.Spec.Template.Spec.Containers[0]
+	util.AssertEqual(t, container.Resources.Limits.Memory().Cmp(resource.MustParse("1Gi")), 0)
+	util.AssertEqual(t, container.Resources.Requests.Cpu().Cmp(resource.MustParse("250m")), 0)
+	util.AssertEqual(t, container.LivenessProbe.PeriodSeconds, int32(10))
+	util.AssertEqual(t, container.LivenessProbe.FailureThreshold, int32(3))
+	if container.ReadinessProbe != nil {
+		t.Error("OptionsProfile() added a readiness probe, wanted only the existing probes tuned")
+	}
+
+	deployment = &appsv1.Deployment{}
+	util.AssertNoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(controller.Object, deployment))
+	if deployment.Spec.Replicas != nil {
+		t.Errorf("OptionsProfile() set the replicas of a controller to %d", *deployment.Spec.Replicas)
+	}
+	util.AssertEqual(t, deployment.Spec.Template.Spec.Containers[0].Resources.Requests.Memory().Cmp(resource.MustParse("256Mi")), 0)
+}
+
+func TestOptionsProfileUnknown(t *testing.T) {
+	deployment := util.MakeUnstructured(t, util.MakeDeployment("controller", corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "controller", Image: "gcr.io/controller:v1"}},
+	}))
+	unchanged := deployment.DeepCopy()
+
+	util.AssertNoError(t, OptionsProfile("")(&deployment))
+	util.AssertDeepEqual(t, &deployment, unchanged)
+
+	if err := OptionsProfile("huge")(&deployment); err == nil {
+		t.Fatal("OptionsProfile() = nil, wanted an error for an unknown profile")
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	listers "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientcache "k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/system"
+)
+
+type overridesKey struct{}
+
+// WithOverrides attaches the TektonOverrides of the component being
+// reconciled to the context, by increasing precedence, for Transform to
+// layer them on top of the spec of the component.
+func WithOverrides(ctx context.Context, overrides []*v1alpha1.TektonOverride) context.Context {
+	return context.WithValue(ctx, overridesKey{}, overrides)
+}
+
+// overridesFromContext returns the overrides attached to the context
+func overridesFromContext(ctx context.Context) []*v1alpha1.TektonOverride {
+	overrides, _ := ctx.Value(overridesKey{}).([]*v1alpha1.TektonOverride)
+	return overrides
+}
+
+// ComponentOverrides returns the TektonOverrides of the operator namespace
+// applying to components of the given kind, by increasing precedence:
+// priority, then name. Those of other namespaces are ignored, since the
+// components are cluster wide.
+func ComponentOverrides(lister listers.TektonOverrideLister, kind string) ([]*v1alpha1.TektonOverride, error) {
+	all, err := lister.TektonOverrides(system.Namespace()).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var overrides []*v1alpha1.TektonOverride
+	for _, o := range all {
+		if o.Targets(kind) {
+			overrides = append(overrides, o)
+		}
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		a, b := overrides[i], overrides[j]
+		if a.Spec.Priority != b.Spec.Priority {
+			return a.Spec.Priority < b.Spec.Priority
+		}
+		return a.Name < b.Name
+	})
+	return overrides, nil
+}
+
+// EnqueueOnOverride returns an event handler for the TektonOverride
+// informer, enqueuing the component of the given kind and name whenever an
+// override of the operator namespace applying to it, before or after the
+// change, changes.
+func EnqueueOnOverride(enqueueKey func(types.NamespacedName), kind, name string) clientcache.ResourceEventHandler {
+	targets := func(obj interface{}) bool {
+		if tombstone, ok := obj.(clientcache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		o, ok := obj.(*v1alpha1.TektonOverride)
+		return ok && o.Namespace == system.Namespace() && o.Targets(kind)
+	}
+	enqueue := func() {
+		enqueueKey(types.NamespacedName{Name: name})
+	}
+	return clientcache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if targets(obj) {
+				enqueue()
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if targets(oldObj) || targets(newObj) {
+				enqueue()
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if targets(obj) {
+				enqueue()
+			}
+		},
+	}
+}
+
+// overrideMetadata returns the transformers of the labels and annotations
+// of the overrides. They are meant to run before those of the component,
+// which don't replace the values already set, so they go by decreasing
+// precedence.
+func overrideMetadata(overrides []*v1alpha1.TektonOverride) []mf.Transformer {
+	var transformers []mf.Transformer
+	for i := len(overrides) - 1; i >= 0; i-- {
+		transformers = append(transformers,
+			ResourceLabels(overrides[i].Spec.Labels),
+			ResourceAnnotations(overrides[i].Spec.Annotations))
+	}
+	return transformers
+}
+
+// overrideScheduling returns the transformers of the scheduling of the
+// overrides, by increasing precedence.
+func overrideScheduling(overrides []*v1alpha1.TektonOverride) []mf.Transformer {
+	var transformers []mf.Transformer
+	for _, o := range overrides {
+		transformers = append(transformers, PodScheduling(o.Spec.Scheduling))
+	}
+	return transformers
+}
+
+// overrideImages returns the transformers of the images of the overrides,
+// by increasing precedence. They are meant to run last, after spec.images
+// of the component.
+func overrideImages(overrides []*v1alpha1.TektonOverride) []mf.Transformer {
+	var transformers []mf.Transformer
+	for _, o := range overrides {
+		if len(o.Spec.Images) != 0 {
+			transformers = append(transformers, WorkloadImages(SpecImages(o.Spec.Images)))
+		}
+	}
+	return transformers
+}
+
+// PodScheduling merges the node selector of the scheduling into that of
+// the pods of the payload workloads, replacing the values of the payload,
+// and adds its tolerations to the pods.
+func PodScheduling(scheduling *v1alpha1.PodScheduling) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if scheduling == nil || !podTemplateKinds.Has(u.GetKind()) {
+			return nil
+		}
+		if len(scheduling.NodeSelector) != 0 {
+			selector, _, err := unstructured.NestedStringMap(u.Object, "spec", "template", "spec", "nodeSelector")
+			if err != nil {
+				return err
+			}
+			if selector == nil {
+				selector = map[string]string{}
+			}
+			for k, v := range scheduling.NodeSelector {
+				selector[k] = v
+			}
+			if err := unstructured.SetNestedStringMap(u.Object, selector, "spec", "template", "spec", "nodeSelector"); err != nil {
+				return err
+			}
+		}
+		if len(scheduling.Tolerations) == 0 {
+			return nil
+		}
+		tolerations, _, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "tolerations")
+		if err != nil {
+			return err
+		}
+		for i := range scheduling.Tolerations {
+			toleration, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&scheduling.Tolerations[i])
+			if err != nil {
+				return err
+			}
+			if !containsValue(tolerations, toleration) {
+				tolerations = append(tolerations, toleration)
+			}
+		}
+		return unstructured.SetNestedSlice(u.Object, tolerations, "spec", "template", "spec", "tolerations")
+	}
+}
+
+func containsValue(values []interface{}, value map[string]interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	listers "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientcache "k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/system"
+)
+
+func override(namespace, name string, priority int32, components ...string) *v1alpha1.TektonOverride {
+	return &v1alpha1.TektonOverride{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       v1alpha1.TektonOverrideSpec{Components: components, Priority: priority},
+	}
+}
+
+func TestComponentOverrides(t *testing.T) {
+	os.Setenv(system.NamespaceEnvKey, "tekton-operator")
+	defer os.Unsetenv(system.NamespaceEnvKey)
+	indexer := clientcache.NewIndexer(clientcache.MetaNamespaceKeyFunc, clientcache.Indexers{})
+	for _, o := range []*v1alpha1.TektonOverride{
+		override("tekton-operator", "high", 10, v1alpha1.KindTektonPipeline),
+		override("tekton-operator", "low", 0, v1alpha1.KindTektonPipeline, v1alpha1.KindTektonTrigger),
+		override("tekton-operator", "base", 0, v1alpha1.KindTektonPipeline),
+		override("tekton-operator", "dashboard", 20, v1alpha1.KindTektonDashboard),
+		// overrides of other namespaces are ignored
+		override("team-a", "foreign", 30, v1alpha1.KindTektonPipeline),
+	} {
+		util.AssertNoError(t, indexer.Add(o))
+	}
+
+	overrides, err := ComponentOverrides(listers.NewTektonOverrideLister(indexer), v1alpha1.KindTektonPipeline)
+	util.AssertNoError(t, err)
+	var names []string
+	for _, o := range overrides {
+		names = append(names, o.Namespace+"/"+o.Name)
+	}
+	util.AssertDeepEqual(t, names, []string{"tekton-operator/base", "tekton-operator/low", "tekton-operator/high"})
+}
+
+func TestEnqueueOnOverride(t *testing.T) {
+	os.Setenv(system.NamespaceEnvKey, "tekton-operator")
+	defer os.Unsetenv(system.NamespaceEnvKey)
+	var enqueued []types.NamespacedName
+	handler := EnqueueOnOverride(func(key types.NamespacedName) {
+		enqueued = append(enqueued, key)
+	}, v1alpha1.KindTektonTrigger, TriggerResourceName)
+
+	pipeline := override("tekton-operator", "o", 0, v1alpha1.KindTektonPipeline)
+	trigger := override("tekton-operator", "o", 0, v1alpha1.KindTektonTrigger)
+	foreign := override("team", "o", 0, v1alpha1.KindTektonTrigger)
+	handler.OnAdd(pipeline)
+	handler.OnUpdate(pipeline, pipeline)
+	handler.OnAdd(foreign)
+	util.AssertEqual(t, len(enqueued), 0)
+
+	handler.OnAdd(trigger)
+	// an override no longer applying to the component still enqueues it,
+	// to remove what it layered
+	handler.OnUpdate(trigger, pipeline)
+	handler.OnDelete(clientcache.DeletedFinalStateUnknown{Key: "tekton-operator/o", Obj: trigger})
+	util.AssertEqual(t, len(enqueued), 3)
+	util.AssertEqual(t, enqueued[0], types.NamespacedName{Name: TriggerResourceName})
+}
+
+func TestOverridesPrecedence(t *testing.T) {
+	deployment := util.MakeDeployment("controller", corev1.PodSpec{
+		Containers:   []corev1.Container{{Name: "controller", Image: "gcr.io/controller:v1"}},
+		NodeSelector: map[string]string{"kubernetes.io/os": "linux", "pool": "default"},
+		Tolerations:  []corev1.Toleration{{Key: "payload", Operator: corev1.TolerationOpExists}},
+	})
+	deployment.Labels = map[string]string{"app": "controller"}
+	manifest, err := mf.ManifestFrom(mf.Slice{util.MakeUnstructured(t, deployment)})
+	util.AssertNoError(t, err)
+
+	instance := &v1alpha1.TektonPipeline{
+		Spec: v1alpha1.TektonPipelineSpec{
+			CommonSpec: v1alpha1.CommonSpec{
+				TargetNamespace: "tekton-pipelines",
+				Labels:          map[string]string{"app": "spec", "team": "spec", "tier": "spec"},
+			},
+		},
+	}
+	low := override("team-a", "low", 0, v1alpha1.KindTektonPipeline)
+	low.Spec.Labels = map[string]string{"team": "low", "owner": "low"}
+	low.Spec.Scheduling = &v1alpha1.PodScheduling{
+		NodeSelector: map[string]string{"pool": "low"},
+		Tolerations:  []corev1.Toleration{{Key: "low", Operator: corev1.TolerationOpExists}},
+	}
+	low.Spec.Images = map[string]string{"controller": "registry.example.com/low:v1"}
+	high := override("team-b", "high", 10, v1alpha1.KindTektonPipeline)
+	high.Spec.Labels = map[string]string{"owner": "high"}
+	high.Spec.Scheduling = &v1alpha1.PodScheduling{NodeSelector: map[string]string{"pool": "high"}}
+	high.Spec.Images = map[string]string{"controller": "registry.example.com/high:v1"}
+
+	ctx := WithOverrides(context.Background(), []*v1alpha1.TektonOverride{low, high})
+	util.AssertNoError(t, Transform(ctx, &manifest, instance,
+		WorkloadImages(SpecImages(map[string]string{"controller": "registry.example.com/spec:v1"}))))
+
+	got := &appsv1.Deployment{}
+	util.AssertNoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(manifest.Resources()[0].Object, got))
+	util.AssertDeepEqual(t, got.Labels, map[string]string{
+		"app": "controller", "team": "low", "owner": "high", "tier": "spec",
+		v1alpha1.ManagedByKey: v1alpha1.ManagedByValue,
+	})
+	util.AssertDeepEqual(t, got.Spec.Template.Spec.NodeSelector, map[string]string{"kubernetes.io/os": "linux", "pool": "high"})
+	util.AssertEqual(t, len(got.Spec.Template.Spec.Tolerations), 2)
+	util.AssertEqual(t, got.Spec.Template.Spec.Containers[0].Image, "registry.example.com/high:v1")
+}
+
+func TestPodScheduling(t *testing.T) {
+	toleration := corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "tekton", Effect: corev1.TaintEffectNoSchedule}
+	deployment := util.MakeDeployment("controller", corev1.PodSpec{Tolerations: []corev1.Toleration{toleration}})
+	manifest, err := mf.ManifestFrom(mf.Slice{util.MakeUnstructured(t, deployment)})
+	util.AssertNoError(t, err)
+
+	scheduling := &v1alpha1.PodScheduling{Tolerations: []corev1.Toleration{toleration}}
+	manifest, err = manifest.Transform(PodScheduling(scheduling), PodScheduling(nil))
+	util.AssertNoError(t, err)
+	got := &appsv1.Deployment{}
+	util.AssertNoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(manifest.Resources()[0].Object, got))
+	util.AssertDeepEqual(t, got.Spec.Template.Spec.Tolerations, []corev1.Toleration{toleration})
+	util.AssertEqual(t, len(got.Spec.Template.Spec.NodeSelector), 0)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"sync"
+
+	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ParallelTransform is the Transform of the manifest spread over a bounded
+// pool of workers, each running all the transformers on every resource it
+// takes in turn, for payloads of thousands of resources, e.g. the addon's.
+// The transformers must be safe to call concurrently on distinct
+// resources. The error of the first resource failing, in the order of the
+// manifest, is returned.
+func ParallelTransform(manifest mf.Manifest, workers int, fns ...mf.Transformer) (mf.Manifest, error) {
+	if workers <= 1 {
+		return manifest.Transform(fns...)
+	}
+	resources := manifest.Resources()
+	if workers > len(resources) {
+		workers = len(resources)
+	}
+	errs := make([]error, len(resources))
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(resources); i += workers {
+				errs[i] = transform(&resources[i], fns)
+			}
+		}(w)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return mf.Manifest{}, err
+		}
+	}
+	transformed, err := mf.ManifestFrom(mf.Slice(resources))
+	if err != nil {
+		return mf.Manifest{}, err
+	}
+	// the result keeps the client and logger of the manifest
+	return manifest.Filter(mf.Nothing).Append(transformed), nil
+}
+
+func transform(u *unstructured.Unstructured, fns []mf.Transformer) error {
+	for _, fn := range fns {
+		if fn == nil {
+			continue
+		}
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Serialized returns the transformers sharing a single lock, so that those
+// which aren't safe to call concurrently, e.g. the ones of the options of
+// TransformWith and of out-of-tree extensions, can run in ParallelTransform
+// along the others.
+func Serialized(fns ...mf.Transformer) []mf.Transformer {
+	var mu sync.Mutex
+	serialized := make([]mf.Transformer, len(fns))
+	for i, fn := range fns {
+		if fn == nil {
+			continue
+		}
+		fn := fn
+		serialized[i] = func(u *unstructured.Unstructured) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return fn(u)
+		}
+	}
+	return serialized
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParallelTransform(t *testing.T) {
+	resources := make([]unstructured.Unstructured, 100)
+	for i := range resources {
+		resources[i] = namespacedResource("tekton.dev/v1", "Task", "tekton-pipelines", fmt.Sprintf("task-%d", i))
+	}
+	client := util.NewFakeClient()
+	manifest, err := mf.ManifestFrom(mf.Slice(resources), mf.UseClient(client))
+	util.AssertNoError(t, err)
+	label := func(u *unstructured.Unstructured) error {
+		u.SetLabels(map[string]string{"name": u.GetName()})
+		return nil
+	}
+
+	serial, err := manifest.Transform(label)
+	util.AssertNoError(t, err)
+	for _, workers := range []int{0, 1, 4, 1000} {
+		parallel, err := ParallelTransform(manifest, workers, label, nil)
+		util.AssertNoError(t, err)
+		util.AssertDeepEqual(t, parallel.Resources(), serial.Resources())
+		util.AssertEqual(t, parallel.Client, mf.Client(client))
+	}
+
+	// the first resource failing in the order of the manifest fails it
+	failing := func(u *unstructured.Unstructured) error {
+		if u.GetName() == "task-42" || u.GetName() == "task-43" {
+			return fmt.Errorf("%s failed", u.GetName())
+		}
+		return nil
+	}
+	_, err = ParallelTransform(manifest, 4, failing)
+	util.AssertEqual(t, err.Error(), "task-42 failed")
+}
+
+func TestSerialized(t *testing.T) {
+	resources := make([]unstructured.Unstructured, 100)
+	for i := range resources {
+		resources[i] = namespacedResource("tekton.dev/v1", "Task", "tekton-pipelines", fmt.Sprintf("task-%d", i))
+	}
+	manifest, err := mf.ManifestFrom(mf.Slice(resources))
+	util.AssertNoError(t, err)
+
+	// neither of them is safe to call concurrently
+	var running, most int32
+	var names []string
+	track := func(*unstructured.Unstructured) error {
+		if n := atomic.AddInt32(&running, 1); n > atomic.LoadInt32(&most) {
+			atomic.StoreInt32(&most, n)
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+	collect := func(u *unstructured.Unstructured) error {
+		names = append(names, u.GetName())
+		return nil
+	}
+	_, err = ParallelTransform(manifest, 8, Serialized(track, nil, collect)...)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, atomic.LoadInt32(&most), int32(1))
+	util.AssertEqual(t, len(names), 100)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RestrictedPodSecurity returns a transformer making the pod templates of
+// the payload comply with the restricted Pod Security Standard: pods run as
+// non-root with the RuntimeDefault seccomp profile, and containers drop all
+// capabilities but NET_BIND_SERVICE and can't escalate their privileges.
+// Stricter settings of the payload are kept. It is a no-op unless enabled.
+func RestrictedPodSecurity(enabled bool) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if !enabled {
+			return nil
+		}
+		path := podSpecPath(u.GetKind())
+		if path == nil {
+			return nil
+		}
+		podSpec, found, err := unstructured.NestedMap(u.Object, path...)
+		if err != nil || !found {
+			return err
+		}
+		// seccompProfile is newer than the vendored API types, so the pod
+		// template is changed as is
+		restrictPod(podSpec)
+		for _, field := range []string{"containers", "initContainers"} {
+			containers, _ := podSpec[field].([]interface{})
+			for _, c := range containers {
+				if container, ok := c.(map[string]interface{}); ok {
+					restrictContainer(container)
+				}
+			}
+		}
+		return unstructured.SetNestedMap(u.Object, podSpec, path...)
+	}
+}
+
+func restrictPod(podSpec map[string]interface{}) {
+	sc := securityContext(podSpec)
+	sc["runAsNonRoot"] = true
+	if typ, _, _ := unstructured.NestedString(sc, "seccompProfile", "type"); typ == "" || typ == "Unconfined" {
+		sc["seccompProfile"] = map[string]interface{}{"type": "RuntimeDefault"}
+	}
+}
+
+func restrictContainer(container map[string]interface{}) {
+	sc := securityContext(container)
+	sc["allowPrivilegeEscalation"] = false
+	if privileged, _ := sc["privileged"].(bool); privileged {
+		sc["privileged"] = false
+	}
+	if nonRoot, ok := sc["runAsNonRoot"].(bool); ok && !nonRoot {
+		delete(sc, "runAsNonRoot")
+	}
+	if typ, _, _ := unstructured.NestedString(sc, "seccompProfile", "type"); typ == "Unconfined" {
+		delete(sc, "seccompProfile")
+	}
+
+	capabilities, _ := sc["capabilities"].(map[string]interface{})
+	if capabilities == nil {
+		capabilities = map[string]interface{}{}
+		sc["capabilities"] = capabilities
+	}
+	var add []interface{}
+	added, _ := capabilities["add"].([]interface{})
+	for _, c := range added {
+		if c == "NET_BIND_SERVICE" {
+			add = append(add, c)
+		}
+	}
+	if len(add) == 0 {
+		delete(capabilities, "add")
+	} else {
+		capabilities["add"] = add
+	}
+	capabilities["drop"] = []interface{}{"ALL"}
+}
+
+// securityContext returns the securityContext of a pod spec or container,
+// adding an empty one if unset.
+func securityContext(object map[string]interface{}) map[string]interface{} {
+	sc, ok := object["securityContext"].(map[string]interface{})
+	if !ok {
+		sc = map[string]interface{}{}
+		object["securityContext"] = sc
+	}
+	return sc
+}
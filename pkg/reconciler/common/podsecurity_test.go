@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRestrictedPodSecurity(t *testing.T) {
+	privileged, escalation := true, true
+	deployment := util.MakeUnstructured(t, util.MakeDeployment("controller", corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:  "controller",
+			Image: "gcr.io/controller:v1",
+			SecurityContext: &corev1.SecurityContext{
+				Privileged:               &privileged,
+				AllowPrivilegeEscalation: &escalation,
+				Capabilities:             &corev1.Capabilities{Add: []corev1.Capability{"SYS_ADMIN", "NET_BIND_SERVICE"}},
+			},
+		}},
+		InitContainers: []corev1.Container{{Name: "init", Image: "gcr.io/init:v1"}},
+	}))
+	unchanged := deployment.DeepCopy()
+	util.AssertNoError(t, RestrictedPodSecurity(false)(&deployment))
+	util.AssertDeepEqual(t, &deployment, unchanged)
+
+	util.AssertNoError(t, RestrictedPodSecurity(true)(&deployment))
+	podSpec, _, _ := unstructured.NestedMap(deployment.Object, "spec", "template", "spec")
+	util.AssertDeepEqual(t, podSpec["securityContext"], map[string]interface{}{
+		"runAsNonRoot":   true,
+		"seccompProfile": map[string]interface{}{"type": "RuntimeDefault"},
+	})
+	containers := podSpec["containers"].([]interface{})
+	util.AssertDeepEqual(t, containers[0].(map[string]interface{})["securityContext"], map[string]interface{}{
+		"privileged":               false,
+		"allowPrivilegeEscalation": false,
+		"capabilities": map[string]interface{}{
+			"add":  []interface{}{"NET_BIND_SERVICE"},
+			"drop": []interface{}{"ALL"},
+		},
+	})
+	initContainers := podSpec["initContainers"].([]interface{})
+	util.AssertDeepEqual(t, initContainers[0].(map[string]interface{})["securityContext"], map[string]interface{}{
+		"allowPrivilegeEscalation": false,
+		"capabilities":             map[string]interface{}{"drop": []interface{}{"ALL"}},
+	})
+
+	// the seccomp profile of the payload is kept unless unconfined
+	cronJob := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1beta1",
+		"kind":       "CronJob",
+		"spec": map[string]interface{}{"jobTemplate": map[string]interface{}{"spec": map[string]interface{}{
+			"template": map[string]interface{}{"spec": map[string]interface{}{
+				"securityContext": map[string]interface{}{
+					"seccompProfile": map[string]interface{}{"type": "Localhost", "localhostProfile": "tekton.json"},
+				},
+			}},
+		}}},
+	}}
+	util.AssertNoError(t, RestrictedPodSecurity(true)(&cronJob))
+	profile, _, _ := unstructured.NestedMap(cronJob.Object, "spec", "jobTemplate", "spec", "template", "spec", "securityContext", "seccompProfile")
+	util.AssertDeepEqual(t, profile, map[string]interface{}{"type": "Localhost", "localhostProfile": "tekton.json"})
+}
@@ -19,6 +19,8 @@ import (
 	"os"
 	"sort"
 
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -26,24 +28,50 @@ import (
 // ApplyProxySettings is a transformer that propagate any proxy environment variables
 // set on the operator deployment to the underlying deployment.
 func ApplyProxySettings(u *unstructured.Unstructured) error {
-	if u.GetKind() != "Deployment" {
-		// Don't do anything on something else than Deployment
-		return nil
-	}
+	return ProxySettings(nil)(u)
+}
 
-	var proxyEnv = []corev1.EnvVar{{
+// ProxySettings returns a transformer that sets HTTPS_PROXY, HTTP_PROXY and
+// NO_PROXY on every container of the payload deployments, from the given
+// proxy or, for the variables it leaves unset, from the environment of the
+// operator.
+func ProxySettings(proxy *v1alpha1.Proxy) mf.Transformer {
+	if proxy == nil {
+		proxy = &v1alpha1.Proxy{}
+	}
+	valueOr := func(value, name string) string {
+		if value != "" {
+			return value
+		}
+		return os.Getenv(name)
+	}
+	proxyEnv := []corev1.EnvVar{{
 		Name:  "HTTPS_PROXY",
-		Value: os.Getenv("HTTPS_PROXY"),
+		Value: valueOr(proxy.HTTPSProxy, "HTTPS_PROXY"),
 	}, {
 		Name:  "HTTP_PROXY",
-		Value: os.Getenv("HTTP_PROXY"),
+		Value: valueOr(proxy.HTTPProxy, "HTTP_PROXY"),
 	}, {
 		Name:  "NO_PROXY",
-		Value: os.Getenv("NO_PROXY"),
+		Value: valueOr(proxy.NoProxy, "NO_PROXY"),
 	}}
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Deployment" {
+			// Don't do anything on something else than Deployment
+			return nil
+		}
+		for _, field := range []string{"containers", "initContainers"} {
+			if err := applyProxyEnv(u, proxyEnv, "spec", "template", "spec", field); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
 
+func applyProxyEnv(u *unstructured.Unstructured, proxyEnv []corev1.EnvVar, fields ...string) error {
 	m := u.Object
-	containers, found, err := unstructured.NestedSlice(m, "spec", "template", "spec", "containers")
+	containers, found, err := unstructured.NestedSlice(m, fields...)
 	if err != nil {
 		return err
 	}
@@ -71,7 +99,7 @@ func ApplyProxySettings(u *unstructured.Unstructured) error {
 			return err
 		}
 	}
-	if err := unstructured.SetNestedField(m, containers, "spec", "template", "spec", "containers"); err != nil {
+	if err := unstructured.SetNestedField(m, containers, fields...); err != nil {
 		return err
 	}
 
@@ -20,6 +20,7 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/env"
 	appsv1 "k8s.io/api/apps/v1"
@@ -108,6 +109,30 @@ func TestApplyProxySettingsRemovingProxy(t *testing.T) {
 	assert.DeepEqual(t, actual, expected)
 }
 
+func TestProxySettingsFromSpec(t *testing.T) {
+	defer env.PatchAll(t, map[string]string{
+		"HTTP_PROXY": "http://operator:3128",
+		"NO_PROXY":   "index.docker.io",
+	})()
+	proxy := &v1alpha1.Proxy{
+		HTTPProxy:  "http://spec:3128",
+		HTTPSProxy: "http://spec:3129",
+	}
+	proxyEnv := map[string]string{
+		"HTTP_PROXY":  "http://spec:3128",
+		"HTTPS_PROXY": "http://spec:3129",
+		"NO_PROXY":    "index.docker.io",
+	}
+	actual := unstructuredDeployment(t, withInitContainer, withEnv(extraEnvVars))
+	expected := unstructuredDeployment(t, withInitContainer, withEnv(toEnvVar(proxyEnv), extraEnvVars))
+
+	if err := ProxySettings(proxy)(actual); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.DeepEqual(t, actual, expected)
+}
+
 type deploymentModifier func(*appsv1.Deployment)
 
 func unstructuredDeployment(t *testing.T, modifiers ...deploymentModifier) *unstructured.Unstructured {
@@ -160,18 +185,24 @@ func unstructuredDeployment(t *testing.T, modifiers ...deploymentModifier) *unst
 
 func withEnv(envs ...[]corev1.EnvVar) func(*appsv1.Deployment) {
 	return func(d *appsv1.Deployment) {
-		for i, c := range d.Spec.Template.Spec.Containers {
-			for _, env := range envs {
-				c.Env = append(c.Env, env...)
+		for _, containers := range [][]corev1.Container{d.Spec.Template.Spec.Containers, d.Spec.Template.Spec.InitContainers} {
+			for i, c := range containers {
+				for _, env := range envs {
+					c.Env = append(c.Env, env...)
+				}
+				sort.Slice(c.Env, func(i, j int) bool {
+					return c.Env[i].Name < c.Env[j].Name
+				})
+				containers[i] = c
 			}
-			sort.Slice(c.Env, func(i, j int) bool {
-				return c.Env[i].Name < c.Env[j].Name
-			})
-			d.Spec.Template.Spec.Containers[i] = c
 		}
 	}
 }
 
+func withInitContainer(d *appsv1.Deployment) {
+	d.Spec.Template.Spec.InitContainers = append(d.Spec.Template.Spec.InitContainers, d.Spec.Template.Spec.Containers[0])
+}
+
 func toEnvVar(env map[string]string) []corev1.EnvVar {
 	envvar := []corev1.EnvVar{}
 	for e, v := range env {
@@ -0,0 +1,250 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// PrecheckImagePulls resolves the images of the payload deployments
+// against their registry when spec.registry.pullPrecheck is set, with the
+// credentials of spec.registry.imagePullSecrets and through spec.proxy, so
+// that an image which can't be pulled fails the install before pods crash
+// loop on it. Images are only checked when the generation isn't installed
+// yet or isn't ready, the pods of a ready install pulling them already.
+func PrecheckImagePulls(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	spec := instance.GetSpec()
+	status := instance.GetStatus()
+	if !spec.GetRegistry().PullPrecheck {
+		return nil
+	}
+	if status.GetObservedGeneration() == instance.GetGeneration() && status.IsReady() {
+		return nil
+	}
+	registries, err := payloadRegistries(manifest, instance)
+	if err != nil {
+		return err
+	}
+	for _, image := range deploymentImages(manifest) {
+		ref, reference := image, ""
+		if i := strings.Index(image, "@"); i != -1 {
+			ref, reference = image[:i], image[i+1:]
+		}
+		registry, repository, tag := parseImage(ref)
+		if reference == "" {
+			reference = tag
+		}
+		if err := precheckImagePull(ctx, registries.access(registry), registry, repository, reference); err != nil {
+			err = &operrors.ImagePullPrecheckFailed{Err: fmt.Errorf("Image %s can't be pulled: %v", image, err)}
+			operrors.MarkFailed(status, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// precheckImagePull asks the registry for the manifest of the image.
+func precheckImagePull(ctx context.Context, access *registryAccess, registry, repository, reference string) error {
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", registry, repository, reference)
+	resp, err := access.do(ctx, http.MethodHead, manifestURL, manifestMediaTypes)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", manifestURL, resp.Status)
+	}
+	return nil
+}
+
+// deploymentImages returns the images of the containers of the payload
+// deployments, the controllers and webhooks of the components.
+func deploymentImages(manifest *mf.Manifest) []string {
+	images := sets.NewString()
+	for _, u := range manifest.Filter(mf.ByKind("Deployment")).Resources() {
+		for _, field := range []string{"containers", "initContainers"} {
+			containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "template", "spec", field)
+			for _, c := range containers {
+				container, _ := c.(map[string]interface{})
+				if image, _ := container["image"].(string); image != "" {
+					images.Insert(image)
+				}
+			}
+		}
+	}
+	return images.List()
+}
+
+// pullSecretCredentials returns the credentials of the image pull secrets
+// of the namespace, by registry host. Secrets missing or of another type
+// are skipped, the kubelet ignoring them too.
+func pullSecretCredentials(manifest *mf.Manifest, namespace string, secrets []string) (map[string]registryAccess, error) {
+	credentials := map[string]registryAccess{}
+	for _, name := range secrets {
+		secret := namespacedResource("v1", "Secret", namespace, name)
+		live, err := manifest.Client.Get(&secret)
+		if apierrors.IsNotFound(err) || (err == nil && live == nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var auths map[string]dockerAuth
+		switch secretType, _, _ := unstructured.NestedString(live.Object, "type"); corev1.SecretType(secretType) {
+		case corev1.SecretTypeDockerConfigJson:
+			var config struct {
+				Auths map[string]dockerAuth `json:"auths"`
+			}
+			if err := decodeSecretKey(live, corev1.DockerConfigJsonKey, &config); err != nil {
+				return nil, fmt.Errorf("invalid image pull secret %s/%s: %w", namespace, name, err)
+			}
+			auths = config.Auths
+		case corev1.SecretTypeDockercfg:
+			if err := decodeSecretKey(live, corev1.DockerConfigKey, &auths); err != nil {
+				return nil, fmt.Errorf("invalid image pull secret %s/%s: %w", namespace, name, err)
+			}
+		}
+		for server, auth := range auths {
+			host := registryHost(server)
+			if _, ok := credentials[host]; ok {
+				// the first secret of a registry wins, like for the kubelet
+				continue
+			}
+			username, password := auth.Username, auth.Password
+			if decoded, err := base64.StdEncoding.DecodeString(auth.Auth); err == nil && auth.Auth != "" {
+				if parts := strings.SplitN(string(decoded), ":", 2); len(parts) == 2 {
+					username, password = parts[0], parts[1]
+				}
+			}
+			credentials[host] = registryAccess{username: username, password: password}
+		}
+	}
+	return credentials, nil
+}
+
+// dockerAuth are the credentials of a registry in a docker config
+type dockerAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// decodeSecretKey decodes the JSON document of the key of the secret.
+func decodeSecretKey(secret *unstructured.Unstructured, key string, v interface{}) error {
+	encoded, _, _ := unstructured.NestedString(secret.Object, "data", key)
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// registryHost returns the host of a server of a docker config, which may
+// be a URL, as parseImage returns it.
+func registryHost(server string) string {
+	host := server
+	if u, err := url.Parse(server); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	host = strings.SplitN(host, "/", 2)[0]
+	if host == "docker.io" || host == "index.docker.io" {
+		return dockerHubRegistry
+	}
+	return host
+}
+
+// registryClientWithProxy returns a client like registryClient going
+// through the given proxy or, for the fields it leaves unset, the proxy of
+// the environment of the operator, like ProxySettings.
+func registryClientWithProxy(proxy *v1alpha1.Proxy) *http.Client {
+	if proxy == nil {
+		proxy = &v1alpha1.Proxy{}
+	}
+	valueOr := func(value, name string) string {
+		if value != "" {
+			return value
+		}
+		return os.Getenv(name)
+	}
+	httpsProxy := valueOr(proxy.HTTPSProxy, "HTTPS_PROXY")
+	httpProxy := valueOr(proxy.HTTPProxy, "HTTP_PROXY")
+	noProxy := valueOr(proxy.NoProxy, "NO_PROXY")
+
+	transport, ok := registryClient.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		proxyURL := httpProxy
+		if req.URL.Scheme == "https" {
+			proxyURL = httpsProxy
+		}
+		if proxyURL == "" || bypassProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+		if !strings.Contains(proxyURL, "://") {
+			proxyURL = "http://" + proxyURL
+		}
+		return url.Parse(proxyURL)
+	}
+	return &http.Client{Timeout: registryClient.Timeout, Transport: transport}
+}
+
+// bypassProxy returns true if the host matches an entry of noProxy: *, an
+// IP, a CIDR or a domain, including its subdomains.
+func bypassProxy(host, noProxy string) bool {
+	ip := net.ParseIP(host)
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return true
+		case ip != nil:
+			if _, cidr, err := net.ParseCIDR(entry); err == nil && cidr.Contains(ip) {
+				return true
+			}
+			if entry == host {
+				return true
+			}
+		default:
+			entry = strings.TrimPrefix(entry, ".")
+			if host == entry || strings.HasSuffix(host, "."+entry) {
+				return true
+			}
+		}
+	}
+	return false
+}
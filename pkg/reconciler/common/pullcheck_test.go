@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	fake "github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func pullSecret(name string, secretType corev1.SecretType, key, config string) *unstructured.Unstructured {
+	secret := namespacedResource("v1", "Secret", "tekton-pipelines", name)
+	secret.Object["type"] = string(secretType)
+	secret.Object["data"] = map[string]interface{}{key: base64.StdEncoding.EncodeToString([]byte(config))}
+	return &secret
+}
+
+func TestPullSecretCredentials(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:s3cr3t"))
+	client := fake.New(
+		pullSecret("quay", corev1.SecretTypeDockerConfigJson, corev1.DockerConfigJsonKey,
+			`{"auths": {"https://quay.io": {"auth": "`+auth+`"}, "https://index.docker.io/v1/": {"username": "hub", "password": "pass"}}}`),
+		pullSecret("legacy", corev1.SecretTypeDockercfg, corev1.DockerConfigKey,
+			`{"quay.io": {"username": "other", "password": "other"}, "registry.example.com": {"username": "legacy", "password": "pass"}}`),
+	)
+	manifest, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	credentials, err := pullSecretCredentials(&manifest, "tekton-pipelines", []string{"quay", "missing", "legacy"})
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(credentials), 3)
+	util.AssertEqual(t, credentials["quay.io"], registryAccess{username: "robot", password: "s3cr3t"})
+	util.AssertEqual(t, credentials[dockerHubRegistry], registryAccess{username: "hub", password: "pass"})
+	util.AssertEqual(t, credentials["registry.example.com"], registryAccess{username: "legacy", password: "pass"})
+}
+
+func TestBypassProxy(t *testing.T) {
+	for _, test := range []struct {
+		host, noProxy string
+		bypass        bool
+	}{
+		{"quay.io", "", false},
+		{"quay.io", "*", true},
+		{"quay.io", "example.com, quay.io", true},
+		{"cdn.quay.io", ".quay.io", true},
+		{"notquay.io", "quay.io", false},
+		{"10.0.0.12", "10.0.0.0/8", true},
+		{"192.168.1.1", "10.0.0.0/8,192.168.1.2", false},
+	} {
+		util.AssertEqual(t, bypassProxy(test.host, test.noProxy), test.bypass)
+	}
+}
+
+func TestPrecheckImagePulls(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "robot" || pass != "s3cr3t" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodHead || r.URL.Path != "/v2/tekton/controller/manifests/v1" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	defer func(client *http.Client) { registryClient = client }(registryClient)
+	registryClient = server.Client()
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	client := fake.New(pullSecret("registry", corev1.SecretTypeDockerConfigJson, corev1.DockerConfigJsonKey,
+		`{"auths": {"`+host+`": {"username": "robot", "password": "s3cr3t"}}}`))
+	deployment := func(image string) mf.Manifest {
+		manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
+			util.MakeUnstructured(t, util.MakeDeployment("controller", corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "controller", Image: image}},
+			})),
+		}), mf.UseClient(client))
+		util.AssertNoError(t, err)
+		return manifest
+	}
+
+	instance := &v1alpha1.TektonPipeline{}
+	instance.SetGeneration(1)
+	instance.Spec.TargetNamespace = "tekton-pipelines"
+	instance.Status.InitializeConditions()
+	missing := deployment(host + "/tekton/webhook:v1")
+	// disabled by default
+	util.AssertNoError(t, PrecheckImagePulls(context.Background(), &missing, instance))
+
+	instance.Spec.Registry = v1alpha1.Registry{PullPrecheck: true, ImagePullSecrets: []string{"registry"}}
+	found := deployment(host + "/tekton/controller:v1")
+	util.AssertNoError(t, PrecheckImagePulls(context.Background(), &found, instance))
+
+	err := PrecheckImagePulls(context.Background(), &missing, instance)
+	if err == nil {
+		t.Fatal("PrecheckImagePulls() = nil, wanted an error for a missing image")
+	}
+	util.AssertEqual(t, operrors.Reason(err), v1alpha1.ReasonImagePullPrecheckFailed)
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).Reason, v1alpha1.ReasonImagePullPrecheckFailed)
+
+	// without the credentials
+	instance.Spec.Registry.ImagePullSecrets = nil
+	if err := PrecheckImagePulls(context.Background(), &found, instance); err == nil {
+		t.Error("PrecheckImagePulls() = nil, wanted an error without credentials")
+	}
+
+	// the images of a ready install are pulled already
+	instance.Status.MarkInstallSucceeded()
+	instance.Status.MarkDeploymentsAvailable()
+	instance.Status.SetObservedGeneration(1)
+	util.AssertNoError(t, PrecheckImagePulls(context.Background(), &missing, instance))
+}
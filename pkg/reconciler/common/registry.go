@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// RegistryOverride replaces the registry of the payload images, see
+// imagesTransformer. The rest of the reference is kept, so
+// gcr.io/tekton-releases/controller:v1 becomes
+// <registry>/tekton-releases/controller:v1.
+func RegistryOverride(registry string) mf.Transformer {
+	registry = strings.TrimSuffix(registry, "/")
+	if registry == "" {
+		return func(*unstructured.Unstructured) error { return nil }
+	}
+	return imagesTransformer(func(image string) string {
+		return rewriteRegistry(registry, image)
+	})
+}
+
+// ImagePullSecrets adds the given secrets of the target namespace to the
+// image pull secrets of the payload workloads and ServiceAccounts, keeping
+// the ones they already have.
+func ImagePullSecrets(secrets []string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if len(secrets) == 0 {
+			return nil
+		}
+		switch {
+		case podTemplateKinds.Has(u.GetKind()):
+			return addImagePullSecrets(u, secrets, "spec", "template", "spec", "imagePullSecrets")
+		case u.GetKind() == "ServiceAccount":
+			return addImagePullSecrets(u, secrets, "imagePullSecrets")
+		}
+		return nil
+	}
+}
+
+func addImagePullSecrets(u *unstructured.Unstructured, secrets []string, fields ...string) error {
+	current, _, err := unstructured.NestedSlice(u.Object, fields...)
+	if err != nil {
+		return err
+	}
+	existing := sets.NewString()
+	for _, ref := range current {
+		if ref, ok := ref.(map[string]interface{}); ok {
+			name, _ := ref["name"].(string)
+			existing.Insert(name)
+		}
+	}
+	for _, secret := range secrets {
+		if !existing.Has(secret) {
+			existing.Insert(secret)
+			current = append(current, map[string]interface{}{"name": secret})
+		}
+	}
+	return unstructured.SetNestedSlice(u.Object, current, fields...)
+}
+
+// imagesTransformer applies rewrite to the images of workloads, including
+// the image args of their containers, of the steps and sidecars of tasks,
+// of the image params of tasks and of StepActions. References to params,
+// e.g. $(params.BUILDER_IMAGE), are left as is.
+func imagesTransformer(rewriteImage func(string) string) mf.Transformer {
+	rewrite := func(image string) string {
+		if strings.Contains(image, "$(") {
+			return image
+		}
+		return rewriteImage(image)
+	}
+	return func(u *unstructured.Unstructured) error {
+		switch {
+		case podTemplateKinds.Has(u.GetKind()):
+			for _, field := range []string{"containers", "initContainers"} {
+				if err := rewriteImages(u, rewrite, true, "spec", "template", "spec", field); err != nil {
+					return err
+				}
+			}
+		case TaskKinds(u):
+			for _, field := range []string{"steps", "sidecars"} {
+				if err := rewriteImages(u, rewrite, false, "spec", field); err != nil {
+					return err
+				}
+			}
+			return rewriteImageParams(u, rewrite)
+		case byGroupKind(TektonGroup, "StepAction")(u):
+			image, found, err := unstructured.NestedString(u.Object, "spec", "image")
+			if err != nil || !found {
+				return err
+			}
+			if err := unstructured.SetNestedField(u.Object, rewrite(image), "spec", "image"); err != nil {
+				return err
+			}
+			return rewriteImageParams(u, rewrite)
+		}
+		return nil
+	}
+}
+
+// rewriteImages rewrites the image of each container in the list at fields
+// and, with args set, the values of their image args.
+func rewriteImages(u *unstructured.Unstructured, rewrite func(string) string, args bool, fields ...string) error {
+	containers, found, err := unstructured.NestedSlice(u.Object, fields...)
+	if err != nil || !found {
+		return err
+	}
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if image, ok := container["image"].(string); ok && image != "" {
+			container["image"] = rewrite(image)
+		}
+		if !args {
+			continue
+		}
+		if containerArgs, ok := container["args"].([]interface{}); ok {
+			rewriteImageArgs(containerArgs, rewrite)
+		}
+	}
+	return unstructured.SetNestedSlice(u.Object, containers, fields...)
+}
+
+// rewriteImageArgs rewrites the values of flags named like -<name>-image,
+// given either as -flag=value or as -flag value.
+func rewriteImageArgs(args []interface{}, rewrite func(string) string) {
+	for i, a := range args {
+		arg, ok := a.(string)
+		if !ok {
+			continue
+		}
+		if values, hasValue := splitsByEqual(arg); hasValue {
+			if isImageFlag(values[0]) {
+				args[i] = values[0] + "=" + rewrite(values[1])
+			}
+			continue
+		}
+		if isImageFlag(arg) && i+1 < len(args) {
+			if value, ok := args[i+1].(string); ok {
+				args[i+1] = rewrite(value)
+			}
+		}
+	}
+}
+
+func isImageFlag(arg string) bool {
+	return strings.HasPrefix(arg, "-") && strings.HasSuffix(strings.ToLower(arg), "image")
+}
+
+// rewriteImageParams rewrites the defaults of the params whose name ends in
+// image, e.g. BUILDER_IMAGE.
+func rewriteImageParams(u *unstructured.Unstructured, rewrite func(string) string) error {
+	params, found, err := unstructured.NestedSlice(u.Object, "spec", "params")
+	if err != nil || !found {
+		return err
+	}
+	for _, p := range params {
+		param, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := param["name"].(string)
+		image, ok := param["default"].(string)
+		if !ok || image == "" || !strings.HasSuffix(strings.ToLower(name), "image") {
+			continue
+		}
+		param["default"] = rewrite(image)
+	}
+	return unstructured.SetNestedSlice(u.Object, params, "spec", "params")
+}
+
+// rewriteRegistry replaces the registry of the image. Images without a
+// registry are on Docker Hub, whose official images live under library/.
+func rewriteRegistry(registry, image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	switch {
+	case len(parts) == 1:
+		return registry + "/library/" + image
+	case strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost":
+		return registry + "/" + parts[1]
+	default:
+		return registry + "/" + image
+	}
+}
@@ -39,8 +39,6 @@ const (
 	COMMA = ","
 )
 
-var cache = map[string]mf.Manifest{}
-
 // TargetVersion returns the version of the manifest to be installed
 // per the spec in the component. If spec.version is empty, the latest
 // version known to the operator is returned.
@@ -48,9 +46,28 @@ func TargetVersion(instance v1alpha1.TektonComponent) string {
 	return latestRelease(instance)
 }
 
-// TargetManifest returns the manifest for the TargetVersion
+// installedVersion is the TargetVersion of a component, empty for the
+// components shipping no payload of their own, e.g. the TektonConfig on
+// Kubernetes whose installer sets hold the resources it generates.
+func installedVersion(instance v1alpha1.TektonComponent) string {
+	if _, err := os.Stat(ComponentDir(instance)); os.IsNotExist(err) {
+		return ""
+	}
+	return TargetVersion(instance)
+}
+
+// TargetManifest returns the manifest for the TargetVersion. The manifests
+// of the versions the component moved away from are dropped from the
+// cache.
 func TargetManifest(instance v1alpha1.TektonComponent) (mf.Manifest, error) {
-	return Fetch(manifestPath(TargetVersion(instance), instance))
+	version := TargetVersion(instance)
+	component := ComponentDir(instance)
+	m, err := manifests.get(manifestKey{component: component, version: version}, manifestPath(version, instance))
+	if err != nil {
+		return m, err
+	}
+	manifests.retain(component, version, instance.GetStatus().GetVersion())
+	return m, nil
 }
 
 // InstalledManifest returns the version currently installed, which is
@@ -62,18 +79,15 @@ func InstalledManifest(instance v1alpha1.TektonComponent) (mf.Manifest, error) {
 	if len(instance.GetStatus().GetManifests()) == 0 && current == "" {
 		return TargetManifest(instance)
 	}
-	return Fetch(installedManifestPath(current, instance))
+	if len(instance.GetStatus().GetManifests()) != 0 {
+		return Fetch(installedManifestPath(current, instance))
+	}
+	return manifests.get(manifestKey{component: ComponentDir(instance), version: current}, installedManifestPath(current, instance))
 }
 
+// Fetch returns the manifest of the path, parsed once only
 func Fetch(path string) (mf.Manifest, error) {
-	if m, ok := cache[path]; ok {
-		return m, nil
-	}
-	result, err := mf.NewManifest(path)
-	if err == nil {
-		cache[path] = result
-	}
-	return result, err
+	return manifests.get(manifestKey{version: path}, path)
 }
 
 func ComponentDir(instance v1alpha1.TektonComponent) string {
@@ -89,6 +103,16 @@ func ComponentDir(instance v1alpha1.TektonComponent) string {
 		return filepath.Join(koDataDir, "tekton-addon")
 	case *v1alpha1.TektonConfig:
 		return filepath.Join(koDataDir, "tekton-config")
+	case *v1alpha1.TektonResult:
+		return filepath.Join(koDataDir, "tekton-results")
+	case *v1alpha1.TektonChain:
+		return filepath.Join(koDataDir, "tekton-chains")
+	case *v1alpha1.TektonHub:
+		return filepath.Join(koDataDir, "tekton-hub")
+	case *v1alpha1.OpenShiftPipelinesAsCode:
+		return filepath.Join(koDataDir, "pipelines-as-code")
+	case *v1alpha1.ManualApprovalGate:
+		return filepath.Join(koDataDir, "manual-approval-gate")
 	}
 	return ""
 }
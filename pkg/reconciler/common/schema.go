@@ -0,0 +1,276 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ValidateCustomResources is a Stage checking the custom resources of the
+// manifest, e.g. ClusterTasks or ClusterInterceptors, against the CRDs
+// defining them before anything is applied: their version must be served
+// and they must match its schema, without fields the API server would
+// prune. The CRDs are taken from the manifest, or else from the cluster.
+// Custom resources of unknown CRDs are left to the API server.
+func ValidateCustomResources(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	crds := map[schema.GroupKind]*unstructured.Unstructured{}
+	for _, u := range manifest.Filter(mf.CRDs).Resources() {
+		crd := u
+		crds[crdGroupKind(&crd)] = &crd
+	}
+
+	var problems []string
+	for _, u := range manifest.Filter(mf.Not(mf.CRDs)).Resources() {
+		gk := u.GroupVersionKind().GroupKind()
+		crd, ok := crds[gk]
+		if !ok {
+			live, err := liveCRD(manifest.Client, u.GroupVersionKind())
+			if err != nil {
+				return err
+			}
+			crds[gk], crd = live, live
+		}
+		if crd == nil {
+			continue
+		}
+		for _, problem := range validateCustomResource(crd, &u) {
+			problems = append(problems, fmt.Sprintf("%s %s: %s", u.GetKind(), u.GetName(), problem))
+		}
+	}
+	if len(problems) != 0 {
+		err := &operrors.InvalidPayload{Err: errors.New("Payload doesn't match its CRDs: " + strings.Join(problems, "; "))}
+		operrors.MarkFailed(instance.GetStatus(), err)
+		return err
+	}
+	return nil
+}
+
+func crdGroupKind(crd *unstructured.Unstructured) schema.GroupKind {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	return schema.GroupKind{Group: group, Kind: kind}
+}
+
+// liveCRD gets the CRD of a kind from the cluster. It returns nil for
+// built-in kinds and kinds without a CRD.
+func liveCRD(client mf.Client, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	if !strings.Contains(gvk.Group, ".") || strings.HasSuffix(gvk.Group, ".k8s.io") {
+		return nil, nil
+	}
+	plural, _ := meta.UnsafeGuessKindToResource(gvk)
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: crdGVR.Group, Version: crdGVR.Version, Kind: "CustomResourceDefinition"})
+	crd.SetName(plural.Resource + "." + gvk.Group)
+	live, err := client.Get(crd)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return live, err
+}
+
+// validateCustomResource returns the problems of a custom resource with
+// respect to its CRD, of the v1 or v1beta1 shape.
+func validateCustomResource(crd, u *unstructured.Unstructured) []string {
+	version := u.GroupVersionKind().Version
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	var names []string
+	var definition map[string]interface{}
+	for _, v := range versions {
+		if v, ok := v.(map[string]interface{}); ok {
+			name, _ := v["name"].(string)
+			names = append(names, name)
+			if name == version {
+				definition = v
+			}
+		}
+	}
+	if single, _, _ := unstructured.NestedString(crd.Object, "spec", "version"); len(versions) == 0 && single == version {
+		definition = map[string]interface{}{"served": true}
+	}
+	if definition == nil {
+		return []string{fmt.Sprintf("version %s is not defined by CRD %s, which defines %s", version, crd.GetName(), strings.Join(names, ", "))}
+	}
+	if served, _ := definition["served"].(bool); !served {
+		return []string{fmt.Sprintf("version %s is not served by CRD %s", version, crd.GetName())}
+	}
+
+	openAPI, found, _ := unstructured.NestedMap(definition, "schema", "openAPIV3Schema")
+	if !found {
+		openAPI, found, _ = unstructured.NestedMap(crd.Object, "spec", "validation", "openAPIV3Schema")
+	}
+	if !found {
+		return nil
+	}
+	// v1beta1 CRDs keep unknown fields unless told otherwise
+	preserve, found, _ := unstructured.NestedBool(crd.Object, "spec", "preserveUnknownFields")
+	pruning := !preserve && (found || crd.GroupVersionKind().Version == "v1")
+
+	object := map[string]interface{}{}
+	for k, v := range u.Object {
+		if k != "apiVersion" && k != "kind" && k != "metadata" {
+			object[k] = v
+		}
+	}
+	return validateValue("", object, openAPI, pruning)
+}
+
+// validateValue checks a value against an OpenAPI v3 schema of a CRD,
+// covering types, enums, required properties and, when pruning, unknown
+// fields.
+func validateValue(path string, value interface{}, s map[string]interface{}, pruning bool) []string {
+	if value == nil {
+		if nullable, _ := s["nullable"].(bool); nullable {
+			return nil
+		}
+	}
+	if preserve, _ := s["x-kubernetes-preserve-unknown-fields"].(bool); preserve {
+		pruning = false
+	}
+	at := path
+	if at == "" {
+		at = "."
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok && len(enum) != 0 && !inEnum(value, enum) {
+		return []string{fmt.Sprintf("%s: %v is not one of %v", at, value, enum)}
+	}
+
+	typ, _ := s["type"].(string)
+	if intOrString, _ := s["x-kubernetes-int-or-string"].(bool); intOrString {
+		if _, ok := value.(string); ok || isInteger(value) {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: expected an integer or a string", at)}
+	}
+	switch typ {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an object", at)}
+		}
+		return validateObject(path, object, s, pruning)
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an array", at)}
+		}
+		items, ok := s["items"].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		var problems []string
+		for i, item := range array {
+			problems = append(problems, validateValue(fmt.Sprintf("%s[%d]", path, i), item, items, pruning)...)
+		}
+		return problems
+	case "string":
+		if _, ok := value.(string); !ok {
+			return []string{fmt.Sprintf("%s: expected a string", at)}
+		}
+	case "integer":
+		if !isInteger(value) {
+			return []string{fmt.Sprintf("%s: expected an integer", at)}
+		}
+	case "number":
+		switch value.(type) {
+		case int64, float64:
+		default:
+			return []string{fmt.Sprintf("%s: expected a number", at)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s: expected a boolean", at)}
+		}
+	}
+	return nil
+}
+
+func validateObject(path string, object, s map[string]interface{}, pruning bool) []string {
+	var problems []string
+	required, _ := s["required"].([]interface{})
+	for _, r := range required {
+		if name, _ := r.(string); name != "" {
+			if _, ok := object[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s.%s: required", path, name))
+			}
+		}
+	}
+
+	properties, _ := s["properties"].(map[string]interface{})
+	var additional map[string]interface{}
+	allowsAdditional := false
+	switch a := s["additionalProperties"].(type) {
+	case map[string]interface{}:
+		additional, allowsAdditional = a, true
+	case bool:
+		allowsAdditional = a
+	}
+
+	keys := make([]string, 0, len(object))
+	for k := range object {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		field := path + "." + k
+		if property, ok := properties[k].(map[string]interface{}); ok {
+			problems = append(problems, validateValue(field, object[k], property, pruning)...)
+			continue
+		}
+		if additional != nil {
+			problems = append(problems, validateValue(field, object[k], additional, pruning)...)
+			continue
+		}
+		if pruning && !allowsAdditional {
+			problems = append(problems, fmt.Sprintf("%s: unknown field, would be pruned", field))
+		}
+	}
+	return problems
+}
+
+func isInteger(value interface{}) bool {
+	switch v := value.(type) {
+	case int64:
+		return true
+	case float64:
+		return v == math.Trunc(v)
+	}
+	return false
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(e, value) || (isInteger(e) && isInteger(value) && fmt.Sprint(e) == fmt.Sprint(value)) {
+			return true
+		}
+	}
+	return false
+}
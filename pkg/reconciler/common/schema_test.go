@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func interceptorCRD(version string, served bool) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "clusterinterceptors.triggers.tekton.dev"},
+		"spec": map[string]interface{}{
+			"group": "triggers.tekton.dev",
+			"names": map[string]interface{}{"kind": "ClusterInterceptor", "plural": "clusterinterceptors"},
+			"versions": []interface{}{map[string]interface{}{
+				"name":   version,
+				"served": served,
+				"schema": map[string]interface{}{"openAPIV3Schema": map[string]interface{}{
+					"type":     "object",
+					"required": []interface{}{"spec"},
+					"properties": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"clientConfig": map[string]interface{}{
+									"type":                                 "object",
+									"x-kubernetes-preserve-unknown-fields": true,
+								},
+								"timeout": map[string]interface{}{"type": "integer"},
+								"mode":    map[string]interface{}{"type": "string", "enum": []interface{}{"http", "https"}},
+							},
+						},
+					},
+				}},
+			}},
+		},
+	}}
+}
+
+func interceptor(version string, spec map[string]interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "triggers.tekton.dev/" + version,
+		"kind":       "ClusterInterceptor",
+		"metadata":   map[string]interface{}{"name": "cel"},
+		"spec":       spec,
+	}}
+}
+
+func TestValidateCustomResources(t *testing.T) {
+	valid := map[string]interface{}{
+		"clientConfig": map[string]interface{}{"service": map[string]interface{}{"name": "interceptors"}},
+		"timeout":      int64(10),
+		"mode":         "https",
+	}
+	tests := []struct {
+		name     string
+		crd      unstructured.Unstructured
+		resource unstructured.Unstructured
+		problems []string
+	}{{
+		name:     "valid",
+		crd:      interceptorCRD("v1alpha1", true),
+		resource: interceptor("v1alpha1", valid),
+	}, {
+		name:     "unknown version",
+		crd:      interceptorCRD("v1alpha1", true),
+		resource: interceptor("v1beta1", valid),
+		problems: []string{"version v1beta1 is not defined by CRD clusterinterceptors.triggers.tekton.dev, which defines v1alpha1"},
+	}, {
+		name:     "version not served",
+		crd:      interceptorCRD("v1alpha1", false),
+		resource: interceptor("v1alpha1", valid),
+		problems: []string{"version v1alpha1 is not served by CRD clusterinterceptors.triggers.tekton.dev"},
+	}, {
+		name: "schema mismatch",
+		crd:  interceptorCRD("v1alpha1", true),
+		resource: interceptor("v1alpha1", map[string]interface{}{
+			"timeout":  "10s",
+			"mode":     "grpc",
+			"caBundle": "abc",
+		}),
+		problems: []string{
+			".spec.caBundle: unknown field, would be pruned",
+			".spec.mode: grpc is not one of [http https]",
+			".spec.timeout: expected an integer",
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			crd := test.crd
+			util.AssertDeepEqual(t, validateCustomResource(&crd, &test.resource), test.problems)
+		})
+	}
+}
+
+func TestValidateCustomResourcesStage(t *testing.T) {
+	deployment := util.MakeUnstructured(t, util.MakeDeployment("controller", corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "controller", Image: "gcr.io/controller:v1"}},
+	}))
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
+		interceptorCRD("v1alpha1", true),
+		interceptor("v1alpha1", map[string]interface{}{}),
+		interceptor("v1beta1", map[string]interface{}{}),
+		deployment,
+	}))
+	util.AssertNoError(t, err)
+	manifest.Client = util.NewFakeClient()
+
+	instance := &v1alpha1.TektonTrigger{}
+	instance.Status.InitializeConditions()
+	err = ValidateCustomResources(context.Background(), &manifest, instance)
+	if err == nil {
+		t.Fatal("ValidateCustomResources() = nil, wanted an error for the v1beta1 ClusterInterceptor")
+	}
+	if !strings.Contains(err.Error(), "ClusterInterceptor cel: version v1beta1 is not defined") {
+		t.Errorf("ValidateCustomResources() = %v, wanted the problem of the ClusterInterceptor", err)
+	}
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).IsFalse(), true)
+
+	// Custom resources of CRDs neither in the manifest nor in the cluster are skipped
+	manifest, err = mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{interceptor("v1beta1", nil)}))
+	util.AssertNoError(t, err)
+	manifest.Client = util.NewFakeClient()
+	util.AssertNoError(t, ValidateCustomResources(context.Background(), &manifest, instance))
+}
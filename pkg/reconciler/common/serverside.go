@@ -0,0 +1,236 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+
+	mfc "github.com/manifestival/client-go-client"
+	mfdynamic "github.com/manifestival/client-go-client/pkg/dynamic"
+	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"knative.dev/pkg/logging"
+)
+
+// FieldManager is the field manager of the fields of the resources the
+// operator applies server side.
+const FieldManager = "tekton-operator"
+
+// ownManagers are the field managers of the operator, the one of the
+// manifestival updates applied client side before included
+var ownManagers = map[string]bool{
+	FieldManager:   true,
+	"manifestival": true,
+}
+
+// ServerSideApplier is a manifestival client able to apply a resource
+// server side, owning the fields the resource sets only. A dry run
+// returns the resource as the apply would leave it, without persisting it.
+type ServerSideApplier interface {
+	mf.Client
+	ApplyServerSide(u *unstructured.Unstructured, dryRun bool) (*unstructured.Unstructured, error)
+}
+
+// serverSideClient is the manifestival client of a cluster, applying
+// resources server side on demand.
+type serverSideClient struct {
+	mf.Client
+	resources mfdynamic.ResourceGetter
+}
+
+// NewClient returns the manifestival client of the cluster of the config,
+// which is a ServerSideApplier.
+func NewClient(cfg *rest.Config) (mf.Client, error) {
+	client, err := mfc.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := mfdynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &serverSideClient{Client: client, resources: resources}, nil
+}
+
+// ApplyServerSide implements ServerSideApplier. The ownership of the
+// conflicting fields is forced over only when the operator itself held
+// them, e.g. through the manifestival updates applied before: the fields
+// held by others, e.g. an autoscaler, fail the apply with a conflict.
+func (c *serverSideClient) ApplyServerSide(u *unstructured.Unstructured, dryRun bool) (*unstructured.Unstructured, error) {
+	resource, err := c.resources.ResourceInterface(u)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(u.Object)
+	if err != nil {
+		return nil, err
+	}
+	opts := metav1.PatchOptions{FieldManager: FieldManager}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	applied, err := resource.Patch(context.TODO(), u.GetName(), types.ApplyPatchType, data, opts)
+	if apierrors.IsConflict(err) && ownConflicts(err) {
+		force := true
+		opts.Force = &force
+		return resource.Patch(context.TODO(), u.GetName(), types.ApplyPatchType, data, opts)
+	}
+	return applied, err
+}
+
+// ownConflicts returns true if the fields of the conflict of an apply are
+// all held by field managers of the operator.
+func ownConflicts(err error) bool {
+	status, ok := err.(apierrors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return false
+	}
+	conflicts := 0
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflicts++
+		match := conflictManager.FindStringSubmatch(cause.Message)
+		if match == nil || !ownManagers[match[1]] {
+			return false
+		}
+	}
+	return conflicts != 0
+}
+
+// conflictManager matches the field manager of the message of a conflict,
+// e.g. `conflict with "kube-controller-manager" using apps/v1`
+var conflictManager = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+type serverSideApplyKey struct{}
+
+// WithServerSideApply attaches to the context whether Apply applies the
+// resources server side.
+func WithServerSideApply(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, serverSideApplyKey{}, enabled)
+}
+
+// serverSideApply returns true if the context asks for the resources to
+// be applied server side.
+func serverSideApply(ctx context.Context) bool {
+	enabled, _ := ctx.Value(serverSideApplyKey{}).(bool)
+	return enabled
+}
+
+// applyServerSide applies the resources of the manifest server side with
+// the applier, recording the outcome of each with the recorder: the live
+// resource is read first to tell creations and updates apart.
+func applyServerSide(applier ServerSideApplier, recorder *applyRecorder, manifest mf.Manifest) error {
+	for _, spec := range manifest.Resources() {
+		u := spec.DeepCopy()
+		live, err := recorder.Get(u)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		created := err != nil || live == nil
+		applied, err := applier.ApplyServerSide(u, false)
+		switch {
+		case err != nil:
+			recorder.record(u, applyFailed)
+			return err
+		case created:
+			recorder.record(u, applyCreated)
+		case applied.GetResourceVersion() != live.GetResourceVersion():
+			recorder.record(u, applyUpdated)
+		}
+	}
+	return nil
+}
+
+// serverSideDrift returns the number of resources of the manifest a server
+// side apply would change, found by a dry run of the apply: the
+// last-applied annotation the client side DryRun compares against isn't
+// updated by server side applies.
+func serverSideDrift(applier ServerSideApplier, manifest mf.Manifest) (int, error) {
+	drifted := 0
+	for _, spec := range manifest.Resources() {
+		u := spec.DeepCopy()
+		live, err := applier.Get(u)
+		if apierrors.IsNotFound(err) {
+			drifted++
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		applied, err := applier.ApplyServerSide(u, true)
+		if err != nil {
+			return 0, err
+		}
+		if !equality.Semantic.DeepEqual(contentOf(live), contentOf(applied)) {
+			drifted++
+		}
+	}
+	return drifted, nil
+}
+
+// contentOf returns the content of the resource without the metadata
+// every write changes
+func contentOf(u *unstructured.Unstructured) map[string]interface{} {
+	c := u.DeepCopy()
+	unstructured.RemoveNestedField(c.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(c.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(c.Object, "metadata", "generation")
+	return c.Object
+}
+
+// withoutAutoscaledReplicas returns the manifest without the replicas of
+// the workloads a HorizontalPodAutoscaler of the manifest scales, so that
+// a server side apply leaves them to the autoscaler rather than taking
+// them over.
+func withoutAutoscaledReplicas(manifest mf.Manifest) (mf.Manifest, error) {
+	targets := autoscalerTargets(manifest)
+	if len(targets) == 0 {
+		return manifest, nil
+	}
+	return manifest.Transform(func(u *unstructured.Unstructured) error {
+		if targets[autoscalerTarget(u.GetNamespace(), u.GetKind(), u.GetName())] {
+			unstructured.RemoveNestedField(u.Object, "spec", "replicas")
+		}
+		return nil
+	})
+}
+
+// applier returns how Apply applies the groups of resources of the
+// manifest: server side when the context asks for it and the client of
+// the manifest supports it, through the manifestival apply otherwise.
+func applier(ctx context.Context, client mf.Client, recorder *applyRecorder) func(mf.Manifest) error {
+	if serverSideApply(ctx) {
+		if ssa, ok := client.(ServerSideApplier); ok {
+			return func(manifest mf.Manifest) error {
+				return applyServerSide(ssa, recorder, manifest)
+			}
+		}
+		logging.FromContext(ctx).Warn("The client can't apply server side, applying client side")
+	}
+	return func(manifest mf.Manifest) error {
+		return manifest.Apply()
+	}
+}
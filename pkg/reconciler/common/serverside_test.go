@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type fakeServerSideClient struct {
+	*util.FakeClient
+	applies []string
+}
+
+func (f *fakeServerSideClient) ApplyServerSide(obj *unstructured.Unstructured, dryRun bool) (*unstructured.Unstructured, error) {
+	f.applies = append(f.applies, obj.GetName())
+	applied := obj.DeepCopy()
+	applied.SetResourceVersion("2")
+	return applied, f.Err
+}
+
+func TestApplyServerSide(t *testing.T) {
+	deployment := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	resources := []unstructured.Unstructured{
+		namespacedResource("apps/v1", "Deployment", "tekton-pipelines", "controller"),
+		namespacedResource("apps/v1", "Deployment", "tekton-pipelines", "webhook"),
+	}
+	tests := []struct {
+		name         string
+		enabled      bool
+		wantApplies  []string
+		wantCreates  int
+		wantOutcomes map[string]int64
+	}{{
+		name:         "server side",
+		enabled:      true,
+		wantApplies:  []string{"controller", "webhook"},
+		wantOutcomes: map[string]int64{applyCreated: 2},
+	}, {
+		name:         "client side",
+		wantCreates:  2,
+		wantOutcomes: map[string]int64{applyCreated: 2},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &fakeServerSideClient{FakeClient: util.NewFakeClient()}
+			recorder := newApplyRecorder(client)
+			manifest, err := mf.ManifestFrom(mf.Slice(resources), mf.UseClient(recorder))
+			util.AssertNoError(t, err)
+			ctx := WithServerSideApply(context.Background(), test.enabled)
+			util.AssertNoError(t, applier(ctx, client, recorder)(manifest))
+			util.AssertDeepEqual(t, client.applies, test.wantApplies)
+			util.AssertEqual(t, len(client.Creates), test.wantCreates)
+			util.AssertDeepEqual(t, recorder.counts(), map[schema.GroupVersionKind]map[string]int64{deployment: test.wantOutcomes})
+		})
+	}
+}
+
+func TestApplyServerSideUnsupported(t *testing.T) {
+	client := util.NewFakeClient()
+	recorder := newApplyRecorder(client)
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
+		namespacedResource("apps/v1", "Deployment", "tekton-pipelines", "controller"),
+	}), mf.UseClient(client))
+	util.AssertNoError(t, err)
+	// clients which can't apply server side apply client side
+	ctx := WithServerSideApply(context.Background(), true)
+	util.AssertNoError(t, applier(ctx, client, recorder)(manifest))
+	util.AssertEqual(t, len(client.Creates), 1)
+}
+
+// dryRunClient applies server side by replacing the live resource, its
+// dry runs returning the replaced resource
+type dryRunClient struct {
+	*util.FakeClient
+}
+
+func (c dryRunClient) ApplyServerSide(obj *unstructured.Unstructured, dryRun bool) (*unstructured.Unstructured, error) {
+	applied := obj.DeepCopy()
+	applied.SetResourceVersion("3")
+	applied.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: FieldManager}})
+	return applied, nil
+}
+
+func TestServerSideDrift(t *testing.T) {
+	controller := namespacedResource("apps/v1", "Deployment", "tekton-pipelines", "controller")
+	webhook := namespacedResource("apps/v1", "Deployment", "tekton-pipelines", "webhook")
+	live := webhook.DeepCopy()
+	live.SetResourceVersion("2")
+	client := dryRunClient{util.NewFakeClient(*live)}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{webhook}), mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	drifted, err := serverSideDrift(client, manifest)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, drifted, 0)
+
+	// a missing resource and a changed one drifted
+	webhook.SetLabels(map[string]string{"version": "v2"})
+	manifest, err = mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{controller, webhook}), mf.UseClient(client))
+	util.AssertNoError(t, err)
+	drifted, err = serverSideDrift(client, manifest)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, drifted, 2)
+}
+
+func TestOwnConflicts(t *testing.T) {
+	conflict := func(managers ...string) error {
+		var causes []metav1.StatusCause
+		for _, manager := range managers {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldManagerConflict,
+				Message: fmt.Sprintf("conflict with %q using apps/v1", manager),
+				Field:   ".spec.replicas",
+			})
+		}
+		return &apierrors.StatusError{ErrStatus: metav1.Status{
+			Reason:  metav1.StatusReasonConflict,
+			Details: &metav1.StatusDetails{Causes: causes},
+		}}
+	}
+	util.AssertEqual(t, ownConflicts(conflict("manifestival")), true)
+	util.AssertEqual(t, ownConflicts(conflict("manifestival", FieldManager)), true)
+	util.AssertEqual(t, ownConflicts(conflict("manifestival", "kube-controller-manager")), false)
+	util.AssertEqual(t, ownConflicts(conflict()), false)
+	util.AssertEqual(t, ownConflicts(errors.New("boom")), false)
+}
+
+func TestWithoutAutoscaledReplicas(t *testing.T) {
+	deployment := func(name string) unstructured.Unstructured {
+		u := namespacedResource("apps/v1", "Deployment", "tekton-pipelines", name)
+		u.Object["spec"] = map[string]interface{}{"replicas": int64(1)}
+		return u
+	}
+	hpa := namespacedResource("autoscaling/v2beta1", "HorizontalPodAutoscaler", "tekton-pipelines", "tekton-pipelines-webhook")
+	hpa.Object["spec"] = map[string]interface{}{"scaleTargetRef": map[string]interface{}{
+		"apiVersion": "apps/v1", "kind": "Deployment", "name": "webhook",
+	}}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment("controller"), deployment("webhook"), hpa}))
+	util.AssertNoError(t, err)
+
+	result, err := withoutAutoscaledReplicas(manifest)
+	util.AssertNoError(t, err)
+	_, found, _ := unstructured.NestedInt64(util.FindResource(t, result, "Deployment", "webhook").Object, "spec", "replicas")
+	util.AssertEqual(t, found, false)
+	replicas, _, _ := unstructured.NestedInt64(util.FindResource(t, result, "Deployment", "controller").Object, "spec", "replicas")
+	util.AssertEqual(t, replicas, int64(1))
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/pkg/system"
+)
+
+const (
+	serviceMonitorAPIVersion = "monitoring.coreos.com/v1"
+	serviceMonitorKind       = "ServiceMonitor"
+	// metricsPort is the name of the port of the Services serving metrics
+	metricsPort           = "http-metrics"
+	defaultScrapeInterval = "30s"
+
+	// operatorMetricsService is the Service of the metrics of the operator,
+	// and the name of their ServiceMonitor
+	operatorMetricsService = "tekton-operator-metrics"
+	// operatorMonitorPart names the installer set of the ServiceMonitor of
+	// the operator, see InstallPart
+	operatorMonitorPart = "operator-monitor"
+)
+
+// operatorMetricsSelector selects the Service of the metrics of the operator
+var operatorMetricsSelector = map[string]string{"app": "tekton-operator"}
+
+// ServiceMonitors is a Stage appending a Prometheus Operator ServiceMonitor
+// for the controller Services of the payload serving metrics when
+// spec.config.serviceMonitors is set, but for the Services a ServiceMonitor
+// of the payload scrapes already. The ServiceMonitors select their Service
+// by its labels but the versions, so that they keep scraping across
+// upgrades. When it is unset, the ServiceMonitors the operator generated
+// before are deleted.
+func ServiceMonitors(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	monitors := instance.GetSpec().GetConfig().ServiceMonitors
+	services := manifest.Filter(mf.ByKind("Service"), monitoredService).Resources()
+	if monitors == nil {
+		for i := range services {
+			if err := deleteGeneratedMonitor(manifest, services[i].GetNamespace(), services[i].GetName()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := validateServiceMonitors(monitors); err != nil {
+		operrors.MarkFailed(instance.GetStatus(), err)
+		return err
+	}
+	if len(services) == 0 {
+		return nil
+	}
+	payloadMonitors := manifest.Filter(mf.ByKind(serviceMonitorKind)).Resources()
+	var resources []unstructured.Unstructured
+	for _, service := range services {
+		if scrapedBy(&service, payloadMonitors) {
+			// e.g. openshift-pipelines-monitor, Prometheus would scrape
+			// the Service twice
+			if err := deleteGeneratedMonitor(manifest, service.GetNamespace(), service.GetName()); err != nil {
+				return err
+			}
+			continue
+		}
+		resources = append(resources, serviceMonitor(service.GetName(), service.GetNamespace(),
+			stableLabels(service.GetLabels()), service.GetLabels(), monitors))
+	}
+	generated, err := mf.ManifestFrom(mf.Slice(resources))
+	if err != nil {
+		return err
+	}
+	if err := Transform(ctx, &generated, instance); err != nil {
+		return err
+	}
+	*manifest = manifest.Append(generated)
+	return nil
+}
+
+// OperatorServiceMonitor is a Stage installing the ServiceMonitor of the
+// metrics of the operator in its namespace when spec.config.serviceMonitors
+// is set, in an installer set of its own, and deleting it once unset if
+// that installer set records it. The TektonConfig runs it, the operator
+// having a single one.
+func OperatorServiceMonitor(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	monitors := instance.GetSpec().GetConfig().ServiceMonitors
+	if monitors == nil {
+		return deleteOperatorMonitor(ctx, manifest, instance)
+	}
+	if err := validateServiceMonitors(monitors); err != nil {
+		operrors.MarkFailed(instance.GetStatus(), err)
+		return err
+	}
+	monitor := serviceMonitor(operatorMetricsService, system.Namespace(), operatorMetricsSelector, operatorMetricsSelector, monitors)
+	operator, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{monitor}), mf.UseClient(manifest.Client))
+	if err != nil {
+		return err
+	}
+	if err := TransformWith(ctx, &operator, instance, TransformOptions{Namespace: PreserveNamespace}); err != nil {
+		return err
+	}
+	return InstallPart(ctx, operatorMonitorPart, &operator, instance)
+}
+
+// deleteOperatorMonitor deletes the ServiceMonitor of the operator, and
+// the installer set recording it, which leaves its resources behind when
+// deleted. Nothing is deleted without the installer set, the monitor never
+// being generated then.
+func deleteOperatorMonitor(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	sets := installerSets(ctx)
+	if sets == nil {
+		return nil
+	}
+	_, err := sets.Get(ctx, InstallerSetName(instance, operatorMonitorPart), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := deleteGeneratedMonitor(manifest, system.Namespace(), operatorMetricsService); err != nil {
+		return err
+	}
+	empty, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(manifest.Client))
+	if err != nil {
+		return err
+	}
+	return InstallPart(ctx, operatorMonitorPart, &empty, instance)
+}
+
+// monitoredService selects the Services of the controllers of the payload
+// which serve metrics
+func monitoredService(u *unstructured.Unstructured) bool {
+	if !strings.Contains(u.GetName(), "controller") {
+		return false
+	}
+	ports, _, _ := unstructured.NestedSlice(u.Object, "spec", "ports")
+	for _, port := range ports {
+		if p, ok := port.(map[string]interface{}); ok && p["name"] == metricsPort {
+			return true
+		}
+	}
+	return false
+}
+
+// scrapedBy returns true if one of the ServiceMonitors scrapes the metrics
+// port of the Service
+func scrapedBy(service *unstructured.Unstructured, monitors []unstructured.Unstructured) bool {
+	for i := range monitors {
+		if scrapes(&monitors[i], service) {
+			return true
+		}
+	}
+	return false
+}
+
+// scrapes returns true if the ServiceMonitor selects the Service, by its
+// namespace and labels, and scrapes its metrics port
+func scrapes(monitor, service *unstructured.Unstructured) bool {
+	any, _, _ := unstructured.NestedBool(monitor.Object, "spec", "namespaceSelector", "any")
+	names, _, _ := unstructured.NestedStringSlice(monitor.Object, "spec", "namespaceSelector", "matchNames")
+	switch {
+	case any:
+	case len(names) == 0:
+		// the namespace of the ServiceMonitor only
+		if monitor.GetNamespace() != service.GetNamespace() {
+			return false
+		}
+	case !sets.NewString(names...).Has(service.GetNamespace()):
+		return false
+	}
+
+	raw, _, _ := unstructured.NestedMap(monitor.Object, "spec", "selector")
+	selector := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, selector); err != nil {
+		return false
+	}
+	matches, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil || !matches.Matches(labels.Set(service.GetLabels())) {
+		return false
+	}
+
+	endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "endpoints")
+	for _, endpoint := range endpoints {
+		if e, ok := endpoint.(map[string]interface{}); ok && e["port"] == metricsPort {
+			return true
+		}
+	}
+	return false
+}
+
+// validateServiceMonitors checks the interval of spec.config.serviceMonitors
+func validateServiceMonitors(monitors *v1alpha1.ServiceMonitors) error {
+	if monitors.Interval == "" {
+		return nil
+	}
+	if _, err := time.ParseDuration(monitors.Interval); err != nil {
+		return &operrors.TransformError{Err: fmt.Errorf("invalid spec.config.serviceMonitors.interval %q: %w", monitors.Interval, err)}
+	}
+	return nil
+}
+
+// stableLabels returns the labels of a Service but the ones changing with
+// the version of the payload
+func stableLabels(labels map[string]string) map[string]string {
+	stable := map[string]string{}
+	for k, v := range labels {
+		if k == "version" || k == "app.kubernetes.io/version" || strings.HasSuffix(k, "/release") {
+			continue
+		}
+		stable[k] = v
+	}
+	return stable
+}
+
+// serviceMonitor returns the generated ServiceMonitor of the namespace
+// called name, scraping the metrics port of the Services of the namespace
+// matching selector
+func serviceMonitor(name, namespace string, selector, labels map[string]string, monitors *v1alpha1.ServiceMonitors) unstructured.Unstructured {
+	interval := monitors.Interval
+	if interval == "" {
+		interval = defaultScrapeInterval
+	}
+	all := map[string]string{}
+	for _, l := range []map[string]string{labels, monitors.Labels, {LabelGenerated: "true"}} {
+		for k, v := range l {
+			all[k] = v
+		}
+	}
+	matchLabels := map[string]interface{}{}
+	for k, v := range selector {
+		matchLabels[k] = v
+	}
+
+	u := namespacedResource(serviceMonitorAPIVersion, serviceMonitorKind, namespace, name)
+	u.SetLabels(all)
+	u.Object["spec"] = map[string]interface{}{
+		"namespaceSelector": map[string]interface{}{
+			"matchNames": []interface{}{namespace},
+		},
+		"selector": map[string]interface{}{
+			"matchLabels": matchLabels,
+		},
+		"endpoints": []interface{}{
+			map[string]interface{}{"port": metricsPort, "interval": interval},
+		},
+	}
+	return u
+}
+
+// deleteGeneratedMonitor deletes the ServiceMonitor of the namespace called
+// name if the operator generated it. Clusters without the Prometheus
+// Operator have none.
+func deleteGeneratedMonitor(manifest *mf.Manifest, namespace, name string) error {
+	if manifest.Client == nil {
+		return nil
+	}
+	u := namespacedResource(serviceMonitorAPIVersion, serviceMonitorKind, namespace, name)
+	live, err := manifest.Client.Get(&u)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) || (err == nil && live == nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if live.GetLabels()[LabelGenerated] != "true" {
+		return nil
+	}
+	if err := manifest.Client.Delete(live); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ServiceMonitor %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"knative.dev/pkg/system"
+)
+
+func metricsService(name string, ports ...string) *corev1.Service {
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "tekton-pipelines",
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "controller",
+				"app.kubernetes.io/version":   "v0.19.0",
+				"pipeline.tekton.dev/release": "v0.19.0",
+			},
+		},
+	}
+	for _, p := range ports {
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{Name: p, Port: 9090})
+	}
+	return service
+}
+
+func TestServiceMonitors(t *testing.T) {
+	resources := []unstructured.Unstructured{
+		util.MakeUnstructured(t, metricsService("tekton-pipelines-controller", metricsPort, "probes")),
+		util.MakeUnstructured(t, metricsService("tekton-pipelines-webhook", metricsPort)),
+		util.MakeUnstructured(t, metricsService("tekton-events-controller", "probes")),
+	}
+	client := &generatedClient{util.NewFakeClient()}
+	manifest, err := mf.ManifestFrom(mf.Slice(resources), mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	// generated monitors are deleted while spec.config.serviceMonitors is unset
+	instance := &v1alpha1.TektonPipeline{Spec: v1alpha1.TektonPipelineSpec{CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "tekton-pipelines"}}}
+	util.AssertNoError(t, ServiceMonitors(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(manifest.Resources()), 3)
+	util.AssertEqual(t, len(client.Deletes), 1)
+	util.AssertEqual(t, client.Deletes[0].GetName(), "tekton-pipelines-controller")
+
+	instance.Spec.Config.ServiceMonitors = &v1alpha1.ServiceMonitors{Labels: map[string]string{"release": "prometheus"}}
+	util.AssertNoError(t, ServiceMonitors(context.Background(), &manifest, instance))
+	monitors := manifest.Filter(mf.ByKind(serviceMonitorKind)).Resources()
+	util.AssertEqual(t, len(monitors), 1)
+	monitor := monitors[0]
+	util.AssertEqual(t, monitor.GetName(), "tekton-pipelines-controller")
+	util.AssertEqual(t, monitor.GetNamespace(), "tekton-pipelines")
+	util.AssertEqual(t, monitor.GetLabels()[LabelGenerated], "true")
+	util.AssertEqual(t, monitor.GetLabels()["release"], "prometheus")
+	selector, _, _ := unstructured.NestedStringMap(monitor.Object, "spec", "selector", "matchLabels")
+	util.AssertDeepEqual(t, selector, map[string]string{"app.kubernetes.io/name": "controller"})
+	endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "endpoints")
+	util.AssertDeepEqual(t, endpoints, []interface{}{map[string]interface{}{"port": metricsPort, "interval": defaultScrapeInterval}})
+
+	instance.Spec.Config.ServiceMonitors.Interval = "often"
+	util.AssertError(t, ServiceMonitors(context.Background(), &manifest, instance))
+}
+
+func TestServiceMonitorsScrapedByPayload(t *testing.T) {
+	payload := &unstructured.Unstructured{}
+	payload.SetAPIVersion("monitoring.coreos.com/v1")
+	payload.SetKind(serviceMonitorKind)
+	payload.SetName("openshift-pipelines-monitor")
+	payload.SetNamespace("tekton-pipelines")
+	util.AssertNoError(t, unstructured.SetNestedField(payload.Object, map[string]interface{}{
+		"endpoints":         []interface{}{map[string]interface{}{"port": metricsPort}},
+		"namespaceSelector": map[string]interface{}{"matchNames": []interface{}{"tekton-pipelines"}},
+		"selector":          map[string]interface{}{"matchLabels": map[string]interface{}{"app.kubernetes.io/name": "controller"}},
+	}, "spec"))
+	resources := []unstructured.Unstructured{
+		util.MakeUnstructured(t, metricsService("tekton-pipelines-controller", metricsPort)),
+		*payload,
+	}
+	client := &generatedClient{util.NewFakeClient()}
+	manifest, err := mf.ManifestFrom(mf.Slice(resources), mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	// the payload monitor scrapes the controller already
+	instance := &v1alpha1.TektonPipeline{Spec: v1alpha1.TektonPipelineSpec{CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "tekton-pipelines"}}}
+	instance.Spec.Config.ServiceMonitors = &v1alpha1.ServiceMonitors{}
+	util.AssertNoError(t, ServiceMonitors(context.Background(), &manifest, instance))
+	monitors := manifest.Filter(mf.ByKind(serviceMonitorKind)).Resources()
+	util.AssertEqual(t, len(monitors), 1)
+	util.AssertEqual(t, monitors[0].GetName(), "openshift-pipelines-monitor")
+	util.AssertEqual(t, len(client.Deletes), 1)
+	util.AssertEqual(t, client.Deletes[0].GetName(), "tekton-pipelines-controller")
+
+	// but not in another namespace
+	util.AssertNoError(t, unstructured.SetNestedStringSlice(payload.Object, []string{"openshift-monitoring"}, "spec", "namespaceSelector", "matchNames"))
+	manifest, err = mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{resources[0], *payload}), mf.UseClient(&generatedClient{util.NewFakeClient()}))
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, ServiceMonitors(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(manifest.Filter(mf.ByKind(serviceMonitorKind)).Resources()), 2)
+}
+
+func TestOperatorServiceMonitor(t *testing.T) {
+	os.Setenv(system.NamespaceEnvKey, "tekton-operator")
+	defer os.Unsetenv(system.NamespaceEnvKey)
+	sets := fake.NewSimpleClientset().OperatorV1alpha1().TektonInstallerSets()
+	ctx := WithInstallerSets(context.Background(), sets)
+	name := InstallerSetName(&v1alpha1.TektonConfig{ObjectMeta: metav1.ObjectMeta{Name: ConfigResourceName}}, operatorMonitorPart)
+
+	// nothing recorded, nothing deleted
+	client := &generatedClient{util.NewFakeClient()}
+	manifest, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(client))
+	util.AssertNoError(t, err)
+	instance := &v1alpha1.TektonConfig{ObjectMeta: metav1.ObjectMeta{Name: ConfigResourceName}}
+	util.AssertNoError(t, OperatorServiceMonitor(ctx, &manifest, instance))
+	util.AssertEqual(t, len(client.Deletes), 0)
+
+	// the monitor is recorded in the namespace of the operator, whatever
+	// the target namespace
+	instance.Spec.TargetNamespace = "tekton-pipelines"
+	instance.Spec.Config.ServiceMonitors = &v1alpha1.ServiceMonitors{Interval: "1m"}
+	if err := OperatorServiceMonitor(ctx, &manifest, instance); !errors.Is(err, ErrInstallerSetPending) {
+		t.Fatalf("expected the installer set to be pending, got %v", err)
+	}
+	util.AssertEqual(t, len(manifest.Resources()), 0)
+	set, err := sets.Get(ctx, name, metav1.GetOptions{})
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(set.Spec.Manifests), 1)
+	monitor := set.Spec.Manifests[0]
+	util.AssertEqual(t, monitor.GetNamespace(), "tekton-operator")
+	util.AssertEqual(t, monitor.GetName(), operatorMetricsService)
+	util.AssertEqual(t, set.GetOwnerReferences()[0].Name, ConfigResourceName)
+	selector, _, _ := unstructured.NestedStringMap(monitor.Object, "spec", "selector", "matchLabels")
+	util.AssertDeepEqual(t, selector, operatorMetricsSelector)
+	endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "endpoints")
+	util.AssertDeepEqual(t, endpoints, []interface{}{map[string]interface{}{"port": metricsPort, "interval": "1m"}})
+
+	// applied without installer sets
+	created := util.NewFakeClient()
+	applied, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(created))
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, OperatorServiceMonitor(context.Background(), &applied, instance))
+	util.AssertEqual(t, len(created.Creates), 1)
+	util.AssertEqual(t, created.Creates[0].GetNamespace(), "tekton-operator")
+
+	// the recorded monitor is deleted once unset, with its installer set
+	instance.Spec.Config.ServiceMonitors = nil
+	util.AssertNoError(t, OperatorServiceMonitor(ctx, &manifest, instance))
+	util.AssertEqual(t, len(client.Deletes), 1)
+	util.AssertEqual(t, client.Deletes[0].GetNamespace(), "tekton-operator")
+	util.AssertEqual(t, client.Deletes[0].GetName(), operatorMetricsService)
+	if _, err := sets.Get(ctx, name, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the installer set to be deleted, got %v", err)
+	}
+}
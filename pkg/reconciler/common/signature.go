@@ -0,0 +1,245 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+)
+
+const (
+	// cosignSignatureAnnotation holds the signature of a layer of a cosign
+	// signature image
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// maxSignatureSize bounds the signature manifests and payloads read
+	maxSignatureSize = 1 << 20
+)
+
+var (
+	signatureMediaTypes = strings.Join([]string{
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+	}, ",")
+
+	// verifiedImages caches the digests verified with a key, signatures of a
+	// digest don't change
+	verifiedImages sync.Map
+)
+
+// VerifyImageSignatures verifies the cosign signatures of the payload
+// images against the public keys of spec.signatureVerification, before
+// anything is installed. The registries are reached like the image pulls
+// are checked, see PrecheckImagePulls. An image is valid once signed with any of the
+// keys. The install fails naming every image without a valid signature,
+// and why.
+func VerifyImageSignatures(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	verification := instance.GetSpec().GetSignatureVerification()
+	if verification == nil {
+		return nil
+	}
+	status := instance.GetStatus()
+	var keys []*ecdsa.PublicKey
+	for _, data := range verification.PublicKeys() {
+		key, err := parseCosignPublicKey(data)
+		if err != nil {
+			err = &operrors.TransformError{Err: fmt.Errorf("Invalid signature verification key: %w", err)}
+			operrors.MarkFailed(status, err)
+			return err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		err := &operrors.TransformError{Err: errors.New("spec.signatureVerification sets no public key")}
+		operrors.MarkFailed(status, err)
+		return err
+	}
+
+	images, err := manifestImages(*manifest)
+	if err != nil {
+		return err
+	}
+	registries, err := payloadRegistries(manifest, instance)
+	if err != nil {
+		return err
+	}
+	var msg string
+	var failures []string
+	for _, image := range images {
+		if err := verifyImageSignature(ctx, registries, keys, image); err != nil {
+			msg = fmt.Sprintf("Signature verification of image %s failed: %v", image, err)
+			failures = append(failures, fmt.Sprintf("%s: %v", image, err))
+		}
+	}
+	switch {
+	case len(failures) == 0:
+		return nil
+	case len(failures) > 1:
+		msg = fmt.Sprintf("Signature verification of %d images failed: %s", len(failures), strings.Join(failures, "; "))
+	}
+	err = &operrors.SignatureVerificationFailed{Err: errors.New(msg)}
+	operrors.MarkFailed(status, err)
+	return err
+}
+
+// parseCosignPublicKey parses a PEM encoded ECDSA public key, the format of
+// cosign.pub.
+func parseCosignPublicKey(data string) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM encoded public key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T, expected ECDSA", pub)
+	}
+	return key, nil
+}
+
+// verifyImageSignature looks for a signature of the digest of the image,
+// made with one of the keys, in the cosign signature image stored next to
+// it.
+func verifyImageSignature(ctx context.Context, registries *registries, keys []*ecdsa.PublicKey, image string) error {
+	ref, digest := image, ""
+	if i := strings.Index(image, "@"); i != -1 {
+		ref, digest = image[:i], image[i+1:]
+	}
+	registry, repository, _ := parseImage(ref)
+	access := registries.access(registry)
+	if digest == "" {
+		resolved, err := resolveDigest(ctx, access, image)
+		if err != nil {
+			return err
+		}
+		digest = resolved
+	}
+
+	cacheKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			return err
+		}
+		cacheKey := fmt.Sprintf("%x/%s/%s@%s", sha256.Sum256(der), registry, repository, digest)
+		if _, ok := verifiedImages.Load(cacheKey); ok {
+			return nil
+		}
+		cacheKeys = append(cacheKeys, cacheKey)
+	}
+
+	signatureTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	body, err := registryGet(ctx, access, fmt.Sprintf("https://%s/v2/%s/manifests/%s", registry, repository, signatureTag), signatureMediaTypes)
+	if err != nil {
+		return fmt.Errorf("no signature found: %w", err)
+	}
+	var signatures struct {
+		Layers []struct {
+			Digest      string            `json:"digest"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"layers"`
+	}
+	if err := json.Unmarshal(body, &signatures); err != nil {
+		return fmt.Errorf("invalid signature manifest: %w", err)
+	}
+
+	for _, layer := range signatures.Layers {
+		signature, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		payload, err := registryGet(ctx, access, fmt.Sprintf("https://%s/v2/%s/blobs/%s", registry, repository, layer.Digest), "")
+		if err != nil {
+			return err
+		}
+		for i, key := range keys {
+			if verifyCosignPayload(key, payload, layer.Digest, signature, digest) {
+				verifiedImages.Store(cacheKeys[i], struct{}{})
+				return nil
+			}
+		}
+	}
+	if len(keys) > 1 {
+		return fmt.Errorf("no valid signature for digest %s with any of the %d keys", digest, len(keys))
+	}
+	return fmt.Errorf("no valid signature for digest %s", digest)
+}
+
+// verifyCosignPayload returns true if the payload is the layer with the
+// given digest, signed with the key, and claims the digest of the image.
+func verifyCosignPayload(key *ecdsa.PublicKey, payload []byte, layerDigest, signature, imageDigest string) bool {
+	sum := sha256.Sum256(payload)
+	if layerDigest != "sha256:"+hex.EncodeToString(sum[:]) {
+		return false
+	}
+	der, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	var sig struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil || len(rest) != 0 {
+		return false
+	}
+	if !ecdsa.Verify(key, sum[:], sig.R, sig.S) {
+		return false
+	}
+	var simpleSigning struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	if err := json.Unmarshal(payload, &simpleSigning); err != nil {
+		return false
+	}
+	return simpleSigning.Critical.Image.DockerManifestDigest == imageDigest
+}
+
+// registryGet reads a document from the registry API through the access.
+func registryGet(ctx context.Context, access *registryAccess, apiURL, accept string) ([]byte, error) {
+	resp, err := access.do(ctx, http.MethodGet, apiURL, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", apiURL, resp.Status)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, maxSignatureSize))
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"knative.dev/pkg/apis"
+)
+
+func TestVerifyImageSignatures(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	util.AssertNoError(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	util.AssertNoError(t, err)
+
+	// the registry holds a signature of the controller made with key
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"tekton/controller"},"image":{"docker-manifest-digest":"sha256:signed"},"type":"cosign container image signature"},"optional":null}`)
+	sum := sha256.Sum256(payload)
+	r, s, err := ecdsa.Sign(rand.Reader, key, sum[:])
+	util.AssertNoError(t, err)
+	signature, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	util.AssertNoError(t, err)
+	layerDigest := fmt.Sprintf("sha256:%x", sum)
+	signatureManifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"layers": []map[string]interface{}{{
+			"mediaType": "application/vnd.dev.cosign.simplesigning.v1+json",
+			"digest":    layerDigest,
+			"annotations": map[string]string{
+				cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature),
+			},
+		}},
+	})
+	util.AssertNoError(t, err)
+
+	// the registry is private, read with the credentials of a pull secret
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "robot" || password != "s3cr3t" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/tekton/controller/manifests/sha256-signed.sig":
+			w.Write(signatureManifest)
+		case "/v2/tekton/controller/blobs/" + layerDigest:
+			w.Write(payload)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	defer func(client *http.Client) { registryClient = client }(registryClient)
+	registryClient = server.Client()
+	defer func(resolve func(context.Context, *registryAccess, string) (string, error)) { resolveDigest = resolve }(resolveDigest)
+	resolveDigest = func(context.Context, *registryAccess, string) (string, error) { return "sha256:signed", nil }
+	host := strings.TrimPrefix(server.URL, "https://")
+	client := fake.New(pullSecret("registry", corev1.SecretTypeDockerConfigJson, corev1.DockerConfigJsonKey,
+		`{"auths": {"`+host+`": {"username": "robot", "password": "s3cr3t"}}}`))
+
+	manifestWith := func(image string) mf.Manifest {
+		deployment := util.MakeUnstructured(t, util.MakeDeployment("controller", corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "controller", Image: image}},
+		}))
+		manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment}), mf.UseClient(client))
+		util.AssertNoError(t, err)
+		return manifest
+	}
+	publicKey := func(key *ecdsa.PrivateKey) string {
+		der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		util.AssertNoError(t, err)
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	}
+
+	tests := []struct {
+		name      string
+		image     string
+		key       string
+		keys      []string
+		anonymous bool
+		valid     bool
+	}{
+		{"tag", host + "/tekton/controller:v1", publicKey(key), nil, false, true},
+		{"digest", host + "/tekton/controller@sha256:signed", publicKey(key), nil, false, true},
+		{"unsigned digest", host + "/tekton/controller@sha256:unsigned", publicKey(key), nil, false, false},
+		{"other key", host + "/tekton/controller:v1", publicKey(other), nil, false, false},
+		{"any of the keys", host + "/tekton/controller:v1", publicKey(other), []string{publicKey(key)}, false, true},
+		{"keys only", host + "/tekton/controller:v1", "", []string{publicKey(other), publicKey(key)}, false, true},
+		{"no key", host + "/tekton/controller:v1", "", nil, false, false},
+		{"invalid key", host + "/tekton/controller:v1", "cosign.pub", nil, false, false},
+		{"without credentials", host + "/tekton/controller:v1", publicKey(key), nil, true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			verifiedImages = sync.Map{}
+			manifest := manifestWith(test.image)
+			instance := &v1alpha1.TektonPipeline{}
+			instance.Status.InitializeConditions()
+			instance.Spec.SignatureVerification = &v1alpha1.SignatureVerification{CosignPublicKey: test.key, CosignPublicKeys: test.keys}
+			instance.Spec.TargetNamespace = "tekton-pipelines"
+			if !test.anonymous {
+				instance.Spec.Registry.ImagePullSecrets = []string{"registry"}
+			}
+
+			err := VerifyImageSignatures(context.Background(), &manifest, instance)
+			if test.valid {
+				util.AssertNoError(t, err)
+				return
+			}
+			if err == nil {
+				t.Fatal("VerifyImageSignatures() = nil, wanted an error")
+			}
+			condition := instance.Status.GetCondition(v1alpha1.InstallSucceeded)
+			util.AssertEqual(t, condition.Status, corev1.ConditionFalse)
+		})
+	}
+
+	// Verification is opt-in
+	manifest := manifestWith("unsigned/image:v1")
+	util.AssertNoError(t, VerifyImageSignatures(context.Background(), &manifest, &v1alpha1.TektonPipeline{}))
+}
+
+func TestVerifyImageSignaturesCondition(t *testing.T) {
+	defer func(resolve func(context.Context, *registryAccess, string) (string, error)) { resolveDigest = resolve }(resolveDigest)
+	resolveDigest = func(context.Context, *registryAccess, string) (string, error) { return "", fmt.Errorf("not found") }
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	util.AssertNoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	util.AssertNoError(t, err)
+
+	deployment := util.MakeUnstructured(t, util.MakeDeployment("controller", corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "controller", Image: "gcr.io/controller:v1"}},
+	}))
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment}))
+	util.AssertNoError(t, err)
+	instance := &v1alpha1.TektonPipeline{}
+	instance.Status.InitializeConditions()
+	instance.Spec.SignatureVerification = &v1alpha1.SignatureVerification{
+		CosignPublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}
+
+	if err := VerifyImageSignatures(context.Background(), &manifest, instance); err == nil {
+		t.Fatal("VerifyImageSignatures() = nil, wanted an error")
+	}
+	util.AssertDeepEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).Message,
+		"Install failed with message: Signature verification of image gcr.io/controller:v1 failed: not found")
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).Reason, v1alpha1.ReasonSignatureVerificationFailed)
+	util.AssertEqual(t, instance.Status.GetCondition(apis.ConditionReady).IsTrue(), false)
+
+	// every image failing is reported
+	webhook := util.MakeUnstructured(t, util.MakeDeployment("webhook", corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "webhook", Image: "gcr.io/webhook:v1"}},
+	}))
+	manifest, err = mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment, webhook}))
+	util.AssertNoError(t, err)
+	if err := VerifyImageSignatures(context.Background(), &manifest, instance); err == nil {
+		t.Fatal("VerifyImageSignatures() = nil, wanted an error")
+	}
+	util.AssertDeepEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).Message,
+		"Install failed with message: Signature verification of 2 images failed: gcr.io/controller:v1: not found; gcr.io/webhook:v1: not found")
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// maxResourceSize is the size of the largest request etcd accepts by
+	// default, which bounds the size of a single resource.
+	maxResourceSize = 3 * 1024 * 1024 / 2
+	// defaultResourceSizeWarning is the default size above which a payload
+	// resource is reported, leaving room for the fields the API server adds.
+	defaultResourceSizeWarning = 1024 * 1024
+
+	// ResourceTooLargeReason is the reason of the warning events recorded
+	// for payload resources above the size warning.
+	ResourceTooLargeReason = v1alpha1.ReasonResourceTooLarge
+)
+
+// reportedSizes holds the large resources last reported for each
+// component with some, by UID, for them to be reported again only once they
+// change
+var reportedSizes sync.Map
+
+// CheckResourceSizes is a Stage checking the size every resource of the
+// transformed manifest is stored with: its serialized size, doubled by the
+// last-applied-configuration annotation unless it is applied server side.
+// Resources above spec.config.resourceSizeWarning are reported in a warning
+// event, once until the large resources change, while resources etcd would
+// reject fail the install before anything is applied.
+func CheckResourceSizes(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	logger := logging.FromContext(ctx)
+	config := instance.GetSpec().GetConfig()
+	warning := int64(defaultResourceSizeWarning)
+	if q := config.ResourceSizeWarning; q != nil {
+		warning = q.Value()
+	}
+
+	var large, warnings, tooLarge []string
+	for _, u := range manifest.Resources() {
+		size, err := storedSize(&u, config.ServerSideApply)
+		if err != nil {
+			return err
+		}
+		if size <= warning && size <= maxResourceSize {
+			continue
+		}
+		name := fmt.Sprintf("%s %s", u.GetKind(), u.GetName())
+		large = append(large, fmt.Sprintf("%s (%d bytes)", name, size))
+		warnings = append(warnings, fmt.Sprintf("%s is %d bytes, close to or above the %d bytes etcd accepts", name, size, maxResourceSize))
+		if size > maxResourceSize {
+			tooLarge = append(tooLarge, fmt.Sprintf("%s (%d bytes)", name, size))
+		}
+	}
+
+	reported := strings.Join(large, ", ")
+	if reported == "" {
+		reportedSizes.Delete(instance.GetUID())
+	} else if previous, ok := reportedSizes.Load(instance.GetUID()); !ok || previous != reported {
+		reportedSizes.Store(instance.GetUID(), reported)
+		recorder := controller.GetEventRecorder(ctx)
+		for _, msg := range warnings {
+			logger.Warn(msg)
+			if object, ok := instance.(runtime.Object); ok && recorder != nil {
+				recorder.Event(object, corev1.EventTypeWarning, ResourceTooLargeReason, msg)
+			}
+		}
+	}
+	if len(tooLarge) != 0 {
+		err := &operrors.ResourceTooLarge{Err: fmt.Errorf("Payload resources exceed the %d bytes etcd accepts: %s", maxResourceSize, strings.Join(tooLarge, ", "))}
+		operrors.MarkFailed(instance.GetStatus(), err)
+		return err
+	}
+	return nil
+}
+
+// storedSize returns the size of the resource once applied: its serialized
+// size, with the last-applied-configuration annotation a client side apply
+// adds, holding the resource serialized again.
+func storedSize(u *unstructured.Unstructured, serverSide bool) (int64, error) {
+	data, err := json.Marshal(u.Object)
+	if err != nil || serverSide {
+		return int64(len(data)), err
+	}
+	applied := u.DeepCopy()
+	annotations := applied.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[corev1.LastAppliedConfigAnnotation] = string(data)
+	applied.SetAnnotations(annotations)
+	data, err = json.Marshal(applied.Object)
+	return int64(len(data)), err
+}
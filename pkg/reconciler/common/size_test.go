@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/controller"
+)
+
+func clusterTaskOfSize(name string, size int) unstructured.Unstructured {
+	task := clusterScopedResource("tekton.dev/v1beta1", "ClusterTask", name)
+	task.Object["spec"] = map[string]interface{}{"description": strings.Repeat("x", size)}
+	return task
+}
+
+func TestCheckResourceSizes(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	ctx := controller.WithEventRecorder(context.Background(), recorder)
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
+		clusterTaskOfSize("small", 1024),
+		clusterTaskOfSize("large", 400*1024),
+	}))
+	util.AssertNoError(t, err)
+
+	instance := &v1alpha1.TektonAddon{}
+	instance.Status.InitializeConditions()
+	util.AssertNoError(t, CheckResourceSizes(ctx, &manifest, instance))
+	util.AssertEqual(t, len(recorder.Events), 0)
+
+	threshold := resource.MustParse("256Ki")
+	instance.Spec.Config.ResourceSizeWarning = &threshold
+	util.AssertNoError(t, CheckResourceSizes(ctx, &manifest, instance))
+	util.AssertEqual(t, len(recorder.Events), 1)
+	event := <-recorder.Events
+	if !strings.HasPrefix(event, "Warning ResourceTooLarge ClusterTask large is ") {
+		t.Errorf("event = %q, wanted a warning for the large ClusterTask", event)
+	}
+	// the last-applied-configuration annotation doubles the size
+	if size, _ := strconv.Atoi(strings.Fields(event)[5]); size < 800*1024 {
+		t.Errorf("event = %q, wanted the size with the last-applied-configuration annotation", event)
+	}
+	// the same resources are reported once
+	util.AssertNoError(t, CheckResourceSizes(ctx, &manifest, instance))
+	util.AssertEqual(t, len(recorder.Events), 0)
+	// applied server side, they're below the warning
+	instance.Spec.Config.ServerSideApply = true
+	threshold = resource.MustParse("512Ki")
+	util.AssertNoError(t, CheckResourceSizes(ctx, &manifest, instance))
+	util.AssertEqual(t, len(recorder.Events), 0)
+	instance.Spec.Config.ServerSideApply = false
+
+	// beyond the etcd limit, nothing is applied
+	manifest, err = mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{clusterTaskOfSize("huge", 2*1024*1024)}))
+	util.AssertNoError(t, err)
+	err = CheckResourceSizes(ctx, &manifest, instance)
+	if err == nil || !strings.Contains(err.Error(), "ClusterTask huge") {
+		t.Errorf("CheckResourceSizes() = %v, wanted an error for the huge ClusterTask", err)
+	}
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).IsFalse(), true)
+}
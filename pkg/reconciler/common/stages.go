@@ -18,9 +18,19 @@ package common
 
 import (
 	"context"
+	"reflect"
+	"sync"
 
 	mf "github.com/manifestival/manifestival"
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/logging"
 )
 
@@ -30,13 +40,210 @@ type Stage func(context.Context, *mf.Manifest, v1alpha1.TektonComponent) error
 // Stages are a list of steps
 type Stages []Stage
 
-// Execute each stage in sequence until one returns an error
+// Execute each stage in sequence until one returns an error. If the
+// context carries a correlation ID, the resources written are annotated
+// with it and it is added to the error. If it carries the latest
+// generation of the component, the execution stops with ErrSuperseded
+// before a stage once the spec changed.
 func (stages Stages) Execute(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+	withCorrelatedClient(ctx, manifest)
 	for _, stage := range stages {
+		if superseded(ctx, instance) {
+			logging.FromContext(ctx).Infow("Spec changed during the reconcile, restarting", "generation", instance.GetGeneration())
+			return correlate(ctx, ErrSuperseded)
+		}
 		if err := stage(ctx, manifest, instance); err != nil {
+			return correlate(ctx, err)
+		}
+	}
+	return nil
+}
+
+// Parallel returns a Stage executing the branches concurrently, the stages
+// of each in sequence until one returns an error, e.g. to reconcile child
+// components which don't depend on one another. Each branch works on a
+// copy of the manifest, the changes of which are discarded, and on a copy
+// of the instance, the changes of which to the status are merged into the
+// instance once all the branches returned, see mergeStatus. The errors of
+// the branches are aggregated.
+func Parallel(branches ...Stages) Stage {
+	return func(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
+		errs := make([]error, len(branches))
+		copies := make([]v1alpha1.TektonComponent, len(branches))
+		var wg sync.WaitGroup
+		for i, branch := range branches {
+			copies[i] = instance.(runtime.Object).DeepCopyObject().(v1alpha1.TektonComponent)
+			wg.Add(1)
+			go func(i int, branch Stages, manifest mf.Manifest) {
+				defer wg.Done()
+				for _, stage := range branch {
+					if errs[i] = stage(ctx, &manifest, copies[i]); errs[i] != nil {
+						return
+					}
+				}
+			}(i, branch, manifest.Append())
+		}
+		wg.Wait()
+		if err := mergeStatus(instance, copies); err != nil {
+			errs = append(errs, err)
+		}
+		return utilerrors.NewAggregate(errs)
+	}
+}
+
+// mergeStatus merges the changes the copies made to the status of the
+// instance into it, in the order of the copies: the conditions changed,
+// type by type, the worse of two changes of a condition winning, e.g. a
+// failure over a success, and the other fields changed, field by field.
+func mergeStatus(instance v1alpha1.TektonComponent, copies []v1alpha1.TektonComponent) error {
+	original, err := statusOf(instance)
+	if err != nil {
+		return err
+	}
+	merged, err := statusOf(instance)
+	if err != nil {
+		return err
+	}
+	// the types of the conditions changed by a copy already
+	changed := sets.NewString()
+	for _, c := range copies {
+		status, err := statusOf(c)
+		if err != nil {
 			return err
 		}
+		for field, value := range status {
+			if field != "conditions" && !equality.Semantic.DeepEqual(value, original[field]) {
+				merged[field] = value
+			}
+		}
+		for field := range original {
+			if _, ok := status[field]; !ok && field != "conditions" {
+				delete(merged, field)
+			}
+		}
+		conditions, _, _ := unstructured.NestedSlice(status, "conditions")
+		for _, condition := range conditions {
+			if !hasCondition(original, condition) {
+				setCondition(merged, condition, changed)
+			}
+		}
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(instance)
+	if err != nil {
+		return err
+	}
+	obj["status"] = merged
+	result := instance.(runtime.Object).DeepCopyObject()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, result); err != nil {
+		return err
+	}
+	reflect.ValueOf(instance).Elem().Set(reflect.ValueOf(result).Elem())
+	return nil
+}
+
+// statusOf returns the status of the component as an unstructured map
+func statusOf(instance v1alpha1.TektonComponent) (map[string]interface{}, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(instance)
+	if err != nil {
+		return nil, err
+	}
+	status, _, err := unstructured.NestedMap(obj, "status")
+	if status == nil {
+		status = map[string]interface{}{}
+	}
+	return status, err
+}
+
+// hasCondition returns true if the status holds the condition as it is
+func hasCondition(status map[string]interface{}, condition interface{}) bool {
+	conditions, _, _ := unstructured.NestedSlice(status, "conditions")
+	for _, c := range conditions {
+		if equality.Semantic.DeepEqual(c, condition) {
+			return true
+		}
+	}
+	return false
+}
+
+// setCondition sets the condition in the status, replacing the one of the
+// same type unless another copy changed it already to a worse status
+func setCondition(status map[string]interface{}, condition interface{}, changed sets.String) {
+	conditions, _, _ := unstructured.NestedSlice(status, "conditions")
+	set := condition.(map[string]interface{})
+	conditionType, _ := set["type"].(string)
+	defer changed.Insert(conditionType)
+	for i, c := range conditions {
+		current := c.(map[string]interface{})
+		if current["type"] != conditionType {
+			continue
+		}
+		if !changed.Has(conditionType) || severity(set) >= severity(current) {
+			conditions[i] = condition
+			status["conditions"] = conditions
+		}
+		return
+	}
+	status["conditions"] = append(conditions, condition)
+}
+
+// severity ranks the status of a condition, a failure above a condition
+// unknown yet above a success
+func severity(condition map[string]interface{}) int {
+	switch condition["status"] {
+	case string(corev1.ConditionFalse):
+		return 2
+	case string(corev1.ConditionUnknown):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// LabelGenerated marks the resources the operator generates for the payload
+// deployments, e.g. NetworkPolicies
+const LabelGenerated = "operator.tekton.dev/generated"
+
+// appendGenerated appends the resources generated for the deployments of
+// the manifest, nil when there is nothing to generate, once they went
+// through the common transformers like the rest of the payload.
+func appendGenerated(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent, generate func(*appsv1.Deployment) runtime.Object) error {
+	var resources []unstructured.Unstructured
+	for _, u := range manifest.Filter(mf.ByKind("Deployment")).Resources() {
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, deployment); err != nil {
+			return err
+		}
+		obj := generate(deployment)
+		if obj == nil {
+			continue
+		}
+		resource, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		u := unstructured.Unstructured{Object: resource}
+		labels := u.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[LabelGenerated] = "true"
+		u.SetLabels(labels)
+		resources = append(resources, u)
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+	generated, err := mf.ManifestFrom(mf.Slice(resources))
+	if err != nil {
+		return err
+	}
+	if generated, err = generated.Transform(transformers(ctx, instance, InjectNamespace)...); err != nil {
+		err = &operrors.TransformError{Err: err}
+		operrors.MarkFailed(instance.GetStatus(), err)
+		reportError(ctx, transformErrorCountStat, instance.GetName())
+		return err
 	}
+	*manifest = manifest.Append(generated)
 	return nil
 }
 
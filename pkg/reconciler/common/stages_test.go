@@ -18,7 +18,9 @@ package common
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"sync"
 	"testing"
 
 	mf "github.com/manifestival/manifestival"
@@ -40,6 +42,62 @@ func TestStagesExecute(t *testing.T) {
 	util.AssertEqual(t, len(manifest.Resources()), 4)
 }
 
+func TestParallel(t *testing.T) {
+	manifest, _ := mf.ManifestFrom(mf.Slice{})
+	// the branches block until all of them started
+	var started sync.WaitGroup
+	started.Add(3)
+	wait := func(context.Context, *mf.Manifest, v1alpha1.TektonComponent) error {
+		started.Done()
+		started.Wait()
+		return nil
+	}
+	fail := func(msg string) Stage {
+		return func(context.Context, *mf.Manifest, v1alpha1.TektonComponent) error {
+			return fmt.Errorf("%s", msg)
+		}
+	}
+	var ran bool
+	after := func(context.Context, *mf.Manifest, v1alpha1.TektonComponent) error {
+		ran = true
+		return nil
+	}
+	stage := Parallel(Stages{wait, fail("pipeline"), after}, Stages{wait}, Stages{wait, fail("trigger")})
+	err := stage(context.TODO(), &manifest, &v1alpha1.TektonConfig{})
+	util.AssertEqual(t, err.Error(), "[pipeline, trigger]")
+	// a branch stops at its first error
+	util.AssertEqual(t, ran, false)
+
+	stage = Parallel(Stages{after}, Stages{})
+	util.AssertNoError(t, stage(context.TODO(), &manifest, &v1alpha1.TektonConfig{}))
+	util.AssertEqual(t, ran, true)
+}
+
+func TestParallelStatus(t *testing.T) {
+	manifest, _ := mf.ManifestFrom(mf.Slice{})
+	fail := func(_ context.Context, _ *mf.Manifest, instance v1alpha1.TektonComponent) error {
+		instance.GetStatus().MarkInstallFailed("boom")
+		return nil
+	}
+	succeed := func(_ context.Context, _ *mf.Manifest, instance v1alpha1.TektonComponent) error {
+		instance.GetStatus().MarkInstallSucceeded()
+		instance.GetStatus().SetVersion("v0.19.0")
+		return nil
+	}
+	missing := func(_ context.Context, _ *mf.Manifest, instance v1alpha1.TektonComponent) error {
+		instance.GetStatus().MarkDependencyMissing("tekton-pipelines does not exist")
+		return nil
+	}
+	instance := &v1alpha1.TektonConfig{}
+	instance.Status.InitializeConditions()
+	util.AssertNoError(t, Parallel(Stages{fail}, Stages{succeed}, Stages{missing})(context.TODO(), &manifest, instance))
+	// the changes of all the branches are kept, the failure winning over
+	// the success
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).IsFalse(), true)
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.DependenciesInstalled).IsFalse(), true)
+	util.AssertEqual(t, instance.Status.GetVersion(), "v0.19.0")
+}
+
 func TestDeleteObsoleteResources(t *testing.T) {
 	os.Setenv(KoEnvKey, "testdata/kodata")
 	defer os.Unsetenv(KoEnvKey)
@@ -0,0 +1,231 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/metrics"
+	"knative.dev/pkg/reconciler"
+)
+
+// The leadership transitions of a replica of the operator
+const (
+	leadershipAcquired = "acquired"
+	leadershipLost     = "lost"
+)
+
+var (
+	leaderStat           = stats.Int64("operator_leader", "Whether the replica leads the operator", stats.UnitDimensionless)
+	transitionCountStat  = stats.Int64("operator_leadership_transition_count", "Number of leadership transitions of the replica", stats.UnitDimensionless)
+	handoffLatencyStat   = stats.Float64("operator_leadership_handoff_latency", "Time from acquiring the Lease to the controllers reconciling", stats.UnitMilliseconds)
+	cacheWarmLatencyStat = stats.Float64("operator_cache_warm_latency", "Time from the start of the replica to its caches being synced", stats.UnitMilliseconds)
+
+	transitionTagKey = tag.MustNewKey("transition")
+)
+
+func init() {
+	if err := view.Register(&view.View{
+		Description: "Whether the replica leads the operator, running its controllers",
+		Measure:     leaderStat,
+		Aggregation: view.LastValue(),
+	}, &view.View{
+		Description: "Number of leadership transitions of the replica, by transition",
+		Measure:     transitionCountStat,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{transitionTagKey},
+	}, &view.View{
+		Description: "Time from acquiring the Lease to the controllers reconciling",
+		Measure:     handoffLatencyStat,
+		Aggregation: view.Distribution(1, 10, 100, 1000, 10000, 60000, 300000),
+	}, &view.View{
+		Description: "Time from the start of the replica to its caches being synced",
+		Measure:     cacheWarmLatencyStat,
+		Aggregation: view.Distribution(1000, 5000, 10000, 30000, 60000, 300000, 600000),
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// Standby keeps the controllers of a replica of the operator from
+// reconciling until the replica leads the operator, while their informers
+// keep the caches warm, so that a replica taking over from a failed leader
+// reconciles right away instead of listing the whole cluster first.
+type Standby struct {
+	start time.Time
+	now   func() time.Time
+
+	mu      sync.Mutex
+	wrapped int
+	// the reconcilers the controllers promoted, once their caches synced
+	standing []*standbyReconciler
+	ready    chan struct{}
+	leading  bool
+}
+
+// NewStandby returns a Standby of the controllers of a replica starting
+// now.
+func NewStandby() *Standby {
+	metrics.Record(context.Background(), leaderStat.M(0))
+	return &Standby{start: time.Now(), now: time.Now, ready: make(chan struct{})}
+}
+
+// Controllers returns the controller constructors keeping the leader-aware
+// reconcilers of their controllers on standby. Reconcilers that aren't
+// leader-aware reconcile on every replica, so all the controllers of the
+// operator implement reconciler.LeaderAware.
+func (s *Standby) Controllers(ctors ...injection.ControllerConstructor) []injection.ControllerConstructor {
+	wrapped := make([]injection.ControllerConstructor, 0, len(ctors))
+	for _, ctor := range ctors {
+		ctor := ctor
+		wrapped = append(wrapped, func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+			impl := ctor(ctx, cmw)
+			impl.Reconciler = s.wrap(impl.Reconciler)
+			return impl
+		})
+	}
+	return wrapped
+}
+
+func (s *Standby) wrap(r controller.Reconciler) controller.Reconciler {
+	la, ok := r.(reconciler.LeaderAware)
+	if !ok {
+		return r
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.wrapped++
+	return &standbyReconciler{Reconciler: r, la: la, standby: s}
+}
+
+// Ready is closed once the caches of all the controllers synced, the
+// controllers promoting their reconcilers only then.
+func (s *Standby) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Lead promotes the reconcilers on standby, unless ctx, the leadership of
+// the replica, is already done.
+func (s *Standby) Lead(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ctx.Err() != nil || s.leading {
+		return
+	}
+	acquired := s.now()
+	s.leading = true
+	for _, r := range s.standing {
+		r.promote(ctx)
+	}
+	s.record(ctx, leadershipAcquired)
+	metrics.Record(ctx, handoffLatencyStat.M(float64(s.now().Sub(acquired))/float64(time.Millisecond)))
+}
+
+// StandBy demotes the reconcilers once the replica lost the leadership.
+func (s *Standby) StandBy(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.leading {
+		return
+	}
+	s.leading = false
+	for _, r := range s.standing {
+		r.demote()
+	}
+	s.record(ctx, leadershipLost)
+}
+
+func (s *Standby) record(ctx context.Context, transition string) {
+	leading := int64(0)
+	if s.leading {
+		leading = 1
+	}
+	metrics.Record(ctx, leaderStat.M(leading))
+	if ctx, err := tag.New(ctx, tag.Insert(transitionTagKey, transition)); err == nil {
+		metrics.Record(ctx, transitionCountStat.M(1))
+	}
+}
+
+// register keeps a reconciler promoted by its controller on standby, or
+// promotes it right away when the replica leads.
+func (s *Standby) register(r *standbyReconciler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.standing = append(s.standing, r)
+	if s.leading {
+		r.promote(context.Background())
+	}
+	if len(s.standing) == s.wrapped {
+		metrics.Record(context.Background(), cacheWarmLatencyStat.M(float64(s.now().Sub(s.start))/float64(time.Millisecond)))
+		close(s.ready)
+	}
+}
+
+// standbyReconciler defers the promotion of the reconciler it wraps to
+// its bucket until the replica leads the operator.
+type standbyReconciler struct {
+	controller.Reconciler
+	la      reconciler.LeaderAware
+	standby *Standby
+
+	bucket   reconciler.Bucket
+	enq      func(reconciler.Bucket, types.NamespacedName)
+	promoted bool
+}
+
+// Promote implements reconciler.LeaderAware.
+func (r *standbyReconciler) Promote(b reconciler.Bucket, enq func(reconciler.Bucket, types.NamespacedName)) error {
+	r.bucket, r.enq = b, enq
+	r.standby.register(r)
+	return nil
+}
+
+// Demote implements reconciler.LeaderAware.
+func (r *standbyReconciler) Demote(b reconciler.Bucket) {
+	r.standby.mu.Lock()
+	defer r.standby.mu.Unlock()
+	r.demote()
+}
+
+func (r *standbyReconciler) promote(ctx context.Context) {
+	if r.promoted {
+		return
+	}
+	if err := r.la.Promote(r.bucket, r.enq); err != nil {
+		logging.FromContext(ctx).Errorw("Failed to promote a reconciler", "bucket", r.bucket.Name(), "error", err)
+		return
+	}
+	r.promoted = true
+}
+
+func (r *standbyReconciler) demote() {
+	if !r.promoted {
+		return
+	}
+	r.la.Demote(r.bucket)
+	r.promoted = false
+}
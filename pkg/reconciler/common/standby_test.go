@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/reconciler"
+)
+
+func TestStandby(t *testing.T) {
+	standby := NewStandby()
+	inners := []*fakeReconciler{{}, {}}
+	var wrapped []reconciler.LeaderAware
+	for _, inner := range inners {
+		wrapped = append(wrapped, standby.wrap(inner).(reconciler.LeaderAware))
+	}
+	key := types.NamespacedName{Name: "pipeline"}
+	leaders := func() []bool {
+		return []bool{inners[0].IsLeaderFor(key), inners[1].IsLeaderFor(key)}
+	}
+	ready := func() bool {
+		select {
+		case <-standby.Ready():
+			return true
+		default:
+			return false
+		}
+	}
+	enq := func(reconciler.Bucket, types.NamespacedName) {}
+
+	// the controllers promote their reconcilers once their caches synced,
+	// which stay on standby
+	util.AssertNoError(t, wrapped[0].Promote(reconciler.UniversalBucket(), enq))
+	util.AssertEqual(t, ready(), false)
+	util.AssertNoError(t, wrapped[1].Promote(reconciler.UniversalBucket(), enq))
+	util.AssertEqual(t, ready(), true)
+	util.AssertDeepEqual(t, leaders(), []bool{false, false})
+
+	standby.Lead(context.Background())
+	util.AssertDeepEqual(t, leaders(), []bool{true, true})
+
+	standby.StandBy(context.Background())
+	util.AssertDeepEqual(t, leaders(), []bool{false, false})
+
+	// a leadership lost before the replica led promotes nothing
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	standby.Lead(ctx)
+	util.AssertDeepEqual(t, leaders(), []bool{false, false})
+
+	// a controller demoting its reconciler on shutdown
+	standby.Lead(context.Background())
+	wrapped[0].Demote(reconciler.UniversalBucket())
+	util.AssertDeepEqual(t, leaders(), []bool{false, true})
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+)
+
+// ComponentOption sets up a component made by MakeComponent
+type ComponentOption func(v1alpha1.TektonComponent, *v1alpha1.CommonSpec)
+
+// WithTargetNamespace sets the namespace the component installs its payload in
+func WithTargetNamespace(namespace string) ComponentOption {
+	return func(_ v1alpha1.TektonComponent, spec *v1alpha1.CommonSpec) {
+		spec.TargetNamespace = namespace
+	}
+}
+
+// WithCommonSpec sets up the spec the components of every kind share
+func WithCommonSpec(f func(*v1alpha1.CommonSpec)) ComponentOption {
+	return func(_ v1alpha1.TektonComponent, spec *v1alpha1.CommonSpec) {
+		f(spec)
+	}
+}
+
+// WithVersion records the version of the payload as installed already
+func WithVersion(version string) ComponentOption {
+	return func(comp v1alpha1.TektonComponent, _ *v1alpha1.CommonSpec) {
+		comp.GetStatus().SetVersion(version)
+	}
+}
+
+// WithGeneration sets the generation of the spec of the component
+func WithGeneration(generation int64) ComponentOption {
+	return func(comp v1alpha1.TektonComponent, _ *v1alpha1.CommonSpec) {
+		comp.SetGeneration(generation)
+	}
+}
+
+// MakeComponent makes a component of the given kind, e.g.
+// v1alpha1.KindTektonPipeline, with its conditions initialized as the
+// reconcilers hand it to the stages, transformers and extensions. The
+// component is of the concrete type of its kind, so that it can be
+// asserted to it.
+func MakeComponent(t *testing.T, kind, name string, opts ...ComponentOption) v1alpha1.TektonComponent {
+	t.Helper()
+	var comp v1alpha1.TektonComponent
+	var spec *v1alpha1.CommonSpec
+	switch kind {
+	case v1alpha1.KindTektonPipeline:
+		c := &v1alpha1.TektonPipeline{}
+		comp, spec = c, &c.Spec.CommonSpec
+	case v1alpha1.KindTektonTrigger:
+		c := &v1alpha1.TektonTrigger{}
+		comp, spec = c, &c.Spec.CommonSpec
+	case v1alpha1.KindTektonDashboard:
+		c := &v1alpha1.TektonDashboard{}
+		comp, spec = c, &c.Spec.CommonSpec
+	case v1alpha1.KindTektonAddon:
+		c := &v1alpha1.TektonAddon{}
+		comp, spec = c, &c.Spec.CommonSpec
+	case v1alpha1.KindTektonConfig:
+		c := &v1alpha1.TektonConfig{}
+		comp, spec = c, &c.Spec.CommonSpec
+	case v1alpha1.KindTektonResult:
+		c := &v1alpha1.TektonResult{}
+		comp, spec = c, &c.Spec.CommonSpec
+	case v1alpha1.KindTektonChain:
+		c := &v1alpha1.TektonChain{}
+		comp, spec = c, &c.Spec.CommonSpec
+	case v1alpha1.KindTektonHub:
+		c := &v1alpha1.TektonHub{}
+		comp, spec = c, &c.Spec.CommonSpec
+	case v1alpha1.KindOpenShiftPipelinesAsCode:
+		c := &v1alpha1.OpenShiftPipelinesAsCode{}
+		comp, spec = c, &c.Spec.CommonSpec
+	case v1alpha1.KindManualApprovalGate:
+		c := &v1alpha1.ManualApprovalGate{}
+		comp, spec = c, &c.Spec.CommonSpec
+	default:
+		t.Fatalf("Could not make component of unknown kind %q", kind)
+	}
+	comp.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind(kind))
+	comp.SetName(name)
+	comp.SetGeneration(1)
+	comp.GetStatus().InitializeConditions()
+	for _, opt := range opts {
+		opt(comp, spec)
+	}
+	return comp
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+)
+
+func TestMakeComponent(t *testing.T) {
+	comp := MakeComponent(t, v1alpha1.KindTektonPipeline, "pipeline",
+		WithTargetNamespace("tekton-pipelines"),
+		WithCommonSpec(func(spec *v1alpha1.CommonSpec) { spec.AllowDowngrade = true }),
+		WithVersion("v0.22.0"),
+		WithGeneration(3))
+	pipeline, ok := comp.(*v1alpha1.TektonPipeline)
+	AssertEqual(t, ok, true)
+	AssertEqual(t, pipeline.Kind, v1alpha1.KindTektonPipeline)
+	AssertEqual(t, pipeline.APIVersion, v1alpha1.SchemeGroupVersion.String())
+	AssertEqual(t, pipeline.Name, "pipeline")
+	AssertEqual(t, pipeline.Generation, int64(3))
+	AssertEqual(t, pipeline.Spec.TargetNamespace, "tekton-pipelines")
+	AssertEqual(t, pipeline.Spec.AllowDowngrade, true)
+	AssertEqual(t, pipeline.Status.GetVersion(), "v0.22.0")
+	AssertEqual(t, len(pipeline.Status.Conditions) > 0, true)
+
+	for _, kind := range []string{
+		v1alpha1.KindTektonTrigger, v1alpha1.KindTektonDashboard, v1alpha1.KindTektonAddon,
+		v1alpha1.KindTektonConfig, v1alpha1.KindTektonResult, v1alpha1.KindTektonChain,
+		v1alpha1.KindTektonHub, v1alpha1.KindOpenShiftPipelinesAsCode, v1alpha1.KindManualApprovalGate,
+	} {
+		comp := MakeComponent(t, kind, "component", WithTargetNamespace("target"))
+		AssertEqual(t, comp.GroupVersionKind().Kind, kind)
+		AssertEqual(t, comp.GetSpec().GetTargetNamespace(), "target")
+	}
+}
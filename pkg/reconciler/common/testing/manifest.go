@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MakeManifest makes a manifest of the given objects, typed or
+// unstructured, applied through a FakeClient holding none of them yet
+func MakeManifest(t *testing.T, objs ...interface{}) (mf.Manifest, *FakeClient) {
+	t.Helper()
+	resources := make([]unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		switch u := obj.(type) {
+		case unstructured.Unstructured:
+			resources = append(resources, u)
+		case *unstructured.Unstructured:
+			resources = append(resources, *u)
+		default:
+			resources = append(resources, MakeUnstructured(t, obj))
+		}
+	}
+	client := NewFakeClient()
+	manifest, err := mf.ManifestFrom(mf.Slice(resources), mf.UseClient(client))
+	if err != nil {
+		t.Fatalf("Could not create manifest: %v", err)
+	}
+	return manifest, client
+}
+
+// Transform transforms the manifest with the given transformers,
+// failing the test on any error
+func Transform(t *testing.T, manifest mf.Manifest, transformers ...mf.Transformer) mf.Manifest {
+	t.Helper()
+	result, err := manifest.Transform(transformers...)
+	if err != nil {
+		t.Fatalf("Could not transform manifest: %v", err)
+	}
+	return result
+}
+
+// FindResource gets the resource of the manifest of the given kind and
+// name, failing the test when there is none
+func FindResource(t *testing.T, manifest mf.Manifest, kind, name string) *unstructured.Unstructured {
+	t.Helper()
+	resources := manifest.Filter(mf.ByKind(kind), mf.ByName(name)).Resources()
+	if len(resources) == 0 {
+		t.Fatalf("Could not find %s %q in manifest", kind, name)
+	}
+	return &resources[0]
+}
+
+// FromUnstructured converts the resource into the typed object obj,
+// e.g. a Deployment, failing the test when it can't
+func FromUnstructured(t *testing.T, u *unstructured.Unstructured, obj interface{}) {
+	t.Helper()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		t.Fatalf("Could not convert %s %q: %v", u.GetKind(), u.GetName(), err)
+	}
+}
+
+// FakeClient is a manifestival Client keeping the resources in memory,
+// and recording the resources created, updated and deleted through it.
+// Its zero value holds no resources and is ready to use.
+type FakeClient struct {
+	// Err fails every call of the client when set
+	Err error
+
+	Creates []unstructured.Unstructured
+	Updates []unstructured.Unstructured
+	Deletes []unstructured.Unstructured
+
+	resources map[fakeKey]*unstructured.Unstructured
+}
+
+type fakeKey struct {
+	gvk             schema.GroupVersionKind
+	namespace, name string
+}
+
+var _ mf.Client = (*FakeClient)(nil)
+
+// NewFakeClient makes a FakeClient holding the given resources
+func NewFakeClient(resources ...unstructured.Unstructured) *FakeClient {
+	client := &FakeClient{resources: map[fakeKey]*unstructured.Unstructured{}}
+	for i := range resources {
+		client.resources[keyOf(&resources[i])] = resources[i].DeepCopy()
+	}
+	return client
+}
+
+func keyOf(obj *unstructured.Unstructured) fakeKey {
+	return fakeKey{obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName()}
+}
+
+// Get gets the resource held, or a NotFound error
+func (c *FakeClient) Get(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	live, ok := c.resources[keyOf(obj)]
+	if !ok {
+		gvk := obj.GroupVersionKind()
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, obj.GetName())
+	}
+	return live.DeepCopy(), nil
+}
+
+// Create holds a copy of the resource, failing with AlreadyExists if one
+// is held already
+func (c *FakeClient) Create(obj *unstructured.Unstructured, options ...mf.ApplyOption) error {
+	if c.Err != nil {
+		return c.Err
+	}
+	if _, ok := c.resources[keyOf(obj)]; ok {
+		gvk := obj.GroupVersionKind()
+		return apierrors.NewAlreadyExists(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, obj.GetName())
+	}
+	c.Creates = append(c.Creates, *obj.DeepCopy())
+	c.hold(obj)
+	return nil
+}
+
+// Update replaces the resource held with a copy of the given one
+func (c *FakeClient) Update(obj *unstructured.Unstructured, options ...mf.ApplyOption) error {
+	if c.Err != nil {
+		return c.Err
+	}
+	c.Updates = append(c.Updates, *obj.DeepCopy())
+	c.hold(obj)
+	return nil
+}
+
+func (c *FakeClient) hold(obj *unstructured.Unstructured) {
+	if c.resources == nil {
+		c.resources = map[fakeKey]*unstructured.Unstructured{}
+	}
+	c.resources[keyOf(obj)] = obj.DeepCopy()
+}
+
+// Delete drops the resource held, if any
+func (c *FakeClient) Delete(obj *unstructured.Unstructured, options ...mf.DeleteOption) error {
+	if c.Err != nil {
+		return c.Err
+	}
+	c.Deletes = append(c.Deletes, *obj.DeepCopy())
+	delete(c.resources, keyOf(obj))
+	return nil
+}
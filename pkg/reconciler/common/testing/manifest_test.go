@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestMakeManifest(t *testing.T) {
+	deployment := MakeDeployment("controller", corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "controller", Image: "gcr.io/controller:v1"}},
+	})
+	manifest, client := MakeManifest(t, deployment)
+	manifest = Transform(t, manifest, mf.InjectNamespace("tekton-pipelines"))
+
+	result := &appsv1.Deployment{}
+	FromUnstructured(t, FindResource(t, manifest, "Deployment", "controller"), result)
+	AssertEqual(t, result.Namespace, "tekton-pipelines")
+	AssertEqual(t, result.Spec.Template.Spec.Containers[0].Image, "gcr.io/controller:v1")
+
+	// applies create the resources first, and update them then
+	AssertNoError(t, manifest.Apply())
+	AssertEqual(t, len(client.Creates), 1)
+	live, err := client.Get(&manifest.Resources()[0])
+	AssertNoError(t, err)
+	AssertEqual(t, live.GetNamespace(), "tekton-pipelines")
+	manifest = Transform(t, manifest, func(u *unstructured.Unstructured) error {
+		u.SetLabels(map[string]string{"app": "controller"})
+		return nil
+	})
+	AssertNoError(t, manifest.Apply())
+	AssertEqual(t, len(client.Updates), 1)
+	AssertEqual(t, client.Updates[0].GetLabels()["app"], "controller")
+
+	AssertNoError(t, manifest.Delete())
+	AssertEqual(t, len(client.Deletes), 1)
+	_, err = client.Get(&manifest.Resources()[0])
+	AssertError(t, err)
+
+	client.Err = errors.New("unavailable")
+	AssertError(t, manifest.Apply())
+}
+
+func TestFakeClientZeroValue(t *testing.T) {
+	obj := MakeUnstructured(t, MakeDeployment("controller", corev1.PodSpec{}))
+
+	client := &FakeClient{}
+	_, err := client.Get(&obj)
+	AssertError(t, err)
+	AssertNoError(t, client.Delete(&obj))
+	AssertNoError(t, client.Create(&obj))
+	_, err = client.Get(&obj)
+	AssertNoError(t, err)
+
+	client = &FakeClient{}
+	AssertNoError(t, client.Update(&obj))
+	_, err = client.Get(&obj)
+	AssertNoError(t, err)
+
+	// applies through a zero client create the resources
+	client = &FakeClient{}
+	manifest, err := mf.ManifestFrom(mf.Slice{obj}, mf.UseClient(client))
+	AssertNoError(t, err)
+	AssertNoError(t, manifest.Apply())
+	AssertEqual(t, len(client.Creates), 1)
+}
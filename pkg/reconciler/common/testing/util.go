@@ -98,3 +98,11 @@ func AssertNoError(t *testing.T, err error) {
 		t.Fatalf("expected no error, error: %q", err)
 	}
 }
+
+// AssertError fails the test unless err is set
+func AssertError(t *testing.T, err error) {
+	t.Helper()
+	if err == nil {
+		t.Fatal("expected an error, got none")
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/reconciler"
+)
+
+const (
+	// ResyncPeriodEnvKey is the environment variable of the operator
+	// setting the resync period of its informers.
+	ResyncPeriodEnvKey = "RESYNC_PERIOD"
+	// RequeueBaseDelayEnvKey is the environment variable of the operator
+	// setting the delay before retrying a failed reconcile.
+	RequeueBaseDelayEnvKey = "REQUEUE_BASE_DELAY"
+	// RequeueMaxDelayEnvKey is the environment variable of the operator
+	// bounding the delay before retrying a reconcile failing again.
+	RequeueMaxDelayEnvKey = "REQUEUE_MAX_DELAY"
+
+	// the defaults are those of knative and of the client-go workqueue
+	defaultRequeueBaseDelay = 5 * time.Millisecond
+	defaultRequeueMaxDelay  = 1000 * time.Second
+)
+
+// Timings are the resync period of the informers of the operator and the
+// bounds of the exponential backoff of its failed reconciles.
+type Timings struct {
+	ResyncPeriod     time.Duration
+	RequeueBaseDelay time.Duration
+	RequeueMaxDelay  time.Duration
+}
+
+// TimingsFromEnv reads the Timings of the operator from its environment,
+// the variables which aren't set keeping the defaults.
+func TimingsFromEnv() (Timings, error) {
+	return timingsFrom(os.Getenv)
+}
+
+func timingsFrom(getenv func(string) string) (Timings, error) {
+	timings := Timings{
+		ResyncPeriod:     controller.DefaultResyncPeriod,
+		RequeueBaseDelay: defaultRequeueBaseDelay,
+		RequeueMaxDelay:  defaultRequeueMaxDelay,
+	}
+	if err := durationsFrom(getenv, map[string]*time.Duration{
+		ResyncPeriodEnvKey:     &timings.ResyncPeriod,
+		RequeueBaseDelayEnvKey: &timings.RequeueBaseDelay,
+		RequeueMaxDelayEnvKey:  &timings.RequeueMaxDelay,
+	}); err != nil {
+		return Timings{}, err
+	}
+	if timings.RequeueBaseDelay > timings.RequeueMaxDelay {
+		return Timings{}, fmt.Errorf("%s %s exceeds %s %s", RequeueBaseDelayEnvKey, timings.RequeueBaseDelay,
+			RequeueMaxDelayEnvKey, timings.RequeueMaxDelay)
+	}
+	return timings, nil
+}
+
+// durationsFrom sets the durations to the positive values of their
+// environment variables, keeping those which aren't set.
+func durationsFrom(getenv func(string) string, durations map[string]*time.Duration) error {
+	for key, duration := range durations {
+		value := getenv(key)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		if parsed <= 0 {
+			return fmt.Errorf("invalid %s: %s is not positive", key, value)
+		}
+		*duration = parsed
+	}
+	return nil
+}
+
+// Context attaches the resync period to ctx, for the informer factories
+// to resync with it.
+func (t Timings) Context(ctx context.Context) context.Context {
+	return controller.WithResyncPeriod(ctx, t.ResyncPeriod)
+}
+
+// Controllers returns the controller constructors retrying the failed
+// reconciles of their controllers with the backoff of t.
+func (t Timings) Controllers(ctors ...injection.ControllerConstructor) []injection.ControllerConstructor {
+	wrapped := make([]injection.ControllerConstructor, 0, len(ctors))
+	for _, ctor := range ctors {
+		ctor := ctor
+		wrapped = append(wrapped, func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+			impl := ctor(ctx, cmw)
+			impl.Reconciler = t.withBackoff(impl.Reconciler, impl.EnqueueKeyAfter)
+			return impl
+		})
+	}
+	return wrapped
+}
+
+// withBackoff wraps r to enqueue the keys of its transient failures again
+// after the backoff of t, failing permanently for the controller not to
+// retry them with its own. The wrapper stays leader-aware when r is, the
+// controller only reconciling the buckets it leads.
+func (t Timings) withBackoff(r controller.Reconciler, enqueueAfter func(types.NamespacedName, time.Duration)) controller.Reconciler {
+	backoff := &backoffReconciler{
+		Reconciler:   r,
+		limiter:      workqueue.NewItemExponentialFailureRateLimiter(t.RequeueBaseDelay, t.RequeueMaxDelay),
+		enqueueAfter: enqueueAfter,
+	}
+	if la, ok := r.(reconciler.LeaderAware); ok {
+		return &leaderAwareBackoffReconciler{backoffReconciler: backoff, LeaderAware: la}
+	}
+	return backoff
+}
+
+type backoffReconciler struct {
+	controller.Reconciler
+	limiter      workqueue.RateLimiter
+	enqueueAfter func(types.NamespacedName, time.Duration)
+}
+
+func (r *backoffReconciler) Reconcile(ctx context.Context, key string) error {
+	err := r.Reconciler.Reconcile(ctx, key)
+	if err == nil || controller.IsPermanentError(err) {
+		r.limiter.Forget(key)
+		return err
+	}
+	namespace, name, splitErr := cache.SplitMetaNamespaceKey(key)
+	if splitErr != nil {
+		return controller.NewPermanentError(err)
+	}
+	r.enqueueAfter(types.NamespacedName{Namespace: namespace, Name: name}, r.limiter.When(key))
+	return controller.NewPermanentError(err)
+}
+
+type leaderAwareBackoffReconciler struct {
+	*backoffReconciler
+	reconciler.LeaderAware
+}
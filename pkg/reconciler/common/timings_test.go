@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/reconciler"
+)
+
+func TestTimingsFrom(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	timings, err := timingsFrom(env(nil))
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, timings, Timings{
+		ResyncPeriod:     controller.DefaultResyncPeriod,
+		RequeueBaseDelay: defaultRequeueBaseDelay,
+		RequeueMaxDelay:  defaultRequeueMaxDelay,
+	})
+
+	timings, err = timingsFrom(env(map[string]string{
+		ResyncPeriodEnvKey:     "30m",
+		RequeueBaseDelayEnvKey: "1s",
+		RequeueMaxDelayEnvKey:  "5m",
+	}))
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, timings, Timings{
+		ResyncPeriod:     30 * time.Minute,
+		RequeueBaseDelay: time.Second,
+		RequeueMaxDelay:  5 * time.Minute,
+	})
+
+	for _, vars := range []map[string]string{
+		{ResyncPeriodEnvKey: "hourly"},
+		{RequeueBaseDelayEnvKey: "0s"},
+		{RequeueBaseDelayEnvKey: "10m", RequeueMaxDelayEnvKey: "1m"},
+	} {
+		if _, err := timingsFrom(env(vars)); err == nil {
+			t.Errorf("expected an error for %v", vars)
+		}
+	}
+}
+
+type fakeReconciler struct {
+	reconciler.LeaderAwareFuncs
+	errs []error
+}
+
+func (r *fakeReconciler) Reconcile(context.Context, string) error {
+	err := r.errs[0]
+	r.errs = r.errs[1:]
+	return err
+}
+
+func TestWithBackoff(t *testing.T) {
+	timings := Timings{RequeueBaseDelay: time.Second, RequeueMaxDelay: 3 * time.Second}
+	var delays []time.Duration
+	enqueueAfter := func(key types.NamespacedName, after time.Duration) {
+		util.AssertEqual(t, key, types.NamespacedName{Name: "pipeline"})
+		delays = append(delays, after)
+	}
+	transient := errors.New("connection refused")
+	inner := &fakeReconciler{errs: []error{
+		transient, transient, transient, nil,
+		transient, controller.NewPermanentError(transient), transient,
+	}}
+	r := timings.withBackoff(inner, enqueueAfter)
+	if _, ok := r.(reconciler.LeaderAware); !ok {
+		t.Error("expected the reconciler to stay leader-aware")
+	}
+
+	for i := 0; i < 7; i++ {
+		err := r.Reconcile(context.Background(), "pipeline")
+		if err != nil && !controller.IsPermanentError(err) {
+			t.Errorf("expected a permanent error, got %v", err)
+		}
+	}
+	// the backoff doubles up to its bound and restarts after a success or
+	// a permanent failure
+	util.AssertDeepEqual(t, delays, []time.Duration{
+		time.Second, 2 * time.Second, 3 * time.Second,
+		time.Second,
+		time.Second,
+	})
+}
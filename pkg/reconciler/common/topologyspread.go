@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const defaultTopologyKey = "topology.kubernetes.io/zone"
+
+// TopologySpreadConstraints adds a topologySpreadConstraint selecting the
+// pods of each payload Deployment to its pod template, replacing the one
+// the payload may ship for the same topology key. It is a no-op when
+// spread is nil.
+func TopologySpreadConstraints(spread *v1alpha1.TopologySpread) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if spread == nil || u.GetKind() != "Deployment" {
+			return nil
+		}
+		selector, found, err := unstructured.NestedFieldCopy(u.Object, "spec", "selector")
+		if err != nil || !found {
+			return err
+		}
+		constraint := map[string]interface{}{
+			"topologyKey":       defaultTopologyKey,
+			"maxSkew":           int64(1),
+			"whenUnsatisfiable": string(corev1.ScheduleAnyway),
+			"labelSelector":     selector,
+		}
+		if spread.TopologyKey != "" {
+			constraint["topologyKey"] = spread.TopologyKey
+		}
+		if spread.MaxSkew != 0 {
+			constraint["maxSkew"] = int64(spread.MaxSkew)
+		}
+		if spread.WhenUnsatisfiable != "" {
+			constraint["whenUnsatisfiable"] = string(spread.WhenUnsatisfiable)
+		}
+
+		current, _, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "topologySpreadConstraints")
+		if err != nil {
+			return err
+		}
+		constraints := []interface{}{}
+		for _, c := range current {
+			if c, ok := c.(map[string]interface{}); ok && c["topologyKey"] == constraint["topologyKey"] {
+				continue
+			}
+			constraints = append(constraints, c)
+		}
+		constraints = append(constraints, constraint)
+		return unstructured.SetNestedSlice(u.Object, constraints, "spec", "template", "spec", "topologySpreadConstraints")
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTopologySpreadConstraints(t *testing.T) {
+	d := util.MakeDeployment("controller", corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "controller", Image: "gcr.io/controller:v1"}},
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+			MaxSkew:           2,
+			TopologyKey:       "kubernetes.io/hostname",
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+		}, {
+			MaxSkew:           3,
+			TopologyKey:       "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+		}},
+	})
+	d.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "controller"}}
+	deployment := util.MakeUnstructured(t, d)
+	unchanged := deployment.DeepCopy()
+	util.AssertNoError(t, TopologySpreadConstraints(nil)(&deployment))
+	util.AssertDeepEqual(t, &deployment, unchanged)
+
+	// the constraint of the payload for the same key is replaced
+	util.AssertNoError(t, TopologySpreadConstraints(&v1alpha1.TopologySpread{})(&deployment))
+	constraints, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "topologySpreadConstraints")
+	util.AssertDeepEqual(t, constraints, []interface{}{
+		map[string]interface{}{
+			"maxSkew":           int64(2),
+			"topologyKey":       "kubernetes.io/hostname",
+			"whenUnsatisfiable": "DoNotSchedule",
+		},
+		map[string]interface{}{
+			"maxSkew":           int64(1),
+			"topologyKey":       "topology.kubernetes.io/zone",
+			"whenUnsatisfiable": "ScheduleAnyway",
+			"labelSelector":     map[string]interface{}{"matchLabels": map[string]interface{}{"app": "controller"}},
+		},
+	})
+
+	deployment = *unchanged.DeepCopy()
+	util.AssertNoError(t, TopologySpreadConstraints(&v1alpha1.TopologySpread{
+		TopologyKey:       "kubernetes.io/hostname",
+		MaxSkew:           1,
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+	})(&deployment))
+	constraints, _, _ = unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "topologySpreadConstraints")
+	util.AssertEqual(t, len(constraints), 2)
+	util.AssertDeepEqual(t, constraints[1].(map[string]interface{})["topologyKey"], "kubernetes.io/hostname")
+	util.AssertDeepEqual(t, constraints[1].(map[string]interface{})["maxSkew"], int64(1))
+}
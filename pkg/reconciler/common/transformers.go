@@ -18,52 +18,176 @@ package common
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"strings"
 
 	mf "github.com/manifestival/manifestival"
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
-	appsv1 "k8s.io/api/apps/v1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/logging"
 )
 
+// podTemplateKinds are the workload kinds carrying a pod template under
+// spec.template.
+var podTemplateKinds = sets.NewString("Deployment", "DaemonSet", "ReplicaSet", "StatefulSet")
+
 const (
 	AnnotationPreserveNS = "operator.tekton.dev/preserve-namespace"
 	PipelinesImagePrefix = "IMAGE_PIPELINES_"
 	TriggersImagePrefix  = "IMAGE_TRIGGERS_"
 	AddonsImagePrefix    = "IMAGE_ADDONS_"
+	ResultsImagePrefix   = "IMAGE_RESULTS_"
+	ChainsImagePrefix    = "IMAGE_CHAINS_"
+	HubImagePrefix       = "IMAGE_HUB_"
+	PACImagePrefix       = "IMAGE_PAC_"
+	MAGImagePrefix       = "IMAGE_MAG_"
+	// JobImagePrefix is the prefix of the images of the jobs the operator
+	// runs, e.g. the pruner
+	JobImagePrefix = "IMAGE_JOB_"
 
 	ArgPrefix   = "arg_"
 	ParamPrefix = "param_"
+
+	// TektonGroup is the API group of Tekton Pipelines resources
+	TektonGroup = "tekton.dev"
+
+	// AnnotationPreserveRBSubjectNS keeps the namespaces of the subjects of
+	// a role binding, e.g. ServiceAccounts of the monitoring stack
+	AnnotationPreserveRBSubjectNS = "operator.tekton.dev/preserve-rb-subject-namespace"
 )
 
+// NamespacePolicy tells how the resources of a payload are moved to the
+// target namespace of the component.
+type NamespacePolicy string
+
+const (
+	// InjectNamespace moves the namespaced resources, and the references to
+	// them, to the target namespace, but for the ones annotated with
+	// AnnotationPreserveNS. This is the default.
+	InjectNamespace NamespacePolicy = ""
+	// PreserveNamespace keeps the namespaces the payload ships.
+	PreserveNamespace NamespacePolicy = "preserve"
+)
+
+// TransformOptions are the component and platform specific parts of
+// TransformWith, on top of the transformers common to all components.
+type TransformOptions struct {
+	// Transformers run after the common ones, the ones of the platform
+	// extension first, one at a time whatever the Workers, see Serialized.
+	Transformers []mf.Transformer
+	// Images replace the images of workloads, steps and params after
+	// Transformers, keyed like spec.images.
+	Images map[string]string
+	// Namespace is how the resources are moved to the target namespace.
+	Namespace NamespacePolicy
+	// Filters drop the transformed resources not matching all of them.
+	Filters []mf.Predicate
+	// Strict fails the transformation when a key of Images matches none of
+	// the containers, flags, steps or params of the manifest.
+	Strict bool
+	// Workers is the number of resources transformed at once, one by
+	// default, see ParallelTransform.
+	Workers int
+}
+
 // transformers that are common to all components.
-func transformers(ctx context.Context, obj v1alpha1.TektonComponent) []mf.Transformer {
-	return []mf.Transformer{
-		mf.InjectOwner(obj),
-		injectNamespaceConditional(AnnotationPreserveNS, obj.GetSpec().GetTargetNamespace()),
-		injectNamespaceCRDWebhookClientConfig(obj.GetSpec().GetTargetNamespace()),
+func transformers(ctx context.Context, obj v1alpha1.TektonComponent, policy NamespacePolicy) []mf.Transformer {
+	overrides := overridesFromContext(ctx)
+	var transformers []mf.Transformer
+	if policy != PreserveNamespace {
+		transformers = append(transformers,
+			injectNamespaceConditional(AnnotationPreserveNS, obj.GetSpec().GetTargetNamespace()),
+			injectNamespaceCRDWebhookClientConfig(obj.GetSpec().GetTargetNamespace()),
+			injectNamespaceWebhookClientConfig(AnnotationPreserveNS, obj.GetSpec().GetTargetNamespace()),
+			injectNamespaceAPIService(AnnotationPreserveNS, obj.GetSpec().GetTargetNamespace()),
+			injectNamespaceRoleBindingSubjects(AnnotationPreserveNS, AnnotationPreserveRBSubjectNS, obj.GetSpec().GetTargetNamespace()),
+		)
 	}
+	transformers = append(transformers, managedBy, OptionsProfile(obj.GetSpec().GetOptionsProfile()))
+	transformers = append(transformers, overrideMetadata(overrides)...)
+	transformers = append(transformers,
+		ResourceLabels(obj.GetSpec().GetLabels()),
+		ResourceAnnotations(obj.GetSpec().GetAnnotations()),
+		PodAnnotations(obj.GetSpec().GetPodAnnotations()),
+		SafeToEvict(disruptionSettings(obj.GetSpec()).safeToEvict),
+		CostLabels(CostAllocationLabels(obj)),
+		RegistryOverride(obj.GetSpec().GetRegistry().Override),
+		ImagePullSecrets(obj.GetSpec().GetRegistry().ImagePullSecrets),
+		ProxySettings(obj.GetSpec().GetProxy()),
+		ContainerEnv(obj.GetSpec().GetEnv()),
+		CABundle(obj.GetSpec().GetConfig().CABundleConfigMap),
+		PriorityClassName(disruptionSettings(obj.GetSpec()).priorityClassName),
+		RestrictedPodSecurity(obj.GetSpec().GetConfig().RestrictedPodSecurity),
+		SchedulerName(obj.GetSpec().GetConfig().SchedulerName),
+		TopologySpreadConstraints(obj.GetSpec().GetConfig().TopologySpread),
+		HighAvailability(obj.GetSpec().GetHighAvailability()),
+		DeploymentArgs(obj.GetSpec().GetOptions().Deployments),
+		LogLevels(obj.GetSpec().GetConfig().LogLevels),
+		ConfigMapData(obj.GetSpec().GetOptions().ConfigMaps),
+	)
+	transformers = append(transformers, overrideScheduling(overrides)...)
+	// Owner references can't point across clusters, the garbage collector
+	// of a remote cluster would delete the payload right away.
+	if !isRemote(obj) {
+		transformers = append([]mf.Transformer{mf.InjectOwner(obj)}, transformers...)
+	}
+	return transformers
 }
 
 // Transform will mutate the passed-by-reference manifest with one
 // transformed by platform, common, and any extra passed in
+//
+// Deprecated: use TransformWith, Transform is TransformWith with extra as
+// the Transformers of the options.
 func Transform(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent, extra ...mf.Transformer) error {
+	return TransformWith(ctx, manifest, instance, TransformOptions{Transformers: extra})
+}
+
+// TransformWith mutates the passed-by-reference manifest with the common
+// transformers, then the ones of the options, and the images of the
+// overrides last.
+func TransformWith(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent, opts TransformOptions) error {
 	logger := logging.FromContext(ctx)
 	logger.Debug("Transforming manifest")
 
-	transformers := transformers(ctx, instance)
-	transformers = append(transformers, extra...)
+	images := SpecImages(opts.Images)
+	if opts.Strict {
+		if unknown := sets.StringKeySet(images).Difference(ImageKeys(*manifest)); unknown.Len() != 0 {
+			err := &operrors.TransformError{Err: fmt.Errorf("images matching nothing in the payload: %s", strings.Join(unknown.List(), ", "))}
+			operrors.MarkFailed(instance.GetStatus(), err)
+			reportError(ctx, transformErrorCountStat, instance.GetName())
+			return err
+		}
+	}
+
+	transformers := transformers(ctx, instance, opts.Namespace)
+	// the transformers of the options come from components and extensions
+	// which may not expect to run concurrently
+	transformers = append(transformers, Serialized(opts.Transformers...)...)
+	if len(images) != 0 {
+		transformers = append(transformers, WorkloadImages(images), TaskImages(images), StepActionImages(images))
+	}
+	// the images of the overrides come last to take precedence over
+	// spec.images
+	transformers = append(transformers, overrideImages(overridesFromContext(ctx))...)
 
-	m, err := manifest.Transform(transformers...)
+	m, err := ParallelTransform(*manifest, opts.Workers, transformers...)
 	if err != nil {
-		instance.GetStatus().MarkInstallFailed(err.Error())
+		err = &operrors.TransformError{Err: err}
+		operrors.MarkFailed(instance.GetStatus(), err)
+		reportError(ctx, transformErrorCountStat, instance.GetName())
 		return err
 	}
+	m = filterUnmanagedNamespace(m, instance)
+	if len(opts.Filters) != 0 {
+		m = m.Filter(opts.Filters...)
+	}
 	*manifest = m
 	return nil
 }
@@ -98,6 +222,183 @@ func injectNamespaceCRDWebhookClientConfig(targetNamespace string) mf.Transforme
 	}
 }
 
+// managedBy labels the resource as applied by the operator, for its
+// informers to watch it, see package managed.
+func managedBy(u *unstructured.Unstructured) error {
+	labels := u.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[v1alpha1.ManagedByKey] = v1alpha1.ManagedByValue
+	u.SetLabels(labels)
+	return nil
+}
+
+// ResourceLabels adds the given labels to every resource, keeping the
+// values of the labels the payload ships.
+func ResourceLabels(labels map[string]string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if len(labels) != 0 {
+			u.SetLabels(mergeKeeping(u.GetLabels(), labels))
+		}
+		return nil
+	}
+}
+
+// ResourceAnnotations adds the given annotations to every resource,
+// keeping the values of the annotations the payload ships.
+func ResourceAnnotations(annotations map[string]string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if len(annotations) != 0 {
+			u.SetAnnotations(mergeKeeping(u.GetAnnotations(), annotations))
+		}
+		return nil
+	}
+}
+
+// mergeKeeping adds the entries of extra to current which aren't in it yet.
+func mergeKeeping(current, extra map[string]string) map[string]string {
+	if current == nil {
+		current = map[string]string{}
+	}
+	for k, v := range extra {
+		if _, ok := current[k]; !ok {
+			current[k] = v
+		}
+	}
+	return current
+}
+
+// injectNamespaceWebhookClientConfig points the webhooks of
+// MutatingWebhookConfigurations and ValidatingWebhookConfigurations backed
+// by a service to the service in the target namespace, unless the
+// configuration preserves its namespace.
+func injectNamespaceWebhookClientConfig(preserveNamespace, targetNamespace string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if !webhookConfiguration(u) || u.GetAnnotations()[preserveNamespace] == "true" {
+			return nil
+		}
+		webhooks, _, err := unstructured.NestedSlice(u.Object, "webhooks")
+		if err != nil {
+			return err
+		}
+		for _, w := range webhooks {
+			webhook, ok := w.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, found, _ := unstructured.NestedMap(webhook, "clientConfig", "service"); !found {
+				continue
+			}
+			if err := unstructured.SetNestedField(webhook, targetNamespace, "clientConfig", "service", "namespace"); err != nil {
+				return err
+			}
+		}
+		if len(webhooks) == 0 {
+			return nil
+		}
+		return unstructured.SetNestedSlice(u.Object, webhooks, "webhooks")
+	}
+}
+
+// injectNamespaceAPIService points APIServices backed by a service to the
+// service in the target namespace, unless the APIService preserves its
+// namespace.
+func injectNamespaceAPIService(preserveNamespace, targetNamespace string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "APIService" || u.GetAnnotations()[preserveNamespace] == "true" {
+			return nil
+		}
+		if _, found, err := unstructured.NestedMap(u.Object, "spec", "service"); !found || err != nil {
+			return err
+		}
+		return unstructured.SetNestedField(u.Object, targetNamespace, "spec", "service", "namespace")
+	}
+}
+
+// injectNamespaceRoleBindingSubjects moves the ServiceAccount subjects of
+// RoleBindings and ClusterRoleBindings to the target namespace, including
+// subjects without a namespace, unless the binding preserves its
+// namespace or the namespaces of its subjects, e.g. a binding of a
+// ServiceAccount of the monitoring stack. Users and groups aren't
+// namespaced and are left alone.
+func injectNamespaceRoleBindingSubjects(preserveNamespace, preserveSubjectNamespace, targetNamespace string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		annotations := u.GetAnnotations()
+		if !rolebinding(u) || annotations[preserveNamespace] == "true" || annotations[preserveSubjectNamespace] == "true" {
+			return nil
+		}
+		subjects, found, err := unstructured.NestedSlice(u.Object, "subjects")
+		if err != nil || !found {
+			return err
+		}
+		for _, s := range subjects {
+			if subject, ok := s.(map[string]interface{}); ok && subject["kind"] == "ServiceAccount" {
+				subject["namespace"] = targetNamespace
+			}
+		}
+		return unstructured.SetNestedSlice(u.Object, subjects, "subjects")
+	}
+}
+
+// PodAnnotations adds the given annotations to the pod template of
+// Deployments, DaemonSets, ReplicaSets and StatefulSets, overriding the
+// values shipped in the payload.
+func PodAnnotations(annotations map[string]string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if len(annotations) == 0 || !podTemplateKinds.Has(u.GetKind()) {
+			return nil
+		}
+		current, _, err := unstructured.NestedStringMap(u.Object, "spec", "template", "metadata", "annotations")
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			current = map[string]string{}
+		}
+		for k, v := range annotations {
+			current[k] = v
+		}
+		return unstructured.SetNestedStringMap(u.Object, current, "spec", "template", "metadata", "annotations")
+	}
+}
+
+// PriorityClassName sets the priority class of the pods of the payload
+// Deployments and StatefulSets. It is a no-op when name is empty.
+func PriorityClassName(name string) mf.Transformer {
+	kinds := sets.NewString("Deployment", "StatefulSet")
+	return func(u *unstructured.Unstructured) error {
+		if name == "" || !kinds.Has(u.GetKind()) {
+			return nil
+		}
+		return unstructured.SetNestedField(u.Object, name, "spec", "template", "spec", "priorityClassName")
+	}
+}
+
+// SchedulerName sets the scheduler of the pods of the payload workloads,
+// Jobs and CronJobs included. It is a no-op when name is empty.
+func SchedulerName(name string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		path := podSpecPath(u.GetKind())
+		if name == "" || path == nil {
+			return nil
+		}
+		return unstructured.SetNestedField(u.Object, name, append(path, "schedulerName")...)
+	}
+}
+
+// podSpecPath returns the path to the pod spec of the pod template of a
+// resource of the kind, nil for kinds without pod template.
+func podSpecPath(kind string) []string {
+	switch {
+	case podTemplateKinds.Has(kind) || kind == "Job":
+		return []string{"spec", "template", "spec"}
+	case kind == "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	}
+	return nil
+}
+
 // ImagesFromEnv will provide map of key value.
 func ImagesFromEnv(prefix string) map[string]string {
 	images := map[string]string{}
@@ -115,47 +416,48 @@ func ImagesFromEnv(prefix string) map[string]string {
 	return images
 }
 
-// ToLowerCaseKeys converts key value to lower cases.
-func ToLowerCaseKeys(keyValues map[string]string) map[string]string {
-	newMap := map[string]string{}
-
-	for k, v := range keyValues {
-		key := strings.ToLower(k)
-		newMap[key] = v
-	}
-
-	return newMap
+// WorkloadImages replaces container, init container and args images of
+// Deployments, DaemonSets and ReplicaSets.
+func WorkloadImages(images map[string]string) mf.Transformer {
+	return workloadImages(images, "Deployment", "DaemonSet", "ReplicaSet")
 }
 
-// DeploymentImages replaces container and args images.
+// DeploymentImages replaces container, init container and args images of
+// Deployments.
 func DeploymentImages(images map[string]string) mf.Transformer {
+	return workloadImages(images, "Deployment")
+}
+
+func workloadImages(images map[string]string, kinds ...string) mf.Transformer {
+	workloadKinds := sets.NewString(kinds...)
 	return func(u *unstructured.Unstructured) error {
-		if u.GetKind() != "Deployment" {
+		if !workloadKinds.Has(u.GetKind()) {
 			return nil
 		}
 
-		d := &appsv1.Deployment{}
-		err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, d)
-		if err != nil {
+		template, found, err := unstructured.NestedMap(u.Object, "spec", "template", "spec")
+		if err != nil || !found {
+			return err
+		}
+		podSpec := &corev1.PodSpec{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template, podSpec); err != nil {
 			return err
 		}
 
-		containers := d.Spec.Template.Spec.Containers
-		replaceContainerImages(containers, images)
+		replaceContainerImages(podSpec.Containers, images)
+		replaceContainerImages(podSpec.InitContainers, images)
 
-		unstrObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(d)
+		unstrObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(podSpec)
 		if err != nil {
 			return err
 		}
-		u.SetUnstructuredContent(unstrObj)
-
-		return nil
+		return unstructured.SetNestedMap(u.Object, unstrObj, "spec", "template", "spec")
 	}
 }
 
 func replaceContainerImages(containers []corev1.Container, images map[string]string) {
 	for i, container := range containers {
-		name := formKey("", container.Name)
+		name := NormalizeKey("", container.Name)
 		if url, exist := images[name]; exist {
 			containers[i].Image = url
 		}
@@ -167,14 +469,14 @@ func replaceContainerImages(containers []corev1.Container, images map[string]str
 func replaceContainersArgsImage(container *corev1.Container, images map[string]string) {
 	for a, arg := range container.Args {
 		if argVal, hasArg := splitsByEqual(arg); hasArg {
-			argument := formKey(ArgPrefix, argVal[0])
+			argument := NormalizeKey(ArgPrefix, argVal[0])
 			if url, exist := images[argument]; exist {
 				container.Args[a] = argVal[0] + "=" + url
 			}
 			continue
 		}
 
-		argument := formKey(ArgPrefix, arg)
+		argument := NormalizeKey(ArgPrefix, arg)
 		if url, exist := images[argument]; exist {
 			container.Args[a+1] = url
 		}
@@ -182,14 +484,6 @@ func replaceContainersArgsImage(container *corev1.Container, images map[string]s
 
 }
 
-func formKey(prefix, arg string) string {
-	argument := strings.ToLower(arg)
-	if prefix != "" {
-		argument = prefix + argument
-	}
-	return strings.ReplaceAll(argument, "-", "_")
-}
-
 func splitsByEqual(arg string) ([]string, bool) {
 	values := strings.Split(arg, "=")
 	if len(values) == 2 {
@@ -199,10 +493,20 @@ func splitsByEqual(arg string) ([]string, bool) {
 	return values, false
 }
 
-// TaskImages replaces step and params images.
-func TaskImages(images map[string]string) mf.Transformer {
+// TaskKinds matches the resources TaskImages applies to when no kinds are
+// passed: ClusterTasks and namespaced Tasks of any tekton.dev version.
+var TaskKinds = mf.Any(byGroupKind(TektonGroup, "ClusterTask"), byGroupKind(TektonGroup, "Task"))
+
+// TaskImages replaces step and params images of the resources matched by
+// kinds, e.g. mf.ByGVK for a specific apiVersion, or TaskKinds if none are
+// given.
+func TaskImages(images map[string]string, kinds ...mf.Predicate) mf.Transformer {
+	match := TaskKinds
+	if len(kinds) != 0 {
+		match = mf.Any(kinds...)
+	}
 	return func(u *unstructured.Unstructured) error {
-		if u.GetKind() != "ClusterTask" {
+		if !match(u) {
 			return nil
 		}
 
@@ -235,6 +539,36 @@ func TaskImages(images map[string]string) mf.Transformer {
 	}
 }
 
+// StepActionImages replaces the image of StepActions, keyed by the name of
+// the StepAction, and their params images.
+func StepActionImages(images map[string]string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if !byGroupKind(TektonGroup, "StepAction")(u) {
+			return nil
+		}
+
+		if image, found := images[NormalizeKey("", u.GetName())]; found && image != "" {
+			if err := unstructured.SetNestedField(u.Object, image, "spec", "image"); err != nil {
+				return err
+			}
+		}
+
+		params, found, err := unstructured.NestedSlice(u.Object, "spec", "params")
+		if err != nil || !found {
+			return err
+		}
+		replaceParamsImage(params, images)
+		return unstructured.SetNestedField(u.Object, params, "spec", "params")
+	}
+}
+
+func byGroupKind(group, kind string) mf.Predicate {
+	return func(u *unstructured.Unstructured) bool {
+		gvk := u.GroupVersionKind()
+		return gvk.Group == group && gvk.Kind == kind
+	}
+}
+
 func replaceStepsImages(steps []interface{}, override map[string]string) {
 	for _, s := range steps {
 		step := s.(map[string]interface{})
@@ -244,7 +578,7 @@ func replaceStepsImages(steps []interface{}, override map[string]string) {
 			continue
 		}
 
-		name = formKey("", name)
+		name = NormalizeKey("", name)
 		image, found := override[name]
 		if !found || image == "" {
 			log.Println("Image not found", "step", name, "action", "skip")
@@ -263,7 +597,7 @@ func replaceParamsImage(params []interface{}, override map[string]string) {
 			continue
 		}
 
-		name = formKey(ParamPrefix, name)
+		name = NormalizeKey(ParamPrefix, name)
 		image, found := override[name]
 		if !found || image == "" {
 			log.Println("Image not found", "step", name, "action", "skip")
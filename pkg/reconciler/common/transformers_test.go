@@ -26,10 +26,13 @@ import (
 	"github.com/google/go-cmp/cmp"
 	mf "github.com/manifestival/manifestival"
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/ptr"
 )
 
@@ -93,16 +96,234 @@ func TestCommonTransformers(t *testing.T) {
 	}
 }
 
+func TestTransformWith(t *testing.T) {
+	component := &v1alpha1.TektonPipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-name"},
+		Spec: v1alpha1.TektonPipelineSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "test-ns"},
+		},
+	}
+	deployment := util.MakeDeployment("controller", corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "tekton-pipelines-controller", Image: "busybox"}},
+	})
+	deployment.APIVersion = "apps/v1"
+	deployment.Namespace = "another-ns"
+	newManifest := func() mf.Manifest {
+		manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
+			util.MakeUnstructured(t, deployment),
+			namespacedResource("v1", "ConfigMap", "another-ns", "config"),
+		}))
+		util.AssertNoError(t, err)
+		return manifest
+	}
+	images := map[string]string{"tekton-pipelines-controller": "foo.bar/controller"}
+
+	t.Run("images and filters", func(t *testing.T) {
+		manifest := newManifest()
+		util.AssertNoError(t, TransformWith(context.Background(), &manifest, component, TransformOptions{
+			Images:  images,
+			Filters: []mf.Predicate{mf.ByKind("Deployment")},
+		}))
+		util.AssertEqual(t, len(manifest.Resources()), 1)
+		u := manifest.Resources()[0]
+		util.AssertEqual(t, u.GetNamespace(), "test-ns")
+		containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "containers")
+		util.AssertEqual(t, containers[0].(map[string]interface{})["image"], "foo.bar/controller")
+	})
+
+	t.Run("preserve namespace", func(t *testing.T) {
+		manifest := newManifest()
+		util.AssertNoError(t, TransformWith(context.Background(), &manifest, component, TransformOptions{Namespace: PreserveNamespace}))
+		for _, u := range manifest.Resources() {
+			util.AssertEqual(t, u.GetNamespace(), "another-ns")
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		manifest := newManifest()
+		util.AssertNoError(t, TransformWith(context.Background(), &manifest, component, TransformOptions{Images: images, Strict: true}))
+
+		manifest = newManifest()
+		component.Status.InitializeConditions()
+		err := TransformWith(context.Background(), &manifest, component, TransformOptions{
+			Images: map[string]string{"tekton-pipelines-webhook": "foo.bar/webhook"},
+			Strict: true,
+		})
+		if err == nil {
+			t.Fatal("TransformWith() = nil, wanted an error for the unknown image")
+		}
+		util.AssertEqual(t, err.Error(), "images matching nothing in the payload: tekton_pipelines_webhook")
+		util.AssertEqual(t, component.Status.GetCondition(v1alpha1.InstallSucceeded).IsFalse(), true)
+	})
+}
+
+func TestInjectNamespaceWebhookClientConfig(t *testing.T) {
+	webhooks := func() []interface{} {
+		return []interface{}{
+			map[string]interface{}{
+				"name": "webhook.pipeline.tekton.dev",
+				"clientConfig": map[string]interface{}{
+					"service": map[string]interface{}{"name": "tekton-pipelines-webhook"},
+				},
+			},
+			map[string]interface{}{
+				"name":         "remote.pipeline.tekton.dev",
+				"clientConfig": map[string]interface{}{"url": "https://webhook.example.com"},
+			},
+		}
+	}
+	validating := clusterScopedResource("admissionregistration.k8s.io/v1", "ValidatingWebhookConfiguration", "validation.webhook.pipeline.tekton.dev")
+	validating.Object["webhooks"] = webhooks()
+	mutating := clusterScopedResource("admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", "webhook.pipeline.tekton.dev")
+	mutating.Object["webhooks"] = webhooks()
+	preserved := clusterScopedResource("admissionregistration.k8s.io/v1", "MutatingWebhookConfiguration", "preserved")
+	preserved.SetAnnotations(map[string]string{AnnotationPreserveNS: "true"})
+	preserved.Object["webhooks"] = webhooks()
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{validating, mutating, preserved}))
+	assertNoEror(t, err)
+
+	newManifest, err := manifest.Transform(injectNamespaceWebhookClientConfig(AnnotationPreserveNS, "ci"))
+	assertNoEror(t, err)
+	for i, u := range newManifest.Resources() {
+		hooks, _, _ := unstructured.NestedSlice(u.Object, "webhooks")
+		namespace, _, _ := unstructured.NestedString(hooks[0].(map[string]interface{}), "clientConfig", "service", "namespace")
+		want := "ci"
+		if i == 2 {
+			want = ""
+		}
+		util.AssertEqual(t, namespace, want)
+		util.AssertDeepEqual(t, hooks[1], webhooks()[1])
+	}
+}
+
+func TestInjectNamespaceAPIService(t *testing.T) {
+	apiService := clusterScopedResource("apiregistration.k8s.io/v1", "APIService", "v1alpha2.results.tekton.dev")
+	apiService.Object["spec"] = map[string]interface{}{
+		"group":   "results.tekton.dev",
+		"service": map[string]interface{}{"name": "tekton-results-api-service", "namespace": "tekton-pipelines"},
+	}
+	local := clusterScopedResource("apiregistration.k8s.io/v1", "APIService", "v1.apps")
+	local.Object["spec"] = map[string]interface{}{"group": "apps"}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{apiService, local}))
+	assertNoEror(t, err)
+
+	newManifest, err := manifest.Transform(injectNamespaceAPIService(AnnotationPreserveNS, "ci"))
+	assertNoEror(t, err)
+	resources := newManifest.Resources()
+	namespace, _, _ := unstructured.NestedString(resources[0].Object, "spec", "service", "namespace")
+	util.AssertEqual(t, namespace, "ci")
+	util.AssertDeepEqual(t, resources[1].Object, local.Object)
+
+	apiService.SetAnnotations(map[string]string{AnnotationPreserveNS: "true"})
+	util.AssertNoError(t, injectNamespaceAPIService(AnnotationPreserveNS, "ci")(&apiService))
+	namespace, _, _ = unstructured.NestedString(apiService.Object, "spec", "service", "namespace")
+	util.AssertEqual(t, namespace, "tekton-pipelines")
+}
+
+func TestInjectNamespaceRoleBindingSubjects(t *testing.T) {
+	subjects := func() []interface{} {
+		return []interface{}{
+			map[string]interface{}{"kind": "ServiceAccount", "name": "tekton-pipelines-controller", "namespace": "tekton-pipelines"},
+			map[string]interface{}{"kind": "ServiceAccount", "name": "tekton-pipelines-webhook"},
+			map[string]interface{}{"kind": "Group", "name": "system:authenticated", "apiGroup": "rbac.authorization.k8s.io"},
+		}
+	}
+	clusterRoleBinding := clusterScopedResource("rbac.authorization.k8s.io/v1", "ClusterRoleBinding", "tekton-pipelines-controller-admin")
+	clusterRoleBinding.Object["subjects"] = subjects()
+	roleBinding := namespacedResource("rbac.authorization.k8s.io/v1", "RoleBinding", "tekton-pipelines", "tekton-pipelines-info")
+	roleBinding.Object["subjects"] = subjects()
+	preserved := clusterScopedResource("rbac.authorization.k8s.io/v1", "ClusterRoleBinding", "openshift-pipelines-auth")
+	preserved.SetAnnotations(map[string]string{AnnotationPreserveNS: "true"})
+	preserved.Object["subjects"] = subjects()
+	// e.g. the binding of the Prometheus of OpenShift, reading the metrics
+	// of the target namespace
+	monitoring := namespacedResource("rbac.authorization.k8s.io/v1", "RoleBinding", "tekton-pipelines", "openshift-pipelines-prometheus-k8s-read-binding")
+	monitoring.SetAnnotations(map[string]string{AnnotationPreserveRBSubjectNS: "true"})
+	monitoring.Object["subjects"] = []interface{}{
+		map[string]interface{}{"kind": "ServiceAccount", "name": "prometheus-k8s", "namespace": "openshift-monitoring"},
+	}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{clusterRoleBinding, roleBinding, preserved, monitoring}))
+	assertNoEror(t, err)
+
+	newManifest, err := manifest.Transform(injectNamespaceRoleBindingSubjects(AnnotationPreserveNS, AnnotationPreserveRBSubjectNS, "ci"))
+	assertNoEror(t, err)
+	resources := newManifest.Resources()
+	for _, u := range resources[:2] {
+		got, _, _ := unstructured.NestedSlice(u.Object, "subjects")
+		util.AssertDeepEqual(t, got, []interface{}{
+			map[string]interface{}{"kind": "ServiceAccount", "name": "tekton-pipelines-controller", "namespace": "ci"},
+			map[string]interface{}{"kind": "ServiceAccount", "name": "tekton-pipelines-webhook", "namespace": "ci"},
+			map[string]interface{}{"kind": "Group", "name": "system:authenticated", "apiGroup": "rbac.authorization.k8s.io"},
+		})
+	}
+	got, _, _ := unstructured.NestedSlice(resources[2].Object, "subjects")
+	util.AssertDeepEqual(t, got, subjects())
+	got, _, _ = unstructured.NestedSlice(resources[3].Object, "subjects")
+	util.AssertDeepEqual(t, got, []interface{}{
+		map[string]interface{}{"kind": "ServiceAccount", "name": "prometheus-k8s", "namespace": "openshift-monitoring"},
+	})
+}
+
+func TestResourceLabelsAndAnnotations(t *testing.T) {
+	cm := namespacedResource("v1", "ConfigMap", "ns", "config")
+	cm.SetLabels(map[string]string{"app.kubernetes.io/part-of": "tekton-pipelines"})
+	crd := clusterScopedResource("apiextensions.k8s.io/v1", "CustomResourceDefinition", "tasks.tekton.dev")
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{cm, crd}))
+	assertNoEror(t, err)
+
+	newManifest, err := manifest.Transform(ResourceLabels(nil), ResourceAnnotations(nil))
+	assertNoEror(t, err)
+	util.AssertDeepEqual(t, newManifest.Resources(), manifest.Resources())
+
+	newManifest, err = manifest.Transform(
+		ResourceLabels(map[string]string{"cost-center": "ci", "app.kubernetes.io/part-of": "ci"}),
+		ResourceAnnotations(map[string]string{"backup.velero.io/include": "true"}),
+	)
+	assertNoEror(t, err)
+	resources := newManifest.Resources()
+	util.AssertDeepEqual(t, resources[0].GetLabels(), map[string]string{
+		"cost-center":               "ci",
+		"app.kubernetes.io/part-of": "tekton-pipelines",
+	})
+	util.AssertDeepEqual(t, resources[1].GetLabels(), map[string]string{
+		"cost-center":               "ci",
+		"app.kubernetes.io/part-of": "ci",
+	})
+	for _, u := range resources {
+		util.AssertDeepEqual(t, u.GetAnnotations(), map[string]string{"backup.velero.io/include": "true"})
+	}
+}
+
+func TestPodAnnotations(t *testing.T) {
+	deployment := util.MakeDeployment("controller", corev1.PodSpec{})
+	deployment.Spec.Template.Annotations = map[string]string{
+		"existing": "value",
+		"cluster-autoscaler.kubernetes.io/safe-to-evict": "true",
+	}
+	u := util.MakeUnstructured(t, deployment)
+	annotations := map[string]string{
+		"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
+	}
+	assertNoEror(t, PodAnnotations(annotations)(&u))
+	got, _, err := unstructured.NestedStringMap(u.Object, "spec", "template", "metadata", "annotations")
+	assertNoEror(t, err)
+	util.AssertDeepEqual(t, got, map[string]string{
+		"existing": "value",
+		"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
+	})
+
+	cm := namespacedResource("v1", "ConfigMap", "ns", "config")
+	assertNoEror(t, PodAnnotations(annotations)(&cm))
+	_, found, _ := unstructured.NestedMap(cm.Object, "spec")
+	util.AssertEqual(t, found, false)
+}
+
 func TestImagesFromEnv(t *testing.T) {
 	os.Setenv("IMAGE_PIPELINES_CONTROLLER", "docker.io/pipeline")
 	data := ImagesFromEnv(PipelinesImagePrefix)
 	if !cmp.Equal(data, map[string]string{"CONTROLLER": "docker.io/pipeline"}) {
 		t.Fatalf("Unexpected ImageFromEnv: %s", cmp.Diff(data, map[string]string{"CONTROLLER": "docker.io/pipeline"}))
 	}
-	convertToLower := ToLowerCaseKeys(data)
-	if !cmp.Equal(convertToLower, map[string]string{"controller": "docker.io/pipeline"}) {
-		t.Fatalf("Unexpected ToLowerCaseKeys: %s", cmp.Diff(convertToLower, map[string]string{"controller": "docker.io/pipeline"}))
-	}
 }
 
 func TestReplaceImages(t *testing.T) {
@@ -132,6 +353,41 @@ func TestReplaceImages(t *testing.T) {
 		assertDeployContainersHasImage(t, newManifest.Resources(), "sidecar", "busybox")
 	})
 
+	t.Run("replace init containers by name", func(t *testing.T) {
+		image := "foo.bar/image/init"
+		images := map[string]string{
+			"controller_init": image,
+		}
+		testData := path.Join("testdata", "test-replace-image.yaml")
+
+		manifest, err := mf.ManifestFrom(mf.Recursive(testData))
+		assertNoEror(t, err)
+		newManifest, err := manifest.Transform(DeploymentImages(images))
+		assertNoEror(t, err)
+		assertDeployInitContainersHasImage(t, newManifest.Resources(), "controller-init", image)
+		assertDeployContainersHasImage(t, newManifest.Resources(), "controller-deployment", "busybox")
+	})
+
+	t.Run("replace init containers args by space", func(t *testing.T) {
+		arg := ArgPrefix + "_bash_image"
+		image := "foo.bar/image/bash"
+		images := map[string]string{
+			arg: image,
+		}
+		testData := path.Join("testdata", "test-replace-image.yaml")
+
+		manifest, err := mf.ManifestFrom(mf.Recursive(testData))
+		assertNoEror(t, err)
+		newManifest, err := manifest.Transform(DeploymentImages(images))
+		assertNoEror(t, err)
+		for _, resource := range newManifest.Resources() {
+			initContainers := deploymentFor(t, resource).Spec.Template.Spec.InitContainers
+			if got := initContainers[0].Args[1]; got != image {
+				t.Errorf("not equal: expected %v, got %v", image, got)
+			}
+		}
+	})
+
 	t.Run("replace containers args by space", func(t *testing.T) {
 		arg := ArgPrefix + "__bash_image"
 		image := "foo.bar/image/bash"
@@ -197,6 +453,89 @@ func TestReplaceImages(t *testing.T) {
 	})
 }
 
+func TestTaskImagesKinds(t *testing.T) {
+	image := "foo.bar/image/build"
+	images := map[string]string{
+		"build": image,
+	}
+	testData := path.Join("testdata", "test-replace-task-kinds.yaml")
+
+	stepImages := func(m mf.Manifest) map[string]string {
+		result := map[string]string{}
+		for _, r := range m.Resources() {
+			steps, _, _ := unstructured.NestedSlice(r.Object, "spec", "steps")
+			result[r.GetName()] = steps[0].(map[string]interface{})["image"].(string)
+		}
+		return result
+	}
+
+	t.Run("default kinds", func(t *testing.T) {
+		manifest, err := mf.ManifestFrom(mf.Recursive(testData))
+		assertNoEror(t, err)
+		newManifest, err := manifest.Transform(TaskImages(images))
+		assertNoEror(t, err)
+		util.AssertDeepEqual(t, stepImages(newManifest), map[string]string{
+			"cluster-task": image,
+			"task-v1":      image,
+			"task-v1beta1": image,
+			"not-tekton":   "busybox",
+		})
+	})
+
+	t.Run("configured kinds", func(t *testing.T) {
+		manifest, err := mf.ManifestFrom(mf.Recursive(testData))
+		assertNoEror(t, err)
+		newManifest, err := manifest.Transform(TaskImages(images,
+			mf.ByGVK(schema.GroupVersionKind{Group: TektonGroup, Version: "v1", Kind: "Task"})))
+		assertNoEror(t, err)
+		util.AssertDeepEqual(t, stepImages(newManifest), map[string]string{
+			"cluster-task": "busybox",
+			"task-v1":      image,
+			"task-v1beta1": "busybox",
+			"not-tekton":   "busybox",
+		})
+	})
+}
+
+func TestWorkloadImages(t *testing.T) {
+	image := "foo.bar/image/controller"
+	images := map[string]string{
+		"controller": image,
+	}
+	podSpec := corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:  "controller",
+			Image: "busybox",
+		}},
+	}
+	daemonSet := util.MakeUnstructured(t, util.MakeDaemonSet("test", podSpec))
+	replicaSet := util.MakeUnstructured(t, &appsv1.ReplicaSet{
+		TypeMeta: metav1.TypeMeta{Kind: "ReplicaSet", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: corev1.PodTemplateSpec{Spec: podSpec},
+		},
+	})
+
+	t.Run("DeploymentImages ignores daemonsets", func(t *testing.T) {
+		u := daemonSet.DeepCopy()
+		assertNoEror(t, DeploymentImages(images)(u))
+		containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "containers")
+		util.AssertEqual(t, containers[0].(map[string]interface{})["image"], "busybox")
+	})
+
+	for name, workload := range map[string]unstructured.Unstructured{"daemonset": daemonSet, "replicaset": replicaSet} {
+		t.Run(name, func(t *testing.T) {
+			u := workload.DeepCopy()
+			assertNoEror(t, WorkloadImages(images)(u))
+			containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "containers")
+			util.AssertEqual(t, containers[0].(map[string]interface{})["image"], image)
+		})
+	}
+}
+
 func assertNoEror(t *testing.T, err error) {
 	t.Helper()
 
@@ -232,6 +571,25 @@ func assertDeployContainersHasImage(t *testing.T, resources []unstructured.Unstr
 	}
 }
 
+func assertDeployInitContainersHasImage(t *testing.T, resources []unstructured.Unstructured, name string, image string) {
+	t.Helper()
+
+	for _, resource := range resources {
+		deployment := deploymentFor(t, resource)
+		containers := deployment.Spec.Template.Spec.InitContainers
+
+		for _, container := range containers {
+			if container.Name != name {
+				continue
+			}
+
+			if container.Image != image {
+				t.Errorf("assertion failed; unexpected image: expected %s and got %s", image, container.Image)
+			}
+		}
+	}
+}
+
 func assertDeployContainerArgsHasImage(t *testing.T, resources []unstructured.Unstructured, arg string, image string) {
 	t.Helper()
 
@@ -331,3 +689,135 @@ func deploymentFor(t *testing.T, unstr unstructured.Unstructured) *appsv1.Deploy
 	}
 	return deployment
 }
+
+func TestStepActionImages(t *testing.T) {
+	image := "foo.bar/image/git-clone"
+	initImage := "foo.bar/image/git-init"
+	images := map[string]string{
+		"git_clone":          image,
+		"param_gitinitimage": initImage,
+	}
+	testData := path.Join("testdata", "test-replace-stepaction-image.yaml")
+	manifest, err := mf.ManifestFrom(mf.Recursive(testData))
+	assertNoEror(t, err)
+	newManifest, err := manifest.Transform(StepActionImages(images))
+	assertNoEror(t, err)
+
+	result := map[string]string{}
+	for _, r := range newManifest.Resources() {
+		result[r.GetName()], _, _ = unstructured.NestedString(r.Object, "spec", "image")
+	}
+	util.AssertDeepEqual(t, result, map[string]string{
+		"git-clone": image,
+		"other":     "busybox",
+	})
+
+	params, _, _ := unstructured.NestedSlice(newManifest.Resources()[0].Object, "spec", "params")
+	util.AssertEqual(t, params[0].(map[string]interface{})["default"], initImage)
+}
+
+func TestRegistryOverride(t *testing.T) {
+	testData := path.Join("testdata", "test-registry-override.yaml")
+	manifest, err := mf.ManifestFrom(mf.Recursive(testData))
+	assertNoEror(t, err)
+	newManifest, err := manifest.Transform(RegistryOverride("mirror.corp.local/tekton/"))
+	assertNoEror(t, err)
+	resources := newManifest.Resources()
+
+	d := &appsv1.Deployment{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(resources[0].Object, d)
+	assertNoEror(t, err)
+	util.AssertEqual(t, d.Spec.Template.Spec.InitContainers[0].Image, "mirror.corp.local/tekton/library/busybox")
+	util.AssertEqual(t, d.Spec.Template.Spec.Containers[0].Image, "mirror.corp.local/tekton/tekton-releases/controller:v0.20.0")
+	util.AssertDeepEqual(t, d.Spec.Template.Spec.Containers[0].Args, []string{
+		"-git-image", "mirror.corp.local/tekton/tekton-releases/git-init:v0.20.0",
+		"-shell-image=mirror.corp.local/tekton/ubi8/ubi-minimal:latest",
+		"-nop=nop",
+		"-threads-per-controller", "2",
+	})
+
+	params, _, _ := unstructured.NestedSlice(resources[1].Object, "spec", "params")
+	util.AssertEqual(t, params[0].(map[string]interface{})["default"], "mirror.corp.local/tekton/buildah/stable:v1")
+	util.AssertEqual(t, params[1].(map[string]interface{})["default"], "./Dockerfile")
+	steps, _, _ := unstructured.NestedSlice(resources[1].Object, "spec", "steps")
+	util.AssertEqual(t, steps[0].(map[string]interface{})["image"], "$(params.BUILDER_IMAGE)")
+	sidecars, _, _ := unstructured.NestedSlice(resources[1].Object, "spec", "sidecars")
+	util.AssertEqual(t, sidecars[0].(map[string]interface{})["image"], "mirror.corp.local/tekton/registry")
+
+	image, _, _ := unstructured.NestedString(resources[2].Object, "spec", "image")
+	util.AssertEqual(t, image, "mirror.corp.local/tekton/tektoncd/git-init")
+}
+
+func TestImagePullSecrets(t *testing.T) {
+	deployment := util.MakeUnstructured(t, util.MakeDeployment("controller", corev1.PodSpec{
+		Containers:       []corev1.Container{{Name: "controller", Image: "mirror.corp.local/controller:v1"}},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "existing"}},
+	}))
+	serviceAccount := util.MakeUnstructured(t, &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+		ObjectMeta: metav1.ObjectMeta{Name: "tekton-pipelines-controller"},
+	})
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment, serviceAccount}))
+	assertNoEror(t, err)
+	newManifest, err := manifest.Transform(ImagePullSecrets([]string{"mirror-pull", "existing"}))
+	assertNoEror(t, err)
+	resources := newManifest.Resources()
+
+	d := &appsv1.Deployment{}
+	assertNoEror(t, runtime.DefaultUnstructuredConverter.FromUnstructured(resources[0].Object, d))
+	util.AssertDeepEqual(t, d.Spec.Template.Spec.ImagePullSecrets, []corev1.LocalObjectReference{{Name: "existing"}, {Name: "mirror-pull"}})
+
+	sa := &corev1.ServiceAccount{}
+	assertNoEror(t, runtime.DefaultUnstructuredConverter.FromUnstructured(resources[1].Object, sa))
+	util.AssertDeepEqual(t, sa.ImagePullSecrets, []corev1.LocalObjectReference{{Name: "mirror-pull"}, {Name: "existing"}})
+}
+
+func TestPriorityClassName(t *testing.T) {
+	podSpec := corev1.PodSpec{Containers: []corev1.Container{{Name: "controller", Image: "gcr.io/controller:v1"}}}
+	deployment := util.MakeUnstructured(t, util.MakeDeployment("controller", podSpec))
+	daemonSet := util.MakeUnstructured(t, util.MakeDaemonSet("agent", podSpec))
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment, daemonSet}))
+	assertNoEror(t, err)
+
+	newManifest, err := manifest.Transform(PriorityClassName(""))
+	assertNoEror(t, err)
+	util.AssertDeepEqual(t, newManifest.Resources(), manifest.Resources())
+
+	newManifest, err = manifest.Transform(PriorityClassName("system-cluster-critical"))
+	assertNoEror(t, err)
+	resources := newManifest.Resources()
+	name, _, _ := unstructured.NestedString(resources[0].Object, "spec", "template", "spec", "priorityClassName")
+	util.AssertEqual(t, name, "system-cluster-critical")
+	_, found, _ := unstructured.NestedString(resources[1].Object, "spec", "template", "spec", "priorityClassName")
+	util.AssertEqual(t, found, false)
+}
+
+func TestSchedulerName(t *testing.T) {
+	podSpec := corev1.PodSpec{Containers: []corev1.Container{{Name: "controller", Image: "gcr.io/controller:v1"}}}
+	deployment := util.MakeUnstructured(t, util.MakeDeployment("controller", podSpec))
+	cronJob := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1beta1",
+		"kind":       "CronJob",
+		"metadata":   map[string]interface{}{"name": "pruner"},
+	}}
+	configMap := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "config-defaults"},
+	}}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment, cronJob, configMap}))
+	assertNoEror(t, err)
+
+	newManifest, err := manifest.Transform(SchedulerName(""))
+	assertNoEror(t, err)
+	util.AssertDeepEqual(t, newManifest.Resources(), manifest.Resources())
+
+	newManifest, err = manifest.Transform(SchedulerName("bin-packing"))
+	assertNoEror(t, err)
+	resources := newManifest.Resources()
+	name, _, _ := unstructured.NestedString(resources[0].Object, "spec", "template", "spec", "schedulerName")
+	util.AssertEqual(t, name, "bin-packing")
+	name, _, _ = unstructured.NestedString(resources[1].Object, "spec", "jobTemplate", "spec", "template", "spec", "schedulerName")
+	util.AssertEqual(t, name, "bin-packing")
+	util.AssertDeepEqual(t, resources[2], configMap)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package errors holds the typed errors of the reconcilers, each mapping to
+// the reason of the condition it marks false.
+package errors
+
+import (
+	"errors"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// TransformError is returned when the payload can't be transformed
+type TransformError struct{ Err error }
+
+func (e *TransformError) Error() string { return e.Err.Error() }
+func (e *TransformError) Unwrap() error { return e.Err }
+
+// Reason implements reasoned
+func (e *TransformError) Reason() string { return v1alpha1.ReasonTransformError }
+
+// DependencyMissing is returned when something the component needs, e.g.
+// another component or a secret, doesn't exist yet
+type DependencyMissing struct{ Err error }
+
+func (e *DependencyMissing) Error() string { return e.Err.Error() }
+func (e *DependencyMissing) Unwrap() error { return e.Err }
+
+// Reason implements reasoned
+func (e *DependencyMissing) Reason() string { return v1alpha1.ReasonDependencyMissing }
+
+// ApplyConflict is returned when a payload resource was changed
+// concurrently while applying it
+type ApplyConflict struct{ Err error }
+
+func (e *ApplyConflict) Error() string { return e.Err.Error() }
+func (e *ApplyConflict) Unwrap() error { return e.Err }
+
+// Reason implements reasoned
+func (e *ApplyConflict) Reason() string { return v1alpha1.ReasonApplyConflict }
+
+// TimeoutWaitingReady is returned when the deployments didn't become
+// available within their progress deadline
+type TimeoutWaitingReady struct{ Err error }
+
+func (e *TimeoutWaitingReady) Error() string { return e.Err.Error() }
+func (e *TimeoutWaitingReady) Unwrap() error { return e.Err }
+
+// Reason implements reasoned
+func (e *TimeoutWaitingReady) Reason() string { return v1alpha1.ReasonTimeoutWaitingReady }
+
+// ApplyFailed is returned when payload resources couldn't be applied or
+// deleted, other than because of a conflict
+type ApplyFailed struct{ Err error }
+
+func (e *ApplyFailed) Error() string { return e.Err.Error() }
+func (e *ApplyFailed) Unwrap() error { return e.Err }
+
+// Reason implements reasoned
+func (e *ApplyFailed) Reason() string { return v1alpha1.ReasonApplyFailed }
+
+// ResourceIgnored is returned for an instance the operator doesn't
+// reconcile, e.g. a singleton with an unexpected name
+type ResourceIgnored struct{ Err error }
+
+func (e *ResourceIgnored) Error() string { return e.Err.Error() }
+func (e *ResourceIgnored) Unwrap() error { return e.Err }
+
+// Reason implements reasoned
+func (e *ResourceIgnored) Reason() string { return v1alpha1.ReasonResourceIgnored }
+
+// InvalidPayload is returned when payload resources are invalid, e.g. don't
+// match the schemas of their CRDs
+type InvalidPayload struct{ Err error }
+
+func (e *InvalidPayload) Error() string { return e.Err.Error() }
+func (e *InvalidPayload) Unwrap() error { return e.Err }
+
+// Reason implements reasoned
+func (e *InvalidPayload) Reason() string { return v1alpha1.ReasonInvalidPayload }
+
+// ResourceTooLarge is returned when payload resources exceed the size etcd
+// accepts
+type ResourceTooLarge struct{ Err error }
+
+func (e *ResourceTooLarge) Error() string { return e.Err.Error() }
+func (e *ResourceTooLarge) Unwrap() error { return e.Err }
+
+// Reason implements reasoned
+func (e *ResourceTooLarge) Reason() string { return v1alpha1.ReasonResourceTooLarge }
+
+// ImagePinFailed is returned when the digest of payload images couldn't be
+// resolved to pin them
+type ImagePinFailed struct{ Err error }
+
+func (e *ImagePinFailed) Error() string { return e.Err.Error() }
+func (e *ImagePinFailed) Unwrap() error { return e.Err }
+
+// Reason implements reasoned
+func (e *ImagePinFailed) Reason() string { return v1alpha1.ReasonImagePinFailed }
+
+// ImagePullPrecheckFailed is returned when payload images can't be pulled
+// from their registry
+type ImagePullPrecheckFailed struct{ Err error }
+
+func (e *ImagePullPrecheckFailed) Error() string { return e.Err.Error() }
+func (e *ImagePullPrecheckFailed) Unwrap() error { return e.Err }
+
+// Reason implements reasoned
+func (e *ImagePullPrecheckFailed) Reason() string {
+	return v1alpha1.ReasonImagePullPrecheckFailed
+}
+
+// SignatureVerificationFailed is returned when payload images have no
+// valid signature made with the configured keys
+type SignatureVerificationFailed struct{ Err error }
+
+func (e *SignatureVerificationFailed) Error() string { return e.Err.Error() }
+func (e *SignatureVerificationFailed) Unwrap() error { return e.Err }
+
+// Reason implements reasoned
+func (e *SignatureVerificationFailed) Reason() string {
+	return v1alpha1.ReasonSignatureVerificationFailed
+}
+
+// reasoned errors know the reason of the condition they mark false
+type reasoned interface {
+	error
+	Reason() string
+}
+
+// Reason returns the condition reason of the first typed error in the chain
+// of err, or in the errors it aggregates, ReasonError if there is none.
+func Reason(err error) string {
+	var r reasoned
+	if errors.As(err, &r) {
+		return r.Reason()
+	}
+	var agg utilerrors.Aggregate
+	if errors.As(err, &agg) {
+		for _, err := range agg.Errors() {
+			if reason := Reason(err); reason != v1alpha1.ReasonError {
+				return reason
+			}
+		}
+	}
+	return v1alpha1.ReasonError
+}
+
+// MarkFailed marks the condition of the status err belongs to as false,
+// with the reason of err: DependenciesInstalled for missing dependencies,
+// DeploymentsAvailable for timeouts and InstallSucceeded otherwise.
+func MarkFailed(status v1alpha1.TektonComponentStatus, err error) {
+	switch reason := Reason(err); reason {
+	case v1alpha1.ReasonDependencyMissing:
+		status.MarkDependencyMissing(err.Error())
+	case v1alpha1.ReasonTimeoutWaitingReady:
+		status.MarkDeploymentsTimedOut(err.Error())
+	default:
+		status.MarkInstallFailedWithReason(reason, err.Error())
+	}
+}
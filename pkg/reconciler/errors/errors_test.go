@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"knative.dev/pkg/apis"
+)
+
+func TestMarkFailed(t *testing.T) {
+	cause := errors.New("boom")
+	tests := []struct {
+		name      string
+		err       error
+		condition apis.ConditionType
+		reason    string
+	}{{
+		name:      "untyped",
+		err:       cause,
+		condition: v1alpha1.InstallSucceeded,
+		reason:    v1alpha1.ReasonError,
+	}, {
+		name:      "transform",
+		err:       &TransformError{Err: cause},
+		condition: v1alpha1.InstallSucceeded,
+		reason:    v1alpha1.ReasonTransformError,
+	}, {
+		name:      "wrapped apply conflict",
+		err:       fmt.Errorf("failed to apply: %w", &ApplyConflict{Err: cause}),
+		condition: v1alpha1.InstallSucceeded,
+		reason:    v1alpha1.ReasonApplyConflict,
+	}, {
+		name:      "apply failed",
+		err:       &ApplyFailed{Err: cause},
+		condition: v1alpha1.InstallSucceeded,
+		reason:    v1alpha1.ReasonApplyFailed,
+	}, {
+		name:      "aggregated",
+		err:       utilerrors.NewAggregate([]error{errors.New("other"), &ResourceTooLarge{Err: cause}}),
+		condition: v1alpha1.InstallSucceeded,
+		reason:    v1alpha1.ReasonResourceTooLarge,
+	}, {
+		name:      "dependency missing",
+		err:       &DependencyMissing{Err: cause},
+		condition: v1alpha1.DependenciesInstalled,
+		reason:    v1alpha1.ReasonDependencyMissing,
+	}, {
+		name:      "timeout",
+		err:       &TimeoutWaitingReady{Err: cause},
+		condition: v1alpha1.DeploymentsAvailable,
+		reason:    v1alpha1.ReasonTimeoutWaitingReady,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status := &v1alpha1.TektonPipelineStatus{}
+			status.InitializeConditions()
+			MarkFailed(status, test.err)
+			condition := status.GetCondition(test.condition)
+			util.AssertEqual(t, condition.IsFalse(), true)
+			util.AssertEqual(t, condition.Reason, test.reason)
+			util.AssertEqual(t, errors.Is(test.err, cause), true)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manualapprovalgate
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestTransform(t *testing.T) {
+	os.Setenv(common.KoEnvKey, "../../../../cmd/kubernetes/kodata")
+	defer os.Unsetenv(common.KoEnvKey)
+
+	instance := &v1alpha1.ManualApprovalGate{
+		ObjectMeta: metav1.ObjectMeta{Name: common.MAGResourceName},
+		Spec: v1alpha1.ManualApprovalGateSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "tekton-approvals"},
+			Images:     map[string]string{"manual-approval-gate-webhook": "registry.example.com/mag/webhook:v0.1.0"},
+		},
+	}
+	manifest, err := common.TargetManifest(instance)
+	util.AssertNoError(t, err)
+	r := &Reconciler{extension: common.NoExtension(context.Background()), images: common.NewImageStore()}
+	util.AssertNoError(t, r.transform(context.Background(), &manifest, instance))
+
+	for _, u := range manifest.Resources() {
+		if u.GetNamespace() == "" {
+			continue
+		}
+		util.AssertEqual(t, u.GetNamespace(), "tekton-approvals")
+		if refs := u.GetOwnerReferences(); len(refs) != 1 || refs[0].Kind != v1alpha1.KindManualApprovalGate {
+			t.Errorf("%s %s is not owned by the ManualApprovalGate: %v", u.GetKind(), u.GetName(), refs)
+		}
+	}
+
+	images := map[string]string{}
+	for _, u := range manifest.Filter(mf.ByKind("Deployment")).Resources() {
+		deployment := &appsv1.Deployment{}
+		util.AssertNoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, deployment))
+		for _, c := range deployment.Spec.Template.Spec.Containers {
+			images[c.Name] = c.Image
+		}
+	}
+	util.AssertDeepEqual(t, images, map[string]string{
+		"manual-approval-gate-controller": "ghcr.io/openshift-pipelines/manual-approval-gate/controller:v0.1.0",
+		"manual-approval-gate-webhook":    "registry.example.com/mag/webhook:v0.1.0",
+	})
+
+	for _, u := range manifest.Filter(mf.ByKind("ValidatingWebhookConfiguration")).Resources() {
+		webhooks, _, err := unstructured.NestedSlice(u.Object, "webhooks")
+		util.AssertNoError(t, err)
+		namespace, _, _ := unstructured.NestedString(webhooks[0].(map[string]interface{}), "clientConfig", "service", "namespace")
+		util.AssertEqual(t, namespace, "tekton-approvals")
+	}
+}
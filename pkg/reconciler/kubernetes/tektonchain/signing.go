@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonchain
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// chainsConfig is the ConfigMap of the payload spec.chainsConfig is
+	// written into
+	chainsConfig = "chains-config"
+	// signingSecretsName is the Secret of the payload the chains
+	// controller reads its signing keys from
+	signingSecretsName = "signing-secrets"
+)
+
+// signingSecrets returns a Stage filling the signing-secrets Secret of the
+// payload with the data of spec.signingSecret, read from the operator
+// namespace. Without spec.signingSecret, a signing-secrets Secret already
+// installed is left out of the manifest, so that the keys written into it,
+// e.g. by cosign generate-key-pair, aren't wiped by the empty one of the
+// payload.
+func signingSecrets(kubeClient kubernetes.Interface) common.Stage {
+	return func(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+		instance := comp.(*v1alpha1.TektonChain)
+		if instance.Spec.SigningSecret == "" {
+			return keepInstalledSecret(manifest)
+		}
+		source, err := kubeClient.CoreV1().Secrets(system.Namespace()).Get(ctx, instance.Spec.SigningSecret, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			err := &operrors.DependencyMissing{Err: fmt.Errorf("signing secret %s/%s not found", system.Namespace(), instance.Spec.SigningSecret)}
+			operrors.MarkFailed(comp.GetStatus(), err)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		return fillSigningSecret(manifest, source.Data)
+	}
+}
+
+// keepInstalledSecret drops the signing-secrets Secret from the manifest
+// once it is installed.
+func keepInstalledSecret(manifest *mf.Manifest) error {
+	isSigningSecret := mf.All(mf.ByKind("Secret"), mf.ByName(signingSecretsName))
+	secrets := manifest.Filter(isSigningSecret).Resources()
+	if len(secrets) == 0 {
+		return nil
+	}
+	_, err := manifest.Client.Get(&secrets[0])
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	*manifest = manifest.Filter(mf.Not(isSigningSecret))
+	return nil
+}
+
+// fillSigningSecret replaces the data of the signing-secrets Secret of the
+// manifest.
+func fillSigningSecret(manifest *mf.Manifest, data map[string][]byte) error {
+	encoded := map[string]interface{}{}
+	for k, v := range data {
+		encoded[k] = base64.StdEncoding.EncodeToString(v)
+	}
+	transformed, err := manifest.Transform(func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Secret" || u.GetName() != signingSecretsName {
+			return nil
+		}
+		return unstructured.SetNestedMap(u.Object, encoded, "data")
+	})
+	if err != nil {
+		return err
+	}
+	*manifest = transformed
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonchain
+
+import (
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// secretClient serves the Get of the secrets it holds
+type secretClient struct {
+	mf.Client
+	secrets map[string]map[string]interface{}
+}
+
+func (c *secretClient) Get(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	data, ok := c.secrets[u.GetName()]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, u.GetName())
+	}
+	live := u.DeepCopy()
+	live.Object["data"] = data
+	return live, nil
+}
+
+func payload(t *testing.T, client mf.Client) mf.Manifest {
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: signingSecretsName, Namespace: "tekton-chains"},
+	}
+	configMap := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: chainsConfig, Namespace: "tekton-chains"},
+	}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
+		util.MakeUnstructured(t, secret),
+		util.MakeUnstructured(t, configMap),
+	}), mf.UseClient(client))
+	util.AssertNoError(t, err)
+	return manifest
+}
+
+func TestKeepInstalledSecret(t *testing.T) {
+	manifest := payload(t, &secretClient{})
+	util.AssertNoError(t, keepInstalledSecret(&manifest))
+	util.AssertEqual(t, len(manifest.Resources()), 2)
+
+	manifest = payload(t, &secretClient{secrets: map[string]map[string]interface{}{
+		signingSecretsName: {"cosign.key": "a2V5"},
+	}})
+	util.AssertNoError(t, keepInstalledSecret(&manifest))
+	util.AssertEqual(t, len(manifest.Resources()), 1)
+	util.AssertEqual(t, manifest.Resources()[0].GetKind(), "ConfigMap")
+}
+
+func TestFillSigningSecret(t *testing.T) {
+	manifest := payload(t, &secretClient{})
+	util.AssertNoError(t, fillSigningSecret(&manifest, map[string][]byte{
+		"cosign.key":      []byte("key"),
+		"cosign.password": []byte("password"),
+	}))
+	secret := manifest.Filter(mf.ByKind("Secret")).Resources()[0]
+	data, _, _ := unstructured.NestedStringMap(secret.Object, "data")
+	util.AssertDeepEqual(t, data, map[string]string{
+		"cosign.key":      "a2V5",
+		"cosign.password": "cGFzc3dvcmQ=",
+	})
+	configMap := manifest.Filter(mf.ByKind("ConfigMap")).Resources()[0]
+	_, found, _ := unstructured.NestedMap(configMap.Object, "data")
+	util.AssertEqual(t, found, false)
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	mf "github.com/manifestival/manifestival"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	clientset "github.com/tektoncd/operator/pkg/client/clientset/versioned"
+	pipelineinformer "github.com/tektoncd/operator/pkg/client/informers/externalversions/operator/v1alpha1"
+	tektonchainreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektonchain"
+	operatorlisters "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+// Reconciler implements controller.Reconciler for TektonChain resources.
+type Reconciler struct {
+	// kubeClientSet allows us to talk to the k8s for core APIs
+	kubeClientSet kubernetes.Interface
+	// operatorClientSet allows us to configure operator objects
+	operatorClientSet clientset.Interface
+	// manifest is empty, but with a valid client and logger. all
+	// manifests are immutable, and any created during reconcile are
+	// expected to be appended to this one, obviating the passing of
+	// client & logger
+	manifest mf.Manifest
+	// Platform-specific behavior to affect the transform
+	extension common.Extension
+	// images holds the IMAGE_CHAINS_ overrides of the operator
+	images *common.ImageStore
+	// overrideLister lists the TektonOverrides layered on top of the spec
+	overrideLister operatorlisters.TektonOverrideLister
+	// chainLister gets the latest generation of the TektonChain being reconciled
+	chainLister operatorlisters.TektonChainLister
+
+	pipelineInformer pipelineinformer.TektonPipelineInformer
+}
+
+// Check that our Reconciler implements controller.Reconciler
+var _ tektonchainreconciler.Interface = (*Reconciler)(nil)
+var _ tektonchainreconciler.Finalizer = (*Reconciler)(nil)
+
+// FinalizeKind removes all resources after deletion of a TektonChain.
+func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.TektonChain) pkgreconciler.Event {
+	ctx = common.WithCorrelationID(ctx)
+	logger := logging.FromContext(ctx)
+
+	// List all TektonChains to determine if cluster-scoped resources should be deleted.
+	tcs, err := r.operatorClientSet.OperatorV1alpha1().TektonChains().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list all TektonChains: %w", err)
+	}
+
+	for _, tc := range tcs.Items {
+		if tc.GetDeletionTimestamp().IsZero() {
+			// Not deleting all TektonChains. Nothing to do here.
+			return nil
+		}
+	}
+
+	if err := r.extension.Finalize(ctx, original); err != nil {
+		logger.Error("Failed to finalize platform resources", err)
+	}
+	logger.Info("Deleting cluster-scoped resources")
+	manifest, err := r.installed(ctx, original)
+	if err != nil {
+		logger.Error("Unable to fetch installed manifest; no cluster-scoped resources will be finalized", err)
+		return nil
+	}
+	if _, err := common.TargetCluster(ctx, r.kubeClientSet, manifest, original); err != nil {
+		logger.Error("Unable to reach the target cluster; no cluster-scoped resources will be finalized", err)
+		return nil
+	}
+	if err := common.Uninstall(ctx, manifest); err != nil {
+		logger.Error("Failed to finalize platform resources", err)
+	}
+	return nil
+}
+
+// ReconcileKind compares the actual state with the desired, and attempts to
+// converge the two.
+func (r *Reconciler) ReconcileKind(ctx context.Context, tc *v1alpha1.TektonChain) pkgreconciler.Event {
+	ctx = common.WithCorrelationID(ctx)
+	logger := logging.FromContext(ctx)
+	common.InitializeStatus(tc)
+
+	logger.Infow("Reconciling TektonChains", "status", tc.Status)
+
+	if tc.GetName() != common.ChainResourceName {
+		msg := fmt.Sprintf("Resource ignored, Expected Name: %s, Got Name: %s",
+			common.ChainResourceName,
+			tc.GetName(),
+		)
+		logger.Error(msg)
+		operrors.MarkFailed(tc.GetStatus(), &operrors.ResourceIgnored{Err: errors.New(msg)})
+		return common.ObserveGeneration(ctx, tc, nil)
+	}
+
+	overrides, err := common.ComponentOverrides(r.overrideLister, v1alpha1.KindTektonChain)
+	if err != nil {
+		return err
+	}
+	ctx = common.WithOverrides(ctx, overrides)
+	ctx = common.WithInstallerSets(ctx, r.operatorClientSet.OperatorV1alpha1().TektonInstallerSets())
+	ctx = common.WithLatestGeneration(ctx, func(name string) (int64, error) {
+		latest, err := r.chainLister.Get(name)
+		if err != nil {
+			return 0, err
+		}
+		return latest.Generation, nil
+	})
+
+	// find the valid tekton-pipeline installation
+	if _, err := common.PipelineReady(r.pipelineInformer); err != nil {
+		if err.Error() == common.PipelineNotReady {
+			tc.Status.MarkDependencyInstalling("tekton-pipelines is still installing")
+			// wait for pipeline status to change
+			return fmt.Errorf(common.PipelineNotReady)
+		}
+		// (tektonpipeline.opeator.tekton.dev instance not available yet)
+		tc.Status.MarkDependencyMissing("tekton-pipelines does not exist")
+		return err
+	}
+	tc.Status.MarkDependenciesInstalled()
+
+	if err := r.extension.PreReconcile(ctx, tc); err != nil {
+		return err
+	}
+	manifest := r.manifest.Append()
+	if _, err := common.TargetCluster(ctx, r.kubeClientSet, &manifest, tc); err != nil {
+		operrors.MarkFailed(&tc.Status, err)
+		return err
+	}
+	stages := common.Stages{
+		common.ReportDeprecations(common.DeprecatedDisruptionFields),
+		common.BlockDowngrades,
+		common.AppendTarget,
+		r.transform,
+		common.NetworkPolicies,
+		common.PodDisruptionBudgets,
+		common.HorizontalPodAutoscalers,
+		signingSecrets(r.kubeClientSet),
+		common.DebugBundle(r.kubeClientSet),
+		common.PinImageDigests,
+		common.VerifyImageSignatures,
+		common.PrecheckImagePulls,
+		common.ValidateCustomResources,
+		common.CheckResourceSizes,
+		common.SkipUnchanged(common.Install),
+		common.RecordAPIVersions,
+		common.CheckDeployments,
+	}
+	return common.ObserveGeneration(ctx, tc, stages.Execute(ctx, &manifest, tc))
+}
+
+// transform mutates the passed manifest to one with common, component
+// and platform transformations applied
+func (r *Reconciler) transform(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	instance := comp.(*v1alpha1.TektonChain)
+	images := common.ToLowerCaseKeys(r.images.Images(common.ChainsImagePrefix))
+	extra := append(r.extension.Transformers(instance),
+		common.ConfigMapData(map[string]map[string]string{chainsConfig: instance.Spec.ChainsConfig}),
+		common.WorkloadImages(images))
+	// spec.images come after extra to take precedence over the operator's images
+	return common.TransformWith(ctx, manifest, instance, common.TransformOptions{
+		Transformers: extra,
+		Images:       instance.Spec.Images,
+	})
+}
+
+func (r *Reconciler) installed(ctx context.Context, instance v1alpha1.TektonComponent) (*mf.Manifest, error) {
+	// Create new, empty manifest with valid client and logger
+	installed := r.manifest.Append()
+	stages := common.Stages{common.AppendInstalled, r.transform, common.NetworkPolicies, common.PodDisruptionBudgets, common.HorizontalPodAutoscalers}
+	err := stages.Execute(ctx, &installed, instance)
+	return &installed, err
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorlisters "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
+)
+
+// defaultStatusAggregation is how long the status changes of the child
+// components are batched without spec.statusAggregationSeconds
+const defaultStatusAggregation = 10 * time.Second
+
+// statusAggregation returns how long the status changes of the child
+// components are batched before the TektonConfig reconciles
+func statusAggregation(tc *v1alpha1.TektonConfig) time.Duration {
+	if tc.Spec.StatusAggregationSeconds == nil {
+		return defaultStatusAggregation
+	}
+	return time.Duration(*tc.Spec.StatusAggregationSeconds) * time.Second
+}
+
+// readyChanged returns true if the Ready condition of the child component,
+// or the generation it observed, changed, the progress of its other fields
+// not mattering to the TektonConfig
+func readyChanged(old, new interface{}) bool {
+	if observedGeneration(old) != observedGeneration(new) {
+		return true
+	}
+	oldReady, newReady := readyCondition(old), readyCondition(new)
+	if oldReady == nil || newReady == nil {
+		return oldReady != newReady
+	}
+	return oldReady.Status != newReady.Status || oldReady.Reason != newReady.Reason || oldReady.Message != newReady.Message
+}
+
+// readyCondition returns the Ready condition of the child component, nil
+// if it has none
+func readyCondition(obj interface{}) *apis.Condition {
+	comp, ok := obj.(v1alpha1.TektonComponent)
+	if !ok {
+		return nil
+	}
+	status, ok := comp.GetStatus().(interface {
+		GetCondition(apis.ConditionType) *apis.Condition
+	})
+	if !ok {
+		return nil
+	}
+	return status.GetCondition(apis.ConditionReady)
+}
+
+// observedGeneration returns the generation of its spec the child component
+// installed last, a child being ready only once it installed the latest
+func observedGeneration(obj interface{}) int64 {
+	comp, ok := obj.(v1alpha1.TektonComponent)
+	if !ok {
+		return 0
+	}
+	return comp.GetStatus().GetObservedGeneration()
+}
+
+// enqueueOnChild returns the event handler of the child components,
+// enqueuing the TektonConfig once they come and go or their readiness
+// changes. The changes within the aggregation interval are reconciled
+// together, the work queue holding a single delayed key, so that children
+// churning through conditions don't cause a status update each.
+func enqueueOnChild(enqueueKeyAfter func(types.NamespacedName, time.Duration), lister operatorlisters.TektonConfigLister) cache.ResourceEventHandler {
+	enqueue := func(interface{}) {
+		tc, err := lister.Get(common.ConfigResourceName)
+		if err != nil {
+			return
+		}
+		enqueueKeyAfter(types.NamespacedName{Name: common.ConfigResourceName}, statusAggregation(tc))
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			if readyChanged(old, new) {
+				enqueue(new)
+			}
+		},
+		DeleteFunc: enqueue,
+	}
+}
+
+// childAPIVersions returns the API versions the CRDs of the child
+// components serve, which the TektonConfig reports in turn. The children
+// which don't exist, e.g. the ones of the other platform, are skipped.
+func (r *Reconciler) childAPIVersions(ctx context.Context) []string {
+	client := r.operatorClientSet.OperatorV1alpha1()
+	children := []func() (v1alpha1.TektonComponent, error){
+		func() (v1alpha1.TektonComponent, error) {
+			return client.TektonPipelines().Get(ctx, common.PipelineResourceName, metav1.GetOptions{})
+		},
+		func() (v1alpha1.TektonComponent, error) {
+			return client.TektonTriggers().Get(ctx, common.TriggerResourceName, metav1.GetOptions{})
+		},
+		func() (v1alpha1.TektonComponent, error) {
+			return client.TektonDashboards().Get(ctx, common.DashboardResourceName, metav1.GetOptions{})
+		},
+		func() (v1alpha1.TektonComponent, error) {
+			return client.TektonChains().Get(ctx, common.ChainResourceName, metav1.GetOptions{})
+		},
+		func() (v1alpha1.TektonComponent, error) {
+			return client.OpenShiftPipelinesAsCodes().Get(ctx, common.PACResourceName, metav1.GetOptions{})
+		},
+	}
+	versions := sets.NewString()
+	for _, get := range children {
+		if child, err := get(); err == nil {
+			versions.Insert(child.GetStatus().GetAPIVersions()...)
+		}
+	}
+	if versions.Len() == 0 {
+		return nil
+	}
+	return versions.List()
+}
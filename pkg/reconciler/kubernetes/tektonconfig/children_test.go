@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	operatorlisters "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestEnqueueOnChild(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	tc := &v1alpha1.TektonConfig{ObjectMeta: metav1.ObjectMeta{Name: common.ConfigResourceName}}
+	util.AssertNoError(t, indexer.Add(tc))
+
+	var delays []time.Duration
+	handler := enqueueOnChild(func(key types.NamespacedName, after time.Duration) {
+		util.AssertEqual(t, key.Name, common.ConfigResourceName)
+		delays = append(delays, after)
+	}, operatorlisters.NewTektonConfigLister(indexer))
+
+	installing := &v1alpha1.TektonPipeline{}
+	installing.Status.InitializeConditions()
+	progressed := installing.DeepCopy()
+	progressed.Status.SetVersion("0.19.0")
+	failed := installing.DeepCopy()
+	failed.Status.MarkInstallFailed("webhook unreachable")
+
+	handler.OnAdd(installing)
+	// the progress of other fields than the Ready condition is ignored
+	handler.OnUpdate(installing, progressed)
+	handler.OnUpdate(progressed, failed)
+	util.AssertDeepEqual(t, delays, []time.Duration{defaultStatusAggregation, defaultStatusAggregation})
+	// but for the generation the child observed, its readiness depending on it
+	observed := failed.DeepCopy()
+	observed.Status.SetObservedGeneration(2)
+	handler.OnUpdate(failed, observed)
+	util.AssertEqual(t, len(delays), 3)
+
+	zero := int64(0)
+	tc.Spec.StatusAggregationSeconds = &zero
+	util.AssertNoError(t, indexer.Update(tc))
+	handler.OnDelete(failed)
+	util.AssertEqual(t, delays[3], time.Duration(0))
+}
+
+func TestStatusAggregation(t *testing.T) {
+	tc := &v1alpha1.TektonConfig{}
+	util.AssertEqual(t, statusAggregation(tc), 10*time.Second)
+	seconds := int64(30)
+	tc.Spec.StatusAggregationSeconds = &seconds
+	util.AssertEqual(t, statusAggregation(tc), 30*time.Second)
+}
+
+func TestChildAPIVersions(t *testing.T) {
+	r := &Reconciler{operatorClientSet: fake.NewSimpleClientset(
+		&v1alpha1.TektonPipeline{
+			ObjectMeta: metav1.ObjectMeta{Name: common.PipelineResourceName},
+			Status:     v1alpha1.TektonPipelineStatus{APIVersions: []string{"tekton.dev/v1", "tekton.dev/v1beta1"}},
+		},
+		&v1alpha1.TektonTrigger{
+			ObjectMeta: metav1.ObjectMeta{Name: common.TriggerResourceName},
+			Status:     v1alpha1.TektonTriggerStatus{APIVersions: []string{"triggers.tekton.dev/v1beta1", "tekton.dev/v1"}},
+		},
+	)}
+	util.AssertDeepEqual(t, r.childAPIVersions(context.Background()),
+		[]string{"tekton.dev/v1", "tekton.dev/v1beta1", "triggers.tekton.dev/v1beta1"})
+
+	r = &Reconciler{operatorClientSet: fake.NewSimpleClientset()}
+	util.AssertEqual(t, len(r.childAPIVersions(context.Background())), 0)
+}
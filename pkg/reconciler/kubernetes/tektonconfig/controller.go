@@ -22,15 +22,22 @@ import (
 	mfc "github.com/manifestival/client-go-client"
 	mf "github.com/manifestival/manifestival"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	operatorclient "github.com/tektoncd/operator/pkg/client/injection/client"
+	tektonChaininformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonchain"
 	tektonConfiginformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonconfig"
+	tektonDashboardinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektondashboard"
+	tektonInstallerSetinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektoninstallerset"
+	tektonPipelineinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonpipeline"
+	tektonTriggerinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektontrigger"
 	tektonConfigreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektonconfig"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
@@ -61,11 +68,15 @@ func NewExtendedController(generator common.ExtensionGenerator) injection.Contro
 			logger.Fatalw("Error creating initial manifest", zap.Error(err))
 		}
 
+		images := common.NewImageStore()
+		namespaceInformer := namespaceinformer.Get(ctx)
 		c := &Reconciler{
 			kubeClientSet:     kubeClient,
 			operatorClientSet: operatorclient.Get(ctx),
 			extension:         generator(ctx),
 			manifest:          manifest,
+			images:            images,
+			namespaceLister:   namespaceInformer.Lister(),
 		}
 		impl := tektonConfigreconciler.NewImpl(ctx, c)
 
@@ -78,6 +89,49 @@ func NewExtendedController(generator common.ExtensionGenerator) injection.Contro
 			Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 		})
 
+		// Reconcile the TektonConfig once the installer set of the pruner
+		// is applied
+		tektonInstallerSetinformer.Get(ctx).Informer().AddEventHandler(common.EnqueueOnInstallerSet(impl.EnqueueControllerOf, v1alpha1.KindTektonConfig))
+
+		// Reconcile the TektonConfig when the readiness of a child component
+		// changes, to report it and record its failures as events on the
+		// TektonConfig.
+		childHandler := enqueueOnChild(impl.EnqueueKeyAfter, tektonConfigInformer.Lister())
+		tektonPipelineinformer.Get(ctx).Informer().AddEventHandler(childHandler)
+		tektonTriggerinformer.Get(ctx).Informer().AddEventHandler(childHandler)
+		tektonDashboardinformer.Get(ctx).Informer().AddEventHandler(childHandler)
+		tektonChaininformer.Get(ctx).Informer().AddEventHandler(childHandler)
+
+		// Reconcile the TektonConfig when namespaces come and go, or change
+		// their prune annotations, for the pruner to prune them accordingly.
+		namespaceInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: func(interface{}) bool {
+				tc, err := tektonConfigInformer.Lister().Get(common.ConfigResourceName)
+				return err == nil && tc.Spec.Pruner != nil
+			},
+			Handler: cache.ResourceEventHandlerFuncs{
+				AddFunc: enqueueConfig(impl),
+				UpdateFunc: func(old, new interface{}) {
+					if pruneAnnotationsChanged(old, new) {
+						enqueueConfig(impl)(new)
+					}
+				},
+				DeleteFunc: enqueueConfig(impl),
+			},
+		})
+
+		resyncImages := func() {
+			impl.GlobalResync(tektonConfigInformer.Informer())
+		}
+		common.WatchImages(cmw, images, resyncImages)
+		common.WatchImagesSecret(ctx, kubeClient, images, resyncImages)
+
 		return impl
 	}
 }
+
+func enqueueConfig(impl *controller.Impl) func(interface{}) {
+	return func(interface{}) {
+		impl.EnqueueKey(types.NamespacedName{Name: common.ConfigResourceName})
+	}
+}
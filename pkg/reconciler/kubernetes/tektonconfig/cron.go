@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonconfig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField is a field of a cron schedule, its range and the names its
+// values may be given by
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+// cronFields are the fields of the schedule of a CronJob, in order
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{name: "day of week", min: 0, max: 6, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+// cronDescriptors are the predefined schedules the CronJob controller accepts
+var cronDescriptors = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// parseSchedule validates the schedule of a CronJob the way the CronJob
+// controller parses it: five fields of values, ranges, lists and steps, or
+// a predefined schedule, e.g. @daily, or @every with a duration.
+func parseSchedule(schedule string) error {
+	schedule = strings.TrimSpace(schedule)
+	if strings.HasPrefix(schedule, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(schedule, "@every ")))
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid duration of @every")
+		}
+		return nil
+	}
+	if strings.HasPrefix(schedule, "@") {
+		if !cronDescriptors[schedule] {
+			return fmt.Errorf("unknown descriptor %s", schedule)
+		}
+		return nil
+	}
+	fields := strings.Fields(schedule)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("expected %d fields, got %d", len(cronFields), len(fields))
+	}
+	for i, field := range fields {
+		if err := cronFields[i].parse(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parse validates the comma separated list of ranges of the field.
+func (f cronField) parse(value string) error {
+	for _, expr := range strings.Split(value, ",") {
+		if err := f.parseRange(expr); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", f.name, value, err)
+		}
+	}
+	return nil
+}
+
+// parseRange validates a range of the field: *, ?, a value or two
+// separated by -, followed by a step.
+func (f cronField) parseRange(expr string) error {
+	parts := strings.SplitN(expr, "/", 2)
+	if len(parts) == 2 {
+		n, err := strconv.Atoi(parts[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid step %q", parts[1])
+		}
+	}
+	if parts[0] == "*" || parts[0] == "?" {
+		return nil
+	}
+	bounds := strings.SplitN(parts[0], "-", 2)
+	start, err := f.value(bounds[0])
+	if err != nil {
+		return err
+	}
+	if len(bounds) == 1 {
+		return nil
+	}
+	end, err := f.value(bounds[1])
+	if err != nil {
+		return err
+	}
+	if start > end {
+		return fmt.Errorf("range %s ends before it starts", parts[0])
+	}
+	return nil
+}
+
+// value returns the value of the field, given by number or by name.
+func (f cronField) value(s string) (int, error) {
+	if n, ok := f.names[strings.ToLower(s)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", n, f.min, f.max)
+	}
+	return n, nil
+}
@@ -45,13 +45,31 @@ func (oe kubernetesExtension) PreReconcile(context.Context, v1alpha1.TektonCompo
 }
 func (oe kubernetesExtension) PostReconcile(ctx context.Context, comp v1alpha1.TektonComponent) error {
 	configInstance := comp.(*v1alpha1.TektonConfig)
+	client := oe.operatorClientSet.OperatorV1alpha1()
 	if configInstance.Spec.Profile == common.ProfileAll {
-		return extension.CreateDashboardCR(comp, oe.operatorClientSet.OperatorV1alpha1())
+		if err := extension.CreateDashboardCR(comp, client); err != nil {
+			if td, getErr := extension.GetDashboard(client.TektonDashboards(), common.DashboardResourceName); getErr == nil {
+				common.RecordComponentFailures(ctx, configInstance, "TektonDashboard", td.Status.Conditions)
+			}
+			return err
+		}
+	}
+	if configInstance.Spec.Chain == nil {
+		return extension.TektonChainCRDelete(client.TektonChains(), common.ChainResourceName)
+	}
+	if err := extension.CreateChainCR(comp, client); err != nil {
+		if tc, getErr := extension.GetChain(client.TektonChains(), common.ChainResourceName); getErr == nil {
+			common.RecordComponentFailures(ctx, configInstance, "TektonChain", tc.Status.Conditions)
+		}
+		return err
 	}
 	return nil
 }
 func (oe kubernetesExtension) Finalize(ctx context.Context, comp v1alpha1.TektonComponent) error {
 	configInstance := comp.(*v1alpha1.TektonConfig)
+	if err := extension.TektonChainCRDelete(oe.operatorClientSet.OperatorV1alpha1().TektonChains(), common.ChainResourceName); err != nil {
+		return err
+	}
 	if configInstance.Spec.Profile == common.ProfileAll {
 		return extension.TektonDashboardCRDelete(oe.operatorClientSet.OperatorV1alpha1().TektonDashboards(), common.DashboardResourceName)
 	}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	op "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"knative.dev/pkg/test/logging"
+)
+
+// CreateChainCR creates the TektonChain of spec.chain, or updates its
+// settings, and waits for it to be ready.
+func CreateChainCR(instance v1alpha1.TektonComponent, client op.OperatorV1alpha1Interface) error {
+	configInstance := instance.(*v1alpha1.TektonConfig)
+	if _, err := ensureTektonChainExists(client.TektonChains(), configInstance.Spec.CommonSpec, *configInstance.Spec.Chain); err != nil {
+		return errors.New(err.Error())
+	}
+	if _, err := waitForTektonChainState(client.TektonChains(), common.ChainResourceName,
+		isTektonChainReady); err != nil {
+		log.Println("TektonChain is not in ready state: ", err)
+		return err
+	}
+	return nil
+}
+
+// ensureTektonChainExists creates the TektonChain, labelled as generated so
+// that it is only deleted once spec.chain is unset if TektonConfig created
+// it. The chain settings of an existing TektonChain follow spec.chain.
+func ensureTektonChainExists(clients op.TektonChainInterface, spec v1alpha1.CommonSpec, chain v1alpha1.ChainProperties) (*v1alpha1.TektonChain, error) {
+	tcCR, err := GetChain(clients, common.ChainResourceName)
+	if err == nil {
+		if equality.Semantic.DeepEqual(tcCR.Spec.ChainProperties, chain) {
+			return tcCR, nil
+		}
+		tcCR.Spec.ChainProperties = chain
+		return clients.Update(context.TODO(), tcCR, metav1.UpdateOptions{})
+	}
+	if apierrs.IsNotFound(err) {
+		tcCR = &v1alpha1.TektonChain{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   common.ChainResourceName,
+				Labels: map[string]string{common.LabelGenerated: "true"},
+			},
+			Spec: v1alpha1.TektonChainSpec{
+				CommonSpec:      spec,
+				ChainProperties: chain,
+			},
+		}
+		return clients.Create(context.TODO(), tcCR, metav1.CreateOptions{})
+	}
+	return tcCR, err
+}
+
+func GetChain(clients op.TektonChainInterface, name string) (*v1alpha1.TektonChain, error) {
+	return clients.Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// waitForTektonChainState polls the status of the TektonChain called name
+// from client every `interval` until `inState` returns `true` indicating it
+// is done, returns an error or timeout.
+func waitForTektonChainState(clients op.TektonChainInterface, name string,
+	inState func(s *v1alpha1.TektonChain, err error) (bool, error)) (*v1alpha1.TektonChain, error) {
+	span := logging.GetEmitableSpan(context.Background(), fmt.Sprintf("WaitForTektonChainState/%s/%s", name, "TektonChainIsReady"))
+	defer span.End()
+
+	var lastState *v1alpha1.TektonChain
+	waitErr := wait.PollImmediate(common.Interval, common.Timeout, func() (bool, error) {
+		lastState, err := clients.Get(context.TODO(), name, metav1.GetOptions{})
+		return inState(lastState, err)
+	})
+	if waitErr != nil {
+		return lastState, fmt.Errorf("TektonChain %s is not in desired state, got: %+v: %w: For more info Please check TektonChain CR status", name, lastState, waitErr)
+	}
+	return lastState, nil
+}
+
+// isTektonChainReady will check the status conditions of the TektonChain and return true if the TektonChain is ready
+// with the latest generation of its spec.
+func isTektonChainReady(s *v1alpha1.TektonChain, err error) (bool, error) {
+	return s.Status.IsReady() && s.Status.GetObservedGeneration() == s.Generation, err
+}
+
+// TektonChainCRDelete deletes the TektonChain created by TektonConfig, a
+// TektonChain created by hand being left alone
+func TektonChainCRDelete(clients op.TektonChainInterface, name string) error {
+	tcCR, err := GetChain(clients, name)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if tcCR.Labels[common.LabelGenerated] != "true" {
+		return nil
+	}
+	if err := clients.Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("TektonChain %q failed to delete: %v", name, err)
+	}
+	err = wait.PollImmediate(common.Interval, common.Timeout, func() (bool, error) {
+		_, err := clients.Get(context.TODO(), name, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("Timed out waiting on TektonChain to delete %v", err)
+	}
+	return nil
+}
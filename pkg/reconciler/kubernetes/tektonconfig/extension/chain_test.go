@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/client/injection/client/fake"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektonconfig/pipeline"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ts "knative.dev/pkg/reconciler/testing"
+)
+
+func TestEnsureTektonChainExists(t *testing.T) {
+	ctx, _, _ := ts.SetupFakeContextWithCancel(t)
+	chains := fake.Get(ctx).OperatorV1alpha1().TektonChains()
+	tConfig := pipeline.GetTektonConfig()
+
+	chain := v1alpha1.ChainProperties{ChainsConfig: map[string]string{"artifacts.taskrun.storage": "oci"}}
+	tc, err := ensureTektonChainExists(chains, tConfig.Spec.CommonSpec, chain)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, tc.Labels[common.LabelGenerated], "true")
+	util.AssertEqual(t, tc.Spec.TargetNamespace, tConfig.Spec.TargetNamespace)
+
+	chain.SigningSecret = "cosign-keys"
+	_, err = ensureTektonChainExists(chains, tConfig.Spec.CommonSpec, chain)
+	util.AssertNoError(t, err)
+	tc, err = GetChain(chains, common.ChainResourceName)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, tc.Spec.SigningSecret, "cosign-keys")
+
+	util.AssertNoError(t, TektonChainCRDelete(chains, common.ChainResourceName))
+	_, err = GetChain(chains, common.ChainResourceName)
+	util.AssertEqual(t, apierrs.IsNotFound(err), true)
+}
+
+func TestTektonChainCRDeleteKeepsUserChain(t *testing.T) {
+	ctx, _, _ := ts.SetupFakeContextWithCancel(t)
+	chains := fake.Get(ctx).OperatorV1alpha1().TektonChains()
+	util.AssertNoError(t, TektonChainCRDelete(chains, common.ChainResourceName))
+
+	_, err := chains.Create(context.TODO(), &v1alpha1.TektonChain{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ChainResourceName},
+	}, metav1.CreateOptions{})
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, TektonChainCRDelete(chains, common.ChainResourceName))
+	_, err = GetChain(chains, common.ChainResourceName)
+	util.AssertNoError(t, err)
+}
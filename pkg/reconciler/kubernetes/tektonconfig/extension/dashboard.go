@@ -34,7 +34,7 @@ import (
 
 func CreateDashboardCR(instance v1alpha1.TektonComponent, client operatorv1alpha1.OperatorV1alpha1Interface) error {
 	configInstance := instance.(*v1alpha1.TektonConfig)
-	if _, err := ensureTektonDashboardExists(client.TektonDashboards(), configInstance.Spec.TargetNamespace); err != nil {
+	if _, err := ensureTektonDashboardExists(client.TektonDashboards(), configInstance.Spec.CommonSpec); err != nil {
 		return errors.New(err.Error())
 	}
 	if _, err := waitForTektonDashboardState(client.TektonDashboards(), common.DashboardResourceName,
@@ -45,7 +45,7 @@ func CreateDashboardCR(instance v1alpha1.TektonComponent, client operatorv1alpha
 	return nil
 }
 
-func ensureTektonDashboardExists(clients op.TektonDashboardInterface, targetNS string) (*v1alpha1.TektonDashboard, error) {
+func ensureTektonDashboardExists(clients op.TektonDashboardInterface, spec v1alpha1.CommonSpec) (*v1alpha1.TektonDashboard, error) {
 	tdCR, err := GetDashboard(clients, common.DashboardResourceName)
 	if err == nil {
 		return tdCR, err
@@ -56,9 +56,7 @@ func ensureTektonDashboardExists(clients op.TektonDashboardInterface, targetNS s
 				Name: common.DashboardResourceName,
 			},
 			Spec: v1alpha1.TektonDashboardSpec{
-				CommonSpec: v1alpha1.CommonSpec{
-					TargetNamespace: targetNS,
-				},
+				CommonSpec: spec,
 			},
 		}
 		return clients.Create(context.TODO(), tdCR, metav1.CreateOptions{})
@@ -89,9 +87,10 @@ func waitForTektonDashboardState(clients op.TektonDashboardInterface, name strin
 	return lastState, nil
 }
 
-// isTektonDashboardReady will check the status conditions of the TektonDashboard and return true if the TektonDashboard is ready.
+// isTektonDashboardReady will check the status conditions of the TektonDashboard and return true if the TektonDashboard is ready
+// with the latest generation of its spec.
 func isTektonDashboardReady(s *v1alpha1.TektonDashboard, err error) (bool, error) {
-	return s.Status.IsReady(), err
+	return s.Status.IsReady() && s.Status.GetObservedGeneration() == s.Generation, err
 }
 
 // TektonDashboardCRDelete deletes tha TektonDashboard to see if all resources will be deleted
@@ -26,6 +26,8 @@ import (
 	op "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
 	operatorv1alpha1 "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -34,31 +36,38 @@ import (
 
 func CreatePipelineCR(instance v1alpha1.TektonComponent, client operatorv1alpha1.OperatorV1alpha1Interface) error {
 	configInstance := instance.(*v1alpha1.TektonConfig)
-	if _, err := ensureTektonPipelineExists(client.TektonPipelines(), configInstance.Spec.TargetNamespace); err != nil {
-		return errors.New(err.Error())
+	if _, err := ensureTektonPipelineExists(client.TektonPipelines(), configInstance); err != nil {
+		return &operrors.ApplyFailed{Err: err}
 	}
 	if _, err := waitForTektonPipelineState(client.TektonPipelines(), common.PipelineResourceName,
 		isTektonPipelineReady); err != nil {
 		log.Println("TektonPipeline is not in ready state: ", err)
-		return err
+		return &operrors.TimeoutWaitingReady{Err: err}
 	}
 	return nil
 }
 
-func ensureTektonPipelineExists(clients op.TektonPipelineInterface, targetNS string) (*v1alpha1.TektonPipeline, error) {
+// ensureTektonPipelineExists creates the TektonPipeline of the TektonConfig,
+// controlled by it, or updates its common spec to the one of the
+// TektonConfig. A TektonPipeline the TektonConfig doesn't control, e.g.
+// created by hand, is left as it is.
+func ensureTektonPipelineExists(clients op.TektonPipelineInterface, config *v1alpha1.TektonConfig) (*v1alpha1.TektonPipeline, error) {
 	tpCR, err := GetPipeline(clients, common.PipelineResourceName)
 	if err == nil {
-		return tpCR, err
+		if !metav1.IsControlledBy(tpCR, config) || equality.Semantic.DeepEqual(tpCR.Spec.CommonSpec, config.Spec.CommonSpec) {
+			return tpCR, nil
+		}
+		tpCR.Spec.CommonSpec = config.Spec.CommonSpec
+		return clients.Update(context.TODO(), tpCR, metav1.UpdateOptions{})
 	}
 	if apierrs.IsNotFound(err) {
 		tpCR = &v1alpha1.TektonPipeline{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: common.PipelineResourceName,
+				Name:            common.PipelineResourceName,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(config, config.GroupVersionKind())},
 			},
 			Spec: v1alpha1.TektonPipelineSpec{
-				CommonSpec: v1alpha1.CommonSpec{
-					TargetNamespace: targetNS,
-				},
+				CommonSpec: config.Spec.CommonSpec,
 			},
 		}
 		return clients.Create(context.TODO(), tpCR, metav1.CreateOptions{})
@@ -90,9 +99,10 @@ func waitForTektonPipelineState(clients op.TektonPipelineInterface, name string,
 	return lastState, nil
 }
 
-// IsTektonPipelineReady will check the status conditions of the TektonPipeline and return true if the TektonPipeline is ready.
+// IsTektonPipelineReady will check the status conditions of the TektonPipeline and return true if the TektonPipeline is ready
+// with the latest generation of its spec.
 func isTektonPipelineReady(s *v1alpha1.TektonPipeline, err error) (bool, error) {
-	return s.Status.IsReady(), err
+	return s.Status.IsReady() && s.Status.GetObservedGeneration() == s.Generation, err
 }
 
 // TektonPipelineCRDelete deletes tha TektonPipeline to see if all resources will be deleted
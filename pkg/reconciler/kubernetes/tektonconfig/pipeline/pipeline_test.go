@@ -17,11 +17,14 @@ limitations under the License.
 package pipeline
 
 import (
+	"context"
 	"testing"
 
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	"github.com/tektoncd/operator/pkg/client/injection/client/fake"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
 	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ts "knative.dev/pkg/reconciler/testing"
 )
 
@@ -41,3 +44,51 @@ func TestTektonPipelineCRDelete(t *testing.T) {
 	err := TektonPipelineCRDelete(c.OperatorV1alpha1().TektonPipelines(), common.PipelineResourceName)
 	util.AssertEqual(t, err, nil)
 }
+
+func TestEnsureTektonPipelineExists(t *testing.T) {
+	ctx, _, _ := ts.SetupFakeContextWithCancel(t)
+	clients := fake.Get(ctx).OperatorV1alpha1().TektonPipelines()
+	tConfig := GetTektonConfig()
+	tConfig.UID = "config-uid"
+	created, err := ensureTektonPipelineExists(clients, tConfig)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, metav1.IsControlledBy(created, tConfig), true)
+
+	// a change of the common spec reaches the TektonPipeline
+	tConfig.Spec.TargetNamespace = "tekton"
+	tConfig.Spec.PodAnnotations = map[string]string{"team": "ci"}
+	_, err = ensureTektonPipelineExists(clients, tConfig)
+	util.AssertNoError(t, err)
+	live, err := GetPipeline(clients, common.PipelineResourceName)
+	util.AssertNoError(t, err)
+	util.AssertDeepEqual(t, live.Spec.CommonSpec, tConfig.Spec.CommonSpec)
+
+	// but not one the TektonConfig doesn't control
+	live.OwnerReferences = nil
+	_, err = clients.Update(context.TODO(), live, metav1.UpdateOptions{})
+	util.AssertNoError(t, err)
+	tConfig.Spec.TargetNamespace = "tekton-pipelines"
+	_, err = ensureTektonPipelineExists(clients, tConfig)
+	util.AssertNoError(t, err)
+	live, err = GetPipeline(clients, common.PipelineResourceName)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, live.Spec.TargetNamespace, "tekton")
+}
+
+func TestIsTektonPipelineReady(t *testing.T) {
+	ready := &v1alpha1.TektonPipeline{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	ready.Status.InitializeConditions()
+	ready.Status.MarkDependenciesInstalled()
+	ready.Status.MarkInstallSucceeded()
+	ready.Status.MarkDeploymentsAvailable()
+	ready.Status.SetObservedGeneration(1)
+	// ready with a previous generation of its spec
+	isReady, err := isTektonPipelineReady(ready, nil)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, isReady, false)
+
+	ready.Status.SetObservedGeneration(2)
+	isReady, err = isTektonPipelineReady(ready, nil)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, isReady, true)
+}
@@ -0,0 +1,535 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/ptr"
+)
+
+const (
+	// prunerName names the CronJob of the pruner, and the ServiceAccount
+	// and RBAC it runs with
+	prunerName = "tekton-resource-pruner"
+	// prunerImageKey is the key of the tkn image of the pruner, under
+	// common.JobImagePrefix
+	prunerImageKey = "pruner_tkn"
+	// defaultPrunerImage is the tkn image of the pruner without override,
+	// the release of the CLI the payloads ship
+	defaultPrunerImage = "gcr.io/tekton-releases/github.com/tektoncd/cli/cmd/tkn:v0.15.0"
+	// prunerPart names the installer set of the pruner, see
+	// common.InstallPart
+	prunerPart = "pruner"
+	// prunerJobsAnnotation lists the namespaces with a CronJob of their
+	// own on the CronJob of the pruner, to delete them once stale
+	prunerJobsAnnotation = "operator.tekton.dev/prune.jobs"
+	// maxCronJobName is the longest name of a CronJob, leaving room for
+	// the suffix of the names of its jobs
+	maxCronJobName = 52
+	// prunerJobHashLength is the length of the hash of the namespaces
+	// whose names are too long for the name of their CronJob
+	prunerJobHashLength = 10
+)
+
+// The annotations of the namespaces overriding spec.pruner
+const (
+	// pruneSkipAnnotation opts the namespace out of the pruner when "true"
+	pruneSkipAnnotation = "operator.tekton.dev/prune.skip"
+	// pruneKeepAnnotation sets the number of runs kept in the namespace
+	pruneKeepAnnotation = "operator.tekton.dev/prune.keep"
+	// pruneScheduleAnnotation sets the schedule of the pruner of the
+	// namespace, in cron format, pruned by a CronJob of its own
+	pruneScheduleAnnotation = "operator.tekton.dev/prune.schedule"
+)
+
+// prunedResources are the values of spec.pruner.resources
+var prunedResources = sets.NewString(v1alpha1.PrunePipelineRuns, v1alpha1.PruneTaskRuns)
+
+// validatePruner returns an error describing the first invalid field of
+// spec.pruner, nil if it is unset.
+func validatePruner(pruner *v1alpha1.Pruner) error {
+	if pruner == nil {
+		return nil
+	}
+	if err := parseSchedule(pruner.Schedule); err != nil {
+		return fmt.Errorf("invalid spec.pruner.schedule %q: %v", pruner.Schedule, err)
+	}
+	if pruner.Keep == 0 {
+		return fmt.Errorf("invalid spec.pruner.keep 0, expected at least 1")
+	}
+	if len(pruner.Resources) == 0 {
+		return fmt.Errorf("spec.pruner.resources is empty, expected %s", strings.Join(prunedResources.List(), " or "))
+	}
+	for _, resource := range pruner.Resources {
+		if !prunedResources.Has(resource) {
+			return fmt.Errorf("invalid resource %q in spec.pruner.resources, expected %s", resource, strings.Join(prunedResources.List(), " or "))
+		}
+	}
+	return nil
+}
+
+// reconcilePruner is a Stage creating or updating the CronJobs of
+// spec.pruner, which prune the namespaces existing at the time but the
+// excluded ones, or deleting them once spec.pruner is unset. Namespaces
+// with a schedule of their own get a CronJob each, deleted once they follow
+// spec.pruner again.
+func (r *Reconciler) reconcilePruner(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	tc := comp.(*v1alpha1.TektonConfig)
+	cronJobs, err := r.cronJobAPIVersion()
+	if err != nil {
+		return err
+	}
+	scheduled, err := scheduledNamespaces(manifest, tc, cronJobs)
+	if err != nil {
+		return err
+	}
+	if tc.Spec.Pruner == nil {
+		// deleting the installer set leaves its resources behind
+		empty := manifest.Append()
+		if err := common.InstallPart(ctx, prunerPart, &empty, tc); err != nil {
+			return err
+		}
+		// only the kinds and names matter to delete them
+		pruner, err := r.prunerManifest(manifest, tc, &v1alpha1.Pruner{}, nil, cronJobs)
+		if err != nil {
+			return err
+		}
+		if err := deletePrunerJobs(manifest, tc, scheduled, cronJobs); err != nil {
+			return err
+		}
+		return pruner.Delete()
+	}
+	namespaces, err := r.prunedNamespaces(ctx, tc.Spec.ExcludedNamespaces, tc.Spec.Pruner)
+	if err != nil {
+		return err
+	}
+	pruner, err := r.prunerManifest(manifest, tc, tc.Spec.Pruner, namespaces, cronJobs)
+	if err != nil {
+		return err
+	}
+	if err := common.TransformWith(ctx, &pruner, tc, common.TransformOptions{}); err != nil {
+		return err
+	}
+	if err := common.InstallPart(ctx, prunerPart, &pruner, tc); err != nil {
+		return err
+	}
+	return deleteStalePrunerJobs(manifest, tc, scheduled, namespaces, cronJobs)
+}
+
+// cronJobAPIVersion returns batch/v1 once the cluster serves the CronJobs
+// of it, batch/v1beta1 otherwise, e.g. before Kubernetes 1.21. The answer
+// is cached, the version served changing with an upgrade of the cluster
+// only.
+func (r *Reconciler) cronJobAPIVersion() (string, error) {
+	r.cronJobMu.Lock()
+	defer r.cronJobMu.Unlock()
+	if r.cronJobVersion != "" {
+		return r.cronJobVersion, nil
+	}
+	resources, err := r.kubeClientSet.Discovery().ServerResourcesForGroupVersion("batch/v1")
+	if err != nil {
+		return "", fmt.Errorf("failed to discover batch/v1: %w", err)
+	}
+	r.cronJobVersion = "batch/v1beta1"
+	for _, resource := range resources.APIResources {
+		if resource.Name == "cronjobs" {
+			r.cronJobVersion = "batch/v1"
+		}
+	}
+	return r.cronJobVersion, nil
+}
+
+// prunedNamespace is a namespace the pruner prunes, with the keep and the
+// schedule of its annotations or of spec.pruner, or skips, e.g. opted out
+type prunedNamespace struct {
+	name     string
+	keep     uint
+	schedule string
+	skip     bool
+}
+
+// prunedNamespaces returns the namespaces which aren't excluded, by name,
+// the terminating ones and the ones opted out of the pruner marked skipped.
+// Invalid annotations are logged and ignored.
+func (r *Reconciler) prunedNamespaces(ctx context.Context, excluded []string, pruner *v1alpha1.Pruner) ([]prunedNamespace, error) {
+	logger := logging.FromContext(ctx)
+	list, err := r.namespaceLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var namespaces []prunedNamespace
+	for _, ns := range list {
+		if common.NamespaceExcluded(excluded, ns.Name) {
+			continue
+		}
+		if ns.Status.Phase == corev1.NamespaceTerminating || ns.Annotations[pruneSkipAnnotation] == "true" {
+			namespaces = append(namespaces, prunedNamespace{name: ns.Name, skip: true})
+			continue
+		}
+		pruned := prunedNamespace{name: ns.Name, keep: pruner.Keep, schedule: pruner.Schedule}
+		if value, ok := ns.Annotations[pruneKeepAnnotation]; ok {
+			keep, err := strconv.ParseUint(value, 10, 0)
+			if err == nil && keep > 0 {
+				pruned.keep = uint(keep)
+			} else {
+				logger.Warnw("Ignoring invalid annotation of namespace, expected a number of runs of at least 1",
+					"namespace", ns.Name, "annotation", pruneKeepAnnotation, "value", value)
+			}
+		}
+		if value, ok := ns.Annotations[pruneScheduleAnnotation]; ok {
+			if err := parseSchedule(value); err == nil {
+				pruned.schedule = value
+			} else {
+				logger.Warnw("Ignoring invalid annotation of namespace, expected a schedule in cron format",
+					"namespace", ns.Name, "annotation", pruneScheduleAnnotation, "value", value, "error", err)
+			}
+		}
+		namespaces = append(namespaces, pruned)
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].name < namespaces[j].name })
+	return namespaces, nil
+}
+
+// pruneAnnotationsChanged returns true if the annotations overriding
+// spec.pruner differ between the old and the new version of a namespace.
+func pruneAnnotationsChanged(old, new interface{}) bool {
+	oldNs, ok := old.(*corev1.Namespace)
+	if !ok {
+		return false
+	}
+	newNs, ok := new.(*corev1.Namespace)
+	if !ok {
+		return false
+	}
+	for _, annotation := range []string{pruneSkipAnnotation, pruneKeepAnnotation, pruneScheduleAnnotation} {
+		if oldNs.Annotations[annotation] != newNs.Annotations[annotation] {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduledNamespaces returns the namespaces with a CronJob of their own,
+// from the annotation of the live CronJob of the pruner.
+func scheduledNamespaces(manifest *mf.Manifest, tc *v1alpha1.TektonConfig, apiVersion string) ([]string, error) {
+	cronJob := &unstructured.Unstructured{}
+	cronJob.SetAPIVersion(apiVersion)
+	cronJob.SetKind("CronJob")
+	cronJob.SetNamespace(tc.Spec.GetTargetNamespace())
+	cronJob.SetName(prunerName)
+	live, err := manifest.Client.Get(cronJob)
+	if apierrors.IsNotFound(err) || (err == nil && live == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	value := live.GetAnnotations()[prunerJobsAnnotation]
+	if value == "" {
+		return nil, nil
+	}
+	return strings.Split(value, ","), nil
+}
+
+// deleteStalePrunerJobs deletes the CronJobs of the namespaces which had a
+// schedule of their own, and no longer have, right away rather than once
+// the installer set of the pruner is applied.
+func deleteStalePrunerJobs(manifest *mf.Manifest, tc *v1alpha1.TektonConfig, scheduled []string, namespaces []prunedNamespace, apiVersion string) error {
+	current := sets.NewString()
+	for _, ns := range namespaces {
+		if !ns.skip && ns.schedule != tc.Spec.Pruner.Schedule {
+			current.Insert(ns.name)
+		}
+	}
+	var stale []string
+	for _, ns := range scheduled {
+		if !current.Has(ns) {
+			stale = append(stale, ns)
+		}
+	}
+	return deletePrunerJobs(manifest, tc, stale, apiVersion)
+}
+
+// deletePrunerJobs deletes the CronJobs of the namespaces with a schedule
+// of their own.
+func deletePrunerJobs(manifest *mf.Manifest, tc *v1alpha1.TektonConfig, namespaces []string, apiVersion string) error {
+	var cronJobs []unstructured.Unstructured
+	for _, ns := range namespaces {
+		cronJob := unstructured.Unstructured{}
+		cronJob.SetAPIVersion(apiVersion)
+		cronJob.SetKind("CronJob")
+		cronJob.SetNamespace(tc.Spec.GetTargetNamespace())
+		cronJob.SetName(prunerJobName(ns))
+		cronJobs = append(cronJobs, cronJob)
+	}
+	if len(cronJobs) == 0 {
+		return nil
+	}
+	jobs, err := mf.ManifestFrom(mf.Slice(cronJobs))
+	if err != nil {
+		return err
+	}
+	return manifest.Append(jobs).Delete()
+}
+
+// prunerJobName returns the name of the CronJob of a namespace with a
+// schedule of its own, hashed when too long for the names of its jobs.
+func prunerJobName(namespace string) string {
+	name := prunerName + "-" + namespace
+	if len(name) <= maxCronJobName {
+		return name
+	}
+	hash := sha256.Sum256([]byte(namespace))
+	keep := maxCronJobName - len(prunerName) - 2 - prunerJobHashLength
+	return fmt.Sprintf("%s-%s-%s", prunerName, namespace[:keep], hex.EncodeToString(hash[:])[:prunerJobHashLength])
+}
+
+// prunerManifest returns the resources of the pruner, with the client of
+// manifest. Its CronJobs are of the given API version, batch/v1beta1 and
+// batch/v1 sharing the fields set.
+func (r *Reconciler) prunerManifest(manifest *mf.Manifest, tc *v1alpha1.TektonConfig, pruner *v1alpha1.Pruner, namespaces []prunedNamespace, cronJobs string) (mf.Manifest, error) {
+	image := common.ToLowerCaseKeys(r.images.Images(common.JobImagePrefix))[prunerImageKey]
+	if image == "" {
+		image = defaultPrunerImage
+	}
+	var resources []unstructured.Unstructured
+	for _, obj := range prunerResources(tc, pruner, namespaces, image) {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return mf.Manifest{}, err
+		}
+		resource := unstructured.Unstructured{Object: u}
+		if resource.GetKind() == "CronJob" {
+			resource.SetAPIVersion(cronJobs)
+		}
+		resources = append(resources, resource)
+	}
+	generated, err := mf.ManifestFrom(mf.Slice(resources))
+	if err != nil {
+		return mf.Manifest{}, err
+	}
+	return manifest.Append(generated), nil
+}
+
+// prunerResources returns the CronJob of the pruner in the target
+// namespace, pruning the namespaces following the schedule of spec.pruner,
+// and the CronJobs of the namespaces with a schedule of their own, with the
+// ServiceAccount and the RBAC they run with. The CronJob of the pruner
+// lists the namespaces with a CronJob of their own in an annotation.
+func prunerResources(tc *v1alpha1.TektonConfig, pruner *v1alpha1.Pruner, namespaces []prunedNamespace, image string) []runtime.Object {
+	namespace := tc.Spec.GetTargetNamespace()
+	labels := map[string]string{common.LabelGenerated: "true"}
+	var overrides []prunedNamespace
+	var skipped, scheduled []string
+	var jobs []runtime.Object
+	for _, ns := range namespaces {
+		switch {
+		case ns.skip:
+			skipped = append(skipped, ns.name)
+		case ns.schedule == pruner.Schedule:
+			if ns.keep != pruner.Keep {
+				overrides = append(overrides, ns)
+			}
+		default:
+			skipped = append(skipped, ns.name)
+			scheduled = append(scheduled, ns.name)
+			jobs = append(jobs, prunerCronJob(tc, prunerJobName(ns.name), ns.schedule, prunerScript(pruner.Resources, []prunedNamespace{ns}), image))
+		}
+	}
+	script := followersScript(pruner.Resources, pruner.Keep, overrides, skipped, tc.Spec.ExcludedNamespaces)
+	cronJob := prunerCronJob(tc, prunerName, pruner.Schedule, script, image)
+	if len(scheduled) != 0 {
+		cronJob.Annotations = map[string]string{prunerJobsAnnotation: strings.Join(scheduled, ",")}
+	}
+	return append([]runtime.Object{
+		&corev1.ServiceAccount{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+			ObjectMeta: metav1.ObjectMeta{Name: prunerName, Namespace: namespace, Labels: labels},
+		},
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: prunerName, Labels: labels},
+			Rules: []rbacv1.PolicyRule{{
+				APIGroups: []string{"tekton.dev"},
+				Resources: []string{"pipelineruns", "taskruns"},
+				Verbs:     []string{"get", "list", "delete"},
+			}},
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: prunerName, Labels: labels},
+			Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: prunerName, Namespace: namespace}},
+			RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: prunerName},
+		},
+		cronJob,
+	}, jobs...)
+}
+
+// prunerCronJob returns a CronJob of the target namespace running the
+// pruner script on schedule, its pods labeled for cost allocation like the
+// ones of the components.
+func prunerCronJob(tc *v1alpha1.TektonConfig, name, schedule, script, image string) *batchv1beta1.CronJob {
+	return &batchv1beta1.CronJob{
+		TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1beta1", Kind: "CronJob"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: tc.Spec.GetTargetNamespace(),
+			Labels:    map[string]string{common.LabelGenerated: "true"},
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:                   schedule,
+			ConcurrencyPolicy:          batchv1beta1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptr.Int32(1),
+			FailedJobsHistoryLimit:     ptr.Int32(1),
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: common.CostAllocationLabels(tc)},
+						Spec: corev1.PodSpec{
+							ServiceAccountName: prunerName,
+							RestartPolicy:      corev1.RestartPolicyNever,
+							Containers: []corev1.Container{{
+								Name:    "pruner",
+								Image:   image,
+								Command: []string{"/bin/sh", "-c", script},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// prunerScript returns the shell script deleting the runs of each
+// namespace but the most recent ones, carrying on past failures.
+func prunerScript(resources []string, namespaces []prunedNamespace) string {
+	var script strings.Builder
+	script.WriteString("failed=0\n")
+	for _, ns := range namespaces {
+		for _, resource := range resources {
+			fmt.Fprintf(&script, "tkn %s delete --keep=%d --namespace=%s --force || failed=1\n", resource, ns.keep, ns.name)
+		}
+	}
+	script.WriteString("exit $failed\n")
+	return script.String()
+}
+
+// followersScript returns the shell script of the CronJob of the pruner,
+// deleting the runs of the namespaces following spec.pruner but the most
+// recent ones, carrying on past failures. The namespaces holding runs are
+// listed when the job runs, so that the ones created since the CronJob was
+// applied are pruned and the deleted ones left out; the skipped ones, e.g.
+// with a schedule of their own, and the excluded ones aren't pruned, and
+// the overrides keep a number of runs of their own.
+func followersScript(resources []string, keep uint, overrides []prunedNamespace, skipped, excluded []string) string {
+	var script strings.Builder
+	script.WriteString("failed=0\n")
+	fmt.Fprintf(&script, "for resource in %s; do\n", strings.Join(resources, " "))
+	script.WriteString(`  namespaces=$(tkn $resource list --all-namespaces -o jsonpath='{range .items[*]}{.metadata.namespace}{" "}{end}') || { failed=1; continue; }` + "\n")
+	script.WriteString(`  seen=" "` + "\n")
+	script.WriteString("  for ns in $namespaces; do\n")
+	script.WriteString(`    case "$seen" in *" $ns "*) continue ;; esac` + "\n")
+	script.WriteString(`    seen="$seen$ns "` + "\n")
+	patterns := append([]string{}, skipped...)
+	for _, pattern := range excluded {
+		if p := shellPattern(pattern); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	if len(patterns) != 0 {
+		fmt.Fprintf(&script, "    case \"$ns\" in %s) continue ;; esac\n", strings.Join(patterns, "|"))
+	}
+	fmt.Fprintf(&script, "    keep=%d\n", keep)
+	if len(overrides) != 0 {
+		script.WriteString("    case \"$ns\" in\n")
+		for _, ns := range overrides {
+			fmt.Fprintf(&script, "    %s) keep=%d ;;\n", ns.name, ns.keep)
+		}
+		script.WriteString("    esac\n")
+	}
+	script.WriteString("    tkn $resource delete --keep=$keep --namespace=$ns --force || failed=1\n")
+	script.WriteString("  done\ndone\nexit $failed\n")
+	return script.String()
+}
+
+// shellPattern returns the pattern of a shell case matching the namespaces
+// the glob pattern of spec.excludedNamespaces matches, see
+// common.NamespaceExcluded: its wildcards and character classes are kept
+// and every other character is escaped. Patterns which can't match a
+// namespace, e.g. holding control characters, are dropped.
+func shellPattern(pattern string) string {
+	for _, c := range pattern {
+		if c <= ' ' || c > '~' {
+			return ""
+		}
+	}
+	_, err := path.Match(pattern, "")
+	var shell strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case err != nil:
+			// a malformed pattern only matches itself
+			shell.WriteByte('\\')
+			shell.WriteByte(c)
+		case c == '*' || c == '?':
+			shell.WriteByte(c)
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			shell.WriteByte('\\')
+			shell.WriteByte(pattern[i])
+		case c == '[' && shellClass.MatchString(pattern[i:]):
+			class := shellClass.FindString(pattern[i:])
+			i += len(class) - 1
+			shell.WriteString(strings.Replace(class, "[^", "[!", 1))
+		case c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-':
+			shell.WriteByte(c)
+		default:
+			shell.WriteByte('\\')
+			shell.WriteByte(c)
+		}
+	}
+	return shell.String()
+}
+
+// shellClass matches the character classes of a glob pattern which mean
+// the same in a shell case, those of the characters of namespaces
+var shellClass = regexp.MustCompile(`^\[\^?[a-z0-9-]+\]`)
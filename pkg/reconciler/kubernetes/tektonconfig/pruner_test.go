@@ -0,0 +1,393 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonconfig
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	mffake "github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorfake "github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestValidatePruner(t *testing.T) {
+	valid := v1alpha1.Pruner{Schedule: "0 8 * * *", Keep: 3, Resources: []string{"pipelinerun", "taskrun"}}
+	util.AssertNoError(t, validatePruner(nil))
+	util.AssertNoError(t, validatePruner(&valid))
+
+	for _, test := range []struct {
+		name   string
+		mutate func(*v1alpha1.Pruner)
+		err    string
+	}{{
+		name:   "schedule",
+		mutate: func(p *v1alpha1.Pruner) { p.Schedule = "daily" },
+		err:    `invalid spec.pruner.schedule "daily": expected 5 fields, got 1`,
+	}, {
+		name:   "schedule field",
+		mutate: func(p *v1alpha1.Pruner) { p.Schedule = "0 25 * * *" },
+		err:    `invalid spec.pruner.schedule "0 25 * * *": invalid hour "25": value 25 out of range [0, 23]`,
+	}, {
+		name:   "keep",
+		mutate: func(p *v1alpha1.Pruner) { p.Keep = 0 },
+		err:    "invalid spec.pruner.keep 0, expected at least 1",
+	}, {
+		name:   "no resources",
+		mutate: func(p *v1alpha1.Pruner) { p.Resources = nil },
+		err:    "spec.pruner.resources is empty, expected pipelinerun or taskrun",
+	}, {
+		name:   "resource",
+		mutate: func(p *v1alpha1.Pruner) { p.Resources = []string{"pod"} },
+		err:    `invalid resource "pod" in spec.pruner.resources, expected pipelinerun or taskrun`,
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			pruner := valid
+			test.mutate(&pruner)
+			err := validatePruner(&pruner)
+			if err == nil {
+				t.Fatal("validatePruner() = nil, wanted an error")
+			}
+			util.AssertEqual(t, err.Error(), test.err)
+		})
+	}
+}
+
+func TestReconcilePruner(t *testing.T) {
+	os.Setenv(common.JobImagePrefix+"PRUNER_TKN", "registry.example.com/tkn")
+	defer os.Unsetenv(common.JobImagePrefix + "PRUNER_TKN")
+
+	terminating := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "leaving"}}
+	terminating.Status.Phase = corev1.NamespaceTerminating
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, ns := range []*corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-gone"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		terminating,
+	} {
+		util.AssertNoError(t, indexer.Add(ns))
+	}
+	r := &Reconciler{kubeClientSet: withCronJobs("batch/v1beta1"), namespaceLister: corelisters.NewNamespaceLister(indexer)}
+	client := mffake.New()
+	manifest, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ConfigResourceName},
+		Spec: v1alpha1.TektonConfigSpec{
+			CommonSpec:         v1alpha1.CommonSpec{TargetNamespace: "tekton-pipelines"},
+			ExcludedNamespaces: []string{"kube-*"},
+			Pruner:             &v1alpha1.Pruner{Schedule: "0 8 * * *", Keep: 3, Resources: []string{"taskrun"}},
+		},
+	}
+	util.AssertNoError(t, r.reconcilePruner(context.Background(), &manifest, tc))
+
+	cronJob := &unstructured.Unstructured{}
+	cronJob.SetAPIVersion("batch/v1beta1")
+	cronJob.SetKind("CronJob")
+	cronJob.SetNamespace("tekton-pipelines")
+	cronJob.SetName(prunerName)
+	live, err := client.Get(cronJob)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(live.GetOwnerReferences()), 1)
+	schedule, _, _ := unstructured.NestedString(live.Object, "spec", "schedule")
+	util.AssertEqual(t, schedule, "0 8 * * *")
+	containers, _, _ := unstructured.NestedSlice(live.Object, "spec", "jobTemplate", "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	util.AssertEqual(t, container["image"], "registry.example.com/tkn")
+	command := container["command"].([]interface{})
+	// the namespaces are listed when the job runs: team-b created since is
+	// pruned, team-gone deleted since isn't listed anymore
+	util.AssertDeepEqual(t, runPruner(t, command[2].(string), "default kube-public leaving team-a team-a team-b"), []string{
+		"taskrun delete --keep=3 --namespace=default --force",
+		"taskrun delete --keep=3 --namespace=team-a --force",
+		"taskrun delete --keep=3 --namespace=team-b --force",
+	})
+
+	tc.Spec.Pruner = nil
+	util.AssertNoError(t, r.reconcilePruner(context.Background(), &manifest, tc))
+	if _, err := client.Get(cronJob); err == nil {
+		t.Error("the CronJob of the pruner wasn't deleted")
+	}
+}
+
+func TestReconcilePrunerInstallerSet(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	util.AssertNoError(t, indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}))
+	r := &Reconciler{kubeClientSet: withCronJobs("batch/v1"), namespaceLister: corelisters.NewNamespaceLister(indexer)}
+	client := mffake.New()
+	manifest, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ConfigResourceName},
+		Spec: v1alpha1.TektonConfigSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "tekton-pipelines"},
+			Pruner:     &v1alpha1.Pruner{Schedule: "0 8 * * *", Keep: 3, Resources: []string{"taskrun"}},
+		},
+	}
+	sets := operatorfake.NewSimpleClientset().OperatorV1alpha1().TektonInstallerSets()
+	ctx := common.WithInstallerSets(context.Background(), sets)
+	err = r.reconcilePruner(ctx, &manifest, tc)
+	if !errors.Is(err, common.ErrInstallerSetPending) {
+		t.Fatalf("reconcilePruner() = %v, wanted the installer set to be pending", err)
+	}
+
+	// the installer set applies the pruner, not the TektonConfig
+	cronJob := &unstructured.Unstructured{}
+	cronJob.SetAPIVersion("batch/v1")
+	cronJob.SetKind("CronJob")
+	cronJob.SetNamespace("tekton-pipelines")
+	cronJob.SetName(prunerName)
+	if _, err := client.Get(cronJob); !apierrors.IsNotFound(err) {
+		t.Errorf("the CronJob of the pruner was applied directly: %v", err)
+	}
+	set, err := sets.Get(ctx, common.InstallerSetName(tc, prunerPart), metav1.GetOptions{})
+	util.AssertNoError(t, err)
+	var kinds []string
+	for _, u := range set.Spec.Manifests {
+		kinds = append(kinds, u.GetAPIVersion()+" "+u.GetKind())
+	}
+	sort.Strings(kinds)
+	util.AssertDeepEqual(t, kinds, []string{
+		"batch/v1 CronJob",
+		"rbac.authorization.k8s.io/v1 ClusterRole",
+		"rbac.authorization.k8s.io/v1 ClusterRoleBinding",
+		"v1 ServiceAccount",
+	})
+
+	tc.Spec.Pruner = nil
+	util.AssertNoError(t, r.reconcilePruner(ctx, &manifest, tc))
+	if _, err := sets.Get(ctx, common.InstallerSetName(tc, prunerPart), metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("the installer set of the pruner wasn't deleted: %v", err)
+	}
+}
+
+func TestCronJobAPIVersion(t *testing.T) {
+	for _, version := range []string{"batch/v1beta1", "batch/v1"} {
+		r := &Reconciler{kubeClientSet: withCronJobs(version)}
+		got, err := r.cronJobAPIVersion()
+		util.AssertNoError(t, err)
+		util.AssertEqual(t, got, version)
+	}
+}
+
+// withCronJobs returns a clientset whose cluster serves the CronJobs of
+// the API version
+func withCronJobs(apiVersion string) *kubefake.Clientset {
+	client := kubefake.NewSimpleClientset()
+	batch := &metav1.APIResourceList{GroupVersion: "batch/v1", APIResources: []metav1.APIResource{{Name: "jobs"}}}
+	if apiVersion == "batch/v1" {
+		batch.APIResources = append(batch.APIResources, metav1.APIResource{Name: "cronjobs"})
+	}
+	client.Resources = []*metav1.APIResourceList{batch}
+	return client
+}
+
+func TestPrunerCostLabels(t *testing.T) {
+	tc := &v1alpha1.TektonConfig{
+		Spec: v1alpha1.TektonConfigSpec{
+			CommonSpec: v1alpha1.CommonSpec{
+				TargetNamespace: "tekton-pipelines",
+				CostAllocation:  &v1alpha1.CostAllocation{Labels: map[string]string{"team": "ci"}},
+			},
+		},
+	}
+	pruner := &v1alpha1.Pruner{Schedule: "0 8 * * *", Keep: 3, Resources: []string{"taskrun"}}
+	resources := prunerResources(tc, pruner, nil, defaultPrunerImage)
+	cronJob := resources[len(resources)-1].(*batchv1beta1.CronJob)
+	util.AssertEqual(t, cronJob.Spec.JobTemplate.Spec.Template.Labels["team"], "ci")
+}
+
+func TestReconcilePrunerAnnotations(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	annotated := func(name string, annotations map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+	}
+	for _, ns := range []*corev1.Namespace{
+		annotated("team-a", nil),
+		annotated("team-b", map[string]string{pruneKeepAnnotation: "10"}),
+		annotated("team-c", map[string]string{pruneScheduleAnnotation: "0 * * * *", pruneKeepAnnotation: "1"}),
+		annotated("team-d", map[string]string{pruneSkipAnnotation: "true"}),
+		annotated("team-e", map[string]string{pruneKeepAnnotation: "all", pruneScheduleAnnotation: "hourly"}),
+		annotated("team-f", map[string]string{pruneScheduleAnnotation: "60 * * * *"}),
+	} {
+		util.AssertNoError(t, indexer.Add(ns))
+	}
+	r := &Reconciler{kubeClientSet: withCronJobs("batch/v1beta1"), namespaceLister: corelisters.NewNamespaceLister(indexer)}
+	client := mffake.New()
+	manifest, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ConfigResourceName},
+		Spec: v1alpha1.TektonConfigSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "tekton-pipelines"},
+			Pruner:     &v1alpha1.Pruner{Schedule: "0 8 * * *", Keep: 3, Resources: []string{"pipelinerun"}},
+		},
+	}
+	util.AssertNoError(t, r.reconcilePruner(context.Background(), &manifest, tc))
+
+	cronJob := func(name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("batch/v1beta1")
+		u.SetKind("CronJob")
+		u.SetNamespace("tekton-pipelines")
+		u.SetName(name)
+		return u
+	}
+	script := func(live *unstructured.Unstructured) interface{} {
+		containers, _, _ := unstructured.NestedSlice(live.Object, "spec", "jobTemplate", "spec", "template", "spec", "containers")
+		return containers[0].(map[string]interface{})["command"].([]interface{})[2]
+	}
+	live, err := client.Get(cronJob(prunerName))
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, live.GetAnnotations()[prunerJobsAnnotation], "team-c")
+	util.AssertDeepEqual(t, runPruner(t, script(live).(string), "team-a team-b team-c team-d team-e team-f"), []string{
+		"pipelinerun delete --keep=3 --namespace=team-a --force",
+		"pipelinerun delete --keep=10 --namespace=team-b --force",
+		"pipelinerun delete --keep=3 --namespace=team-e --force",
+		// an invalid schedule falls back to spec.pruner
+		"pipelinerun delete --keep=3 --namespace=team-f --force",
+	})
+	live, err = client.Get(cronJob(prunerName + "-team-c"))
+	util.AssertNoError(t, err)
+	schedule, _, _ := unstructured.NestedString(live.Object, "spec", "schedule")
+	util.AssertEqual(t, schedule, "0 * * * *")
+	util.AssertEqual(t, script(live), strings.Join([]string{
+		"failed=0",
+		"tkn pipelinerun delete --keep=1 --namespace=team-c --force || failed=1",
+		"exit $failed",
+		"",
+	}, "\n"))
+
+	// team-c follows spec.pruner again
+	util.AssertNoError(t, indexer.Update(annotated("team-c", nil)))
+	util.AssertNoError(t, r.reconcilePruner(context.Background(), &manifest, tc))
+	if _, err := client.Get(cronJob(prunerName + "-team-c")); err == nil {
+		t.Error("the CronJob of team-c wasn't deleted")
+	}
+	live, err = client.Get(cronJob(prunerName))
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, live.GetAnnotations()[prunerJobsAnnotation], "")
+
+	// the CronJobs of the namespaces go with the pruner
+	util.AssertNoError(t, indexer.Update(annotated("team-c", map[string]string{pruneScheduleAnnotation: "0 * * * *"})))
+	util.AssertNoError(t, r.reconcilePruner(context.Background(), &manifest, tc))
+	tc.Spec.Pruner = nil
+	util.AssertNoError(t, r.reconcilePruner(context.Background(), &manifest, tc))
+	for _, name := range []string{prunerName, prunerName + "-team-c"} {
+		if _, err := client.Get(cronJob(name)); err == nil {
+			t.Errorf("the CronJob %s wasn't deleted", name)
+		}
+	}
+}
+
+// runPruner runs the script of a pruner with a tkn listing runs in the
+// namespaces, and returns the arguments of the deletes it ran
+func runPruner(t *testing.T, script, namespaces string) []string {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no shell to run the pruner with")
+	}
+	dir, err := ioutil.TempDir("", "pruner")
+	util.AssertNoError(t, err)
+	defer os.RemoveAll(dir)
+	tkn := "#!/bin/sh\ncase \"$2\" in\nlist) echo \"$RUNS\" ;;\ndelete) echo \"$*\" >> \"$DELETES\" ;;\nesac\n"
+	util.AssertNoError(t, ioutil.WriteFile(filepath.Join(dir, "tkn"), []byte(tkn), 0755))
+	deletes := filepath.Join(dir, "deletes")
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Env = append(os.Environ(), "PATH="+dir+":"+os.Getenv("PATH"), "RUNS="+namespaces, "DELETES="+deletes)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("the pruner failed: %v: %s", err, out)
+	}
+	data, err := ioutil.ReadFile(deletes)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	util.AssertNoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestFollowersScript(t *testing.T) {
+	script := followersScript([]string{"pipelinerun", "taskrun"}, 3,
+		[]prunedNamespace{{name: "team-b", keep: 10}}, []string{"team-c"}, []string{"kube-*", "openshift-[^x]?", "a.b", "bad\nname"})
+	util.AssertDeepEqual(t, runPruner(t, script, "a.b axb kube-system openshift-ab openshift-xb team-b team-c"), []string{
+		"pipelinerun delete --keep=3 --namespace=axb --force",
+		"pipelinerun delete --keep=3 --namespace=openshift-xb --force",
+		"pipelinerun delete --keep=10 --namespace=team-b --force",
+		"taskrun delete --keep=3 --namespace=axb --force",
+		"taskrun delete --keep=3 --namespace=openshift-xb --force",
+		"taskrun delete --keep=10 --namespace=team-b --force",
+	})
+}
+
+func TestShellPattern(t *testing.T) {
+	for pattern, shell := range map[string]string{
+		"kube-*":          "kube-*",
+		"team-?":          "team-?",
+		"openshift-[^a]*": "openshift-[!a]*",
+		"a.b":             "a\\.b",
+		"a\\*":            "a\\*",
+		"[a":              "\\[\\a",
+		"$(reboot)":       "\\$\\(reboot\\)",
+		"bad\nname":       "",
+	} {
+		util.AssertEqual(t, shellPattern(pattern), shell)
+	}
+}
+
+func TestPrunerJobName(t *testing.T) {
+	util.AssertEqual(t, prunerJobName("team-c"), "tekton-resource-pruner-team-c")
+	name := prunerJobName("a-namespace-with-a-name-much-too-long-for-a-cronjob")
+	util.AssertEqual(t, len(name), maxCronJobName)
+	util.AssertEqual(t, strings.HasPrefix(name, "tekton-resource-pruner-a-namespace-with-a-"), true)
+	if name == prunerJobName("a-namespace-with-a-name-much-too-long-for-another-cronjob") {
+		t.Error("expected the names of long namespaces to differ")
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	for _, schedule := range []string{"0 8 * * *", "*/15 0-6,22 ? JAN-jun mon-FRI", "5 4 1 1/2 0", "@daily", "@every 90m"} {
+		util.AssertNoError(t, parseSchedule(schedule))
+	}
+	for _, schedule := range []string{"", "hourly", "@fortnightly", "@every", "* * * *", "0 8 * * 7", "0 8 0 * *", "30-10 * * * *", "*/0 * * * *", "0 8 * foo *"} {
+		if err := parseSchedule(schedule); err == nil {
+			t.Errorf("parseSchedule(%q) = nil, wanted an error", schedule)
+		}
+	}
+}
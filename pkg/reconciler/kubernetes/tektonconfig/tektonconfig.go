@@ -18,22 +18,38 @@ package tektonconfig
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	mf "github.com/manifestival/manifestival"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
 
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	clientset "github.com/tektoncd/operator/pkg/client/clientset/versioned"
 	tektonConfigreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektonconfig"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
 	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektonconfig/pipeline"
 	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektonconfig/trigger"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 )
 
+// profiles are the values of spec.profile, an empty profile installing the
+// same components as default
+var profiles = sets.NewString("", common.ProfileLite, common.ProfileBasic, common.ProfileDefault, common.ProfileAll)
+
+// withTriggers returns true if the profile installs TektonTrigger, which
+// every profile but lite does
+func withTriggers(profile string) bool {
+	return profile != common.ProfileLite
+}
+
 // Reconciler implements controller.Reconciler for TektonConfig resources.
 type Reconciler struct {
 	// kubeClientSet allows us to talk to the k8s for core APIs
@@ -47,6 +63,14 @@ type Reconciler struct {
 	manifest mf.Manifest
 	// Platform-specific behavior to affect the transform
 	extension common.Extension
+	// images holds the overrides of the images of the jobs, e.g. the pruner
+	images *common.ImageStore
+	// namespaceLister lists the namespaces the pruner prunes
+	namespaceLister corelisters.NamespaceLister
+	// cronJobMu guards cronJobVersion, the API version of the CronJobs of
+	// the pruner once discovered
+	cronJobMu      sync.Mutex
+	cronJobVersion string
 }
 
 // Check that our Reconciler implements controller.Reconciler
@@ -55,7 +79,9 @@ var _ tektonConfigreconciler.Finalizer = (*Reconciler)(nil)
 
 // FinalizeKind removes all resources after deletion of a TektonConfig.
 func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.TektonConfig) pkgreconciler.Event {
+	ctx = common.WithCorrelationID(ctx)
 	logger := logging.FromContext(ctx)
+	common.ForgetComponentFailures(original)
 
 	// List all TektonConfigs to determine if cluster-scoped resources should be deleted.
 	tps, err := r.operatorClientSet.OperatorV1alpha1().TektonConfigs().List(ctx, metav1.ListOptions{})
@@ -70,16 +96,13 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.Tekton
 		}
 	}
 
-	if original.Spec.Profile == common.ProfileBasic {
-		return pipeline.TektonPipelineCRDelete(r.operatorClientSet.OperatorV1alpha1().TektonPipelines(), common.PipelineResourceName)
-	} else {
-		// TektonPipeline and TektonTrigger is common for profile type default and all
-		if err := pipeline.TektonPipelineCRDelete(r.operatorClientSet.OperatorV1alpha1().TektonPipelines(), common.PipelineResourceName); err != nil {
-			return err
-		}
-		if err := trigger.TektonTriggerCRDelete(r.operatorClientSet.OperatorV1alpha1().TektonTriggers(), common.TriggerResourceName); err != nil {
-			return err
-		}
+	// TektonTrigger is deleted whatever the profile, which may have been
+	// changed to lite after it was installed
+	if err := pipeline.TektonPipelineCRDelete(r.operatorClientSet.OperatorV1alpha1().TektonPipelines(), common.PipelineResourceName); err != nil {
+		return err
+	}
+	if err := trigger.TektonTriggerCRDelete(r.operatorClientSet.OperatorV1alpha1().TektonTriggers(), common.TriggerResourceName); err != nil {
+		return err
 	}
 
 	if err := r.extension.Finalize(ctx, original); err != nil {
@@ -92,9 +115,9 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.Tekton
 // ReconcileKind compares the actual state with the desired, and attempts to
 // converge the two.
 func (r *Reconciler) ReconcileKind(ctx context.Context, tc *v1alpha1.TektonConfig) pkgreconciler.Event {
+	ctx = common.WithCorrelationID(ctx)
 	logger := logging.FromContext(ctx)
-	tc.Status.InitializeConditions()
-	tc.Status.ObservedGeneration = tc.Generation
+	common.InitializeStatus(tc)
 
 	logger.Infow("Reconciling TektonConfig", "status", tc.Status)
 	if tc.GetName() != common.ConfigResourceName {
@@ -103,35 +126,63 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tc *v1alpha1.TektonConfi
 			tc.GetName(),
 		)
 		logger.Error(msg)
-		tc.GetStatus().MarkInstallFailed(msg)
-		return nil
+		operrors.MarkFailed(tc.GetStatus(), &operrors.ResourceIgnored{Err: errors.New(msg)})
+		return common.ObserveGeneration(ctx, tc, nil)
 	}
 
-	var stages common.Stages
-	if tc.Spec.Profile == common.ProfileBasic {
-		stages = common.Stages{
-			r.createPipelineCR,
-		}
+	if !profiles.Has(tc.Spec.Profile) {
+		msg := fmt.Sprintf("Invalid spec.profile %q, expected lite, basic, default or all", tc.Spec.Profile)
+		logger.Error(msg)
+		operrors.MarkFailed(tc.GetStatus(), &operrors.TransformError{Err: errors.New(msg)})
+		return common.ObserveGeneration(ctx, tc, nil)
+	}
+	if err := validatePruner(tc.Spec.Pruner); err != nil {
+		logger.Error(err)
+		operrors.MarkFailed(tc.GetStatus(), &operrors.TransformError{Err: err})
+		return common.ObserveGeneration(ctx, tc, nil)
+	}
+
+	// The children don't depend on one another from here: the ones which
+	// need TektonPipeline wait for it themselves. The pruner needs the
+	// target namespace TektonPipeline creates.
+	branches := []common.Stages{{r.createPipelineCR, r.reconcilePruner}}
+	if withTriggers(tc.Spec.Profile) {
+		// TektonPipeline and TektonTrigger are common to all the other profiles
+		branches = append(branches, common.Stages{r.createTriggerCR})
 	} else {
-		// TektonPipeline and TektonTrigger is common for profile type default and all
-		stages = common.Stages{
-			r.createPipelineCR,
-			r.createTriggerCR,
-		}
+		// the TektonTrigger installed before the profile changed to lite
+		branches = append(branches, common.Stages{r.deleteTriggerCR})
 	}
+	branches = append(branches, common.Stages{r.postReconcile}, common.Stages{common.OperatorServiceMonitor})
+	stages := common.Stages{common.Parallel(branches...)}
 
+	ctx = common.WithInstallerSets(ctx, r.operatorClientSet.OperatorV1alpha1().TektonInstallerSets())
 	manifest := r.manifest.Append()
-	if err := stages.Execute(ctx, &manifest, tc); err != nil {
-		tc.GetStatus().MarkInstallFailed(err.Error())
-		return err
+	err := stages.Execute(ctx, &manifest, tc)
+	if failed := utilerrors.FilterOut(err, installerSetPending); failed != nil {
+		r.recordChildFailures(ctx, tc)
+		operrors.MarkFailed(tc.GetStatus(), failed)
+		return common.ObserveGeneration(ctx, tc, failed)
 	}
-	if err := r.extension.PostReconcile(ctx, tc); err != nil {
-		tc.GetStatus().MarkInstallFailed(err.Error())
-		return err
+	if err != nil {
+		// the installer set of the pruner enqueues the TektonConfig once
+		// applied
+		return common.ObserveGeneration(ctx, tc, err)
 	}
+	tc.Status.SetAPIVersions(r.childAPIVersions(ctx))
 	tc.Status.MarkInstallSucceeded()
 	tc.Status.MarkDeploymentsAvailable()
-	return nil
+	return common.ObserveGeneration(ctx, tc, nil)
+}
+
+func installerSetPending(err error) bool {
+	return errors.Is(err, common.ErrInstallerSetPending)
+}
+
+// postReconcile is the PostReconcile of the extension, which reconciles
+// the children of the platform, as a Stage
+func (r *Reconciler) postReconcile(ctx context.Context, _ *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	return r.extension.PostReconcile(ctx, comp)
 }
 
 func (r *Reconciler) createPipelineCR(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
@@ -141,3 +192,22 @@ func (r *Reconciler) createPipelineCR(ctx context.Context, manifest *mf.Manifest
 func (r *Reconciler) createTriggerCR(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
 	return trigger.CreateTriggerCR(comp, r.operatorClientSet.OperatorV1alpha1())
 }
+
+func (r *Reconciler) deleteTriggerCR(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	return trigger.TektonTriggerCRDelete(r.operatorClientSet.OperatorV1alpha1().TektonTriggers(), common.TriggerResourceName)
+}
+
+// recordChildFailures records the failures of the child components as
+// events on the TektonConfig.
+func (r *Reconciler) recordChildFailures(ctx context.Context, tc *v1alpha1.TektonConfig) {
+	client := r.operatorClientSet.OperatorV1alpha1()
+	if tp, err := pipeline.GetPipeline(client.TektonPipelines(), common.PipelineResourceName); err == nil {
+		common.RecordComponentFailures(ctx, tc, "TektonPipeline", tp.Status.Conditions)
+	}
+	if !withTriggers(tc.Spec.Profile) {
+		return
+	}
+	if tt, err := trigger.GetTrigger(client.TektonTriggers(), common.TriggerResourceName); err == nil {
+		common.RecordComponentFailures(ctx, tc, "TektonTrigger", tt.Status.Conditions)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonconfig
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	mffake "github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/system"
+)
+
+func TestWithTriggers(t *testing.T) {
+	util.AssertEqual(t, withTriggers(common.ProfileLite), false)
+	for _, profile := range []string{"", common.ProfileBasic, common.ProfileDefault, common.ProfileAll} {
+		util.AssertEqual(t, withTriggers(profile), true)
+	}
+}
+
+func TestReconcileInvalidProfile(t *testing.T) {
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ConfigResourceName},
+		Spec:       v1alpha1.TektonConfigSpec{Profile: "minimal"},
+	}
+	r := &Reconciler{}
+	util.AssertNoError(t, r.ReconcileKind(context.Background(), tc))
+	condition := tc.Status.GetCondition(v1alpha1.InstallSucceeded)
+	util.AssertEqual(t, condition.IsFalse(), true)
+	util.AssertEqual(t, condition.Message, `Install failed with message: Invalid spec.profile "minimal", expected lite, basic, default or all`)
+}
+
+func TestDeleteTriggerCR(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1alpha1.TektonTrigger{
+		ObjectMeta: metav1.ObjectMeta{Name: common.TriggerResourceName},
+	})
+	r := &Reconciler{operatorClientSet: client}
+	tc := &v1alpha1.TektonConfig{Spec: v1alpha1.TektonConfigSpec{Profile: common.ProfileLite}}
+	util.AssertNoError(t, r.deleteTriggerCR(context.Background(), nil, tc))
+	_, err := client.OperatorV1alpha1().TektonTriggers().Get(context.Background(), common.TriggerResourceName, metav1.GetOptions{})
+	util.AssertEqual(t, apierrs.IsNotFound(err), true)
+	// nothing to delete
+	util.AssertNoError(t, r.deleteTriggerCR(context.Background(), nil, tc))
+}
+
+// readyStatus marks the status of a component ready
+func readyStatus(t *testing.T, status v1alpha1.TektonComponentStatus) {
+	t.Helper()
+	status.InitializeConditions()
+	status.MarkDependenciesInstalled()
+	status.MarkInstallSucceeded()
+	status.MarkDeploymentsAvailable()
+	status.MarkDeprecatedFieldsUnset()
+	util.AssertEqual(t, status.IsReady(), true)
+}
+
+// TestReconcileKindBranches runs the children of the TektonConfig, which
+// are reconciled concurrently, e.g. with -race
+func TestReconcileKindBranches(t *testing.T) {
+	os.Setenv(system.NamespaceEnvKey, "tekton-operator")
+	defer os.Unsetenv(system.NamespaceEnvKey)
+	tp := &v1alpha1.TektonPipeline{ObjectMeta: metav1.ObjectMeta{Name: common.PipelineResourceName}}
+	readyStatus(t, &tp.Status)
+	tt := &v1alpha1.TektonTrigger{ObjectMeta: metav1.ObjectMeta{Name: common.TriggerResourceName}}
+	readyStatus(t, &tt.Status)
+	client := fake.NewSimpleClientset(tp, tt)
+	manifest, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(mffake.New()))
+	util.AssertNoError(t, err)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	util.AssertNoError(t, indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}))
+	r := &Reconciler{
+		kubeClientSet:     withCronJobs("batch/v1"),
+		operatorClientSet: client,
+		manifest:          manifest,
+		extension:         kubernetesExtension{operatorClientSet: client},
+		namespaceLister:   corelisters.NewNamespaceLister(indexer),
+	}
+
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ConfigResourceName},
+		Spec: v1alpha1.TektonConfigSpec{
+			Profile: common.ProfileBasic,
+			CommonSpec: v1alpha1.CommonSpec{
+				TargetNamespace: "tekton-pipelines",
+				Config:          v1alpha1.Config{ServiceMonitors: &v1alpha1.ServiceMonitors{}},
+			},
+			Pruner: &v1alpha1.Pruner{Schedule: "0 8 * * *", Keep: 3, Resources: []string{"taskrun"}},
+		},
+	}
+	// the installer sets of the pruner and of the ServiceMonitor of the
+	// operator are pending until applied, enqueueing the TektonConfig again
+	util.AssertNoError(t, r.ReconcileKind(context.Background(), tc))
+	util.AssertEqual(t, tc.Status.GetCondition(v1alpha1.InstallSucceeded).IsTrue(), false)
+	sets, err := client.OperatorV1alpha1().TektonInstallerSets().List(context.Background(), metav1.ListOptions{})
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(sets.Items), 2)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	"log"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -31,35 +32,43 @@ import (
 	op "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
 	operatorv1alpha1 "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
 )
 
 func CreateTriggerCR(instance v1alpha1.TektonComponent, client operatorv1alpha1.OperatorV1alpha1Interface) error {
 	configInstance := instance.(*v1alpha1.TektonConfig)
-	if _, err := ensureTektonTriggerExists(client.TektonTriggers(), configInstance.Spec.TargetNamespace); err != nil {
-		return errors.New(err.Error())
+	if _, err := ensureTektonTriggerExists(client.TektonTriggers(), configInstance); err != nil {
+		return &operrors.ApplyFailed{Err: err}
 	}
 	if _, err := waitForTektonTriggerState(client.TektonTriggers(), common.TriggerResourceName,
 		isTektonTriggerReady); err != nil {
 		log.Println("TektonTrigger is not in ready state: ", err)
-		return err
+		return &operrors.TimeoutWaitingReady{Err: err}
 	}
 	return nil
 }
 
-func ensureTektonTriggerExists(clients op.TektonTriggerInterface, targetNS string) (*v1alpha1.TektonTrigger, error) {
+// ensureTektonTriggerExists creates the TektonTrigger of the TektonConfig,
+// controlled by it, or updates its common spec to the one of the
+// TektonConfig. A TektonTrigger the TektonConfig doesn't control, e.g.
+// created by hand, is left as it is.
+func ensureTektonTriggerExists(clients op.TektonTriggerInterface, config *v1alpha1.TektonConfig) (*v1alpha1.TektonTrigger, error) {
 	ttCR, err := GetTrigger(clients, common.TriggerResourceName)
 	if err == nil {
-		return ttCR, err
+		if !metav1.IsControlledBy(ttCR, config) || equality.Semantic.DeepEqual(ttCR.Spec.CommonSpec, config.Spec.CommonSpec) {
+			return ttCR, nil
+		}
+		ttCR.Spec.CommonSpec = config.Spec.CommonSpec
+		return clients.Update(context.TODO(), ttCR, metav1.UpdateOptions{})
 	}
 	if apierrs.IsNotFound(err) {
 		ttCR = &v1alpha1.TektonTrigger{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: common.TriggerResourceName,
+				Name:            common.TriggerResourceName,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(config, config.GroupVersionKind())},
 			},
 			Spec: v1alpha1.TektonTriggerSpec{
-				CommonSpec: v1alpha1.CommonSpec{
-					TargetNamespace: targetNS,
-				},
+				CommonSpec: config.Spec.CommonSpec,
 			},
 		}
 		return clients.Create(context.TODO(), ttCR, metav1.CreateOptions{})
@@ -91,9 +100,10 @@ func waitForTektonTriggerState(clients op.TektonTriggerInterface, name string,
 	return lastState, nil
 }
 
-// isTektonTriggerReady will check the status conditions of the TektonTrigger and return true if the TektonTrigger is ready.
+// isTektonTriggerReady will check the status conditions of the TektonTrigger and return true if the TektonTrigger is ready
+// with the latest generation of its spec.
 func isTektonTriggerReady(s *v1alpha1.TektonTrigger, err error) (bool, error) {
-	return s.Status.IsReady(), err
+	return s.Status.IsReady() && s.Status.GetObservedGeneration() == s.Generation, err
 }
 
 // TektonTriggerCRDelete deletes tha TektonTrigger to see if all resources will be deleted
@@ -20,7 +20,6 @@ import (
 	"context"
 
 	"github.com/go-logr/zapr"
-	mfc "github.com/manifestival/client-go-client"
 	mf "github.com/manifestival/manifestival"
 	"go.uber.org/zap"
 	"k8s.io/client-go/tools/cache"
@@ -28,6 +27,8 @@ import (
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	operatorclient "github.com/tektoncd/operator/pkg/client/injection/client"
 	tektonDashboardinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektondashboard"
+	tektonInstallerSetinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektoninstallerset"
+	tektonOverrideinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonoverride"
 	tektonPipelineinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonpipeline"
 	tektonDashboardreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektondashboard"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
@@ -50,11 +51,13 @@ func NewExtendedController(generator common.ExtensionGenerator) injection.Contro
 	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
 		tektonPipelineInformer := tektonPipelineinformer.Get(ctx)
 		tektonDashboardInformer := tektonDashboardinformer.Get(ctx)
+		tektonOverrideInformer := tektonOverrideinformer.Get(ctx)
+		tektonInstallerSetInformer := tektonInstallerSetinformer.Get(ctx)
 		deploymentInformer := deploymentinformer.Get(ctx)
 		kubeClient := kubeclient.Get(ctx)
 		logger := logging.FromContext(ctx)
 
-		mfclient, err := mfc.NewClient(injection.GetConfig(ctx))
+		mfclient, err := common.NewClient(injection.GetConfig(ctx))
 		if err != nil {
 			logger.Fatalw("Error creating client from injected config", zap.Error(err))
 		}
@@ -69,6 +72,8 @@ func NewExtendedController(generator common.ExtensionGenerator) injection.Contro
 			operatorClientSet: operatorclient.Get(ctx),
 			extension:         generator(ctx),
 			manifest:          manifest,
+			overrideLister:    tektonOverrideInformer.Lister(),
+			dashboardLister:   tektonDashboardInformer.Lister(),
 			pipelineInformer:  tektonPipelineInformer,
 		}
 		impl := tektonDashboardreconciler.NewImpl(ctx, c)
@@ -76,11 +81,15 @@ func NewExtendedController(generator common.ExtensionGenerator) injection.Contro
 		logger.Info("Setting up event handlers")
 
 		tektonDashboardInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+		tektonOverrideInformer.Informer().AddEventHandler(common.EnqueueOnOverride(impl.EnqueueKey, v1alpha1.KindTektonDashboard, common.DashboardResourceName))
+		tektonInstallerSetInformer.Informer().AddEventHandler(common.EnqueueOnInstallerSet(impl.EnqueueControllerOf, v1alpha1.KindTektonDashboard))
+		tektonPipelineInformer.Informer().AddEventHandler(common.EnqueueOnReady(impl.EnqueueKey, common.DashboardResourceName))
 
 		deploymentInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 			FilterFunc: controller.FilterControllerGVK(v1alpha1.SchemeGroupVersion.WithKind("TektonDashboard")),
 			Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 		})
+		common.WatchOwned(ctx, v1alpha1.KindTektonDashboard, impl.EnqueueControllerOf)
 
 		return impl
 	}
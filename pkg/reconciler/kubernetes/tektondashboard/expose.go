@@ -0,0 +1,35 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektondashboard
+
+import (
+	"context"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+)
+
+// dashboardService is the Service of the payload the dashboard is exposed by
+const dashboardService = "tekton-dashboard"
+
+// expose is a Stage appending the Ingress or the Route of spec.expose,
+// pointing at the dashboard Service of the manifest.
+func (r *Reconciler) expose(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	instance := comp.(*v1alpha1.TektonDashboard)
+	return common.ExposeService(ctx, r.kubeClientSet, manifest, instance, instance.Spec.Expose, dashboardService, "spec.expose")
+}
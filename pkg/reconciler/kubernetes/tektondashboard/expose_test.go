@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektondashboard
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func dashboardServiceObject() *corev1.Service {
+	return &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Name: dashboardService, Namespace: "tekton-pipelines"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "http", Port: 9097}},
+		},
+	}
+}
+
+func TestExposeIngress(t *testing.T) {
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(dashboardServiceObject())
+	util.AssertNoError(t, err)
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{{Object: object}}))
+	util.AssertNoError(t, err)
+
+	instance := &v1alpha1.TektonDashboard{}
+	instance.Spec.TargetNamespace = "tekton-pipelines"
+	instance.Spec.Expose = &v1alpha1.ServiceExpose{
+		Type:        v1alpha1.ExposeIngress,
+		Host:        "dashboard.example.com",
+		TLSSecret:   "dashboard-tls",
+		Annotations: map[string]string{"kubernetes.io/ingress.class": "nginx"},
+	}
+	r := &Reconciler{}
+	util.AssertNoError(t, r.expose(context.Background(), &manifest, instance))
+	ingresses := manifest.Filter(mf.ByKind("Ingress")).Resources()
+	util.AssertEqual(t, len(ingresses), 1)
+	ingress := ingresses[0]
+	util.AssertEqual(t, ingress.GetNamespace(), "tekton-pipelines")
+	util.AssertEqual(t, ingress.GetAnnotations()["kubernetes.io/ingress.class"], "nginx")
+	rules, _, _ := unstructured.NestedSlice(ingress.Object, "spec", "rules")
+	util.AssertEqual(t, rules[0].(map[string]interface{})["host"], "dashboard.example.com")
+	tls, _, _ := unstructured.NestedSlice(ingress.Object, "spec", "tls")
+	util.AssertDeepEqual(t, tls, []interface{}{map[string]interface{}{
+		"hosts":      []interface{}{"dashboard.example.com"},
+		"secretName": "dashboard-tls",
+	}})
+
+	instance.Spec.Expose.Type = "LoadBalancer"
+	instance.Status.InitializeConditions()
+	if err := r.expose(context.Background(), &manifest, instance); err == nil {
+		t.Error("expected an error for an invalid spec.expose.type")
+	}
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).Reason, v1alpha1.ReasonTransformError)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektondashboard
+
+import (
+	"fmt"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	dashboardDeployment = "tekton-dashboard"
+	// dashboardRolePrefix is the prefix of the ClusterRoles of the payload
+	dashboardRolePrefix = "tekton-dashboard-"
+)
+
+// writeVerbs are the verbs the ClusterRoles of a read-only dashboard lose
+var writeVerbs = sets.NewString("create", "update", "patch", "delete", "deletecollection", "add", "*")
+
+// readOnly returns a transformer setting the --read-only flag of the
+// dashboard and, when enabled, removing the write verbs from the rules of
+// its ClusterRoles, along with the rules left without verbs.
+func readOnly(enabled bool) mf.Transformer {
+	args := common.DeploymentArgs([]v1alpha1.DeploymentOptions{{
+		Name: dashboardDeployment,
+		Args: v1alpha1.ContainerArgs{Add: []string{fmt.Sprintf("--read-only=%t", enabled)}},
+	}})
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() == "Deployment" {
+			return args(u)
+		}
+		if !enabled || u.GetKind() != "ClusterRole" || !strings.HasPrefix(u.GetName(), dashboardRolePrefix) {
+			return nil
+		}
+		rules, _, err := unstructured.NestedSlice(u.Object, "rules")
+		if err != nil {
+			return err
+		}
+		kept := []interface{}{}
+		for _, r := range rules {
+			rule, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			verbs, _, err := unstructured.NestedStringSlice(rule, "verbs")
+			if err != nil {
+				return err
+			}
+			if verbs = readVerbs(verbs); len(verbs) == 0 {
+				continue
+			}
+			if err := unstructured.SetNestedStringSlice(rule, verbs, "verbs"); err != nil {
+				return err
+			}
+			kept = append(kept, rule)
+		}
+		return unstructured.SetNestedSlice(u.Object, kept, "rules")
+	}
+}
+
+// readVerbs returns the verbs without the write verbs, a wildcard being
+// narrowed to get, list and watch.
+func readVerbs(verbs []string) []string {
+	read := []string{}
+	for _, verb := range verbs {
+		if verb == "*" {
+			read = append(read, "get", "list", "watch")
+		} else if !writeVerbs.Has(verb) {
+			read = append(read, verb)
+		}
+	}
+	return sets.NewString(read...).List()
+}
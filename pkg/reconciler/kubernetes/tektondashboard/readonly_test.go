@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektondashboard
+
+import (
+	"testing"
+
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func dashboardDeploymentWithArgs(args ...interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "tekton-dashboard", "args": args},
+					},
+				},
+			},
+		},
+	}}
+	u.SetKind("Deployment")
+	u.SetName(dashboardDeployment)
+	return u
+}
+
+func TestReadOnlyArgs(t *testing.T) {
+	u := dashboardDeploymentWithArgs("--port=9097", "--read-only=false", "--log-level=info")
+	util.AssertNoError(t, readOnly(true)(u))
+	containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "containers")
+	args, _, _ := unstructured.NestedStringSlice(containers[0].(map[string]interface{}), "args")
+	util.AssertDeepEqual(t, args, []string{"--port=9097", "--log-level=info", "--read-only=true"})
+}
+
+func TestReadOnlyClusterRoles(t *testing.T) {
+	role := func() *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"rules": []interface{}{
+				map[string]interface{}{"resources": []interface{}{"clustertasks"}, "verbs": []interface{}{"get", "list", "watch"}},
+				map[string]interface{}{"resources": []interface{}{"clustertasks"}, "verbs": []interface{}{"create", "update", "delete", "patch"}},
+				map[string]interface{}{"resources": []interface{}{"securitycontextconstraints"}, "verbs": []interface{}{"use"}},
+				map[string]interface{}{"resources": []interface{}{"pipelineruns"}, "verbs": []interface{}{"*"}},
+			},
+		}}
+		u.SetKind("ClusterRole")
+		u.SetName("tekton-dashboard-tenant")
+		return u
+	}
+
+	u := role()
+	util.AssertNoError(t, readOnly(false)(u))
+	util.AssertDeepEqual(t, u, role())
+
+	util.AssertNoError(t, readOnly(true)(u))
+	rules, _, _ := unstructured.NestedSlice(u.Object, "rules")
+	util.AssertDeepEqual(t, rules, []interface{}{
+		map[string]interface{}{"resources": []interface{}{"clustertasks"}, "verbs": []interface{}{"get", "list", "watch"}},
+		map[string]interface{}{"resources": []interface{}{"securitycontextconstraints"}, "verbs": []interface{}{"use"}},
+		map[string]interface{}{"resources": []interface{}{"pipelineruns"}, "verbs": []interface{}{"get", "list", "watch"}},
+	})
+}
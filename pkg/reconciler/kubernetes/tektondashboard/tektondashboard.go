@@ -18,6 +18,7 @@ package tektondashboard
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	mf "github.com/manifestival/manifestival"
@@ -28,7 +29,9 @@ import (
 	clientset "github.com/tektoncd/operator/pkg/client/clientset/versioned"
 	pipelineinformer "github.com/tektoncd/operator/pkg/client/informers/externalversions/operator/v1alpha1"
 	tektondashboardreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektondashboard"
+	operatorlisters "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 )
@@ -46,6 +49,10 @@ type Reconciler struct {
 	manifest mf.Manifest
 	// Platform-specific behavior to affect the transform
 	extension common.Extension
+	// overrideLister lists the TektonOverrides layered on top of the spec
+	overrideLister operatorlisters.TektonOverrideLister
+	// dashboardLister gets the latest generation of the TektonDashboard being reconciled
+	dashboardLister operatorlisters.TektonDashboardLister
 
 	pipelineInformer pipelineinformer.TektonPipelineInformer
 }
@@ -58,6 +65,7 @@ var watchedResourceName = "dashboard"
 
 // FinalizeKind removes all resources after deletion of a TektonDashboards.
 func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.TektonDashboard) pkgreconciler.Event {
+	ctx = common.WithCorrelationID(ctx)
 	logger := logging.FromContext(ctx)
 
 	// List all TektonDashboards to determine if cluster-scoped resources should be deleted.
@@ -82,6 +90,10 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.Tekton
 		logger.Error("Unable to fetch installed manifest; no cluster-scoped resources will be finalized", err)
 		return nil
 	}
+	if _, err := common.TargetCluster(ctx, r.kubeClientSet, manifest, original); err != nil {
+		logger.Error("Unable to reach the target cluster; no cluster-scoped resources will be finalized", err)
+		return nil
+	}
 	if err := common.Uninstall(ctx, manifest); err != nil {
 		logger.Error("Failed to finalize platform resources", err)
 	}
@@ -91,9 +103,9 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.Tekton
 // ReconcileKind compares the actual state with the desired, and attempts to
 // converge the two.
 func (r *Reconciler) ReconcileKind(ctx context.Context, tt *v1alpha1.TektonDashboard) pkgreconciler.Event {
+	ctx = common.WithCorrelationID(ctx)
 	logger := logging.FromContext(ctx)
-	tt.Status.InitializeConditions()
-	tt.Status.ObservedGeneration = tt.Generation
+	common.InitializeStatus(tt)
 
 	logger.Infow("Reconciling TektonDashboards", "status", tt.Status)
 
@@ -103,10 +115,24 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tt *v1alpha1.TektonDashb
 			tt.GetName(),
 		)
 		logger.Error(msg)
-		tt.GetStatus().MarkInstallFailed(msg)
-		return nil
+		operrors.MarkFailed(tt.GetStatus(), &operrors.ResourceIgnored{Err: errors.New(msg)})
+		return common.ObserveGeneration(ctx, tt, nil)
 	}
 
+	overrides, err := common.ComponentOverrides(r.overrideLister, v1alpha1.KindTektonDashboard)
+	if err != nil {
+		return err
+	}
+	ctx = common.WithOverrides(ctx, overrides)
+	ctx = common.WithInstallerSets(ctx, r.operatorClientSet.OperatorV1alpha1().TektonInstallerSets())
+	ctx = common.WithLatestGeneration(ctx, func(name string) (int64, error) {
+		latest, err := r.dashboardLister.Get(name)
+		if err != nil {
+			return 0, err
+		}
+		return latest.Generation, nil
+	})
+
 	// find the valid tekton-pipeline installation
 	if _, err := common.PipelineReady(r.pipelineInformer); err != nil {
 		if err.Error() == common.PipelineNotReady {
@@ -123,32 +149,46 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tt *v1alpha1.TektonDashb
 	if err := r.extension.PreReconcile(ctx, tt); err != nil {
 		return err
 	}
+	manifest := r.manifest.Append()
+	if _, err := common.TargetCluster(ctx, r.kubeClientSet, &manifest, tt); err != nil {
+		operrors.MarkFailed(&tt.Status, err)
+		return err
+	}
 	stages := common.Stages{
+		common.ReportDeprecations(common.DeprecatedDisruptionFields),
+		common.BlockDowngrades,
 		common.AppendTarget,
 		r.transform,
-		common.Install,
+		r.expose,
+		common.NetworkPolicies,
+		common.PodDisruptionBudgets,
+		common.HorizontalPodAutoscalers,
+		common.DebugBundle(r.kubeClientSet),
+		common.PinImageDigests,
+		common.VerifyImageSignatures,
+		common.PrecheckImagePulls,
+		common.ValidateCustomResources,
+		common.CheckResourceSizes,
+		common.SkipUnchanged(common.Install),
+		common.RecordAPIVersions,
 		common.CheckDeployments,
 	}
-	manifest := r.manifest.Append()
-	return stages.Execute(ctx, &manifest, tt)
+	return common.ObserveGeneration(ctx, tt, stages.Execute(ctx, &manifest, tt))
 }
 
 // transform mutates the passed manifest to one with common, component
 // and platform transformations applied
 func (r *Reconciler) transform(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
 	instance := comp.(*v1alpha1.TektonDashboard)
-	extra := []mf.Transformer{
-		common.ApplyProxySettings,
-	}
-	extra = append(extra, r.extension.Transformers(instance)...)
-	return common.Transform(ctx, manifest, instance, extra...)
+	extra := append(r.extension.Transformers(instance), readOnly(instance.Spec.Readonly))
+	return common.TransformWith(ctx, manifest, instance, common.TransformOptions{Transformers: extra})
 }
 
 func (r *Reconciler) installed(ctx context.Context, instance v1alpha1.TektonComponent) (*mf.Manifest, error) {
 	// Create new, empty manifest with valid client and logger
 	installed := r.manifest.Append()
 	// TODO: add ingress, etc
-	stages := common.Stages{common.AppendInstalled, r.transform}
+	stages := common.Stages{common.AppendInstalled, r.transform, common.NetworkPolicies, common.PodDisruptionBudgets, common.HorizontalPodAutoscalers}
 	err := stages.Execute(ctx, &installed, instance)
 	return &installed, err
 }
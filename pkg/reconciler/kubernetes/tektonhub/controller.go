@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonhub
+
+import (
+	"context"
+
+	"github.com/go-logr/zapr"
+	mf "github.com/manifestival/manifestival"
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorclient "github.com/tektoncd/operator/pkg/client/injection/client"
+	tektonHubinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonhub"
+	tektonInstallerSetinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektoninstallerset"
+	tektonOverrideinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonoverride"
+	tektonHubreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektonhub"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+)
+
+// NewController initializes the controller and is called by the generated code
+// Registers eventhandlers to enqueue events
+func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	return NewExtendedController(common.NoExtension)(ctx, cmw)
+}
+
+// NewExtendedController returns a controller extended to a specific platform
+func NewExtendedController(generator common.ExtensionGenerator) injection.ControllerConstructor {
+	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+		tektonHubInformer := tektonHubinformer.Get(ctx)
+		tektonOverrideInformer := tektonOverrideinformer.Get(ctx)
+		tektonInstallerSetInformer := tektonInstallerSetinformer.Get(ctx)
+		deploymentInformer := deploymentinformer.Get(ctx)
+		kubeClient := kubeclient.Get(ctx)
+		logger := logging.FromContext(ctx)
+
+		mfclient, err := common.NewClient(injection.GetConfig(ctx))
+		if err != nil {
+			logger.Fatalw("Error creating client from injected config", zap.Error(err))
+		}
+		mflogger := zapr.NewLogger(logger.Named("manifestival").Desugar())
+		manifest, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(mfclient), mf.UseLogger(mflogger))
+		if err != nil {
+			logger.Fatalw("Error creating initial manifest", zap.Error(err))
+		}
+
+		common.ReportKeyCollisions(ctx, &v1alpha1.TektonHub{}, common.HubImagePrefix)
+		images := common.NewImageStore()
+		ctx = common.WithImageStore(ctx, images)
+
+		c := &Reconciler{
+			kubeClientSet:     kubeClient,
+			operatorClientSet: operatorclient.Get(ctx),
+			extension:         generator(ctx),
+			manifest:          manifest,
+			images:            images,
+			overrideLister:    tektonOverrideInformer.Lister(),
+			hubLister:         tektonHubInformer.Lister(),
+		}
+		impl := tektonHubreconciler.NewImpl(ctx, c)
+
+		logger.Info("Setting up event handlers")
+
+		tektonHubInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+		tektonOverrideInformer.Informer().AddEventHandler(common.EnqueueOnOverride(impl.EnqueueKey, v1alpha1.KindTektonHub, common.HubResourceName))
+		tektonInstallerSetInformer.Informer().AddEventHandler(common.EnqueueOnInstallerSet(impl.EnqueueControllerOf, v1alpha1.KindTektonHub))
+
+		deploymentInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: controller.FilterControllerGVK(v1alpha1.SchemeGroupVersion.WithKind(v1alpha1.KindTektonHub)),
+			Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+		})
+		common.WatchOwned(ctx, v1alpha1.KindTektonHub, impl.EnqueueControllerOf)
+
+		resyncImages := func() {
+			impl.GlobalResync(tektonHubInformer.Informer())
+		}
+		common.WatchImages(cmw, images, resyncImages)
+		common.WatchImagesSecret(ctx, kubeClient, images, resyncImages)
+
+		return impl
+	}
+}
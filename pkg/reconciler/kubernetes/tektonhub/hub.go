@@ -0,0 +1,266 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonhub
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// componentLabel and dbComponent select the database of the payload,
+	// left out when an external one is configured
+	componentLabel = "app.kubernetes.io/component"
+	dbComponent    = "db"
+
+	// the Deployments, Services, ConfigMaps and Secrets of the payload
+	apiDeployment = "tekton-hub-api"
+	apiService    = "tekton-hub-api"
+	apiConfig     = "tekton-hub-api"
+	apiSecret     = "tekton-hub-api"
+	dbSecret      = "tekton-hub-db"
+	uiService     = "tekton-hub-ui"
+	uiConfig      = "tekton-hub-ui"
+)
+
+// requiredKeys are the keys the API reads from its Secrets
+var requiredKeys = map[string][]string{
+	apiSecret: {"GH_CLIENT_ID", "GH_CLIENT_SECRET", "JWT_SIGNING_KEY"},
+	dbSecret:  {"POSTGRES_HOST", "POSTGRES_PORT", "POSTGRES_DB", "POSTGRES_USER", "POSTGRES_PASSWORD"},
+}
+
+// apiConfigData returns the data of the tekton-hub-api ConfigMap set by
+// spec.api
+func apiConfigData(api v1alpha1.HubApi) map[string]string {
+	if api.HubConfigUrl == "" {
+		return nil
+	}
+	return map[string]string{"CONFIG_FILE_URL": api.HubConfigUrl}
+}
+
+// uiConfigData returns the data of the tekton-hub-ui ConfigMap pointing the
+// UI at the hosts the API and the UI are exposed at
+func uiConfigData(spec v1alpha1.TektonHubSpec) map[string]string {
+	data := map[string]string{}
+	if url := exposedURL(spec.Api.Expose, apiService, spec.TargetNamespace); url != "" {
+		data["API_URL"] = url
+		data["AUTH_BASE_URL"] = url
+	}
+	if url := exposedURL(spec.UI.Expose, uiService, spec.TargetNamespace); url != "" {
+		data["REDIRECT_URI"] = url
+	}
+	return data
+}
+
+// exposedURL returns the URL of the host expose serves the service of the
+// namespace at, empty without host or with an invalid template, which
+// ExposeService reports. Routes are always terminated with TLS, Ingresses
+// only with a secret.
+func exposedURL(expose *v1alpha1.ServiceExpose, service, namespace string) string {
+	if expose == nil {
+		return ""
+	}
+	host, err := common.ExposedHost(expose, service, namespace)
+	if err != nil || host == "" {
+		return ""
+	}
+	if expose.Type == v1alpha1.ExposeRoute || expose.TLSSecret != "" {
+		return "https://" + host
+	}
+	return "http://" + host
+}
+
+// secretRefs renames the Secrets the containers of the Deployments read
+// their environment from, by Secret name of the payload. Empty names are
+// left alone.
+func secretRefs(names map[string]string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Deployment" {
+			return nil
+		}
+		for _, field := range []string{"containers", "initContainers"} {
+			containers, found, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", field)
+			if err != nil || !found {
+				continue
+			}
+			for _, c := range containers {
+				container, _ := c.(map[string]interface{})
+				envFrom, _, _ := unstructured.NestedSlice(container, "envFrom")
+				for _, e := range envFrom {
+					source, _ := e.(map[string]interface{})
+					renameRef(source, names, "secretRef", "name")
+				}
+				if len(envFrom) != 0 {
+					container["envFrom"] = envFrom
+				}
+				env, _, _ := unstructured.NestedSlice(container, "env")
+				for _, e := range env {
+					variable, _ := e.(map[string]interface{})
+					renameRef(variable, names, "valueFrom", "secretKeyRef", "name")
+				}
+				if len(env) != 0 {
+					container["env"] = env
+				}
+			}
+			if err := unstructured.SetNestedSlice(u.Object, containers, "spec", "template", "spec", field); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// renameRef renames the Secret referenced at the path of obj
+func renameRef(obj map[string]interface{}, names map[string]string, path ...string) {
+	name, _, _ := unstructured.NestedString(obj, path...)
+	if renamed := names[name]; renamed != "" {
+		_ = unstructured.SetNestedField(obj, renamed, path...)
+	}
+}
+
+// expose is a Stage appending the Ingresses or the Routes of spec.api.expose
+// and spec.ui.expose, pointing at the API and UI Services of the manifest.
+func (r *Reconciler) expose(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	instance := comp.(*v1alpha1.TektonHub)
+	if err := common.ExposeService(ctx, r.kubeClientSet, manifest, instance, instance.Spec.Api.Expose, apiService, "spec.api.expose"); err != nil {
+		return err
+	}
+	return common.ExposeService(ctx, r.kubeClientSet, manifest, instance, instance.Spec.UI.Expose, uiService, "spec.ui.expose")
+}
+
+// dbCredentials is a Stage keeping the credentials of the database of the
+// payload: the tekton-hub-db Secret of the manifest takes the data of the
+// live Secret, which is never overwritten, or a generated password when it
+// doesn't exist yet.
+func dbCredentials(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	secrets := manifest.Filter(mf.ByKind("Secret"), mf.ByName(dbSecret)).Resources()
+	if len(secrets) == 0 {
+		// an external database
+		return nil
+	}
+	var data map[string]interface{}
+	var password string
+	live, err := manifest.Client.Get(&secrets[0])
+	switch {
+	case apierrors.IsNotFound(err):
+		if password, err = generatePassword(); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		data, _, _ = unstructured.NestedMap(live.Object, "data")
+	}
+	transformed, err := manifest.Transform(func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Secret" || u.GetName() != dbSecret {
+			return nil
+		}
+		if password == "" {
+			u.Object["data"] = data
+			delete(u.Object, "stringData")
+			return nil
+		}
+		return unstructured.SetNestedField(u.Object, password, "stringData", "POSTGRES_PASSWORD")
+	})
+	if err != nil {
+		return err
+	}
+	*manifest = transformed
+	return nil
+}
+
+// generatePassword returns a random password of the database
+func generatePassword() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate the database password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// checkSecrets is a Stage making sure the Secrets of the API, and of the
+// external database when configured, hold the keys the API reads before
+// anything is applied, marking the dependency missing otherwise.
+func checkSecrets(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	instance := comp.(*v1alpha1.TektonHub)
+	deployments := manifest.Filter(mf.ByKind("Deployment"), mf.ByName(apiDeployment)).Resources()
+	if len(deployments) == 0 {
+		return nil
+	}
+	namespace := deployments[0].GetNamespace()
+
+	secrets := map[string][]string{nameOr(instance.Spec.Api.Secret, apiSecret): requiredKeys[apiSecret]}
+	if instance.Spec.Db.Secret != "" {
+		secrets[instance.Spec.Db.Secret] = requiredKeys[dbSecret]
+	}
+	var missing []string
+	for _, name := range sortedKeys(secrets) {
+		secret := &unstructured.Unstructured{}
+		secret.SetAPIVersion("v1")
+		secret.SetKind("Secret")
+		secret.SetNamespace(namespace)
+		secret.SetName(name)
+		live, err := manifest.Client.Get(secret)
+		if apierrors.IsNotFound(err) {
+			missing = append(missing, name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, key := range secrets[name] {
+			if _, found, _ := unstructured.NestedString(live.Object, "data", key); !found {
+				missing = append(missing, name+"/"+key)
+			}
+		}
+	}
+	if len(missing) != 0 {
+		msg := fmt.Sprintf("Secrets of the hub missing in namespace %s: %s", namespace, strings.Join(missing, ", "))
+		err := &operrors.DependencyMissing{Err: errors.New(msg)}
+		operrors.MarkFailed(comp.GetStatus(), err)
+		return err
+	}
+	return nil
+}
+
+func nameOr(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonhub
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// secretClient serves the Get of the secrets it holds
+type secretClient struct {
+	mf.Client
+	secrets map[string]map[string]interface{}
+}
+
+func (c *secretClient) Get(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	data, ok := c.secrets[u.GetName()]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, u.GetName())
+	}
+	live := u.DeepCopy()
+	live.Object["data"] = data
+	return live, nil
+}
+
+func apiDeploymentObject(t *testing.T) unstructured.Unstructured {
+	secretRef := func(name string) corev1.EnvFromSource {
+		return corev1.EnvFromSource{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}}}
+	}
+	deployment := util.MakeDeployment(apiDeployment, corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "db-migration", EnvFrom: []corev1.EnvFromSource{secretRef(dbSecret)}}},
+		Containers: []corev1.Container{{Name: "api", EnvFrom: []corev1.EnvFromSource{
+			{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: apiConfig}}},
+			secretRef(apiSecret),
+			secretRef(dbSecret),
+		}}},
+	})
+	deployment.APIVersion = "apps/v1"
+	deployment.Namespace = "tekton-pipelines"
+	return util.MakeUnstructured(t, deployment)
+}
+
+func TestSecretRefs(t *testing.T) {
+	u := apiDeploymentObject(t)
+	util.AssertNoError(t, secretRefs(map[string]string{apiSecret: "", dbSecret: "hub-postgres"})(&u))
+
+	refs := func(field string) []string {
+		containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "template", "spec", field)
+		var names []string
+		for _, source := range containers[0].(map[string]interface{})["envFrom"].([]interface{}) {
+			if name, found, _ := unstructured.NestedString(source.(map[string]interface{}), "secretRef", "name"); found {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	util.AssertDeepEqual(t, refs("containers"), []string{apiSecret, "hub-postgres"})
+	util.AssertDeepEqual(t, refs("initContainers"), []string{"hub-postgres"})
+}
+
+func TestUIConfigData(t *testing.T) {
+	spec := v1alpha1.TektonHubSpec{}
+	util.AssertDeepEqual(t, uiConfigData(spec), map[string]string{})
+
+	spec.Api.Expose = &v1alpha1.ServiceExpose{Type: v1alpha1.ExposeIngress, Host: "api.hub.example.com"}
+	spec.UI.Expose = &v1alpha1.ServiceExpose{Type: v1alpha1.ExposeRoute, Host: "hub.example.com"}
+	util.AssertDeepEqual(t, uiConfigData(spec), map[string]string{
+		"API_URL":       "http://api.hub.example.com",
+		"AUTH_BASE_URL": "http://api.hub.example.com",
+		"REDIRECT_URI":  "https://hub.example.com",
+	})
+
+	spec.TargetNamespace = "tekton-hub"
+	spec.UI.Expose = &v1alpha1.ServiceExpose{Type: v1alpha1.ExposeIngress, HostTemplate: "{{.Name}}.{{.Namespace}}.example.com"}
+	util.AssertEqual(t, uiConfigData(spec)["REDIRECT_URI"], "http://tekton-hub-ui.tekton-hub.example.com")
+}
+
+func TestCheckSecrets(t *testing.T) {
+	client := &secretClient{secrets: map[string]map[string]interface{}{
+		apiSecret: {"GH_CLIENT_ID": "aWQ=", "GH_CLIENT_SECRET": "c2VjcmV0", "JWT_SIGNING_KEY": "a2V5"},
+	}}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{apiDeploymentObject(t)}), mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	instance := &v1alpha1.TektonHub{}
+	instance.Status.InitializeConditions()
+	util.AssertNoError(t, checkSecrets(context.Background(), &manifest, instance))
+
+	instance.Spec.Db.Secret = "hub-postgres"
+	client.secrets["hub-postgres"] = map[string]interface{}{"POSTGRES_HOST": "ZGI="}
+	err = checkSecrets(context.Background(), &manifest, instance)
+	if err == nil {
+		t.Fatal("checkSecrets() = nil, wanted an error for the missing database keys")
+	}
+	util.AssertEqual(t, err.Error(), "Secrets of the hub missing in namespace tekton-pipelines: "+
+		"hub-postgres/POSTGRES_PORT, hub-postgres/POSTGRES_DB, hub-postgres/POSTGRES_USER, hub-postgres/POSTGRES_PASSWORD")
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.DependenciesInstalled).IsFalse(), true)
+}
+
+func TestDBCredentials(t *testing.T) {
+	secret := &corev1.Secret{StringData: map[string]string{"POSTGRES_USER": "postgres"}}
+	secret.APIVersion = "v1"
+	secret.Kind = "Secret"
+	secret.Name = dbSecret
+	client := &secretClient{secrets: map[string]map[string]interface{}{}}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{util.MakeUnstructured(t, secret)}), mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	generated := manifest
+	util.AssertNoError(t, dbCredentials(context.Background(), &generated, &v1alpha1.TektonHub{}))
+	password, _, _ := unstructured.NestedString(generated.Resources()[0].Object, "stringData", "POSTGRES_PASSWORD")
+	if len(password) < 32 {
+		t.Fatalf("POSTGRES_PASSWORD = %q, wanted a generated password", password)
+	}
+
+	client.secrets[dbSecret] = map[string]interface{}{"POSTGRES_USER": "aHVi", "POSTGRES_PASSWORD": "cm90YXRlZA=="}
+	kept := manifest
+	util.AssertNoError(t, dbCredentials(context.Background(), &kept, &v1alpha1.TektonHub{}))
+	_, found, _ := unstructured.NestedMap(kept.Resources()[0].Object, "stringData")
+	util.AssertEqual(t, found, false)
+	data, _, _ := unstructured.NestedMap(kept.Resources()[0].Object, "data")
+	util.AssertDeepEqual(t, data, client.secrets[dbSecret])
+}
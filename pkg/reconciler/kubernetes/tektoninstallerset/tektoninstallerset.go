@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektoninstallerset
+
+import (
+	"context"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	tektonInstallerSetreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektoninstallerset"
+	operatorlisters "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+// Reconciler applies the manifests of TektonInstallerSets and deletes the
+// resources they no longer hold, e.g. a deployment a new payload version
+// removed. The resources are owned by the component of the installer set
+// already, whose deletion deletes them.
+type Reconciler struct {
+	// manifest is empty, but with a valid client and logger
+	manifest mf.Manifest
+	// installerSetLister lists the other installer sets, whose resources
+	// aren't orphans
+	installerSetLister operatorlisters.TektonInstallerSetLister
+}
+
+// Check that our Reconciler implements controller.Reconciler
+var _ tektonInstallerSetreconciler.Interface = (*Reconciler)(nil)
+
+// ReconcileKind applies the manifests of the installer set and reports the
+// outcome in its status, for the observed generation. A ready installer set
+// whose manifests were applied already is left alone.
+func (r *Reconciler) ReconcileKind(ctx context.Context, tis *v1alpha1.TektonInstallerSet) pkgreconciler.Event {
+	logger := logging.FromContext(ctx)
+	tis.Status.InitializeConditions()
+	hash, err := common.AppliedHash(tis)
+	if err != nil {
+		tis.Status.MarkInstallFailedWithReason(v1alpha1.ReasonInvalidPayload, err.Error())
+		return err
+	}
+	if tis.Status.IsReady() && tis.Status.ObservedGeneration == tis.Generation && tis.Status.AppliedHash == hash {
+		// e.g. enqueued by its own status update or a resync, applying
+		// the same manifests would only call the webhooks and fill the
+		// audit log
+		logger.Debug("Manifests applied already, skipping")
+		return nil
+	}
+	if tis.Status.ObservedGeneration != tis.Generation {
+		tis.Status.MarkInstallReconciling()
+	}
+	tis.Status.ObservedGeneration = tis.Generation
+
+	resources, err := mf.ManifestFrom(mf.Slice(tis.Spec.Manifests))
+	if err != nil {
+		tis.Status.MarkInstallFailedWithReason(v1alpha1.ReasonInvalidPayload, err.Error())
+		return err
+	}
+	manifest := r.manifest.Append(resources)
+	logger.Infow("Applying the installer set", "resources", len(manifest.Resources()))
+	ctx = common.WithServerSideApply(ctx, tis.Spec.ServerSideApply)
+	if err := common.Apply(ctx, &manifest, component(tis)); err != nil {
+		tis.Status.MarkInstallFailedWithReason(operrors.Reason(err), err.Error())
+		return err
+	}
+	if err := r.deleteOrphans(ctx, tis); err != nil {
+		tis.Status.MarkInstallFailedWithReason(v1alpha1.ReasonApplyFailed, err.Error())
+		return err
+	}
+	// the retained resources are tracked still, to be deleted once a
+	// later payload no longer holds them
+	tis.Status.Resources = append(installedResources(tis.Spec.Manifests), tis.Spec.Retained...)
+	tis.Status.AppliedHash = hash
+	tis.Status.MarkInstallSucceeded()
+	return nil
+}
+
+// deleteOrphans deletes the resources applied last which the manifests no
+// longer hold, unless another installer set holds them, e.g. after they
+// moved to another group of the addon, or they are retained, e.g. those of
+// a paused sub-payload. Resources are told apart by their group, kind,
+// namespace and name, a resource applied with another version of its API
+// being the same. CRDs and namespaces are kept, deleting them would delete
+// the resources of the users they hold.
+func (r *Reconciler) deleteOrphans(ctx context.Context, tis *v1alpha1.TektonInstallerSet) error {
+	if len(tis.Status.Resources) == 0 {
+		return nil
+	}
+	sets, err := r.installerSetLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	current := map[resourceKey]bool{}
+	for _, set := range append(sets, tis) {
+		if set.Name == tis.Name && set != tis {
+			// stale, the one being reconciled comes last
+			continue
+		}
+		for _, ref := range append(installedResources(set.Spec.Manifests), set.Spec.Retained...) {
+			current[keyOf(ref)] = true
+		}
+	}
+	var orphans []unstructured.Unstructured
+	for _, ref := range tis.Status.Resources {
+		if current[keyOf(ref)] {
+			continue
+		}
+		u := unstructured.Unstructured{}
+		u.SetAPIVersion(ref.APIVersion)
+		u.SetKind(ref.Kind)
+		u.SetNamespace(ref.Namespace)
+		u.SetName(ref.Name)
+		orphans = append(orphans, u)
+	}
+	resources, err := mf.ManifestFrom(mf.Slice(orphans))
+	if err != nil {
+		return err
+	}
+	manifest := r.manifest.Append(resources.Filter(mf.NoCRDs, mf.Not(mf.ByKind("Namespace"))))
+	if len(manifest.Resources()) == 0 {
+		return nil
+	}
+	logging.FromContext(ctx).Infow("Deleting the resources no longer in the installer set", "resources", len(manifest.Resources()))
+	return common.Uninstall(ctx, &manifest)
+}
+
+// resourceKey identifies a resource whatever the version of its API, e.g.
+// the CronJob of the pruner once applied as batch/v1 rather than
+// batch/v1beta1, which is the same object
+type resourceKey struct {
+	group, kind, namespace, name string
+}
+
+func keyOf(ref v1alpha1.InstalledResource) resourceKey {
+	gv, _ := schema.ParseGroupVersion(ref.APIVersion)
+	return resourceKey{group: gv.Group, kind: ref.Kind, namespace: ref.Namespace, name: ref.Name}
+}
+
+// installedResources returns the references to the resources
+func installedResources(resources []unstructured.Unstructured) []v1alpha1.InstalledResource {
+	refs := make([]v1alpha1.InstalledResource, 0, len(resources))
+	for _, u := range resources {
+		refs = append(refs, v1alpha1.InstalledResource{
+			APIVersion: u.GetAPIVersion(),
+			Kind:       u.GetKind(),
+			Namespace:  u.GetNamespace(),
+			Name:       u.GetName(),
+		})
+	}
+	return refs
+}
+
+// component returns the name of the component owning the installer set,
+// the name of the installer set if it has no owner.
+func component(tis *v1alpha1.TektonInstallerSet) string {
+	if owner := metav1.GetControllerOf(tis); owner != nil {
+		return owner.Name
+	}
+	return tis.Name
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektoninstallerset
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	fake "github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorlisters "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+func configMap(name string) unstructured.Unstructured {
+	u := unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind("ConfigMap")
+	u.SetNamespace("tekton-pipelines")
+	u.SetName(name)
+	return u
+}
+
+func TestReconcileKind(t *testing.T) {
+	client := fake.New()
+	manifest, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(client))
+	util.AssertNoError(t, err)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	r := &Reconciler{manifest: manifest, installerSetLister: operatorlisters.NewTektonInstallerSetLister(indexer)}
+
+	tis := &v1alpha1.TektonInstallerSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "tektonpipeline-pipeline", Generation: 2},
+		Spec: v1alpha1.TektonInstallerSetSpec{
+			Manifests: []unstructured.Unstructured{configMap("config-defaults"), configMap("feature-flags")},
+		},
+	}
+	util.AssertNoError(t, r.ReconcileKind(context.Background(), tis))
+	util.AssertEqual(t, tis.Status.IsReady(), true)
+	util.AssertEqual(t, tis.Status.ObservedGeneration, int64(2))
+	for _, name := range []string{"config-defaults", "feature-flags"} {
+		cm := configMap(name)
+		if _, err := client.Get(&cm); err != nil {
+			t.Errorf("ConfigMap %s not applied: %v", name, err)
+		}
+	}
+
+	// applied already, nothing to do until the manifests change or a
+	// reapply is asked for
+	var applied int
+	get := client.Stubs.Get
+	client.Stubs.Get = func(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		applied++
+		return get(u)
+	}
+	r.manifest, err = mf.ManifestFrom(mf.Slice{}, mf.UseClient(client))
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, r.ReconcileKind(context.Background(), tis))
+	util.AssertEqual(t, applied, 0)
+	tis.Annotations = map[string]string{"operator.tekton.dev/reapplied-at": "2021-06-01T12:00:00Z"}
+	util.AssertNoError(t, r.ReconcileKind(context.Background(), tis))
+	util.AssertEqual(t, applied, 2)
+	client.Stubs.Get = get
+
+	client.Stubs.Create = func(*unstructured.Unstructured) error { return errors.New("boom") }
+	r.manifest, err = mf.ManifestFrom(mf.Slice{}, mf.UseClient(client))
+	util.AssertNoError(t, err)
+	tis.Generation = 3
+	tis.Spec.Manifests = append(tis.Spec.Manifests, configMap("config-logging"))
+	if err := r.ReconcileKind(context.Background(), tis); err == nil {
+		t.Fatal("ReconcileKind() = nil, wanted the error of the apply")
+	}
+	util.AssertEqual(t, tis.Status.ObservedGeneration, int64(3))
+	util.AssertEqual(t, tis.Status.GetCondition(v1alpha1.InstallSucceeded).IsFalse(), true)
+}
+
+func TestDeleteOrphans(t *testing.T) {
+	crd := unstructured.Unstructured{}
+	crd.SetAPIVersion("apiextensions.k8s.io/v1")
+	crd.SetKind("CustomResourceDefinition")
+	crd.SetName("pipelines.tekton.dev")
+	ns := unstructured.Unstructured{}
+	ns.SetAPIVersion("v1")
+	ns.SetKind("Namespace")
+	ns.SetName("tekton-pipelines")
+	previous := []unstructured.Unstructured{configMap("config-defaults"), configMap("feature-flags"), configMap("moved"), configMap("paused"), crd, ns}
+	var objs []runtime.Object
+	for i := range previous {
+		objs = append(objs, &previous[i])
+	}
+	client := fake.New(objs...)
+	manifest, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	// the ConfigMap moved to another installer set
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	util.AssertNoError(t, indexer.Add(&v1alpha1.TektonInstallerSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "tektonaddon-addon-clustertasks"},
+		Spec:       v1alpha1.TektonInstallerSetSpec{Manifests: []unstructured.Unstructured{configMap("moved")}},
+	}))
+	r := &Reconciler{manifest: manifest, installerSetLister: operatorlisters.NewTektonInstallerSetLister(indexer)}
+
+	tis := &v1alpha1.TektonInstallerSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "tektonaddon-addon"},
+		Spec: v1alpha1.TektonInstallerSetSpec{
+			Manifests: []unstructured.Unstructured{configMap("config-defaults")},
+			// e.g. paused after it was installed
+			Retained: installedResources([]unstructured.Unstructured{configMap("paused")}),
+		},
+	}
+	tis.Status.Resources = installedResources(previous)
+	util.AssertNoError(t, r.ReconcileKind(context.Background(), tis))
+	util.AssertDeepEqual(t, tis.Status.Resources, installedResources([]unstructured.Unstructured{configMap("config-defaults"), configMap("paused")}))
+
+	for _, u := range previous {
+		_, err := client.Get(&u)
+		deleted := apierrors.IsNotFound(err)
+		util.AssertEqual(t, deleted, u.GetName() == "feature-flags")
+	}
+}
+
+func TestDeleteOrphansVersionChanged(t *testing.T) {
+	cronJob := func(apiVersion string) unstructured.Unstructured {
+		u := unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind("CronJob")
+		u.SetNamespace("tekton-pipelines")
+		u.SetName("tekton-resource-pruner")
+		return u
+	}
+	client := fake.New()
+	// the API server serves the object under both versions
+	get := client.Stubs.Get
+	client.Stubs.Get = func(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		if u.GetKind() == "CronJob" {
+			return u.DeepCopy(), nil
+		}
+		return get(u)
+	}
+	var deleted []string
+	client.Stubs.Delete = func(u *unstructured.Unstructured) error {
+		deleted = append(deleted, u.GetName())
+		return nil
+	}
+	manifest, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(client))
+	util.AssertNoError(t, err)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	r := &Reconciler{manifest: manifest, installerSetLister: operatorlisters.NewTektonInstallerSetLister(indexer)}
+
+	// the same CronJob, applied as batch/v1 once the cluster serves it
+	tis := &v1alpha1.TektonInstallerSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "tektonconfig-pruner"},
+		Spec:       v1alpha1.TektonInstallerSetSpec{Manifests: []unstructured.Unstructured{cronJob("batch/v1")}},
+	}
+	tis.Status.Resources = installedResources([]unstructured.Unstructured{cronJob("batch/v1beta1")})
+	util.AssertNoError(t, r.ReconcileKind(context.Background(), tis))
+	util.AssertEqual(t, len(deleted), 0)
+	util.AssertDeepEqual(t, tis.Status.Resources, installedResources([]unstructured.Unstructured{cronJob("batch/v1")}))
+}
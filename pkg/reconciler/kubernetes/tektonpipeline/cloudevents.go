@@ -0,0 +1,260 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonpipeline
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// cloudEventsSinkKey is the key of config-defaults the controller reads
+	// the sink from
+	cloudEventsSinkKey = "default-cloud-events-sink"
+	// eventsListener names the ConfigMap, Deployment and Service relaying
+	// the events of the runs to the sink
+	eventsListener = "tekton-events-listener"
+	// eventsListenerImage serves the relay, rendering the environment into
+	// its configuration templates on startup. The container is named after
+	// the listener for spec.images and IMAGE_PIPELINES_TEKTON_EVENTS_LISTENER
+	// to replace it.
+	eventsListenerImage = "nginxinc/nginx-unprivileged:1.19.10-alpine"
+	eventsListenerPort  = 8080
+	// eventsTokenEnv holds the token of spec.cloudEvents.authSecret
+	eventsTokenEnv = "SINK_TOKEN"
+	// sinkReservedChars would end or extend the proxy_pass directive of
+	// the relay configuration, or be rendered as variables
+	sinkReservedChars = " \t\r\n;{}$\"'\\#"
+)
+
+// cloudEventsSink returns the default-cloud-events-sink of
+// spec.cloudEvents: the listener when enabled, the sink otherwise.
+func cloudEventsSink(instance *v1alpha1.TektonPipeline) (string, error) {
+	events := instance.Spec.CloudEvents
+	if events == nil {
+		return "", nil
+	}
+	if err := validateSink(events.Sink); err != nil {
+		return "", err
+	}
+	if events.ListenerEnabled() {
+		return fmt.Sprintf("http://%s.%s.svc:%d", eventsListener, instance.Spec.GetTargetNamespace(), eventsListenerPort), nil
+	}
+	return events.Sink, nil
+}
+
+func validateSink(sink string) error {
+	u, err := url.Parse(sink)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid spec.cloudEvents.sink %q, expected an http or https URL", sink)
+	}
+	if strings.ContainsAny(sink, sinkReservedChars) {
+		return fmt.Errorf("invalid spec.cloudEvents.sink %q, whitespace and the characters ;{}$\"'\\# are not allowed", sink)
+	}
+	return nil
+}
+
+// cloudEventsListener is a Stage appending the listener relaying the
+// events to the sink when spec.cloudEvents enables it, before the
+// transformers for it to be namespaced, owned and labelled like the
+// payload. The listener of a previous spec is deleted otherwise.
+func (r *Reconciler) cloudEventsListener(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	instance := comp.(*v1alpha1.TektonPipeline)
+	events := instance.Spec.CloudEvents
+	if !events.ListenerEnabled() {
+		return deleteCloudEventsListener(ctx, manifest, instance.Spec.GetTargetNamespace())
+	}
+	if err := validateSink(events.Sink); err != nil {
+		err := &operrors.TransformError{Err: err}
+		operrors.MarkFailed(instance.GetStatus(), err)
+		return err
+	}
+	resources, err := listenerResources(events, r.listenerImage())
+	if err != nil {
+		return err
+	}
+	listener, err := mf.ManifestFrom(mf.Slice(resources))
+	if err != nil {
+		return err
+	}
+	*manifest = manifest.Append(listener)
+	return nil
+}
+
+// listenerImage returns the image of the relay, the operator's
+// IMAGE_PIPELINES_TEKTON_EVENTS_LISTENER override taking precedence over the
+// default.
+func (r *Reconciler) listenerImage() string {
+	images := common.ToLowerCaseKeys(r.images.Images(common.PipelinesImagePrefix))
+	if image := images[common.NormalizeKey("", eventsListener)]; image != "" {
+		return image
+	}
+	return eventsListenerImage
+}
+
+// deleteCloudEventsListener deletes the listener the operator generated in
+// the namespace, if any.
+func deleteCloudEventsListener(ctx context.Context, manifest *mf.Manifest, namespace string) error {
+	if manifest.Client == nil {
+		return nil
+	}
+	deployment := &unstructured.Unstructured{}
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+	deployment.SetNamespace(namespace)
+	deployment.SetName(eventsListener)
+	live, err := manifest.Client.Get(deployment)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if live.GetLabels()[common.LabelGenerated] != "true" {
+		return nil
+	}
+	logging.FromContext(ctx).Infow("Deleting the CloudEvents listener", "namespace", namespace)
+	for _, kind := range []string{"Deployment", "Service", "ConfigMap"} {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		if kind == "Deployment" {
+			u.SetAPIVersion("apps/v1")
+		}
+		u.SetKind(kind)
+		u.SetNamespace(namespace)
+		u.SetName(eventsListener)
+		if err := manifest.Client.Delete(u); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// listenerConfig returns the configuration template of the relay, the
+// environment variables in it being rendered on startup.
+func listenerConfig(events *v1alpha1.CloudEvents) string {
+	auth := ""
+	if events.AuthSecret != nil {
+		auth = fmt.Sprintf("        proxy_set_header Authorization \"Bearer ${%s}\";\n", eventsTokenEnv)
+	}
+	return fmt.Sprintf(`server {
+    listen %d;
+    location / {
+        proxy_pass %s;
+        proxy_ssl_server_name on;
+%s    }
+}
+`, eventsListenerPort, events.Sink, auth)
+}
+
+// listenerResources returns the ConfigMap, Deployment and Service of the
+// listener relaying the events to the sink.
+func listenerResources(events *v1alpha1.CloudEvents, image string) ([]unstructured.Unstructured, error) {
+	labels := map[string]string{
+		common.LabelGenerated:       "true",
+		"app.kubernetes.io/name":    eventsListener,
+		"app.kubernetes.io/part-of": "tekton-pipelines",
+	}
+	selector := map[string]string{"app.kubernetes.io/name": eventsListener}
+	meta := metav1.ObjectMeta{Name: eventsListener, Labels: labels}
+
+	configMap := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: meta,
+		Data:       map[string]string{"default.conf.template": listenerConfig(events)},
+	}
+
+	container := corev1.Container{
+		Name:         eventsListener,
+		Image:        image,
+		Ports:        []corev1.ContainerPort{{Name: "http", ContainerPort: eventsListenerPort}},
+		VolumeMounts: []corev1.VolumeMount{{Name: "templates", MountPath: "/etc/nginx/templates", ReadOnly: true}},
+	}
+	if ref := events.AuthSecret; ref != nil {
+		container.Env = []corev1.EnvVar{{
+			Name: eventsTokenEnv,
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: ref.Name},
+				Key:                  ref.Key,
+			}},
+		}}
+	}
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: meta,
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{container},
+					Volumes: []corev1.Volume{{
+						Name: "templates",
+						VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: eventsListener},
+						}},
+					}},
+				},
+			},
+		},
+	}
+
+	service := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: meta,
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       eventsListenerPort,
+				TargetPort: intstr.FromString("http"),
+			}},
+		},
+	}
+
+	var resources []unstructured.Unstructured
+	for _, obj := range []runtime.Object{configMap, deployment, service} {
+		object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		u := unstructured.Unstructured{Object: object}
+		// the converter sets the empty status and creation timestamps
+		unstructured.RemoveNestedField(u.Object, "status")
+		unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(u.Object, "spec", "template", "metadata", "creationTimestamp")
+		resources = append(resources, u)
+	}
+	return resources, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonpipeline
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCloudEventsSink(t *testing.T) {
+	instance := &v1alpha1.TektonPipeline{}
+	instance.Spec.TargetNamespace = "tekton-pipelines"
+	sink, err := cloudEventsSink(instance)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, sink, "")
+
+	instance.Spec.CloudEvents = &v1alpha1.CloudEvents{Sink: "https://events.example.com/tekton"}
+	sink, err = cloudEventsSink(instance)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, sink, "https://events.example.com/tekton")
+
+	instance.Spec.CloudEvents.AuthSecret = &v1alpha1.SecretKeyReference{Name: "events", Key: "token"}
+	sink, err = cloudEventsSink(instance)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, sink, "http://tekton-events-listener.tekton-pipelines.svc:8080")
+
+	for _, invalid := range []string{"", "events.example.com", "ftp://events.example.com", "https://",
+		"https://events.example.com; return 200", "https://events.example.com/${SINK_TOKEN}", "https://events.example.com/}"} {
+		instance.Spec.CloudEvents.Sink = invalid
+		if _, err := cloudEventsSink(instance); err == nil {
+			t.Errorf("expected an error for the sink %q", invalid)
+		}
+	}
+}
+
+func TestCloudEventsListener(t *testing.T) {
+	instance := &v1alpha1.TektonPipeline{}
+	instance.Status.InitializeConditions()
+	instance.Spec.CloudEvents = &v1alpha1.CloudEvents{Sink: "https://events.example.com", Listener: true}
+	r := &Reconciler{}
+	manifest, err := mf.ManifestFrom(mf.Slice{})
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, r.cloudEventsListener(context.Background(), &manifest, instance))
+
+	resources := manifest.Resources()
+	util.AssertEqual(t, len(resources), 3)
+	for _, u := range resources {
+		util.AssertEqual(t, u.GetName(), eventsListener)
+	}
+	config, _, _ := unstructured.NestedString(resources[0].Object, "data", "default.conf.template")
+	util.AssertEqual(t, strings.Contains(config, "proxy_pass https://events.example.com;"), true)
+	util.AssertEqual(t, strings.Contains(config, "Authorization"), false)
+	containers, _, _ := unstructured.NestedSlice(resources[1].Object, "spec", "template", "spec", "containers")
+	_, found, _ := unstructured.NestedSlice(containers[0].(map[string]interface{}), "env")
+	util.AssertEqual(t, found, false)
+	image, _, _ := unstructured.NestedString(containers[0].(map[string]interface{}), "image")
+	util.AssertEqual(t, image, eventsListenerImage)
+
+	instance.Spec.CloudEvents.AuthSecret = &v1alpha1.SecretKeyReference{Name: "events", Key: "token"}
+	manifest, _ = mf.ManifestFrom(mf.Slice{})
+	util.AssertNoError(t, r.cloudEventsListener(context.Background(), &manifest, instance))
+	resources = manifest.Resources()
+	config, _, _ = unstructured.NestedString(resources[0].Object, "data", "default.conf.template")
+	util.AssertEqual(t, strings.Contains(config, `proxy_set_header Authorization "Bearer ${SINK_TOKEN}";`), true)
+	containers, _, _ = unstructured.NestedSlice(resources[1].Object, "spec", "template", "spec", "containers")
+	env, _, _ := unstructured.NestedSlice(containers[0].(map[string]interface{}), "env")
+	ref, _, _ := unstructured.NestedStringMap(env[0].(map[string]interface{}), "valueFrom", "secretKeyRef")
+	util.AssertDeepEqual(t, ref, map[string]string{"name": "events", "key": "token"})
+
+	instance.Spec.CloudEvents.Sink = "events.example.com"
+	manifest, _ = mf.ManifestFrom(mf.Slice{})
+	if err := r.cloudEventsListener(context.Background(), &manifest, instance); err == nil {
+		t.Fatal("expected an error for an invalid sink")
+	}
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).Reason, v1alpha1.ReasonTransformError)
+}
+
+func TestCloudEventsListenerImage(t *testing.T) {
+	r := &Reconciler{images: common.NewImageStore()}
+	util.AssertEqual(t, r.listenerImage(), eventsListenerImage)
+
+	r.images.OnConfigMapChanged(&corev1.ConfigMap{Data: map[string]string{
+		"IMAGE_PIPELINES_TEKTON_EVENTS_LISTENER": "registry.example.com/nginx-unprivileged:1.19.10-alpine",
+	}})
+	util.AssertEqual(t, r.listenerImage(), "registry.example.com/nginx-unprivileged:1.19.10-alpine")
+}
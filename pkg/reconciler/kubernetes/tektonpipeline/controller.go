@@ -20,13 +20,15 @@ import (
 	"context"
 
 	"github.com/go-logr/zapr"
-	mfc "github.com/manifestival/client-go-client"
 	mf "github.com/manifestival/manifestival"
 	"go.uber.org/zap"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	operatorclient "github.com/tektoncd/operator/pkg/client/injection/client"
+	tektonInstallerSetinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektoninstallerset"
+	tektonOverrideinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonoverride"
 	tektonPipelineinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonpipeline"
 	tektonPipelinereconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektonpipeline"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
@@ -48,36 +50,61 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 func NewExtendedController(generator common.ExtensionGenerator) injection.ControllerConstructor {
 	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
 		tektonPipelineInformer := tektonPipelineinformer.Get(ctx)
+		tektonOverrideInformer := tektonOverrideinformer.Get(ctx)
+		tektonInstallerSetInformer := tektonInstallerSetinformer.Get(ctx)
 		deploymentInformer := deploymentinformer.Get(ctx)
 		kubeClient := kubeclient.Get(ctx)
 		logger := logging.FromContext(ctx)
 
-		mfclient, err := mfc.NewClient(injection.GetConfig(ctx))
+		mfclient, err := common.NewClient(injection.GetConfig(ctx))
 		if err != nil {
 			logger.Fatalw("Error creating client from injected config", zap.Error(err))
 		}
+		dynamicClient, err := dynamic.NewForConfig(injection.GetConfig(ctx))
+		if err != nil {
+			logger.Fatalw("Error creating dynamic client from injected config", zap.Error(err))
+		}
 		mflogger := zapr.NewLogger(logger.Named("manifestival").Desugar())
 		manifest, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(mfclient), mf.UseLogger(mflogger))
 		if err != nil {
 			logger.Fatalw("Error creating initial manifest", zap.Error(err))
 		}
 
+		common.ReportKeyCollisions(ctx, &v1alpha1.TektonPipeline{}, common.PipelinesImagePrefix)
+		images := common.NewImageStore()
+		ctx = common.WithImageStore(ctx, images)
+
 		c := &Reconciler{
 			kubeClientSet:     kubeClient,
 			operatorClientSet: operatorclient.Get(ctx),
+			dynamicClient:     dynamicClient,
 			extension:         generator(ctx),
+			images:            images,
 			manifest:          manifest,
+			overrideLister:    tektonOverrideInformer.Lister(),
+			leaderElection:    common.NewLeaderElectionCache(ctx, kubeClient),
+			pipelineLister:    tektonPipelineInformer.Lister(),
 		}
 		impl := tektonPipelinereconciler.NewImpl(ctx, c)
+		c.enqueueAfter = impl.EnqueueAfter
 
 		logger.Info("Setting up event handlers")
 
 		tektonPipelineInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+		tektonOverrideInformer.Informer().AddEventHandler(common.EnqueueOnOverride(impl.EnqueueKey, v1alpha1.KindTektonPipeline, common.PipelineResourceName))
+		tektonInstallerSetInformer.Informer().AddEventHandler(common.EnqueueOnInstallerSet(impl.EnqueueControllerOf, v1alpha1.KindTektonPipeline))
 
 		deploymentInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 			FilterFunc: controller.FilterControllerGVK(v1alpha1.SchemeGroupVersion.WithKind("TektonPipeline")),
 			Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 		})
+		common.WatchOwned(ctx, v1alpha1.KindTektonPipeline, impl.EnqueueControllerOf)
+
+		resyncImages := func() {
+			impl.GlobalResync(tektonPipelineInformer.Informer())
+		}
+		common.WatchImages(cmw, images, resyncImages)
+		common.WatchImagesSecret(ctx, kubeClient, images, resyncImages)
 
 		return impl
 	}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonpipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+)
+
+const configDefaultsConfig = "config-defaults"
+
+// configDefaults returns the data of the config-defaults ConfigMap set by
+// spec.defaults, or an error if a default has an invalid value.
+func configDefaults(defaults *v1alpha1.PipelineDefaults) (map[string]string, error) {
+	data := map[string]string{}
+	if defaults == nil {
+		return data, nil
+	}
+	if minutes := defaults.DefaultTimeoutMinutes; minutes != nil {
+		if *minutes < 0 {
+			return nil, fmt.Errorf("invalid spec.defaults.defaultTimeoutMinutes %d, expected 0 or more", *minutes)
+		}
+		data["default-timeout-minutes"] = strconv.Itoa(int(*minutes))
+	}
+	if defaults.DefaultServiceAccount != "" {
+		data["default-service-account"] = defaults.DefaultServiceAccount
+	}
+	if defaults.DefaultManagedByLabelValue != "" {
+		data["default-managed-by-label-value"] = defaults.DefaultManagedByLabelValue
+	}
+	if defaults.DefaultPodTemplate != nil {
+		// the payload parses the template as YAML, of which JSON is a subset
+		template, err := json.Marshal(defaults.DefaultPodTemplate)
+		if err != nil {
+			return nil, err
+		}
+		data["default-pod-template"] = string(template)
+	}
+	return data, nil
+}
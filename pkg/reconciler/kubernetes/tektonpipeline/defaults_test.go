@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonpipeline
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestConfigDefaults(t *testing.T) {
+	data, err := configDefaults(nil)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(data), 0)
+
+	timeout := int32(90)
+	data, err = configDefaults(&v1alpha1.PipelineDefaults{
+		DefaultTimeoutMinutes:      &timeout,
+		DefaultServiceAccount:      "pipeline",
+		DefaultManagedByLabelValue: "ci",
+		DefaultPodTemplate: &v1alpha1.PodTemplate{
+			NodeSelector: map[string]string{"pool": "builds"},
+			Tolerations:  []corev1.Toleration{{Key: "builds", Operator: corev1.TolerationOpExists}},
+		},
+	})
+	util.AssertNoError(t, err)
+	util.AssertDeepEqual(t, data, map[string]string{
+		"default-timeout-minutes":        "90",
+		"default-service-account":        "pipeline",
+		"default-managed-by-label-value": "ci",
+		"default-pod-template":           `{"nodeSelector":{"pool":"builds"},"tolerations":[{"key":"builds","operator":"Exists"}]}`,
+	})
+
+	negative := int32(-1)
+	if _, err := configDefaults(&v1alpha1.PipelineDefaults{DefaultTimeoutMinutes: &negative}); err == nil {
+		t.Error("expected an error for a negative defaultTimeoutMinutes")
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonpipeline
+
+import "github.com/tektoncd/operator/pkg/reconciler/common"
+
+// deprecations reports the keys of spec.options.configMaps replaced by
+// spec.featureFlags, spec.defaults and spec.cloudEvents, and the disruption
+// fields replaced by spec.disruptionPolicy.
+var deprecations = common.ReportDeprecations(common.DeprecatedDisruptionFields, common.DeprecatedConfigMapKeys(map[string]map[string]string{
+	featureFlagsConfig: {
+		"enable-api-fields":                             "spec.featureFlags.enableAPIFields",
+		"disable-affinity-assistant":                    "spec.featureFlags.disableAffinityAssistant",
+		"enable-custom-tasks":                           "spec.featureFlags.enableCustomTasks",
+		"running-in-environment-with-injected-sidecars": "spec.featureFlags.runningInEnvironmentWithInjectedSidecars",
+		"disable-creds-init":                            "spec.featureFlags.disableCredsInit",
+		"disable-home-env-overwrite":                    "spec.featureFlags.disableHomeEnvOverwrite",
+		"disable-working-directory-overwrite":           "spec.featureFlags.disableWorkingDirOverwrite",
+		"enable-tekton-oci-bundles":                     "spec.featureFlags.enableTektonOCIBundles",
+		"require-git-ssh-secret-known-hosts":            "spec.featureFlags.requireGitSSHSecretKnownHosts",
+	},
+	configDefaultsConfig: {
+		"default-timeout-minutes":        "spec.defaults.defaultTimeoutMinutes",
+		"default-service-account":        "spec.defaults.defaultServiceAccount",
+		"default-managed-by-label-value": "spec.defaults.defaultManagedByLabelValue",
+		"default-pod-template":           "spec.defaults.defaultPodTemplate",
+		cloudEventsSinkKey:               "spec.cloudEvents.sink",
+	},
+}))
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonpipeline
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const featureFlagsConfig = "feature-flags"
+
+// apiFields are the values of enable-api-fields
+var apiFields = sets.NewString("stable", "beta", "alpha")
+
+// featureFlags returns the data of the feature-flags ConfigMap set by
+// spec.featureFlags, or an error if a flag has an invalid value.
+func featureFlags(flags *v1alpha1.PipelineFeatureFlags) (map[string]string, error) {
+	data := map[string]string{}
+	if flags == nil {
+		return data, nil
+	}
+	if flags.EnableAPIFields != "" {
+		if !apiFields.Has(flags.EnableAPIFields) {
+			return nil, fmt.Errorf("invalid spec.featureFlags.enableAPIFields %q, expected one of %s",
+				flags.EnableAPIFields, strings.Join(apiFields.List(), ", "))
+		}
+		data["enable-api-fields"] = flags.EnableAPIFields
+	}
+	for key, value := range map[string]*bool{
+		"disable-affinity-assistant":                    flags.DisableAffinityAssistant,
+		"enable-custom-tasks":                           flags.EnableCustomTasks,
+		"running-in-environment-with-injected-sidecars": flags.RunningInEnvironmentWithInjectedSidecars,
+		"disable-creds-init":                            flags.DisableCredsInit,
+		"disable-home-env-overwrite":                    flags.DisableHomeEnvOverwrite,
+		"disable-working-directory-overwrite":           flags.DisableWorkingDirOverwrite,
+		"enable-tekton-oci-bundles":                     flags.EnableTektonOCIBundles,
+		"require-git-ssh-secret-known-hosts":            flags.RequireGitSSHSecretKnownHosts,
+	} {
+		if value != nil {
+			data[key] = strconv.FormatBool(*value)
+		}
+	}
+	return data, nil
+}
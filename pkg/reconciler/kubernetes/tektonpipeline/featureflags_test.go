@@ -0,0 +1,48 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonpipeline
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+)
+
+func TestFeatureFlags(t *testing.T) {
+	yes, no := true, false
+
+	data, err := featureFlags(nil)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(data), 0)
+
+	data, err = featureFlags(&v1alpha1.PipelineFeatureFlags{
+		EnableAPIFields:                          "alpha",
+		DisableAffinityAssistant:                 &yes,
+		RunningInEnvironmentWithInjectedSidecars: &no,
+	})
+	util.AssertNoError(t, err)
+	util.AssertDeepEqual(t, data, map[string]string{
+		"enable-api-fields":                             "alpha",
+		"disable-affinity-assistant":                    "true",
+		"running-in-environment-with-injected-sidecars": "false",
+	})
+
+	if _, err := featureFlags(&v1alpha1.PipelineFeatureFlags{EnableAPIFields: "experimental"}); err == nil {
+		t.Error("expected an error for an invalid enableAPIFields")
+	}
+}
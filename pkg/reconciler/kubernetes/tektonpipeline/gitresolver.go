@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonpipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	gitResolverConfig     = "git-resolver-config"
+	resolversDeployment   = "tekton-pipelines-remote-resolvers"
+	gitResolverSSHVolume  = "git-resolver-ssh-key"
+	gitResolverSSHPath    = "/home/nonroot/.ssh"
+	gitResolverSSHKeyFile = "id_rsa"
+)
+
+// configureGitResolver returns a transformer rendering spec.gitResolver into
+// the ConfigMap of the git resolver, the API token being read by the
+// resolver from its namespace, and mounting the SSH key into the resolvers
+// deployment. Payloads without the resolver are left unchanged.
+func configureGitResolver(resolver *v1alpha1.GitResolver) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if resolver == nil {
+			return nil
+		}
+		switch {
+		case u.GetKind() == "ConfigMap" && u.GetName() == gitResolverConfig:
+			data, _, err := unstructured.NestedStringMap(u.Object, "data")
+			if err != nil {
+				return err
+			}
+			if data == nil {
+				data = map[string]string{}
+			}
+			if resolver.ServerURL != "" {
+				data["server-url"] = resolver.ServerURL
+			}
+			if resolver.SCMType != "" {
+				data["scm-type"] = resolver.SCMType
+			}
+			if token := resolver.APITokenSecret; token != nil {
+				data["api-token-secret-name"] = token.Name
+				data["api-token-secret-key"] = token.Key
+				data["api-token-secret-namespace"] = u.GetNamespace()
+			}
+			return unstructured.SetNestedStringMap(u.Object, data, "data")
+		case u.GetKind() == "Deployment" && u.GetName() == resolversDeployment && resolver.SSHKeySecret != nil:
+			return mountSSHKey(u, resolver.SSHKeySecret)
+		}
+		return nil
+	}
+}
+
+func mountSSHKey(u *unstructured.Unstructured, secret *v1alpha1.SecretKeyReference) error {
+	template, found, err := unstructured.NestedMap(u.Object, "spec", "template", "spec")
+	if err != nil || !found {
+		return err
+	}
+	podSpec := &corev1.PodSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template, podSpec); err != nil {
+		return err
+	}
+
+	mode := int32(0400)
+	volumes := podSpec.Volumes[:0]
+	for _, volume := range podSpec.Volumes {
+		if volume.Name != gitResolverSSHVolume {
+			volumes = append(volumes, volume)
+		}
+	}
+	podSpec.Volumes = append(volumes, corev1.Volume{
+		Name: gitResolverSSHVolume,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secret.Name,
+				Items:      []corev1.KeyToPath{{Key: secret.Key, Path: gitResolverSSHKeyFile, Mode: &mode}},
+			},
+		},
+	})
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		mounted := false
+		for _, mount := range container.VolumeMounts {
+			mounted = mounted || mount.Name == gitResolverSSHVolume
+		}
+		if !mounted {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      gitResolverSSHVolume,
+				MountPath: gitResolverSSHPath,
+				ReadOnly:  true,
+			})
+		}
+	}
+
+	unstrObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(podSpec)
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedMap(u.Object, unstrObj, "spec", "template", "spec")
+}
+
+// checkGitResolverSecrets is a Stage making sure the secrets referenced by
+// spec.gitResolver exist in the namespace of the resolver before anything
+// is applied, marking the dependency missing otherwise.
+func checkGitResolverSecrets(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	instance := comp.(*v1alpha1.TektonPipeline)
+	resolver := instance.Spec.GitResolver
+	if resolver == nil {
+		return nil
+	}
+	configs := manifest.Filter(mf.ByKind("ConfigMap"), mf.ByName(gitResolverConfig)).Resources()
+	if len(configs) == 0 {
+		return nil
+	}
+	namespace := configs[0].GetNamespace()
+
+	var missing []string
+	for _, ref := range []*v1alpha1.SecretKeyReference{resolver.APITokenSecret, resolver.SSHKeySecret} {
+		if ref == nil {
+			continue
+		}
+		secret := &unstructured.Unstructured{}
+		secret.SetAPIVersion("v1")
+		secret.SetKind("Secret")
+		secret.SetNamespace(namespace)
+		secret.SetName(ref.Name)
+		live, err := manifest.Client.Get(secret)
+		if apierrors.IsNotFound(err) {
+			missing = append(missing, ref.Name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if _, found, _ := unstructured.NestedString(live.Object, "data", ref.Key); !found {
+			missing = append(missing, ref.Name+"/"+ref.Key)
+		}
+	}
+	if len(missing) != 0 {
+		msg := fmt.Sprintf("Secrets of the git resolver missing in namespace %s: %s", namespace, strings.Join(missing, ", "))
+		err := &operrors.DependencyMissing{Err: errors.New(msg)}
+		operrors.MarkFailed(&instance.Status, err)
+		return err
+	}
+	return nil
+}
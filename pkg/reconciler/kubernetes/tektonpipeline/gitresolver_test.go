@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonpipeline
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// secretClient serves the Get of the secrets it holds
+type secretClient struct {
+	mf.Client
+	secrets map[string]map[string]interface{}
+}
+
+func (c *secretClient) Get(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	data, ok := c.secrets[u.GetName()]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, u.GetName())
+	}
+	live := u.DeepCopy()
+	live.Object["data"] = data
+	return live, nil
+}
+
+func resolverConfigMap() unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": gitResolverConfig, "namespace": "tekton-pipelines-resolvers"},
+		"data":       map[string]interface{}{"fetch-timeout": "1m"},
+	}}
+}
+
+func TestConfigureGitResolver(t *testing.T) {
+	resolver := &v1alpha1.GitResolver{
+		ServerURL:      "https://github.example.com",
+		SCMType:        "github",
+		APITokenSecret: &v1alpha1.SecretKeyReference{Name: "git-token", Key: "token"},
+		SSHKeySecret:   &v1alpha1.SecretKeyReference{Name: "git-ssh", Key: "ssh-privatekey"},
+	}
+
+	cm := resolverConfigMap()
+	util.AssertNoError(t, configureGitResolver(resolver)(&cm))
+	data, _, _ := unstructured.NestedStringMap(cm.Object, "data")
+	util.AssertDeepEqual(t, data, map[string]string{
+		"fetch-timeout":              "1m",
+		"server-url":                 "https://github.example.com",
+		"scm-type":                   "github",
+		"api-token-secret-name":      "git-token",
+		"api-token-secret-key":       "token",
+		"api-token-secret-namespace": "tekton-pipelines-resolvers",
+	})
+
+	deployment := util.MakeUnstructured(t, util.MakeDeployment(resolversDeployment, corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "controller", Image: "gcr.io/resolvers:v1"}},
+	}))
+	util.AssertNoError(t, configureGitResolver(resolver)(&deployment))
+	util.AssertNoError(t, configureGitResolver(resolver)(&deployment))
+	volumes, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "volumes")
+	util.AssertEqual(t, len(volumes), 1)
+	mounts, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+	util.AssertDeepEqual(t, mounts[0].(map[string]interface{})["volumeMounts"], []interface{}{map[string]interface{}{
+		"name":      gitResolverSSHVolume,
+		"mountPath": gitResolverSSHPath,
+		"readOnly":  true,
+	}})
+
+	// anonymous cloning leaves the payload as it is
+	cm = resolverConfigMap()
+	util.AssertNoError(t, configureGitResolver(nil)(&cm))
+	util.AssertDeepEqual(t, cm.Object, resolverConfigMap().Object)
+}
+
+func TestCheckGitResolverSecrets(t *testing.T) {
+	client := &secretClient{secrets: map[string]map[string]interface{}{
+		"git-token": {"token": "dG9rZW4="},
+	}}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{resolverConfigMap()}), mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	instance := &v1alpha1.TektonPipeline{}
+	instance.Status.InitializeConditions()
+	instance.Spec.GitResolver = &v1alpha1.GitResolver{
+		APITokenSecret: &v1alpha1.SecretKeyReference{Name: "git-token", Key: "token"},
+	}
+	util.AssertNoError(t, checkGitResolverSecrets(context.Background(), &manifest, instance))
+
+	instance.Spec.GitResolver.SSHKeySecret = &v1alpha1.SecretKeyReference{Name: "git-ssh", Key: "ssh-privatekey"}
+	err = checkGitResolverSecrets(context.Background(), &manifest, instance)
+	if err == nil {
+		t.Fatal("checkGitResolverSecrets() = nil, wanted an error for the missing SSH key")
+	}
+	util.AssertEqual(t, err.Error(), "Secrets of the git resolver missing in namespace tekton-pipelines-resolvers: git-ssh")
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.DependenciesInstalled).IsFalse(), true)
+}
@@ -18,16 +18,21 @@ package tektonpipeline
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	mf "github.com/manifestival/manifestival"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	clientset "github.com/tektoncd/operator/pkg/client/clientset/versioned"
 	tektonpipelinereconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektonpipeline"
+	operatorlisters "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 )
@@ -38,6 +43,9 @@ type Reconciler struct {
 	kubeClientSet kubernetes.Interface
 	// operatorClientSet allows us to configure operator objects
 	operatorClientSet clientset.Interface
+	// dynamicClient is used to rewrite custom resources when the
+	// storage version of a CRD changes
+	dynamicClient dynamic.Interface
 	// manifest is empty, but with a valid client and logger. all
 	// manifests are immutable, and any created during reconcile are
 	// expected to be appended to this one, obviating the passing of
@@ -45,6 +53,18 @@ type Reconciler struct {
 	manifest mf.Manifest
 	// Platform-specific behavior to affect the transform
 	extension common.Extension
+	// images holds the IMAGE_PIPELINES_ overrides of the operator
+	images *common.ImageStore
+	// overrideLister lists the TektonOverrides layered on top of the spec
+	overrideLister operatorlisters.TektonOverrideLister
+	// pipelineLister gets the latest generation of the TektonPipeline being reconciled
+	pipelineLister operatorlisters.TektonPipelineLister
+	// enqueueAfter schedules another reconcile, e.g. to resume a storage
+	// version migration
+	enqueueAfter func(obj interface{}, after time.Duration)
+	// leaderElection caches the leader election records of the target
+	// namespace
+	leaderElection *common.LeaderElectionCache
 }
 
 // Check that our Reconciler implements controller.Reconciler
@@ -53,6 +73,7 @@ var _ tektonpipelinereconciler.Finalizer = (*Reconciler)(nil)
 
 // FinalizeKind removes all resources after deletion of a TektonPipeline.
 func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.TektonPipeline) pkgreconciler.Event {
+	ctx = common.WithCorrelationID(ctx)
 	logger := logging.FromContext(ctx)
 
 	// List all TektonPipelines to determine if cluster-scoped resources should be deleted.
@@ -77,6 +98,22 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.Tekton
 		logger.Error("Unable to fetch installed manifest; no cluster-scoped resources will be finalized", err)
 		return nil
 	}
+	kubeClient := r.kubeClientSet
+	cfg, err := common.TargetCluster(ctx, r.kubeClientSet, manifest, original)
+	if err != nil {
+		logger.Error("Unable to reach the target cluster; no cluster-scoped resources will be finalized", err)
+		return nil
+	}
+	if cfg != nil {
+		if kubeClient, err = kubernetes.NewForConfig(cfg); err != nil {
+			logger.Error("Unable to reach the target cluster; no cluster-scoped resources will be finalized", err)
+			return nil
+		}
+	}
+	if err := common.ProtectTargetNamespace(kubeClient)(ctx, manifest, original); err != nil {
+		logger.Error("Unable to verify the target namespace; it will not be finalized", err)
+		*manifest = manifest.Filter(mf.Not(mf.ByKind("Namespace")))
+	}
 	if err := common.Uninstall(ctx, manifest); err != nil {
 		logger.Error("Failed to finalize platform resources", err)
 	}
@@ -86,9 +123,9 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.Tekton
 // ReconcileKind compares the actual state with the desired, and attempts to
 // converge the two.
 func (r *Reconciler) ReconcileKind(ctx context.Context, tp *v1alpha1.TektonPipeline) pkgreconciler.Event {
+	ctx = common.WithCorrelationID(ctx)
 	logger := logging.FromContext(ctx)
-	tp.Status.InitializeConditions()
-	tp.Status.ObservedGeneration = tp.Generation
+	common.InitializeStatus(tp)
 
 	logger.Infow("Reconciling TektonPipeline", "status", tp.Status)
 	if tp.GetName() != common.PipelineResourceName {
@@ -97,21 +134,74 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tp *v1alpha1.TektonPipel
 			tp.GetName(),
 		)
 		logger.Error(msg)
-		tp.GetStatus().MarkInstallFailed(msg)
-		return nil
+		operrors.MarkFailed(tp.GetStatus(), &operrors.ResourceIgnored{Err: errors.New(msg)})
+		return common.ObserveGeneration(ctx, tp, nil)
 	}
 
+	overrides, err := common.ComponentOverrides(r.overrideLister, v1alpha1.KindTektonPipeline)
+	if err != nil {
+		return err
+	}
+	ctx = common.WithOverrides(ctx, overrides)
+	ctx = common.WithInstallerSets(ctx, r.operatorClientSet.OperatorV1alpha1().TektonInstallerSets())
+	ctx = common.WithLatestGeneration(ctx, func(name string) (int64, error) {
+		latest, err := r.pipelineLister.Get(name)
+		if err != nil {
+			return 0, err
+		}
+		return latest.Generation, nil
+	})
+	ctx = common.WithRequeue(ctx, func(after time.Duration) {
+		r.enqueueAfter(tp, after)
+	})
+
 	if err := r.extension.PreReconcile(ctx, tp); err != nil {
 		return err
 	}
+	manifest := r.manifest.Append()
+	dynamicClient := r.dynamicClient
+	kubeClient := r.kubeClientSet
+	leaderElection := r.leaderElection
+	cfg, err := common.TargetCluster(ctx, r.kubeClientSet, &manifest, tp)
+	if err != nil {
+		operrors.MarkFailed(&tp.Status, err)
+		return err
+	}
+	if cfg != nil {
+		if dynamicClient, err = dynamic.NewForConfig(cfg); err != nil {
+			return err
+		}
+		if kubeClient, err = kubernetes.NewForConfig(cfg); err != nil {
+			return err
+		}
+		// the informers watch the cluster of the operator only
+		leaderElection = nil
+	}
 	stages := common.Stages{
+		deprecations,
+		common.BlockDowngrades,
 		common.AppendTarget,
+		r.cloudEventsListener,
 		r.transform,
-		common.Install,
+		common.NetworkPolicies,
+		common.PodDisruptionBudgets,
+		common.HorizontalPodAutoscalers,
+		common.Observability,
+		common.ServiceMonitors,
+		checkGitResolverSecrets,
+		common.DebugBundle(r.kubeClientSet),
+		common.PinImageDigests,
+		common.VerifyImageSignatures,
+		common.PrecheckImagePulls,
+		common.ValidateCustomResources,
+		common.CheckResourceSizes,
+		common.SkipUnchanged(common.Install),
+		common.RecordAPIVersions,
 		common.CheckDeployments,
+		common.CleanupLeaderElection(kubeClient, leaderElection),
+		common.MigrateStorageVersions(dynamicClient),
 	}
-	manifest := r.manifest.Append()
-	return stages.Execute(ctx, &manifest, tp)
+	return common.ObserveGeneration(ctx, tp, stages.Execute(ctx, &manifest, tp))
 }
 
 // transform mutates the passed manifest to one with common, component
@@ -119,18 +209,45 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tp *v1alpha1.TektonPipel
 func (r *Reconciler) transform(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
 	//logger := logging.FromContext(ctx)
 	instance := comp.(*v1alpha1.TektonPipeline)
-	extra := []mf.Transformer{
-		common.ApplyProxySettings,
+	flags, err := featureFlags(instance.Spec.FeatureFlags)
+	if err != nil {
+		err = &operrors.TransformError{Err: err}
+		operrors.MarkFailed(&instance.Status, err)
+		return err
+	}
+	defaults, err := configDefaults(instance.Spec.Defaults)
+	if err != nil {
+		err = &operrors.TransformError{Err: err}
+		operrors.MarkFailed(&instance.Status, err)
+		return err
+	}
+	sink, err := cloudEventsSink(instance)
+	if err != nil {
+		err = &operrors.TransformError{Err: err}
+		operrors.MarkFailed(&instance.Status, err)
+		return err
+	}
+	if sink != "" {
+		defaults[cloudEventsSinkKey] = sink
 	}
-	extra = append(extra, r.extension.Transformers(instance)...)
-	return common.Transform(ctx, manifest, instance, extra...)
+	extra := append(r.extension.Transformers(instance),
+		configureGitResolver(instance.Spec.GitResolver),
+		common.ConfigMapData(map[string]map[string]string{
+			featureFlagsConfig:   flags,
+			configDefaultsConfig: defaults,
+		}))
+	// spec.images come after extra to take precedence over the extension's images
+	return common.TransformWith(ctx, manifest, instance, common.TransformOptions{
+		Transformers: extra,
+		Images:       instance.Spec.Images,
+	})
 }
 
 func (r *Reconciler) installed(ctx context.Context, instance v1alpha1.TektonComponent) (*mf.Manifest, error) {
 	// Create new, empty manifest with valid client and logger
 	installed := r.manifest.Append()
 	// TODO: add ingress, etc
-	stages := common.Stages{common.AppendInstalled, r.transform}
+	stages := common.Stages{common.AppendInstalled, r.cloudEventsListener, r.transform, common.NetworkPolicies, common.PodDisruptionBudgets, common.HorizontalPodAutoscalers, common.ServiceMonitors, common.InstalledObservability}
 	err := stages.Execute(ctx, &installed, instance)
 	return &installed, err
 }
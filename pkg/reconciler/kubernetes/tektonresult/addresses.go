@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonresult
+
+import (
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// releaseNamespace is the namespace the results payload is released for,
+// which the addresses of its services, e.g. the API server the watcher
+// calls, are written with
+const releaseNamespace = "tekton-pipelines"
+
+// serviceAddresses returns a Transformer rewriting the cluster addresses of
+// the services of the release namespace, in the arguments and environment
+// of the containers of the deployments, to those of the namespace the
+// payload is installed to.
+func serviceAddresses(namespace string) mf.Transformer {
+	from := "." + releaseNamespace + ".svc"
+	to := "." + namespace + ".svc"
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Deployment" || namespace == releaseNamespace {
+			return nil
+		}
+		path := []string{"spec", "template", "spec", "containers"}
+		containers, _, err := unstructured.NestedSlice(u.Object, path...)
+		if err != nil {
+			return err
+		}
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if args, ok := container["args"].([]interface{}); ok {
+				for i, arg := range args {
+					if s, ok := arg.(string); ok {
+						args[i] = strings.ReplaceAll(s, from, to)
+					}
+				}
+			}
+			if env, ok := container["env"].([]interface{}); ok {
+				for _, e := range env {
+					if v, ok := e.(map[string]interface{}); ok {
+						if value, ok := v["value"].(string); ok {
+							v["value"] = strings.ReplaceAll(value, from, to)
+						}
+					}
+				}
+			}
+		}
+		return unstructured.SetNestedSlice(u.Object, containers, path...)
+	}
+}
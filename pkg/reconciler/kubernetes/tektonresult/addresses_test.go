@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonresult
+
+import (
+	"testing"
+
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestServiceAddresses(t *testing.T) {
+	deployment := util.MakeDeployment("tekton-results-watcher", corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name: "watcher",
+			Args: []string{"-api_addr", "tekton-results-api-service.tekton-pipelines.svc.cluster.local:50051"},
+			Env: []corev1.EnvVar{
+				{Name: "DB_ADDR", Value: "tekton-results-mysql.tekton-pipelines.svc.cluster.local"},
+				{Name: "SYSTEM_NAMESPACE", ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+				}},
+			},
+		}},
+	})
+	u := util.MakeUnstructured(t, deployment)
+	util.AssertNoError(t, serviceAddresses("tekton-results")(&u))
+
+	containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	util.AssertDeepEqual(t, container["args"], []interface{}{"-api_addr", "tekton-results-api-service.tekton-results.svc.cluster.local:50051"})
+	env := container["env"].([]interface{})
+	util.AssertEqual(t, env[0].(map[string]interface{})["value"], "tekton-results-mysql.tekton-results.svc.cluster.local")
+	_, found := env[1].(map[string]interface{})["value"]
+	util.AssertEqual(t, found, false)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonresult
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// apiDeployment is the deployment of the results API server, which reads
+// the credentials of its database from a Secret
+const apiDeployment = "tekton-results-api"
+
+// checkDatabaseSecret is a Stage making sure the Secret keys the API server
+// reads its database credentials from exist before anything is applied,
+// marking the dependency missing otherwise. The database isn't part of the
+// payload.
+func checkDatabaseSecret(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	deployments := manifest.Filter(mf.ByKind("Deployment"), mf.ByName(apiDeployment)).Resources()
+	if len(deployments) == 0 {
+		return nil
+	}
+	namespace := deployments[0].GetNamespace()
+	refs, err := secretKeyRefs(&deployments[0])
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, name := range sortedKeys(refs) {
+		secret := &unstructured.Unstructured{}
+		secret.SetAPIVersion("v1")
+		secret.SetKind("Secret")
+		secret.SetNamespace(namespace)
+		secret.SetName(name)
+		live, err := manifest.Client.Get(secret)
+		if apierrors.IsNotFound(err) {
+			missing = append(missing, name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, key := range refs[name] {
+			if _, found, _ := unstructured.NestedString(live.Object, "data", key); !found {
+				missing = append(missing, name+"/"+key)
+			}
+		}
+	}
+	if len(missing) != 0 {
+		msg := fmt.Sprintf("Secrets of the results database missing in namespace %s: %s", namespace, strings.Join(missing, ", "))
+		err := &operrors.DependencyMissing{Err: errors.New(msg)}
+		operrors.MarkFailed(comp.GetStatus(), err)
+		return err
+	}
+	return nil
+}
+
+// secretKeyRefs returns the keys of the required Secrets the containers of
+// the deployment read environment variables from, by Secret name.
+func secretKeyRefs(u *unstructured.Unstructured) (map[string][]string, error) {
+	deployment := &appsv1.Deployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, deployment); err != nil {
+		return nil, err
+	}
+	refs := map[string][]string{}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil {
+				continue
+			}
+			ref := env.ValueFrom.SecretKeyRef
+			if ref.Optional != nil && *ref.Optional {
+				continue
+			}
+			refs[ref.Name] = append(refs[ref.Name], ref.Key)
+		}
+	}
+	return refs, nil
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
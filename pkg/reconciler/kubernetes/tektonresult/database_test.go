@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonresult
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// secretClient serves the Get of the secrets it holds
+type secretClient struct {
+	mf.Client
+	secrets map[string]map[string]interface{}
+}
+
+func (c *secretClient) Get(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	data, ok := c.secrets[u.GetName()]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, u.GetName())
+	}
+	live := u.DeepCopy()
+	live.Object["data"] = data
+	return live, nil
+}
+
+func apiDeploymentWithEnv(t *testing.T, env ...corev1.EnvVar) unstructured.Unstructured {
+	deployment := util.MakeDeployment(apiDeployment, corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "api", Env: env}},
+	})
+	deployment.APIVersion = "apps/v1"
+	deployment.Namespace = "tekton-pipelines"
+	return util.MakeUnstructured(t, deployment)
+}
+
+func secretEnv(name, secret, key string, optional bool) corev1.EnvVar {
+	return corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+			Key:                  key,
+			Optional:             &optional,
+		},
+	}}
+}
+
+func TestCheckDatabaseSecret(t *testing.T) {
+	client := &secretClient{secrets: map[string]map[string]interface{}{
+		"tekton-results-mysql": {"user": "cm9vdA=="},
+	}}
+	deployment := apiDeploymentWithEnv(t,
+		corev1.EnvVar{Name: "DB_NAME", Value: "tekton_results"},
+		secretEnv("DB_USER", "tekton-results-mysql", "user", false),
+		secretEnv("DB_CA", "tekton-results-ca", "ca.crt", true),
+	)
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment}), mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	instance := &v1alpha1.TektonResult{}
+	instance.Status.InitializeConditions()
+	util.AssertNoError(t, checkDatabaseSecret(context.Background(), &manifest, instance))
+
+	deployment = apiDeploymentWithEnv(t,
+		secretEnv("DB_USER", "tekton-results-mysql", "user", false),
+		secretEnv("DB_PASSWORD", "tekton-results-mysql", "password", false),
+		secretEnv("DB_CERT", "tekton-results-tls", "tls.crt", false),
+	)
+	manifest, err = mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment}), mf.UseClient(client))
+	util.AssertNoError(t, err)
+	err = checkDatabaseSecret(context.Background(), &manifest, instance)
+	if err == nil {
+		t.Fatal("checkDatabaseSecret() = nil, wanted an error for the missing password")
+	}
+	util.AssertEqual(t, err.Error(), "Secrets of the results database missing in namespace tekton-pipelines: tekton-results-mysql/password, tekton-results-tls")
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.DependenciesInstalled).IsFalse(), true)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektontrigger
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	featureFlagsConfig   = "feature-flags-triggers"
+	configDefaultsConfig = "config-defaults-triggers"
+)
+
+// apiFields are the values of enable-api-fields
+var apiFields = sets.NewString("stable", "alpha")
+
+// deprecations reports the keys of spec.options.configMaps replaced by
+// spec.featureFlags and spec.defaults, and the disruption fields replaced
+// by spec.disruptionPolicy.
+var deprecations = common.ReportDeprecations(common.DeprecatedDisruptionFields, common.DeprecatedConfigMapKeys(map[string]map[string]string{
+	featureFlagsConfig: {
+		"enable-api-fields": "spec.featureFlags.enableAPIFields",
+	},
+	configDefaultsConfig: {
+		"default-service-account": "spec.defaults.defaultServiceAccount",
+		"el-events":               "spec.defaults.eventListenerEvents",
+		"el-security-context":     "spec.defaults.eventListenerSecurityContext",
+	},
+}))
+
+// triggersConfig returns the data of the triggers ConfigMaps set by
+// spec.featureFlags and spec.defaults, by ConfigMap name, or an error if
+// a field has an invalid value.
+func triggersConfig(spec *v1alpha1.TektonTriggerSpec) (map[string]map[string]string, error) {
+	flags, defaults := map[string]string{}, map[string]string{}
+	if f := spec.FeatureFlags; f != nil && f.EnableAPIFields != "" {
+		if !apiFields.Has(f.EnableAPIFields) {
+			return nil, fmt.Errorf("invalid spec.featureFlags.enableAPIFields %q, expected one of %s",
+				f.EnableAPIFields, strings.Join(apiFields.List(), ", "))
+		}
+		flags["enable-api-fields"] = f.EnableAPIFields
+	}
+	if d := spec.Defaults; d != nil {
+		if d.DefaultServiceAccount != "" {
+			defaults["default-service-account"] = d.DefaultServiceAccount
+		}
+		if d.EventListenerEvents != nil {
+			defaults["el-events"] = "disable"
+			if *d.EventListenerEvents {
+				defaults["el-events"] = "enable"
+			}
+		}
+		if d.EventListenerSecurityContext != nil {
+			defaults["el-security-context"] = strconv.FormatBool(*d.EventListenerSecurityContext)
+		}
+	}
+	return map[string]map[string]string{
+		featureFlagsConfig:   flags,
+		configDefaultsConfig: defaults,
+	}, nil
+}
+
+// appendConfigMaps is a Stage appending the triggers ConfigMaps the typed
+// spec sets but the payload doesn't ship, as older releases lack them.
+// They are appended before the transformers run, for them to be
+// namespaced, owned and filled like the ConfigMaps of the payload.
+func appendConfigMaps(_ context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	config, err := triggersConfig(&comp.(*v1alpha1.TektonTrigger).Spec)
+	if err != nil {
+		// reported by the transform
+		return nil
+	}
+	var missing []unstructured.Unstructured
+	for name, data := range config {
+		if len(data) == 0 || len(manifest.Filter(mf.ByKind("ConfigMap"), mf.ByName(name)).Resources()) != 0 {
+			continue
+		}
+		cm := unstructured.Unstructured{}
+		cm.SetAPIVersion("v1")
+		cm.SetKind("ConfigMap")
+		cm.SetName(name)
+		cm.SetLabels(map[string]string{
+			"app.kubernetes.io/instance": "default",
+			"app.kubernetes.io/part-of":  "tekton-triggers",
+		})
+		missing = append(missing, cm)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].GetName() < missing[j].GetName() })
+	configMaps, err := mf.ManifestFrom(mf.Slice(missing))
+	if err != nil {
+		return err
+	}
+	*manifest = manifest.Append(configMaps)
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektontrigger
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTriggersConfig(t *testing.T) {
+	yes, no := true, false
+
+	config, err := triggersConfig(&v1alpha1.TektonTriggerSpec{})
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(config[featureFlagsConfig]), 0)
+	util.AssertEqual(t, len(config[configDefaultsConfig]), 0)
+
+	config, err = triggersConfig(&v1alpha1.TektonTriggerSpec{
+		FeatureFlags: &v1alpha1.TriggerFeatureFlags{EnableAPIFields: "alpha"},
+		Defaults: &v1alpha1.TriggerDefaults{
+			DefaultServiceAccount:        "triggers",
+			EventListenerEvents:          &yes,
+			EventListenerSecurityContext: &no,
+		},
+	})
+	util.AssertNoError(t, err)
+	util.AssertDeepEqual(t, config, map[string]map[string]string{
+		featureFlagsConfig: {"enable-api-fields": "alpha"},
+		configDefaultsConfig: {
+			"default-service-account": "triggers",
+			"el-events":               "enable",
+			"el-security-context":     "false",
+		},
+	})
+
+	_, err = triggersConfig(&v1alpha1.TektonTriggerSpec{
+		FeatureFlags: &v1alpha1.TriggerFeatureFlags{EnableAPIFields: "beta"},
+	})
+	if err == nil {
+		t.Error("expected an error for an invalid enableAPIFields")
+	}
+}
+
+func TestAppendConfigMaps(t *testing.T) {
+	shipped := unstructured.Unstructured{}
+	shipped.SetAPIVersion("v1")
+	shipped.SetKind("ConfigMap")
+	shipped.SetName(featureFlagsConfig)
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{shipped}))
+	util.AssertNoError(t, err)
+
+	instance := &v1alpha1.TektonTrigger{}
+	util.AssertNoError(t, appendConfigMaps(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(manifest.Resources()), 1)
+
+	// only the ConfigMaps the payload lacks are appended
+	instance.Spec.FeatureFlags = &v1alpha1.TriggerFeatureFlags{EnableAPIFields: "alpha"}
+	instance.Spec.Defaults = &v1alpha1.TriggerDefaults{DefaultServiceAccount: "triggers"}
+	util.AssertNoError(t, appendConfigMaps(context.Background(), &manifest, instance))
+	resources := manifest.Resources()
+	util.AssertEqual(t, len(resources), 2)
+	util.AssertEqual(t, resources[1].GetName(), configDefaultsConfig)
+}
@@ -20,13 +20,16 @@ import (
 	"context"
 
 	"github.com/go-logr/zapr"
-	mfc "github.com/manifestival/client-go-client"
 	mf "github.com/manifestival/manifestival"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	operatorclient "github.com/tektoncd/operator/pkg/client/injection/client"
+	tektonInstallerSetinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektoninstallerset"
+	tektonOverrideinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonoverride"
 	tektonPipelineinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonpipeline"
 	tektonTriggerinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektontrigger"
 	tektonTriggerreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektontrigger"
@@ -37,6 +40,7 @@ import (
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
 	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
 )
 
 // NewController initializes the controller and is called by the generated code
@@ -50,37 +54,73 @@ func NewExtendedController(generator common.ExtensionGenerator) injection.Contro
 	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
 		tektonPipelineInformer := tektonPipelineinformer.Get(ctx)
 		tektonTriggersInformer := tektonTriggerinformer.Get(ctx)
+		tektonOverrideInformer := tektonOverrideinformer.Get(ctx)
+		tektonInstallerSetInformer := tektonInstallerSetinformer.Get(ctx)
 		deploymentInformer := deploymentinformer.Get(ctx)
 		kubeClient := kubeclient.Get(ctx)
 		logger := logging.FromContext(ctx)
 
-		mfclient, err := mfc.NewClient(injection.GetConfig(ctx))
+		mfclient, err := common.NewClient(injection.GetConfig(ctx))
 		if err != nil {
 			logger.Fatalw("Error creating client from injected config", zap.Error(err))
 		}
+		dynamicClient, err := dynamic.NewForConfig(injection.GetConfig(ctx))
+		if err != nil {
+			logger.Fatalw("Error creating dynamic client from injected config", zap.Error(err))
+		}
 		mflogger := zapr.NewLogger(logger.Named("manifestival").Desugar())
 		manifest, err := mf.ManifestFrom(mf.Slice{}, mf.UseClient(mfclient), mf.UseLogger(mflogger))
 		if err != nil {
 			logger.Fatalw("Error creating initial manifest", zap.Error(err))
 		}
 
+		common.ReportKeyCollisions(ctx, &v1alpha1.TektonTrigger{}, common.TriggersImagePrefix)
+		images := common.NewImageStore()
+		ctx = common.WithImageStore(ctx, images)
+
 		c := &Reconciler{
 			kubeClientSet:     kubeClient,
 			operatorClientSet: operatorclient.Get(ctx),
+			dynamicClient:     dynamicClient,
 			extension:         generator(ctx),
 			manifest:          manifest,
+			overrideLister:    tektonOverrideInformer.Lister(),
+			leaderElection:    common.NewLeaderElectionCache(ctx, kubeClient),
+			triggerLister:     tektonTriggersInformer.Lister(),
 			pipelineInformer:  tektonPipelineInformer,
 		}
 		impl := tektonTriggerreconciler.NewImpl(ctx, c)
+		c.enqueueAfter = impl.EnqueueAfter
 
 		logger.Info("Setting up event handlers")
 
 		tektonTriggersInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+		tektonOverrideInformer.Informer().AddEventHandler(common.EnqueueOnOverride(impl.EnqueueKey, v1alpha1.KindTektonTrigger, common.TriggerResourceName))
+		tektonInstallerSetInformer.Informer().AddEventHandler(common.EnqueueOnInstallerSet(impl.EnqueueControllerOf, v1alpha1.KindTektonTrigger))
+		tektonPipelineInformer.Informer().AddEventHandler(common.EnqueueOnReady(impl.EnqueueKey, common.TriggerResourceName))
 
 		deploymentInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 			FilterFunc: controller.FilterControllerGVK(v1alpha1.SchemeGroupVersion.WithKind("TektonTrigger")),
 			Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 		})
+		common.WatchOwned(ctx, v1alpha1.KindTektonTrigger, impl.EnqueueControllerOf)
+
+		// Default the scheduling of the EventListeners as soon as the
+		// triggers controller creates their deployments.
+		deploymentInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: pkgreconciler.LabelExistsFilterFunc(eventListenerLabel),
+			Handler: cache.ResourceEventHandlerFuncs{
+				AddFunc: func(interface{}) {
+					impl.EnqueueKey(types.NamespacedName{Name: common.TriggerResourceName})
+				},
+			},
+		})
+
+		resyncImages := func() {
+			impl.GlobalResync(tektonTriggersInformer.Informer())
+		}
+		common.WatchImages(cmw, images, resyncImages)
+		common.WatchImagesSecret(ctx, kubeClient, images, resyncImages)
 
 		return impl
 	}
@@ -0,0 +1,247 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektontrigger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"knative.dev/pkg/logging"
+)
+
+// eventListenerLabel is set by the triggers controller on the deployments
+// of the EventListeners.
+const eventListenerLabel = "eventlistener"
+
+const (
+	// defaultedLabel marks the EventListeners the operator defaulted, to
+	// be found again once the defaults are unset or the component deleted
+	defaultedLabel = "operator.tekton.dev/defaulted"
+	// defaultedAnnotation records the defaults the operator set on the
+	// EventListener, those still holding these values being its own
+	defaultedAnnotation = "operator.tekton.dev/applied-defaults"
+)
+
+var eventListenerGVR = schema.GroupVersionResource{
+	Group:    "triggers.tekton.dev",
+	Version:  "v1alpha1",
+	Resource: "eventlisteners",
+}
+
+// applyEventListenerDefaults returns a Stage which sets the scheduling
+// constraints and the labels of spec.eventListenerDefaults, and the cost
+// allocation labels of the component, on the EventListeners which don't set
+// their own, for the triggers controller to pass them on to the pods. The
+// defaults set before and no longer in the spec are removed.
+func applyEventListenerDefaults(client dynamic.Interface) common.Stage {
+	return func(ctx context.Context, _ *mf.Manifest, comp v1alpha1.TektonComponent) error {
+		return defaultEventListeners(ctx, client, eventListenerDefaults(comp.(*v1alpha1.TektonTrigger)))
+	}
+}
+
+// revertEventListenerDefaults removes the defaults the operator set on the
+// EventListeners, e.g. once the component is deleted.
+func revertEventListenerDefaults(ctx context.Context, client dynamic.Interface) error {
+	return defaultEventListeners(ctx, client, nil)
+}
+
+// defaultEventListeners sets the defaults on the EventListeners, all of
+// them, or only those defaulted before to revert them without defaults.
+func defaultEventListeners(ctx context.Context, client dynamic.Interface, defaults *v1alpha1.EventListenerDefaults) error {
+	opts := metav1.ListOptions{}
+	if defaults == nil {
+		opts.LabelSelector = defaultedLabel + "=true"
+	}
+	listeners, err := client.Resource(eventListenerGVR).List(ctx, opts)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list EventListeners: %w", err)
+	}
+	for i := range listeners.Items {
+		el := &listeners.Items[i]
+		changed, err := defaultEventListener(el, defaults)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		logging.FromContext(ctx).Infow("Defaulting EventListener scheduling", "namespace", el.GetNamespace(), "name", el.GetName())
+		if _, err := client.Resource(eventListenerGVR).Namespace(el.GetNamespace()).Update(ctx, el, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to default EventListener %s/%s: %w", el.GetNamespace(), el.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// eventListenerDefaults returns spec.eventListenerDefaults with the cost
+// allocation labels of the component added to its labels, replacing their
+// values, nil without either.
+func eventListenerDefaults(instance *v1alpha1.TektonTrigger) *v1alpha1.EventListenerDefaults {
+	costLabels := common.CostAllocationLabels(instance)
+	if len(costLabels) == 0 {
+		return instance.Spec.EventListenerDefaults
+	}
+	defaults := &v1alpha1.EventListenerDefaults{}
+	if instance.Spec.EventListenerDefaults != nil {
+		defaults = instance.Spec.EventListenerDefaults.DeepCopy()
+	}
+	if defaults.Labels == nil {
+		defaults.Labels = map[string]string{}
+	}
+	for k, v := range costLabels {
+		defaults.Labels[k] = v
+	}
+	return defaults
+}
+
+// defaultEventListener sets the node selector and the tolerations of the
+// pod template of the EventListener, spec.resources.kubernetesResource if
+// used or else spec.podTemplate, when they are unset, and the labels the
+// EventListener doesn't set. The defaults it set before, recorded in its
+// annotations, are updated, or removed when no longer in defaults, nil
+// reverting them all; those changed by others since are left alone. It
+// returns true if the EventListener was changed.
+func defaultEventListener(el *unstructured.Unstructured, defaults *v1alpha1.EventListenerDefaults) (bool, error) {
+	if defaults == nil {
+		defaults = &v1alpha1.EventListenerDefaults{}
+	}
+	path := []string{"spec", "podTemplate"}
+	if _, found, _ := unstructured.NestedMap(el.Object, "spec", "resources", "kubernetesResource"); found {
+		path = []string{"spec", "resources", "kubernetesResource", "spec", "template", "spec"}
+	}
+	field := func(name string) []string {
+		return append(append([]string{}, path...), name)
+	}
+	original := el.DeepCopy()
+	applied := v1alpha1.EventListenerDefaults{}
+	if record := el.GetAnnotations()[defaultedAnnotation]; record != "" {
+		// a corrupted record leaves the fields to their current owners
+		_ = json.Unmarshal([]byte(record), &applied)
+	}
+	next := v1alpha1.EventListenerDefaults{}
+
+	nodeSelector, found, _ := unstructured.NestedStringMap(el.Object, field("nodeSelector")...)
+	own := found && len(applied.NodeSelector) != 0 && equality.Semantic.DeepEqual(nodeSelector, applied.NodeSelector)
+	switch {
+	case len(defaults.NodeSelector) != 0 && (!found || own):
+		if err := unstructured.SetNestedStringMap(el.Object, defaults.NodeSelector, field("nodeSelector")...); err != nil {
+			return false, err
+		}
+		next.NodeSelector = defaults.NodeSelector
+	case own:
+		unstructured.RemoveNestedField(el.Object, field("nodeSelector")...)
+	}
+
+	tolerations, found, err := nestedTolerations(el, field("tolerations"))
+	if err != nil {
+		return false, err
+	}
+	own = found && len(applied.Tolerations) != 0 && equality.Semantic.DeepEqual(tolerations, applied.Tolerations)
+	switch {
+	case len(defaults.Tolerations) != 0 && (!found || own):
+		tolerations := make([]interface{}, 0, len(defaults.Tolerations))
+		for i := range defaults.Tolerations {
+			toleration, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&defaults.Tolerations[i])
+			if err != nil {
+				return false, err
+			}
+			tolerations = append(tolerations, toleration)
+		}
+		if err := unstructured.SetNestedSlice(el.Object, tolerations, field("tolerations")...); err != nil {
+			return false, err
+		}
+		next.Tolerations = defaults.Tolerations
+	case own:
+		unstructured.RemoveNestedField(el.Object, field("tolerations")...)
+	}
+
+	labels := el.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for key, value := range applied.Labels {
+		if labels[key] == value && defaults.Labels[key] == "" {
+			delete(labels, key)
+		}
+	}
+	for key, value := range defaults.Labels {
+		if current, ok := labels[key]; !ok || current == applied.Labels[key] {
+			labels[key] = value
+			if next.Labels == nil {
+				next.Labels = map[string]string{}
+			}
+			next.Labels[key] = value
+		}
+	}
+
+	annotations := el.GetAnnotations()
+	if len(next.NodeSelector) == 0 && len(next.Tolerations) == 0 && len(next.Labels) == 0 {
+		delete(labels, defaultedLabel)
+		delete(annotations, defaultedAnnotation)
+	} else {
+		record, err := json.Marshal(next)
+		if err != nil {
+			return false, err
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		labels[defaultedLabel] = "true"
+		annotations[defaultedAnnotation] = string(record)
+	}
+	el.SetLabels(labels)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	el.SetAnnotations(annotations)
+	return !equality.Semantic.DeepEqual(original.Object, el.Object), nil
+}
+
+// nestedTolerations returns the tolerations of the field of the
+// EventListener.
+func nestedTolerations(el *unstructured.Unstructured, field []string) ([]corev1.Toleration, bool, error) {
+	items, found, err := unstructured.NestedSlice(el.Object, field...)
+	if !found || err != nil {
+		return nil, found, err
+	}
+	tolerations := make([]corev1.Toleration, len(items))
+	for i, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, true, fmt.Errorf("invalid toleration of EventListener %s/%s", el.GetNamespace(), el.GetName())
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &tolerations[i]); err != nil {
+			return nil, true, err
+		}
+	}
+	return tolerations, true, nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektontrigger
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDefaultEventListener(t *testing.T) {
+	defaults := &v1alpha1.EventListenerDefaults{
+		NodeSelector: map[string]string{"node-role": "triggers"},
+		Tolerations: []corev1.Toleration{{
+			Key:      "dedicated",
+			Operator: corev1.TolerationOpEqual,
+			Value:    "triggers",
+			Effect:   corev1.TaintEffectNoSchedule,
+		}},
+	}
+	toleration := map[string]interface{}{
+		"key":      "dedicated",
+		"operator": "Equal",
+		"value":    "triggers",
+		"effect":   "NoSchedule",
+	}
+
+	// the pod template of a plain EventListener
+	el := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"serviceAccountName": "el"},
+	}}
+	changed, err := defaultEventListener(el, defaults)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, changed, true)
+	util.AssertDeepEqual(t, el.Object["spec"], map[string]interface{}{
+		"serviceAccountName": "el",
+		"podTemplate": map[string]interface{}{
+			"nodeSelector": map[string]interface{}{"node-role": "triggers"},
+			"tolerations":  []interface{}{toleration},
+		},
+	})
+	changed, err = defaultEventListener(el, defaults)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, changed, false)
+
+	// the Kubernetes resource of an EventListener, keeping its own node selector
+	el = &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"resources": map[string]interface{}{
+				"kubernetesResource": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"nodeSelector": map[string]interface{}{"zone": "a"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+	changed, err = defaultEventListener(el, defaults)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, changed, true)
+	podSpec, _, _ := unstructured.NestedMap(el.Object, "spec", "resources", "kubernetesResource", "spec", "template", "spec")
+	util.AssertDeepEqual(t, podSpec, map[string]interface{}{
+		"nodeSelector": map[string]interface{}{"zone": "a"},
+		"tolerations":  []interface{}{toleration},
+	})
+}
+
+func TestDefaultEventListenerLabels(t *testing.T) {
+	defaults := &v1alpha1.EventListenerDefaults{
+		Labels: map[string]string{"team": "ci", "cost-center": "shared"},
+	}
+	el := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	el.SetLabels(map[string]string{"team": "web"})
+
+	changed, err := defaultEventListener(el, defaults)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, changed, true)
+	util.AssertDeepEqual(t, el.GetLabels(), map[string]string{"team": "web", "cost-center": "shared", defaultedLabel: "true"})
+
+	changed, err = defaultEventListener(el, defaults)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, changed, false)
+}
+
+func TestEventListenerDefaultsCostAllocation(t *testing.T) {
+	instance := &v1alpha1.TektonTrigger{}
+	if defaults := eventListenerDefaults(instance); defaults != nil {
+		t.Errorf("expected no defaults, got %v", defaults)
+	}
+
+	instance.Spec.EventListenerDefaults = &v1alpha1.EventListenerDefaults{
+		NodeSelector: map[string]string{"pool": "ci"},
+		Labels:       map[string]string{"team": "web", "tier": "edge"},
+	}
+	instance.Spec.CostAllocation = &v1alpha1.CostAllocation{
+		Labels:     map[string]string{"team": "ci"},
+		Components: map[string]map[string]string{v1alpha1.KindTektonTrigger: {"cost-center": "triggers"}},
+	}
+	defaults := eventListenerDefaults(instance)
+	util.AssertDeepEqual(t, defaults.NodeSelector, map[string]string{"pool": "ci"})
+	util.AssertDeepEqual(t, defaults.Labels, map[string]string{"team": "ci", "tier": "edge", "cost-center": "triggers"})
+	// the spec is left alone
+	util.AssertDeepEqual(t, instance.Spec.EventListenerDefaults.Labels, map[string]string{"team": "web", "tier": "edge"})
+}
+
+func TestDefaultEventListenerRevert(t *testing.T) {
+	defaults := &v1alpha1.EventListenerDefaults{
+		NodeSelector: map[string]string{"node-role": "triggers"},
+		Tolerations: []corev1.Toleration{{
+			Key:      "dedicated",
+			Operator: corev1.TolerationOpExists,
+		}},
+		Labels: map[string]string{"cost-center": "shared", "tier": "edge"},
+	}
+	el := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"serviceAccountName": "el"},
+	}}
+	el.SetLabels(map[string]string{"team": "web"})
+	original := el.DeepCopy()
+
+	changed, err := defaultEventListener(el, defaults)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, changed, true)
+
+	// a changed default replaces the one set before, a dropped one is removed
+	changed, err = defaultEventListener(el, &v1alpha1.EventListenerDefaults{
+		NodeSelector: map[string]string{"node-role": "ci"},
+		Labels:       map[string]string{"cost-center": "ci"},
+	})
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, changed, true)
+	podTemplate, _, _ := unstructured.NestedMap(el.Object, "spec", "podTemplate")
+	util.AssertDeepEqual(t, podTemplate, map[string]interface{}{
+		"nodeSelector": map[string]interface{}{"node-role": "ci"},
+	})
+	util.AssertDeepEqual(t, el.GetLabels(), map[string]string{"team": "web", "cost-center": "ci", defaultedLabel: "true"})
+
+	// the values changed by the user since are theirs
+	el.SetLabels(map[string]string{"team": "web", "cost-center": "web", defaultedLabel: "true"})
+
+	// no defaults revert the EventListener
+	changed, err = defaultEventListener(el, nil)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, changed, true)
+	original.SetLabels(map[string]string{"team": "web", "cost-center": "web"})
+	unstructured.SetNestedMap(original.Object, map[string]interface{}{}, "spec", "podTemplate")
+	util.AssertDeepEqual(t, el.Object, original.Object)
+}
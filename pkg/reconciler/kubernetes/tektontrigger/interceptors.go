@@ -0,0 +1,225 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektontrigger
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/logging"
+	certresources "knative.dev/pkg/webhook/certificates/resources"
+)
+
+const (
+	// coreInterceptors is the name of the Deployment and the Service of the
+	// core interceptors
+	coreInterceptors = "tekton-triggers-core-interceptors"
+	// coreInterceptorsSecret holds the serving certificate of the core
+	// interceptors
+	coreInterceptorsSecret = "tekton-triggers-core-interceptors-certs"
+	// interceptorCAHashKey annotates the pods of the core interceptors with
+	// the hash of their CA, for them to restart on a rotation and serve the
+	// new certificate
+	interceptorCAHashKey = "operator.tekton.dev/interceptor-ca-hash"
+
+	defaultInterceptorCertValidity = 365 * 24 * time.Hour
+	// namespaceRetry is how long until the certificate is written again
+	// when the target namespace doesn't exist yet
+	namespaceRetry = 10 * time.Second
+)
+
+// interceptorCertificates returns a Stage which, when spec.interceptorTLS
+// is managed, generates the serving certificate of the core interceptors
+// into their Secret, rotates it once less than a fifth of its validity is
+// left, and sets its CA on the ClusterInterceptors of the payload. Payloads
+// without the core interceptors, e.g. Triggers 0.10.2, are left
+// alone, and the Secret written for a previous payload deleted. The
+// Secret is written directly rather than applied with the payload, for the
+// private key not to be recorded in the installer set, and the Secret the
+// payload ships is dropped for its apply not to clear the certificate.
+func interceptorCertificates(kubeClient kubernetes.Interface, requeue common.Requeue) common.Stage {
+	return func(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+		tt := comp.(*v1alpha1.TektonTrigger)
+		if tt.Spec.InterceptorTLS == nil || !tt.Spec.InterceptorTLS.Managed {
+			return nil
+		}
+		logger := logging.FromContext(ctx)
+		validity := interceptorCertValidity(tt.Spec.InterceptorTLS)
+		namespace := tt.Spec.GetTargetNamespace()
+		secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, coreInterceptorsSecret, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			secret = nil
+		} else if err != nil {
+			return fmt.Errorf("failed to get the certificate of the core interceptors: %w", err)
+		}
+		if !withCoreInterceptors(manifest) {
+			logger.Info("The payload has no core interceptors, their certificate isn't managed")
+			return deleteInterceptorCertificate(ctx, kubeClient, tt, secret)
+		}
+		now := common.ClockFrom(ctx).Now()
+		data, expiry, generated, err := interceptorCertificate(ctx, secret, namespace, validity, now)
+		if err != nil {
+			return err
+		}
+		if generated {
+			logger.Infow("Writing a new certificate of the core interceptors", "namespace", namespace, "expiry", expiry)
+			err := writeInterceptorCertificate(ctx, kubeClient, tt, secret, data)
+			if apierrors.IsNotFound(err) {
+				// the namespace is created by the install which follows
+				requeue(namespaceRetry)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to write the certificate of the core interceptors: %w", err)
+			}
+		}
+		requeue(expiry.Add(-validity / 5).Sub(now))
+
+		*manifest = manifest.Filter(mf.Not(mf.All(mf.ByKind("Secret"), mf.ByName(coreInterceptorsSecret))))
+		transformed, err := manifest.Transform(interceptorCABundle(data[certresources.CACert]))
+		if err != nil {
+			return err
+		}
+		*manifest = transformed
+		return nil
+	}
+}
+
+// withCoreInterceptors returns true if the manifest has the Deployment of
+// the core interceptors.
+func withCoreInterceptors(manifest *mf.Manifest) bool {
+	return len(manifest.Filter(mf.ByKind("Deployment"), mf.ByName(coreInterceptors)).Resources()) != 0
+}
+
+// deleteInterceptorCertificate deletes the Secret of the core interceptors
+// if the operator wrote it for the component.
+func deleteInterceptorCertificate(ctx context.Context, kubeClient kubernetes.Interface, tt *v1alpha1.TektonTrigger, live *corev1.Secret) error {
+	if live == nil || !metav1.IsControlledBy(live, tt) {
+		return nil
+	}
+	err := kubeClient.CoreV1().Secrets(live.Namespace).Delete(ctx, live.Name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete the certificate of the core interceptors: %w", err)
+	}
+	return nil
+}
+
+func interceptorCertValidity(tls *v1alpha1.InterceptorTLS) time.Duration {
+	if tls.Validity == nil || tls.Validity.Duration <= 0 {
+		return defaultInterceptorCertValidity
+	}
+	return tls.Validity.Duration
+}
+
+// certificateExpiry returns when the serving certificate of the Secret
+// expires, and false if the Secret doesn't hold a complete certificate.
+func certificateExpiry(secret *corev1.Secret) (time.Time, bool) {
+	if secret == nil || len(secret.Data[certresources.ServerKey]) == 0 || len(secret.Data[certresources.CACert]) == 0 {
+		return time.Time{}, false
+	}
+	block, _ := pem.Decode(secret.Data[certresources.ServerCert])
+	if block == nil {
+		return time.Time{}, false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return cert.NotAfter, true
+}
+
+// interceptorCertificate returns the certificate of the live Secret of the
+// core interceptors and its expiry, or a new one, generated, when the
+// Secret doesn't hold one or it expires in less than a fifth of validity.
+func interceptorCertificate(ctx context.Context, live *corev1.Secret, namespace string, validity time.Duration, now time.Time) (map[string][]byte, time.Time, bool, error) {
+	if expiry, ok := certificateExpiry(live); ok && expiry.Sub(now) >= validity/5 {
+		return live.Data, expiry, false, nil
+	}
+	expiry := now.Add(validity)
+	key, cert, ca, err := certresources.CreateCerts(ctx, coreInterceptors, namespace, expiry)
+	if err != nil {
+		return nil, time.Time{}, false, fmt.Errorf("failed to generate the certificate of the core interceptors: %w", err)
+	}
+	return map[string][]byte{
+		certresources.ServerKey:  key,
+		certresources.ServerCert: cert,
+		certresources.CACert:     ca,
+	}, expiry, true, nil
+}
+
+// writeInterceptorCertificate writes the certificate into the Secret of
+// the core interceptors, creating it if live is nil. The Secret is owned
+// by the component, but on remote clusters.
+func writeInterceptorCertificate(ctx context.Context, kubeClient kubernetes.Interface, tt *v1alpha1.TektonTrigger, live *corev1.Secret, data map[string][]byte) error {
+	secrets := kubeClient.CoreV1().Secrets(tt.Spec.GetTargetNamespace())
+	if live != nil {
+		updated := live.DeepCopy()
+		updated.Data = data
+		_, err := secrets.Update(ctx, updated, metav1.UpdateOptions{})
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      coreInterceptorsSecret,
+			Namespace: tt.Spec.GetTargetNamespace(),
+			Labels: map[string]string{
+				"app.kubernetes.io/part-of": "tekton-triggers",
+			},
+		},
+		Data: data,
+	}
+	if tt.Spec.GetKubeconfigSecret() == nil {
+		secret.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(tt, v1alpha1.SchemeGroupVersion.WithKind(v1alpha1.KindTektonTrigger)),
+		}
+	}
+	_, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+	return err
+}
+
+// interceptorCABundle returns a Transformer setting the CA on the
+// ClusterInterceptors served by the core interceptors, and its hash on the
+// pods of the core interceptors.
+func interceptorCABundle(ca []byte) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		switch {
+		case u.GetKind() == "ClusterInterceptor":
+			service, _, _ := unstructured.NestedString(u.Object, "spec", "clientConfig", "service", "name")
+			if service != coreInterceptors {
+				return nil
+			}
+			return unstructured.SetNestedField(u.Object, base64.StdEncoding.EncodeToString(ca), "spec", "clientConfig", "caBundle")
+		case u.GetKind() == "Deployment" && u.GetName() == coreInterceptors:
+			return unstructured.SetNestedField(u.Object, fmt.Sprintf("%x", sha256.Sum256(ca)),
+				"spec", "template", "metadata", "annotations", interceptorCAHashKey)
+		}
+		return nil
+	}
+}
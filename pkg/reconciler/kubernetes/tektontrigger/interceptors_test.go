@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektontrigger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+	certresources "knative.dev/pkg/webhook/certificates/resources"
+)
+
+func interceptorsManifest(t *testing.T) mf.Manifest {
+	t.Helper()
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
+		{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]interface{}{"name": coreInterceptorsSecret},
+		}},
+		util.MakeUnstructured(t, util.MakeDeployment(coreInterceptors, corev1.PodSpec{})),
+		{Object: map[string]interface{}{
+			"apiVersion": "triggers.tekton.dev/v1alpha1",
+			"kind":       "ClusterInterceptor",
+			"metadata":   map[string]interface{}{"name": "github"},
+			"spec": map[string]interface{}{
+				"clientConfig": map[string]interface{}{
+					"service": map[string]interface{}{"name": coreInterceptors},
+				},
+			},
+		}},
+	}))
+	util.AssertNoError(t, err)
+	return manifest
+}
+
+func TestInterceptorCertificate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	validity := 365 * 24 * time.Hour
+
+	data, expiry, generated, err := interceptorCertificate(ctx, nil, "tekton-pipelines", validity, now)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, generated, true)
+	util.AssertEqual(t, expiry.Equal(now.Add(validity)), true)
+	secret := &corev1.Secret{Data: data}
+	notAfter, ok := certificateExpiry(secret)
+	util.AssertEqual(t, ok, true)
+	util.AssertEqual(t, notAfter.Sub(now) > validity-time.Hour, true)
+
+	// a valid certificate is kept
+	kept, _, generated, err := interceptorCertificate(ctx, secret, "tekton-pipelines", validity, now.Add(200*24*time.Hour))
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, generated, false)
+	util.AssertDeepEqual(t, kept, data)
+
+	// one with less than a fifth of its validity left is rotated
+	rotated, _, generated, err := interceptorCertificate(ctx, secret, "tekton-pipelines", validity, now.Add(300*24*time.Hour))
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, generated, true)
+	util.AssertEqual(t, string(rotated[certresources.CACert]) != string(data[certresources.CACert]), true)
+
+	// so is an incomplete one
+	_, _, generated, err = interceptorCertificate(ctx, &corev1.Secret{}, "tekton-pipelines", validity, now)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, generated, true)
+}
+
+func TestInterceptorCABundle(t *testing.T) {
+	ca := []byte("ca")
+	manifest, err := interceptorsManifest(t).Transform(interceptorCABundle(ca))
+	util.AssertNoError(t, err)
+	interceptor := manifest.Filter(mf.ByKind("ClusterInterceptor")).Resources()[0]
+	caBundle, _, _ := unstructured.NestedString(interceptor.Object, "spec", "clientConfig", "caBundle")
+	util.AssertEqual(t, caBundle, base64.StdEncoding.EncodeToString(ca))
+	deployment := manifest.Filter(mf.ByKind("Deployment")).Resources()[0]
+	hash, _, _ := unstructured.NestedString(deployment.Object, "spec", "template", "metadata", "annotations", interceptorCAHashKey)
+	util.AssertEqual(t, hash, fmt.Sprintf("%x", sha256.Sum256(ca)))
+}
+
+func TestInterceptorCertificatesWithoutCoreInterceptors(t *testing.T) {
+	tt := &v1alpha1.TektonTrigger{
+		ObjectMeta: metav1.ObjectMeta{Name: "trigger", UID: "uid"},
+		Spec: v1alpha1.TektonTriggerSpec{
+			CommonSpec:     v1alpha1.CommonSpec{TargetNamespace: "tekton-pipelines"},
+			InterceptorTLS: &v1alpha1.InterceptorTLS{Managed: true},
+		},
+	}
+	// the Secret written for a previous payload
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      coreInterceptorsSecret,
+			Namespace: "tekton-pipelines",
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(tt, v1alpha1.SchemeGroupVersion.WithKind(v1alpha1.KindTektonTrigger)),
+			},
+		},
+	})
+	manifest := interceptorsManifest(t).Filter(mf.Not(mf.ByKind("Deployment")))
+	requeued := false
+	stage := interceptorCertificates(kubeClient, func(time.Duration) { requeued = true })
+	util.AssertNoError(t, stage(context.Background(), &manifest, tt))
+	util.AssertEqual(t, requeued, false)
+	util.AssertEqual(t, len(manifest.Resources()), 2)
+	_, err := kubeClient.CoreV1().Secrets("tekton-pipelines").Get(context.Background(), coreInterceptorsSecret, metav1.GetOptions{})
+	util.AssertEqual(t, apierrors.IsNotFound(err), true)
+}
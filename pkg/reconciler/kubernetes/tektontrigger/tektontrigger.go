@@ -18,17 +18,22 @@ package tektontrigger
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	mf "github.com/manifestival/manifestival"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	clientset "github.com/tektoncd/operator/pkg/client/clientset/versioned"
 	pipelineinformer "github.com/tektoncd/operator/pkg/client/informers/externalversions/operator/v1alpha1"
 	tektontriggerreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektontrigger"
+	operatorlisters "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 )
@@ -39,6 +44,9 @@ type Reconciler struct {
 	kubeClientSet kubernetes.Interface
 	// operatorClientSet allows us to configure operator objects
 	operatorClientSet clientset.Interface
+	// dynamicClient is used to rewrite custom resources when the
+	// storage version of a CRD changes
+	dynamicClient dynamic.Interface
 	// manifest is empty, but with a valid client and logger. all
 	// manifests are immutable, and any created during reconcile are
 	// expected to be appended to this one, obviating the passing of
@@ -46,6 +54,16 @@ type Reconciler struct {
 	manifest mf.Manifest
 	// Platform-specific behavior to affect the transform
 	extension common.Extension
+	// overrideLister lists the TektonOverrides layered on top of the spec
+	overrideLister operatorlisters.TektonOverrideLister
+	// triggerLister gets the latest generation of the TektonTrigger being reconciled
+	triggerLister operatorlisters.TektonTriggerLister
+	// enqueueAfter schedules another reconcile, e.g. to resume a storage
+	// version migration
+	enqueueAfter func(obj interface{}, after time.Duration)
+	// leaderElection caches the leader election records of the target
+	// namespace
+	leaderElection *common.LeaderElectionCache
 
 	pipelineInformer pipelineinformer.TektonPipelineInformer
 }
@@ -56,6 +74,7 @@ var _ tektontriggerreconciler.Finalizer = (*Reconciler)(nil)
 
 // FinalizeKind removes all resources after deletion of a TektonTriggers.
 func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.TektonTrigger) pkgreconciler.Event {
+	ctx = common.WithCorrelationID(ctx)
 	logger := logging.FromContext(ctx)
 
 	// List all TektonTriggers to determine if cluster-scoped resources should be deleted.
@@ -74,12 +93,19 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.Tekton
 	if err := r.extension.Finalize(ctx, original); err != nil {
 		logger.Error("Failed to finalize platform resources", err)
 	}
+	if err := revertEventListenerDefaults(ctx, r.dynamicClient); err != nil {
+		logger.Error("Failed to revert the EventListener defaults", err)
+	}
 	logger.Info("Deleting cluster-scoped resources")
 	manifest, err := r.installed(ctx, original)
 	if err != nil {
 		logger.Error("Unable to fetch installed manifest; no cluster-scoped resources will be finalized", err)
 		return nil
 	}
+	if _, err := common.TargetCluster(ctx, r.kubeClientSet, manifest, original); err != nil {
+		logger.Error("Unable to reach the target cluster; no cluster-scoped resources will be finalized", err)
+		return nil
+	}
 	if err := common.Uninstall(ctx, manifest); err != nil {
 		logger.Error("Failed to finalize platform resources", err)
 	}
@@ -89,9 +115,9 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.Tekton
 // ReconcileKind compares the actual state with the desired, and attempts to
 // converge the two.
 func (r *Reconciler) ReconcileKind(ctx context.Context, tt *v1alpha1.TektonTrigger) pkgreconciler.Event {
+	ctx = common.WithCorrelationID(ctx)
 	logger := logging.FromContext(ctx)
-	tt.Status.InitializeConditions()
-	tt.Status.ObservedGeneration = tt.Generation
+	common.InitializeStatus(tt)
 
 	logger.Infow("Reconciling TektonTriggers", "status", tt.Status)
 
@@ -101,9 +127,27 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tt *v1alpha1.TektonTrigg
 			tt.GetName(),
 		)
 		logger.Error(msg)
-		tt.GetStatus().MarkInstallFailed(msg)
-		return nil
+		operrors.MarkFailed(tt.GetStatus(), &operrors.ResourceIgnored{Err: errors.New(msg)})
+		return common.ObserveGeneration(ctx, tt, nil)
+	}
+
+	overrides, err := common.ComponentOverrides(r.overrideLister, v1alpha1.KindTektonTrigger)
+	if err != nil {
+		return err
+	}
+	ctx = common.WithOverrides(ctx, overrides)
+	ctx = common.WithInstallerSets(ctx, r.operatorClientSet.OperatorV1alpha1().TektonInstallerSets())
+	ctx = common.WithLatestGeneration(ctx, func(name string) (int64, error) {
+		latest, err := r.triggerLister.Get(name)
+		if err != nil {
+			return 0, err
+		}
+		return latest.Generation, nil
+	})
+	requeue := func(after time.Duration) {
+		r.enqueueAfter(tt, after)
 	}
+	ctx = common.WithRequeue(ctx, requeue)
 
 	//find the valid tekton-pipeline installation
 	if _, err := common.PipelineReady(r.pipelineInformer); err != nil {
@@ -121,32 +165,76 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tt *v1alpha1.TektonTrigg
 	if err := r.extension.PreReconcile(ctx, tt); err != nil {
 		return err
 	}
+	manifest := r.manifest.Append()
+	dynamicClient := r.dynamicClient
+	kubeClient := r.kubeClientSet
+	leaderElection := r.leaderElection
+	cfg, err := common.TargetCluster(ctx, r.kubeClientSet, &manifest, tt)
+	if err != nil {
+		operrors.MarkFailed(&tt.Status, err)
+		return err
+	}
+	if cfg != nil {
+		if dynamicClient, err = dynamic.NewForConfig(cfg); err != nil {
+			return err
+		}
+		if kubeClient, err = kubernetes.NewForConfig(cfg); err != nil {
+			return err
+		}
+		// the informers watch the cluster of the operator only
+		leaderElection = nil
+	}
 	stages := common.Stages{
+		deprecations,
+		common.BlockDowngrades,
 		common.AppendTarget,
+		appendConfigMaps,
+		interceptorCertificates(kubeClient, requeue),
 		r.transform,
-		common.Install,
+		common.NetworkPolicies,
+		common.PodDisruptionBudgets,
+		common.HorizontalPodAutoscalers,
+		common.Observability,
+		common.ServiceMonitors,
+		common.DebugBundle(r.kubeClientSet),
+		common.PinImageDigests,
+		common.VerifyImageSignatures,
+		common.PrecheckImagePulls,
+		common.ValidateCustomResources,
+		common.CheckResourceSizes,
+		common.SkipUnchanged(common.Install),
+		common.RecordAPIVersions,
 		common.CheckDeployments,
+		common.CleanupLeaderElection(kubeClient, leaderElection),
+		common.MigrateStorageVersions(dynamicClient),
+		applyEventListenerDefaults(dynamicClient),
 	}
-	manifest := r.manifest.Append()
-	return stages.Execute(ctx, &manifest, tt)
+	return common.ObserveGeneration(ctx, tt, stages.Execute(ctx, &manifest, tt))
 }
 
 // transform mutates the passed manifest to one with common, component
 // and platform transformations applied
 func (r *Reconciler) transform(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
 	instance := comp.(*v1alpha1.TektonTrigger)
-	extra := []mf.Transformer{
-		common.ApplyProxySettings,
+	config, err := triggersConfig(&instance.Spec)
+	if err != nil {
+		err = &operrors.TransformError{Err: err}
+		operrors.MarkFailed(&instance.Status, err)
+		return err
 	}
-	extra = append(extra, r.extension.Transformers(instance)...)
-	return common.Transform(ctx, manifest, instance, extra...)
+	extra := append(r.extension.Transformers(instance), common.ConfigMapData(config))
+	// spec.images come after extra to take precedence over the extension's images
+	return common.TransformWith(ctx, manifest, instance, common.TransformOptions{
+		Transformers: extra,
+		Images:       instance.Spec.Images,
+	})
 }
 
 func (r *Reconciler) installed(ctx context.Context, instance v1alpha1.TektonComponent) (*mf.Manifest, error) {
 	// Create new, empty manifest with valid client and logger
 	installed := r.manifest.Append()
 	// TODO: add ingress, etc
-	stages := common.Stages{common.AppendInstalled, r.transform}
+	stages := common.Stages{common.AppendInstalled, appendConfigMaps, r.transform, common.NetworkPolicies, common.PodDisruptionBudgets, common.HorizontalPodAutoscalers, common.ServiceMonitors, common.InstalledObservability}
 	err := stages.Execute(ctx, &installed, instance)
 	return &installed, err
 }
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trustbundle
+
+import (
+	"context"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	tektonConfiginformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonconfig"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	clientcache "k8s.io/client-go/tools/cache"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+// configMapResync is how often the ConfigMaps watched are listed again
+const configMapResync = 10 * time.Minute
+
+// NewController initializes the controller replicating the trust bundle
+// of the TektonConfig into the namespaces
+func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	namespaceInformer := namespaceinformer.Get(ctx)
+	tektonConfigInformer := tektonConfiginformer.Get(ctx)
+	kubeClient := kubeclient.Get(ctx)
+	logger := logging.FromContext(ctx)
+
+	// the source bundle, in the operator namespace
+	sources := clientcache.NewListWatchFromClient(kubeClient.CoreV1().RESTClient(), "configmaps", system.Namespace(), nil)
+	sourceInformer := newConfigMapInformer(sources)
+	// the replicas, restored when edited or deleted
+	replicas := clientcache.NewFilteredListWatchFromClient(kubeClient.CoreV1().RESTClient(), "configmaps", metav1.NamespaceAll,
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = replicaLabel + "=true"
+		})
+	replicaInformer := newConfigMapInformer(replicas)
+
+	r := &Reconciler{
+		kubeClientSet:   kubeClient,
+		namespaceLister: namespaceInformer.Lister(),
+		configLister:    tektonConfigInformer.Lister(),
+		sourceLister:    corelisters.NewConfigMapLister(sourceInformer.GetIndexer()),
+		replicaLister:   corelisters.NewConfigMapLister(replicaInformer.GetIndexer()),
+		synced: func() bool {
+			return sourceInformer.HasSynced() && replicaInformer.HasSynced()
+		},
+	}
+	r.PromoteFunc = r.promote
+	impl := controller.NewImpl(r, logger, "trustbundle")
+
+	logger.Info("Setting up event handlers")
+	namespaceInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+	resync := func(interface{}) {
+		impl.GlobalResync(namespaceInformer.Informer())
+	}
+	// only the trust bundle settings of the TektonConfig matter to the
+	// replicas, not the other changes of its spec nor its status
+	tektonConfigInformer.Informer().AddEventHandler(clientcache.ResourceEventHandlerFuncs{
+		AddFunc: resync,
+		UpdateFunc: func(old, obj interface{}) {
+			if trustBundleChanged(old, obj) {
+				resync(obj)
+			}
+		},
+		DeleteFunc: resync,
+	})
+
+	watchConfigMaps(ctx, sourceInformer, func(cm *corev1.ConfigMap) {
+		if r.isSource(cm) {
+			resync(cm)
+		}
+	})
+	watchConfigMaps(ctx, replicaInformer, func(cm *corev1.ConfigMap) {
+		impl.EnqueueKey(types.NamespacedName{Name: cm.Namespace})
+	})
+	return impl
+}
+
+// trustBundleChanged returns true if the update of the TektonConfig
+// changes its trust bundle settings or excluded namespaces.
+func trustBundleChanged(old, obj interface{}) bool {
+	before, ok := old.(*v1alpha1.TektonConfig)
+	if !ok {
+		return true
+	}
+	after, ok := obj.(*v1alpha1.TektonConfig)
+	if !ok {
+		return true
+	}
+	return !equality.Semantic.DeepEqual(before.Spec.TrustBundle, after.Spec.TrustBundle) ||
+		!equality.Semantic.DeepEqual(before.Spec.ExcludedNamespaces, after.Spec.ExcludedNamespaces)
+}
+
+// newConfigMapInformer returns an informer of the ConfigMaps of lw
+func newConfigMapInformer(lw clientcache.ListerWatcher) clientcache.SharedIndexInformer {
+	return clientcache.NewSharedIndexInformer(lw, &corev1.ConfigMap{}, configMapResync,
+		clientcache.Indexers{clientcache.NamespaceIndex: clientcache.MetaNamespaceIndexFunc})
+}
+
+// watchConfigMaps calls onChange with the ConfigMaps of the informer
+// added, updated or deleted, running it until ctx is done.
+func watchConfigMaps(ctx context.Context, informer clientcache.SharedIndexInformer, onChange func(*corev1.ConfigMap)) {
+	changed := func(obj interface{}) {
+		if tombstone, ok := obj.(clientcache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		if cm, ok := obj.(*corev1.ConfigMap); ok {
+			onChange(cm)
+		}
+	}
+	informer.AddEventHandler(clientcache.ResourceEventHandlerFuncs{
+		AddFunc:    changed,
+		UpdateFunc: func(_, obj interface{}) { changed(obj) },
+		DeleteFunc: changed,
+	})
+	go informer.Run(ctx.Done())
+}
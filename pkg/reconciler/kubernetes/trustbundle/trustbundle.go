@@ -0,0 +1,207 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trustbundle
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorlisters "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+)
+
+// replicaLabel marks the replicas of the trust bundle. ConfigMaps of the
+// same name without it belong to the users and are left alone.
+const replicaLabel = "operator.tekton.dev/trust-bundle"
+
+// Reconciler replicates the trust bundle of the TektonConfig into a
+// namespace, keyed by the name of the namespace. Only the leader of the
+// operator reconciles, standby replicas keeping their caches warm.
+type Reconciler struct {
+	reconciler.LeaderAwareFuncs
+
+	// kubeClientSet allows us to talk to the k8s for core APIs
+	kubeClientSet kubernetes.Interface
+	// namespaceLister gets the namespaces to replicate to
+	namespaceLister corelisters.NamespaceLister
+	// configLister gets the TektonConfig holding the trust bundle settings
+	configLister operatorlisters.TektonConfigLister
+	// sourceLister gets the ConfigMaps of the operator namespace, holding
+	// the source bundle
+	sourceLister corelisters.ConfigMapLister
+	// replicaLister lists the replicas of the bundle in the namespaces
+	replicaLister corelisters.ConfigMapLister
+	// synced returns true once the ConfigMap listers are filled
+	synced func() bool
+}
+
+// promote enqueues all the namespaces once the replica leads the bucket
+func (r *Reconciler) promote(bkt reconciler.Bucket, enq func(reconciler.Bucket, types.NamespacedName)) error {
+	namespaces, err := r.namespaceLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, ns := range namespaces {
+		enq(bkt, types.NamespacedName{Name: ns.Name})
+	}
+	return nil
+}
+
+// trustBundle returns the trust bundle settings and the excluded
+// namespaces of the TektonConfig, nil without either.
+func (r *Reconciler) trustBundle() (*v1alpha1.TrustBundle, []string, error) {
+	config, err := r.configLister.Get(common.ConfigResourceName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return config.Spec.TrustBundle, config.Spec.ExcludedNamespaces, nil
+}
+
+// isSource returns true if the ConfigMap is the source of the trust bundle
+func (r *Reconciler) isSource(cm *corev1.ConfigMap) bool {
+	bundle, _, err := r.trustBundle()
+	return err == nil && bundle != nil && cm.Namespace == system.Namespace() && cm.Name == bundle.ConfigMap
+}
+
+// Reconcile creates, updates or deletes the replica of the trust bundle in
+// the namespace of the key.
+func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+	if !r.IsLeaderFor(types.NamespacedName{Name: key}) {
+		// reconciled by the leader, or once promoted
+		return nil
+	}
+	ns, err := r.namespaceLister.Get(key)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !r.synced() {
+		return fmt.Errorf("waiting for the ConfigMaps of the trust bundle to sync")
+	}
+	bundle, excluded, err := r.trustBundle()
+	if err != nil {
+		return err
+	}
+	wanted, err := replicated(bundle, excluded, ns)
+	if err != nil {
+		return err
+	}
+
+	var source *corev1.ConfigMap
+	if wanted {
+		source, err = r.sourceLister.ConfigMaps(system.Namespace()).Get(bundle.ConfigMap)
+		if apierrors.IsNotFound(err) {
+			// replicated once it is created
+			logger.Warnw("Trust bundle ConfigMap not found", "configmap", bundle.ConfigMap)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	configMaps := r.kubeClientSet.CoreV1().ConfigMaps(ns.Name)
+	existing, err := r.replicaLister.ConfigMaps(ns.Name).List(labels.SelectorFromSet(labels.Set{replicaLabel: "true"}))
+	if err != nil {
+		return err
+	}
+	var current *corev1.ConfigMap
+	for _, cm := range existing {
+		if wanted && cm.Name == bundle.GetName() {
+			current = cm
+			continue
+		}
+		// replicas of a bundle renamed or no longer wanted
+		logger.Infow("Deleting trust bundle replica", "namespace", cm.Namespace, "name", cm.Name)
+		if err := configMaps.Delete(ctx, cm.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	if !wanted {
+		return nil
+	}
+
+	desired := replica(bundle, source, ns.Name)
+	if current == nil {
+		_, err = configMaps.Create(ctx, desired, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			logger.Warnw("ConfigMap of the trust bundle name not owned by the operator, leaving it alone",
+				"namespace", ns.Name, "name", desired.Name)
+			return nil
+		}
+		return err
+	}
+	if reflect.DeepEqual(current.Data, desired.Data) && reflect.DeepEqual(current.BinaryData, desired.BinaryData) {
+		return nil
+	}
+	// the lister shares its objects
+	current = current.DeepCopy()
+	current.Data, current.BinaryData = desired.Data, desired.BinaryData
+	if _, err := configMaps.Update(ctx, current, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update trust bundle replica %s/%s: %w", ns.Name, current.Name, err)
+	}
+	return nil
+}
+
+// replicated returns true if the namespace gets a replica of the bundle:
+// it is selected, not excluded, not terminating and not the operator
+// namespace holding the source.
+func replicated(bundle *v1alpha1.TrustBundle, excluded []string, ns *corev1.Namespace) (bool, error) {
+	if bundle == nil || bundle.ConfigMap == "" || ns.Name == system.Namespace() ||
+		ns.Status.Phase == corev1.NamespaceTerminating || common.NamespaceExcluded(excluded, ns.Name) {
+		return false, nil
+	}
+	if bundle.NamespaceSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(bundle.NamespaceSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid spec.trustBundle.namespaceSelector: %w", err)
+	}
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+// replica returns the replica of the source bundle in the namespace
+func replica(bundle *v1alpha1.TrustBundle, source *corev1.ConfigMap, namespace string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bundle.GetName(),
+			Namespace: namespace,
+			Labels:    map[string]string{replicaLabel: "true"},
+		},
+		Data:       source.Data,
+		BinaryData: source.BinaryData,
+	}
+}
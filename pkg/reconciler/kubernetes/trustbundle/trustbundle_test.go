@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trustbundle
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorlisters "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func TestReplicated(t *testing.T) {
+	namespace := func(name string, labels map[string]string, phase corev1.NamespacePhase) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status:     corev1.NamespaceStatus{Phase: phase},
+		}
+	}
+	all := &v1alpha1.TrustBundle{ConfigMap: "ca-bundle"}
+	selected := &v1alpha1.TrustBundle{
+		ConfigMap:         "ca-bundle",
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"ci": "true"}},
+	}
+	excluded := []string{"kube-*"}
+	tests := []struct {
+		name   string
+		bundle *v1alpha1.TrustBundle
+		ns     *corev1.Namespace
+		want   bool
+	}{
+		{"no bundle", nil, namespace("team-a", nil, corev1.NamespaceActive), false},
+		{"all namespaces", all, namespace("team-a", nil, corev1.NamespaceActive), true},
+		{"excluded namespace", all, namespace("kube-system", nil, corev1.NamespaceActive), false},
+		{"operator namespace", all, namespace(system.Namespace(), nil, corev1.NamespaceActive), false},
+		{"terminating namespace", all, namespace("team-a", nil, corev1.NamespaceTerminating), false},
+		{"selected namespace", selected, namespace("team-a", map[string]string{"ci": "true"}, corev1.NamespaceActive), true},
+		{"unselected namespace", selected, namespace("team-b", nil, corev1.NamespaceActive), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := replicated(test.bundle, excluded, test.ns)
+			util.AssertNoError(t, err)
+			util.AssertEqual(t, got, test.want)
+		})
+	}
+}
+
+func TestReplicatedInvalidSelector(t *testing.T) {
+	bundle := &v1alpha1.TrustBundle{
+		ConfigMap: "ca-bundle",
+		NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key: "ci", Operator: "Unknown",
+		}}},
+	}
+	if _, err := replicated(bundle, nil, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}); err == nil {
+		t.Error("expected an error for an invalid selector")
+	}
+}
+
+func TestReplica(t *testing.T) {
+	source := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: system.Namespace(), Labels: map[string]string{"app": "x"}},
+		Data:       map[string]string{"ca-bundle.crt": "PEM"},
+	}
+	got := replica(&v1alpha1.TrustBundle{ConfigMap: "ca-bundle"}, source, "team-a")
+	util.AssertEqual(t, got.Name, v1alpha1.DefaultTrustBundleName)
+	util.AssertEqual(t, got.Namespace, "team-a")
+	util.AssertDeepEqual(t, got.Labels, map[string]string{replicaLabel: "true"})
+	util.AssertDeepEqual(t, got.Data, source.Data)
+}
+
+func TestReconcileStandby(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, name := range []string{"team-a", "team-b"} {
+		util.AssertNoError(t, indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}))
+	}
+	// no clients: a standby replica must not reach out to the cluster
+	r := &Reconciler{namespaceLister: corelisters.NewNamespaceLister(indexer)}
+	r.PromoteFunc = r.promote
+	util.AssertNoError(t, r.Reconcile(context.Background(), "team-a"))
+
+	var enqueued []string
+	enq := func(_ reconciler.Bucket, key types.NamespacedName) {
+		enqueued = append(enqueued, key.Name)
+	}
+	util.AssertNoError(t, r.Promote(reconciler.UniversalBucket(), enq))
+	sort.Strings(enqueued)
+	util.AssertDeepEqual(t, enqueued, []string{"team-a", "team-b"})
+	util.AssertEqual(t, r.IsLeaderFor(types.NamespacedName{Name: "team-a"}), true)
+
+	r.Demote(reconciler.UniversalBucket())
+	util.AssertNoError(t, r.Reconcile(context.Background(), "team-a"))
+}
+
+func TestTrustBundleChanged(t *testing.T) {
+	config := &v1alpha1.TektonConfig{}
+	config.Spec.TrustBundle = &v1alpha1.TrustBundle{ConfigMap: "ca-bundle"}
+
+	status := config.DeepCopy()
+	status.Status.MarkInstallSucceeded()
+	util.AssertEqual(t, trustBundleChanged(config, status), false)
+
+	profile := config.DeepCopy()
+	profile.Spec.Profile = "lite"
+	util.AssertEqual(t, trustBundleChanged(config, profile), false)
+
+	renamed := config.DeepCopy()
+	renamed.Spec.TrustBundle.ConfigMap = "corporate-ca"
+	util.AssertEqual(t, trustBundleChanged(config, renamed), true)
+
+	excluded := config.DeepCopy()
+	excluded.Spec.ExcludedNamespaces = []string{"kube-*"}
+	util.AssertEqual(t, trustBundleChanged(config, excluded), true)
+}
+
+func TestReconcileFromListers(t *testing.T) {
+	namespaces := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	configs := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	sources := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	replicas := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+
+	util.AssertNoError(t, namespaces.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}))
+	config := &v1alpha1.TektonConfig{ObjectMeta: metav1.ObjectMeta{Name: common.ConfigResourceName}}
+	config.Spec.TrustBundle = &v1alpha1.TrustBundle{ConfigMap: "ca-bundle"}
+	util.AssertNoError(t, configs.Add(config))
+	util.AssertNoError(t, sources.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: system.Namespace()},
+		Data:       map[string]string{"ca-bundle.crt": "PEM"},
+	}))
+	stale := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "renamed-bundle", Namespace: "team-a", Labels: map[string]string{replicaLabel: "true"}},
+	}
+	util.AssertNoError(t, replicas.Add(stale))
+
+	client := fake.NewSimpleClientset(stale)
+	synced := false
+	r := &Reconciler{
+		kubeClientSet:   client,
+		namespaceLister: corelisters.NewNamespaceLister(namespaces),
+		configLister:    operatorlisters.NewTektonConfigLister(configs),
+		sourceLister:    corelisters.NewConfigMapLister(sources),
+		replicaLister:   corelisters.NewConfigMapLister(replicas),
+		synced:          func() bool { return synced },
+	}
+	r.PromoteFunc = r.promote
+	util.AssertNoError(t, r.Promote(reconciler.UniversalBucket(), func(reconciler.Bucket, types.NamespacedName) {}))
+
+	if err := r.Reconcile(context.Background(), "team-a"); err == nil {
+		t.Fatal("expected an error until the listers are synced")
+	}
+
+	synced = true
+	util.AssertNoError(t, r.Reconcile(context.Background(), "team-a"))
+	// the reads went through the listers
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "get" || action.GetVerb() == "list" {
+			t.Errorf("unexpected %s of %s", action.GetVerb(), action.GetResource().Resource)
+		}
+	}
+	got, err := client.CoreV1().ConfigMaps("team-a").Get(context.Background(), v1alpha1.DefaultTrustBundleName, metav1.GetOptions{})
+	util.AssertNoError(t, err)
+	util.AssertDeepEqual(t, got.Data, map[string]string{"ca-bundle.crt": "PEM"})
+	if _, err := client.CoreV1().ConfigMaps("team-a").Get(context.Background(), "renamed-bundle", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the stale replica to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshiftpipelinesascode
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// settingsConfig is the ConfigMap of the payload spec.settings is merged
+// into
+const settingsConfig = "pipelines-as-code"
+
+// boolSettings are the settings the controller parses as booleans
+var boolSettings = []string{"remote-tasks", "secret-auto-create"}
+
+// validateSettings returns an error if a setting of spec.settings the
+// controller would fail to parse has an invalid value. Other settings are
+// passed through as is.
+func validateSettings(settings map[string]string) error {
+	for _, key := range boolSettings {
+		if value, ok := settings[key]; ok {
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("invalid spec.settings.%s %q, expected true or false", key, value)
+			}
+		}
+	}
+	if value, ok := settings["max-keep-days"]; ok {
+		if days, err := strconv.Atoi(value); err != nil || days < 0 {
+			return fmt.Errorf("invalid spec.settings.max-keep-days %q, expected a number of days", value)
+		}
+	}
+	return nil
+}
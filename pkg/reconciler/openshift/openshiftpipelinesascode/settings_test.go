@@ -0,0 +1,42 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshiftpipelinesascode
+
+import (
+	"testing"
+
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+)
+
+func TestValidateSettings(t *testing.T) {
+	util.AssertNoError(t, validateSettings(nil))
+	util.AssertNoError(t, validateSettings(map[string]string{
+		"application-name":   "CI",
+		"secret-auto-create": "false",
+		"max-keep-days":      "7",
+	}))
+
+	err := validateSettings(map[string]string{"remote-tasks": "sometimes"})
+	if err == nil {
+		t.Fatal("expected an error for an invalid remote-tasks")
+	}
+	util.AssertEqual(t, err.Error(), `invalid spec.settings.remote-tasks "sometimes", expected true or false`)
+
+	if err := validateSettings(map[string]string{"max-keep-days": "-1"}); err == nil {
+		t.Error("expected an error for a negative max-keep-days")
+	}
+}
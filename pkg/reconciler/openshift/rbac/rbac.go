@@ -60,6 +60,14 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, ns *corev1.Namespace) pk
 		logger.Infow("Reconciling Namespace: IGNORE", "status", ns.GetName())
 		return nil
 	}
+	excluded, err := common.ExcludedNamespaces(ctx, r.operatorClientSet)
+	if err != nil {
+		return err
+	}
+	if common.NamespaceExcluded(excluded, ns.GetName()) {
+		logger.Infow("Reconciling Namespace: EXCLUDED", "status", ns.GetName())
+		return nil
+	}
 	logger.Infow("Reconciling Default SA in ", "Namespace", ns.GetName())
 
 	sa, err := r.ensureSA(ctx, ns)
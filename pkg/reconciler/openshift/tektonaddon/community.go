@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonaddon
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+)
+
+// communityTaskURLTemplate is the catalog location of a community task,
+// parameterized by task name and version
+const communityTaskURLTemplate = "https://raw.githubusercontent.com/tektoncd/catalog/master/task/%[1]s/%[2]s/%[1]s.yaml"
+
+// communityTasks are the community tasks installed by default, with their
+// catalog version
+var communityTasks = map[string]string{
+	"jib-maven":                "0.1",
+	"maven":                    "0.1",
+	"tkn":                      "0.1",
+	"helm-upgrade-from-source": "0.1",
+	"helm-upgrade-from-repo":   "0.1",
+	"trigger-jenkins-job":      "0.1",
+	"git-cli":                  "0.1",
+	"pull-request":             "0.1",
+	"kubeconfig-creator":       "0.1",
+}
+
+var (
+	taskNameRegexp    = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	taskVersionRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)*$`)
+)
+
+// resolveCommunityTasks merges the version pins over the default community
+// tasks and returns them sorted by name, with the url of their manifest or
+// the reason it can't be formed.
+func resolveCommunityTasks(pins map[string]string) []v1alpha1.CommunityTaskStatus {
+	versions := map[string]string{}
+	for name, version := range communityTasks {
+		versions[name] = version
+	}
+	for name, version := range pins {
+		versions[name] = version
+	}
+
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tasks := make([]v1alpha1.CommunityTaskStatus, 0, len(names))
+	for _, name := range names {
+		task := v1alpha1.CommunityTaskStatus{Name: name, Version: versions[name]}
+		switch {
+		case !taskNameRegexp.MatchString(task.Name):
+			task.Error = fmt.Sprintf("invalid task name %q", task.Name)
+		case !taskVersionRegexp.MatchString(task.Version):
+			task.Error = fmt.Sprintf("invalid version %q", task.Version)
+		default:
+			task.URL = fmt.Sprintf(communityTaskURLTemplate, task.Name, task.Version)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
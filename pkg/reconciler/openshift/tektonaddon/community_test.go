@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonaddon
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+)
+
+func TestResolveCommunityTasks(t *testing.T) {
+	tasks := resolveCommunityTasks(map[string]string{
+		"maven":     "0.2",
+		"buildah":   "0.3",
+		"tkn":       "latest",
+		"../secret": "0.1",
+	})
+	util.AssertEqual(t, len(tasks), len(communityTasks)+2)
+
+	byName := map[string]v1alpha1.CommunityTaskStatus{}
+	for _, task := range tasks {
+		byName[task.Name] = task
+	}
+	util.AssertDeepEqual(t, byName["maven"], v1alpha1.CommunityTaskStatus{
+		Name:    "maven",
+		Version: "0.2",
+		URL:     "https://raw.githubusercontent.com/tektoncd/catalog/master/task/maven/0.2/maven.yaml",
+	})
+	util.AssertDeepEqual(t, byName["buildah"], v1alpha1.CommunityTaskStatus{
+		Name:    "buildah",
+		Version: "0.3",
+		URL:     "https://raw.githubusercontent.com/tektoncd/catalog/master/task/buildah/0.3/buildah.yaml",
+	})
+	util.AssertDeepEqual(t, byName["tkn"], v1alpha1.CommunityTaskStatus{
+		Name:    "tkn",
+		Version: "latest",
+		Error:   `invalid version "latest"`,
+	})
+	util.AssertEqual(t, byName["../secret"].Error, `invalid task name "../secret"`)
+	util.AssertEqual(t, byName["git-cli"].Version, "0.1")
+
+	for i := 1; i < len(tasks); i++ {
+		if tasks[i-1].Name > tasks[i].Name {
+			t.Fatalf("tasks not sorted: %s before %s", tasks[i-1].Name, tasks[i].Name)
+		}
+	}
+}
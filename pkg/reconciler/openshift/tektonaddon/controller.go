@@ -19,10 +19,12 @@ package tektonaddon
 import (
 	"context"
 	"github.com/go-logr/zapr"
-	mfc "github.com/manifestival/client-go-client"
 	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	operatorclient "github.com/tektoncd/operator/pkg/client/injection/client"
 	tektonAddoninformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonaddon"
+	tektonInstallerSetinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektoninstallerset"
+	tektonOverrideinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonoverride"
 	tektonPipelineinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonpipeline"
 	tektonTriggerinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektontrigger"
 	tektonAddonreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektonaddon"
@@ -49,10 +51,12 @@ func NewExtendedController(generator common.ExtensionGenerator) injection.Contro
 		tektonPipelineInformer := tektonPipelineinformer.Get(ctx)
 		tektonTriggerInformer := tektonTriggerinformer.Get(ctx)
 		tektonAddonInformer := tektonAddoninformer.Get(ctx)
+		tektonOverrideInformer := tektonOverrideinformer.Get(ctx)
+		tektonInstallerSetInformer := tektonInstallerSetinformer.Get(ctx)
 		kubeClient := kubeclient.Get(ctx)
 		logger := logging.FromContext(ctx)
 
-		mfclient, err := mfc.NewClient(injection.GetConfig(ctx))
+		mfclient, err := common.NewClient(injection.GetConfig(ctx))
 		if err != nil {
 			logger.Fatalw("Error creating client from injected config", zap.Error(err))
 		}
@@ -62,11 +66,17 @@ func NewExtendedController(generator common.ExtensionGenerator) injection.Contro
 			logger.Fatalw("Error creating initial manifest", zap.Error(err))
 		}
 
+		common.ReportKeyCollisions(ctx, &v1alpha1.TektonAddon{}, common.AddonsImagePrefix)
+		images := common.NewImageStore()
+		ctx = common.WithImageStore(ctx, images)
+
 		c := &Reconciler{
 			kubeClientSet:     kubeClient,
 			operatorClientSet: operatorclient.Get(ctx),
 			extension:         generator(ctx),
 			manifest:          manifest,
+			overrideLister:    tektonOverrideInformer.Lister(),
+			addonLister:       tektonAddonInformer.Lister(),
 			pipelineInformer:  tektonPipelineInformer,
 			triggerInformer:   tektonTriggerInformer,
 		}
@@ -75,6 +85,17 @@ func NewExtendedController(generator common.ExtensionGenerator) injection.Contro
 		logger.Info("Setting up event handlers")
 
 		tektonAddonInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+		tektonOverrideInformer.Informer().AddEventHandler(common.EnqueueOnOverride(impl.EnqueueKey, v1alpha1.KindTektonAddon, common.AddonResourceName))
+		tektonInstallerSetInformer.Informer().AddEventHandler(common.EnqueueOnInstallerSet(impl.EnqueueControllerOf, v1alpha1.KindTektonAddon))
+		tektonPipelineInformer.Informer().AddEventHandler(common.EnqueueOnReady(impl.EnqueueKey, common.AddonResourceName))
+		tektonTriggerInformer.Informer().AddEventHandler(common.EnqueueOnReady(impl.EnqueueKey, common.AddonResourceName))
+		common.WatchOwned(ctx, v1alpha1.KindTektonAddon, impl.EnqueueControllerOf)
+		resyncImages := func() {
+			impl.GlobalResync(tektonAddonInformer.Informer())
+		}
+		common.WatchImages(cmw, images, resyncImages)
+		common.WatchImagesSecret(ctx, kubeClient, images, resyncImages)
+
 		return impl
 	}
 }
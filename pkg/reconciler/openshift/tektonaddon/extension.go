@@ -25,16 +25,21 @@ import (
 )
 
 // NoPlatform "generates" a NilExtension
-func OpenShiftExtension(context.Context) common.Extension {
-	return openshiftExtension{}
+func OpenShiftExtension(ctx context.Context) common.Extension {
+	return openshiftExtension{
+		images: common.ImageStoreFromContext(ctx),
+	}
 }
 
-type openshiftExtension struct{}
+type openshiftExtension struct {
+	images *common.ImageStore
+}
 
 func (oe openshiftExtension) Transformers(comp v1alpha1.TektonComponent) []mf.Transformer {
-	addonImages := common.ToLowerCaseKeys(common.ImagesFromEnv(common.AddonsImagePrefix))
+	addonImages := common.ToLowerCaseKeys(oe.images.Images(common.AddonsImagePrefix))
 	return []mf.Transformer{
 		common.TaskImages(addonImages),
+		common.StepActionImages(addonImages),
 	}
 }
 func (oe openshiftExtension) PreReconcile(context.Context, v1alpha1.TektonComponent) error {
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonaddon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"knative.dev/pkg/apis"
+)
+
+// addonGroups are the groups of resources of the addon installed, in an
+// installer set of their own, and reported by a condition, on their own,
+// so that a failing group doesn't hide whether the others installed.
+var addonGroups = []struct {
+	part      string
+	condition apis.ConditionType
+	resources mf.Predicate
+}{
+	{"clustertasks", v1alpha1.ClusterTasksReady, mf.ByKind("ClusterTask")},
+	{"pipelinetemplates", v1alpha1.PipelineTemplatesReady, mf.All(mf.ByKind("Pipeline"), byAPIGroup("tekton.dev"))},
+	{"triggersresources", v1alpha1.TriggersResourcesReady, byAPIGroup("triggers.tekton.dev")},
+}
+
+func byAPIGroup(group string) mf.Predicate {
+	return func(u *unstructured.Unstructured) bool {
+		return u.GroupVersionKind().Group == group
+	}
+}
+
+// installGroups returns a Stage installing the resources out of the groups
+// first, then each group, carrying on past failures. The condition of a
+// group without resources, e.g. paused, is marked true as nothing of it
+// failed. The groups whose installer set is pending keep their condition
+// until it applied them. The installer sets of the parts are named after
+// the provider, so that the Red Hat and the community payloads don't
+// replace one another's.
+func installGroups(provider string) common.Stage {
+	return func(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+		return installProviderGroups(ctx, provider, manifest, comp.(*v1alpha1.TektonAddon))
+	}
+}
+
+// providerPart returns the installer set part of the provider, the part of
+// the Red Hat payload being unprefixed
+func providerPart(provider, part string) string {
+	switch {
+	case provider == providerTypeRedHat:
+		return part
+	case part == "":
+		return provider
+	}
+	return provider + "-" + part
+}
+
+func installProviderGroups(ctx context.Context, provider string, manifest *mf.Manifest, instance *v1alpha1.TektonAddon) error {
+	var failed []string
+	var cause error
+	pending := false
+	rest := *manifest
+	for _, g := range addonGroups {
+		rest = rest.Filter(mf.Not(g.resources))
+	}
+	if err := common.InstallPart(ctx, providerPart(provider, ""), &rest, instance); errors.Is(err, common.ErrInstallerSetPending) {
+		pending = true
+	} else if err != nil {
+		failed = append(failed, "resources out of the groups")
+		cause = err
+	}
+	for _, g := range addonGroups {
+		group := manifest.Filter(g.resources)
+		err := common.InstallPart(ctx, providerPart(provider, g.part), &group, instance)
+		if errors.Is(err, common.ErrInstallerSetPending) {
+			pending = true
+			continue
+		}
+		if err != nil {
+			instance.Status.MarkGroupFailed(g.condition, operrors.Reason(err), err.Error())
+			failed = append(failed, string(g.condition))
+			if cause == nil {
+				cause = err
+			}
+			continue
+		}
+		instance.Status.MarkGroupReady(g.condition)
+	}
+	if cause != nil {
+		// each Install marks the install succeeded or failed, the result is
+		// the one of all the groups
+		err := fmt.Errorf("failed to install %s: %w", strings.Join(failed, ", "), cause)
+		operrors.MarkFailed(instance.GetStatus(), err)
+		return err
+	}
+	if pending {
+		instance.Status.MarkInstallReconciling()
+		return common.ErrInstallerSetPending
+	}
+	return nil
+}
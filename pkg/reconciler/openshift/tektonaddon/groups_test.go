@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonaddon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	fake "github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorfake "github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func addonResource(apiVersion, kind, name string) unstructured.Unstructured {
+	u := unstructured.Unstructured{}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetName(name)
+	return u
+}
+
+func TestInstallGroups(t *testing.T) {
+	os.Setenv(common.KoEnvKey, "../../../../cmd/openshift/kodata")
+	defer os.Unsetenv(common.KoEnvKey)
+
+	client := fake.New()
+	create := client.Stubs.Create
+	client.Stubs.Create = func(u *unstructured.Unstructured) error {
+		if u.GetKind() == "Pipeline" {
+			return errors.New("test")
+		}
+		return create(u)
+	}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
+		addonResource("tekton.dev/v1beta1", "ClusterTask", "buildah"),
+		addonResource("tekton.dev/v1beta1", "Pipeline", "s2i-go"),
+		addonResource("console.openshift.io/v1", "ConsoleCLIDownload", "tkn"),
+	}), mf.UseClient(client))
+	util.AssertNoError(t, err)
+
+	instance := &v1alpha1.TektonAddon{}
+	instance.Status.InitializeConditions()
+	err = installGroups(providerTypeRedHat)(context.Background(), &manifest, instance)
+	if err == nil {
+		t.Fatal("installGroups() = nil, wanted an error for the failing pipelines")
+	}
+	util.AssertEqual(t, err.Error(), "failed to install PipelineTemplatesReady: failed to apply non rbac manifest: test")
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.InstallSucceeded).IsFalse(), true)
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.PipelineTemplatesReady).IsFalse(), true)
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.ClusterTasksReady).IsTrue(), true)
+	// no trigger resources, nothing failed
+	util.AssertEqual(t, instance.Status.GetCondition(v1alpha1.TriggersResourcesReady).IsTrue(), true)
+
+	live, err := client.Get(&manifest.Filter(mf.ByKind("ClusterTask")).Resources()[0])
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, live.GetName(), "buildah")
+}
+
+func TestInstallGroupsOfProviders(t *testing.T) {
+	os.Setenv(common.KoEnvKey, "../../../../cmd/openshift/kodata")
+	defer os.Unsetenv(common.KoEnvKey)
+
+	installerSets := operatorfake.NewSimpleClientset().OperatorV1alpha1().TektonInstallerSets()
+	ctx := common.WithInstallerSets(context.Background(), installerSets)
+	instance := &v1alpha1.TektonAddon{}
+	instance.SetName(common.AddonResourceName)
+	instance.Status.InitializeConditions()
+
+	redhat, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
+		addonResource("tekton.dev/v1beta1", "ClusterTask", "buildah"),
+		addonResource("tekton.dev/v1beta1", "Pipeline", "s2i-go"),
+		addonResource("console.openshift.io/v1", "ConsoleCLIDownload", "tkn"),
+	}))
+	util.AssertNoError(t, err)
+	community, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
+		addonResource("tekton.dev/v1beta1", "ClusterTask", "maven"),
+	}))
+	util.AssertNoError(t, err)
+
+	// the resources each installer set holds, which the installer set
+	// reconciler would delete once they leave it
+	held := func() map[string]sets.String {
+		list, err := installerSets.List(ctx, metav1.ListOptions{})
+		util.AssertNoError(t, err)
+		result := map[string]sets.String{}
+		for _, set := range list.Items {
+			result[set.Name] = sets.NewString()
+			for _, u := range set.Spec.Manifests {
+				result[set.Name].Insert(u.GetKind() + "/" + u.GetName())
+			}
+		}
+		return result
+	}
+
+	var first map[string]sets.String
+	for i := 0; i < 2; i++ {
+		for _, stage := range []struct {
+			provider string
+			manifest mf.Manifest
+		}{{providerTypeRedHat, redhat}, {providerTypeCommunity, community}} {
+			err := installGroups(stage.provider)(ctx, &stage.manifest, instance)
+			if err != nil && !errors.Is(err, common.ErrInstallerSetPending) {
+				t.Fatalf("installGroups(%s) = %v", stage.provider, err)
+			}
+		}
+		if i == 0 {
+			first = held()
+			continue
+		}
+		// installing both payloads again drops nothing from the sets
+		for name, resources := range held() {
+			if !resources.Equal(first[name]) {
+				t.Errorf("installer set %s holds %v, wanted %v", name, resources.List(), first[name].List())
+			}
+		}
+	}
+	util.AssertDeepEqual(t, first["tektonaddon-addon"].List(), []string{"ConsoleCLIDownload/tkn"})
+	util.AssertDeepEqual(t, first["tektonaddon-addon-clustertasks"].List(), []string{"ClusterTask/buildah"})
+	util.AssertDeepEqual(t, first["tektonaddon-addon-pipelinetemplates"].List(), []string{"Pipeline/s2i-go"})
+	util.AssertDeepEqual(t, first["tektonaddon-addon-community-clustertasks"].List(), []string{"ClusterTask/maven"})
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonaddon
+
+import (
+	"context"
+	"fmt"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
+)
+
+// addonParams are the resources each param of TektonAddon enables.
+var addonParams = map[string]mf.Predicate{
+	v1alpha1.ClusterTasksParam:          mf.All(mf.ByKind("ClusterTask"), mf.ByLabel(labelProviderType, providerTypeRedHat)),
+	v1alpha1.PipelineTemplatesParam:     mf.All(mf.ByKind("Pipeline"), byAPIGroup("tekton.dev")),
+	v1alpha1.CommunityClusterTasksParam: mf.All(mf.ByKind("ClusterTask"), mf.ByLabel(labelProviderType, providerTypeCommunity)),
+}
+
+// enabledParams returns whether each param of addonParams is enabled,
+// failing on unknown params and values other than true and false.
+func enabledParams(params []v1alpha1.Param) (map[string]bool, error) {
+	enabled := make(map[string]bool, len(addonParams))
+	for name := range addonParams {
+		enabled[name] = true
+	}
+	for _, p := range params {
+		if _, ok := addonParams[p.Name]; !ok {
+			return nil, fmt.Errorf("unknown param %q, expected one of %s, %s or %s", p.Name,
+				v1alpha1.ClusterTasksParam, v1alpha1.PipelineTemplatesParam, v1alpha1.CommunityClusterTasksParam)
+		}
+		if p.Value != "true" && p.Value != "false" {
+			return nil, fmt.Errorf("invalid value %q of param %s, expected true or false", p.Value, p.Name)
+		}
+		enabled[p.Name] = p.Value == "true"
+	}
+	return enabled, nil
+}
+
+// deleteDisabled is a Stage dropping the resources of the params disabled
+// in spec.params from the manifest. They are deleted from the cluster when
+// the generation of the spec wasn't installed yet, e.g. after a param
+// flipped to false.
+func deleteDisabled(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	instance := comp.(*v1alpha1.TektonAddon)
+	enabled, err := enabledParams(instance.Spec.Params)
+	if err != nil {
+		return err
+	}
+	var disabled []mf.Predicate
+	for name, resources := range addonParams {
+		if !enabled[name] {
+			disabled = append(disabled, resources)
+		}
+	}
+	if len(disabled) == 0 {
+		return nil
+	}
+	unwanted := manifest.Filter(mf.Any(disabled...))
+	*manifest = manifest.Filter(mf.Not(mf.Any(disabled...)))
+	if instance.Status.GetObservedGeneration() == instance.GetGeneration() {
+		return nil
+	}
+	if err := common.Uninstall(ctx, &unwanted); err != nil {
+		operrors.MarkFailed(&instance.Status, err)
+		return err
+	}
+	return nil
+}
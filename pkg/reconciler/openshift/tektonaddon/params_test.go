@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonaddon
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	fake "github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestEnabledParams(t *testing.T) {
+	enabled, err := enabledParams([]v1alpha1.Param{{Name: v1alpha1.PipelineTemplatesParam, Value: "false"}})
+	util.AssertNoError(t, err)
+	util.AssertDeepEqual(t, enabled, map[string]bool{
+		v1alpha1.ClusterTasksParam:          true,
+		v1alpha1.PipelineTemplatesParam:     false,
+		v1alpha1.CommunityClusterTasksParam: true,
+	})
+
+	_, err = enabledParams([]v1alpha1.Param{{Name: "triggers", Value: "false"}})
+	util.AssertEqual(t, err.Error(), `unknown param "triggers", expected one of clusterTasks, pipelineTemplates or communityClusterTasks`)
+	_, err = enabledParams([]v1alpha1.Param{{Name: v1alpha1.ClusterTasksParam, Value: "no"}})
+	util.AssertEqual(t, err.Error(), `invalid value "no" of param clusterTasks, expected true or false`)
+}
+
+func TestDeleteDisabled(t *testing.T) {
+	task := func(name, provider string) unstructured.Unstructured {
+		u := addonResource("tekton.dev/v1beta1", "ClusterTask", name)
+		u.SetLabels(map[string]string{labelProviderType: provider})
+		return u
+	}
+	resources := []unstructured.Unstructured{
+		task("buildah", providerTypeRedHat),
+		task("maven", providerTypeCommunity),
+		addonResource("tekton.dev/v1beta1", "Pipeline", "s2i-go"),
+	}
+	client := fake.New()
+	manifest, err := mf.ManifestFrom(mf.Slice(resources), mf.UseClient(client))
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, manifest.Apply())
+
+	instance := &v1alpha1.TektonAddon{}
+	instance.Generation = 2
+	instance.Status.ObservedGeneration = 1
+	instance.Spec.Params = []v1alpha1.Param{{Name: v1alpha1.ClusterTasksParam, Value: "false"}}
+	util.AssertNoError(t, deleteDisabled(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(manifest.Resources()), 2)
+	util.AssertEqual(t, len(manifest.Filter(mf.ByName("buildah")).Resources()), 0)
+	if _, err := client.Get(&resources[0]); err == nil {
+		t.Error("buildah wasn't deleted")
+	}
+	_, err = client.Get(&resources[1])
+	util.AssertNoError(t, err)
+
+	// installed already, the resources are dropped but not deleted
+	util.AssertNoError(t, client.Create(&resources[0]))
+	manifest, err = mf.ManifestFrom(mf.Slice(resources), mf.UseClient(client))
+	util.AssertNoError(t, err)
+	instance.Status.ObservedGeneration = 2
+	util.AssertNoError(t, deleteDisabled(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(manifest.Resources()), 2)
+	_, err = client.Get(&resources[0])
+	util.AssertNoError(t, err)
+}
@@ -18,6 +18,7 @@ package tektonaddon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -29,10 +30,13 @@ import (
 	clientset "github.com/tektoncd/operator/pkg/client/clientset/versioned"
 	informer "github.com/tektoncd/operator/pkg/client/informers/externalversions/operator/v1alpha1"
 	tektonaddonreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektonaddon"
+	operatorlisters "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
 	tektonaddon "github.com/tektoncd/operator/pkg/reconciler/openshift/tektonaddon/pipelinetemplates"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
@@ -51,6 +55,10 @@ type Reconciler struct {
 	manifest mf.Manifest
 	// Platform-specific behavior to affect the transform
 	extension common.Extension
+	// overrideLister lists the TektonOverrides layered on top of the spec
+	overrideLister operatorlisters.TektonOverrideLister
+	// addonLister gets the latest generation of the TektonAddon being reconciled
+	addonLister operatorlisters.TektonAddonLister
 
 	pipelineInformer informer.TektonPipelineInformer
 	triggerInformer  informer.TektonTriggerInformer
@@ -69,20 +77,9 @@ const (
 var _ tektonaddonreconciler.Interface = (*Reconciler)(nil)
 var _ tektonaddonreconciler.Finalizer = (*Reconciler)(nil)
 
-var communityResourceURLs = []string{
-	"https://raw.githubusercontent.com/tektoncd/catalog/master/task/jib-maven/0.1/jib-maven.yaml",
-	"https://raw.githubusercontent.com/tektoncd/catalog/master/task/maven/0.1/maven.yaml",
-	"https://raw.githubusercontent.com/tektoncd/catalog/master/task/tkn/0.1/tkn.yaml",
-	"https://raw.githubusercontent.com/tektoncd/catalog/master/task/helm-upgrade-from-source/0.1/helm-upgrade-from-source.yaml",
-	"https://raw.githubusercontent.com/tektoncd/catalog/master/task/helm-upgrade-from-repo/0.1/helm-upgrade-from-repo.yaml",
-	"https://raw.githubusercontent.com/tektoncd/catalog/master/task/trigger-jenkins-job/0.1/trigger-jenkins-job.yaml",
-	"https://raw.githubusercontent.com/tektoncd/catalog/master/task/git-cli/0.1/git-cli.yaml",
-	"https://raw.githubusercontent.com/tektoncd/catalog/master/task/pull-request/0.1/pull-request.yaml",
-	"https://raw.githubusercontent.com/tektoncd/catalog/master/task/kubeconfig-creator/0.1/kubeconfig-creator.yaml",
-}
-
 // FinalizeKind removes all resources after deletion of a TektonTriggers.
 func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.TektonAddon) pkgreconciler.Event {
+	ctx = common.WithCorrelationID(ctx)
 	logger := logging.FromContext(ctx)
 
 	// List all TektonAddons to determine if cluster-scoped resources should be deleted.
@@ -107,6 +104,10 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.Tekton
 		logger.Error("Unable to fetch installed manifest; no cluster-scoped resources will be finalized", err)
 		return nil
 	}
+	if _, err := common.TargetCluster(ctx, r.kubeClientSet, manifest, original); err != nil {
+		logger.Error("Unable to reach the target cluster; no cluster-scoped resources will be finalized", err)
+		return nil
+	}
 	if err := common.Uninstall(ctx, manifest); err != nil {
 		logger.Error("Failed to finalize platform resources", err)
 	}
@@ -116,9 +117,9 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.Tekton
 // ReconcileKind compares the actual state with the desired, and attempts to
 // converge the two.
 func (r *Reconciler) ReconcileKind(ctx context.Context, tt *v1alpha1.TektonAddon) pkgreconciler.Event {
+	ctx = common.WithCorrelationID(ctx)
 	logger := logging.FromContext(ctx)
-	tt.Status.InitializeConditions()
-	tt.Status.ObservedGeneration = tt.Generation
+	common.InitializeStatus(tt)
 
 	logger.Infow("Reconciling TektonAddons", "status", tt.Status)
 
@@ -128,10 +129,24 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tt *v1alpha1.TektonAddon
 			tt.GetName(),
 		)
 		logger.Error(msg)
-		tt.GetStatus().MarkInstallFailed(msg)
-		return nil
+		operrors.MarkFailed(tt.GetStatus(), &operrors.ResourceIgnored{Err: errors.New(msg)})
+		return common.ObserveGeneration(ctx, tt, nil)
 	}
 
+	overrides, err := common.ComponentOverrides(r.overrideLister, v1alpha1.KindTektonAddon)
+	if err != nil {
+		return err
+	}
+	ctx = common.WithOverrides(ctx, overrides)
+	ctx = common.WithInstallerSets(ctx, r.operatorClientSet.OperatorV1alpha1().TektonInstallerSets())
+	ctx = common.WithLatestGeneration(ctx, func(name string) (int64, error) {
+		latest, err := r.addonLister.Get(name)
+		if err != nil {
+			return 0, err
+		}
+		return latest.Generation, nil
+	})
+
 	//find the valid tekton-pipeline installation
 	if _, err := common.PipelineReady(r.pipelineInformer); err != nil {
 		if err.Error() == common.PipelineNotReady {
@@ -155,39 +170,166 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tt *v1alpha1.TektonAddon
 		return err
 	}
 	tt.Status.MarkDependenciesInstalled()
+	if err := validatePause(tt.Spec.Pause); err != nil {
+		logger.Error(err)
+		tt.Status.MarkInstallFailedWithReason(v1alpha1.ReasonTransformError, err.Error())
+		return common.ObserveGeneration(ctx, tt, nil)
+	}
+	tt.Status.Paused = tt.Spec.Pause
+	if len(tt.Spec.Pause) != 0 {
+		logger.Infow("Sync of addon payloads paused", "payloads", tt.Spec.Pause)
+	}
+	if _, err := enabledParams(tt.Spec.Params); err != nil {
+		logger.Error(err)
+		tt.Status.MarkInstallFailedWithReason(v1alpha1.ReasonTransformError, err.Error())
+		return common.ObserveGeneration(ctx, tt, nil)
+	}
 
 	if err := r.extension.PreReconcile(ctx, tt); err != nil {
 		return err
 	}
+	manifest := r.manifest.Append()
+	if _, err := common.TargetCluster(ctx, r.kubeClientSet, &manifest, tt); err != nil {
+		operrors.MarkFailed(&tt.Status, err)
+		return err
+	}
+	base := manifest.Append()
+	if len(tt.Spec.Pause) != 0 {
+		retained, err := r.pausedResources(ctx, tt)
+		if err != nil {
+			tt.Status.MarkInstallFailedWithReason(v1alpha1.ReasonTransformError, err.Error())
+			return common.ObserveGeneration(ctx, tt, err)
+		}
+		ctx = common.WithRetainedResources(ctx, retained)
+	}
+
 	stages := common.Stages{
+		common.BlockDowngrades,
 		r.appendAddonTarget,
 		r.addonTransform,
-		common.Install,
+		deleteDisabled,
+		common.DebugBundle(r.kubeClientSet),
+		common.PinImageDigests,
+		common.VerifyImageSignatures,
+		common.PrecheckImagePulls,
+		common.ValidateCustomResources,
+		common.CheckResourceSizes,
+		common.SkipUnchanged(installGroups(providerTypeRedHat)),
 		common.CheckDeployments,
 	}
-	manifest := r.manifest.Append()
 	if err := stages.Execute(ctx, &manifest, tt); err != nil {
-		return err
+		return common.ObserveGeneration(ctx, tt, err)
+	}
+	if paused(tt, v1alpha1.AddonCommunityTasks) {
+		return common.ObserveGeneration(ctx, tt, nil)
 	}
 	// Install addon for community tasks
 	stages = common.Stages{
 		r.appendCommunityTarget,
 		r.communityTransform,
-		common.Install,
+		deleteDisabled,
+		common.PinImageDigests,
+		common.VerifyImageSignatures,
+		common.PrecheckImagePulls,
+		common.ValidateCustomResources,
+		common.CheckResourceSizes,
+		common.SkipUnchanged(installGroups(providerTypeCommunity)),
 		common.CheckDeployments,
 	}
-	manifest = r.manifest.Append()
-	return stages.Execute(ctx, &manifest, tt)
+	manifest = base.Append()
+	return common.ObserveGeneration(ctx, tt, stages.Execute(ctx, &manifest, tt))
 }
 
 // appendAddonTarget mutates the passed manifest by appending one
 // appropriate for the passed TektonComponent
 func (r *Reconciler) appendAddonTarget(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
-	if err := addPipelineTemplates(manifest); err != nil {
-		return err
+	instance := comp.(*v1alpha1.TektonAddon)
+	if !paused(instance, v1alpha1.AddonPipelineTemplates) {
+		if err := addPipelineTemplates(manifest); err != nil {
+			return err
+		}
 	}
 
-	return applyAddons(manifest)
+	return appendAddons(manifest, func(payload string) bool {
+		return !paused(instance, payload)
+	})
+}
+
+// appendPausedTarget mutates the passed manifest by appending the
+// resources of the paused sub-payloads of the addon
+func (r *Reconciler) appendPausedTarget(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	instance := comp.(*v1alpha1.TektonAddon)
+	if paused(instance, v1alpha1.AddonPipelineTemplates) {
+		if err := addPipelineTemplates(manifest); err != nil {
+			return err
+		}
+	}
+	return appendAddons(manifest, func(payload string) bool {
+		return payload != "" && paused(instance, payload)
+	})
+}
+
+// pausedResources returns the references to the resources of the paused
+// sub-payloads, which the installer sets keep as they are in the cluster
+// rather than deleting them as orphans.
+func (r *Reconciler) pausedResources(ctx context.Context, instance *v1alpha1.TektonAddon) ([]v1alpha1.InstalledResource, error) {
+	manifest := r.manifest.Append()
+	if err := (common.Stages{r.appendPausedTarget, r.addonTransform}).Execute(ctx, &manifest, instance); err != nil {
+		return nil, err
+	}
+	refs := make([]v1alpha1.InstalledResource, 0, len(manifest.Resources()))
+	for _, u := range manifest.Resources() {
+		refs = append(refs, v1alpha1.InstalledResource{
+			APIVersion: u.GetAPIVersion(),
+			Kind:       u.GetKind(),
+			Namespace:  u.GetNamespace(),
+			Name:       u.GetName(),
+		})
+	}
+	return refs, nil
+}
+
+// addonDirs maps the directories of the addon release to the sub-payloads
+// they hold
+var addonDirs = map[string]string{
+	"01-clustertriggerbindings": v1alpha1.AddonClusterTriggerBindings,
+	"02-clustertasks":           v1alpha1.AddonClusterTasks,
+	"03-pipelines":              v1alpha1.AddonPipelines,
+	"04-consolecli":             v1alpha1.AddonConsoleCLIDownload,
+	"05-optional":               v1alpha1.AddonOptional,
+}
+
+// pausable are the sub-payloads whose sync can be paused
+var pausable = []string{
+	v1alpha1.AddonClusterTriggerBindings,
+	v1alpha1.AddonClusterTasks,
+	v1alpha1.AddonPipelines,
+	v1alpha1.AddonConsoleCLIDownload,
+	v1alpha1.AddonOptional,
+	v1alpha1.AddonPipelineTemplates,
+	v1alpha1.AddonCommunityTasks,
+}
+
+// validatePause rejects the sub-payloads of spec.pause which don't exist,
+// e.g. misspelled, which would leave the sync running while it looks paused
+func validatePause(pause []string) error {
+	known := sets.NewString(pausable...)
+	for _, payload := range pause {
+		if !known.Has(payload) {
+			return fmt.Errorf("unknown sub-payload %q in spec.pause, expected one of %s", payload, strings.Join(pausable, ", "))
+		}
+	}
+	return nil
+}
+
+// paused returns true if the sync of the sub-payload is paused
+func paused(instance *v1alpha1.TektonAddon, payload string) bool {
+	for _, p := range instance.Spec.Pause {
+		if p == payload {
+			return true
+		}
+	}
+	return false
 }
 
 func addPipelineTemplates(manifest *mf.Manifest) error {
@@ -196,12 +338,26 @@ func addPipelineTemplates(manifest *mf.Manifest) error {
 	return tektonaddon.GeneratePipelineTemplates(addonLocation, manifest)
 }
 
-func applyAddons(manifest *mf.Manifest) error {
+// appendAddons appends the resources of the addon release whose
+// sub-payload is selected, the resources out of the directories of the
+// sub-payloads being of the "" one
+func appendAddons(manifest *mf.Manifest, selected func(payload string) bool) error {
 	koDataDir := os.Getenv(common.KoEnvKey)
 	addonLocation := filepath.Join(koDataDir, "tekton-addon")
 	var files []string
 	if err := filepath.Walk(addonLocation, func(path string, info os.FileInfo, err error) error {
-		files = append(files, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if payload, ok := addonDirs[info.Name()]; ok && !selected(payload) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if selected(addonPayload(addonLocation, path)) {
+			files = append(files, path)
+		}
 		return nil
 	}); err != nil {
 		return err
@@ -216,18 +372,53 @@ func applyAddons(manifest *mf.Manifest) error {
 	return nil
 }
 
+// addonPayload returns the sub-payload of the file of the addon release,
+// "" for a file out of the directories of the sub-payloads
+func addonPayload(addonLocation, path string) string {
+	rel, err := filepath.Rel(addonLocation, path)
+	if err != nil {
+		return ""
+	}
+	for _, dir := range strings.Split(filepath.ToSlash(rel), "/") {
+		if payload, ok := addonDirs[dir]; ok {
+			return payload
+		}
+	}
+	return ""
+}
+
 // appendCommunityTarget mutates the passed manifest by appending one
 // appropriate for the passed TektonComponent
 func (r *Reconciler) appendCommunityTarget(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
 	if runtime.GOARCH == "ppc64le" || runtime.GOARCH == "s390x" {
 		return nil
 	}
-	urls := strings.Join(communityResourceURLs, ",")
-	m, err := mf.ManifestFrom(mf.Path(urls))
+	instance := comp.(*v1alpha1.TektonAddon)
+	enabled, err := enabledParams(instance.Spec.Params)
 	if err != nil {
 		return err
 	}
-	*manifest = manifest.Append(m)
+	// disabled tasks are fetched only to be deleted, once
+	if !enabled[v1alpha1.CommunityClusterTasksParam] && instance.Status.GetObservedGeneration() == instance.GetGeneration() {
+		return nil
+	}
+	tasks := resolveCommunityTasks(instance.Spec.CommunityTasks)
+	var failed []string
+	for i := range tasks {
+		if tasks[i].Error == "" {
+			m, err := mf.ManifestFrom(mf.Path(tasks[i].URL))
+			if err == nil {
+				*manifest = manifest.Append(m)
+				continue
+			}
+			tasks[i].Error = err.Error()
+		}
+		failed = append(failed, tasks[i].Name)
+	}
+	instance.Status.CommunityTasks = tasks
+	if len(failed) != 0 {
+		return fmt.Errorf("failed to resolve community tasks: %s", strings.Join(failed, ", "))
+	}
 	return nil
 }
 
@@ -239,7 +430,12 @@ func (r *Reconciler) addonTransform(ctx context.Context, manifest *mf.Manifest,
 		injectLabel(labelProviderType, providerTypeRedHat, overwrite, "ClusterTask"),
 	}
 	extra = append(extra, r.extension.Transformers(instance)...)
-	return common.Transform(ctx, manifest, instance, extra...)
+	// spec.images come after extra to take precedence over the extension's images
+	return common.TransformWith(ctx, manifest, instance, common.TransformOptions{
+		Transformers: extra,
+		Images:       instance.Spec.Images,
+		Workers:      runtime.GOMAXPROCS(0),
+	})
 }
 
 // communityTransform mutates the passed manifest to one with common component
@@ -251,12 +447,23 @@ func (r *Reconciler) communityTransform(ctx context.Context, manifest *mf.Manife
 		injectLabel(labelProviderType, providerTypeCommunity, overwrite, "ClusterTask"),
 	}
 	extra = append(extra, r.extension.Transformers(instance)...)
-	return common.Transform(ctx, manifest, instance, extra...)
+	// spec.images come after extra to take precedence over the extension's images
+	return common.TransformWith(ctx, manifest, instance, common.TransformOptions{
+		Transformers: extra,
+		Images:       instance.Spec.Images,
+		Workers:      runtime.GOMAXPROCS(0),
+	})
 }
 
 func (r *Reconciler) installed(ctx context.Context, instance v1alpha1.TektonComponent) (*mf.Manifest, error) {
 	// Create new, empty manifest with valid client and logger
 	installed := r.manifest.Append()
+	// paused sub-payloads are installed all the same
+	if addon, ok := instance.(*v1alpha1.TektonAddon); ok && len(addon.Spec.Pause) != 0 {
+		addon = addon.DeepCopy()
+		addon.Spec.Pause = nil
+		instance = addon
+	}
 	// TODO: add ingress, etc
 	stages := common.Stages{r.appendAddonTarget, r.addonTransform, r.appendCommunityTarget, r.communityTransform}
 	err := stages.Execute(ctx, &installed, instance)
@@ -283,7 +490,7 @@ func replaceKind(fromKind, toKind string) mf.Transformer {
 	}
 }
 
-//injectLabel adds label key:value to a resource
+// injectLabel adds label key:value to a resource
 // overwritePolicy (Retain/Overwrite) decides whehther to overwrite an already existing label
 // []kinds specify the Kinds on which the label should be applied
 // if len(kinds) = 0, label will be apllied to all/any resources irrespective of its Kind
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonaddon
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+)
+
+func TestAppendAddonsPaused(t *testing.T) {
+	os.Setenv(common.KoEnvKey, "../../../../cmd/openshift/kodata")
+	defer os.Unsetenv(common.KoEnvKey)
+
+	instance := &v1alpha1.TektonAddon{}
+	all := mf.Manifest{}
+	util.AssertNoError(t, (&Reconciler{}).appendAddonTarget(context.Background(), &all, instance))
+	if len(all.Filter(mf.ByKind("ClusterTask")).Resources()) == 0 {
+		t.Fatal("appendAddonTarget() added no ClusterTasks")
+	}
+
+	instance.Spec.Pause = []string{v1alpha1.AddonClusterTasks}
+	manifest := mf.Manifest{}
+	util.AssertNoError(t, (&Reconciler{}).appendAddonTarget(context.Background(), &manifest, instance))
+	util.AssertEqual(t, len(manifest.Filter(mf.ByKind("ClusterTask")).Resources()), 0)
+	util.AssertEqual(t, len(manifest.Filter(mf.ByKind("ClusterTriggerBinding")).Resources()),
+		len(all.Filter(mf.ByKind("ClusterTriggerBinding")).Resources()))
+
+	// the resources of the paused directory, retained by the installer
+	// sets, are the ones it installed before, and only those
+	paused := mf.Manifest{}
+	util.AssertNoError(t, (&Reconciler{}).appendPausedTarget(context.Background(), &paused, instance))
+	util.AssertEqual(t, len(paused.Resources())+len(manifest.Resources()), len(all.Resources()))
+	util.AssertEqual(t, len(paused.Filter(mf.ByKind("ClusterTask")).Resources()),
+		len(all.Filter(mf.ByKind("ClusterTask")).Resources()))
+	util.AssertEqual(t, len(paused.Filter(mf.ByKind("ClusterTriggerBinding")).Resources()), 0)
+}
+
+func TestValidatePause(t *testing.T) {
+	util.AssertNoError(t, validatePause(nil))
+	util.AssertNoError(t, validatePause([]string{v1alpha1.AddonClusterTasks, v1alpha1.AddonCommunityTasks}))
+	err := validatePause([]string{v1alpha1.AddonClusterTasks, "clustertasks"})
+	util.AssertEqual(t, err.Error(), `unknown sub-payload "clustertasks" in spec.pause, expected one of `+
+		`clusterTriggerBindings, clusterTasks, pipelines, consoleCLIDownload, optional, pipelineTemplates, communityTasks`)
+}
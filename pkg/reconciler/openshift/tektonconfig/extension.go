@@ -90,11 +90,18 @@ func (oe openshiftExtension) PreReconcile(context.Context, v1alpha1.TektonCompon
 }
 func (oe openshiftExtension) PostReconcile(ctx context.Context, comp v1alpha1.TektonComponent) error {
 	configInstance := comp.(*v1alpha1.TektonConfig)
+	client := oe.operatorClientSet.OperatorV1alpha1()
 	if configInstance.Spec.Profile == common.ProfileAll {
-		if err := extension.CreateAddonCR(comp, oe.operatorClientSet.OperatorV1alpha1()); err != nil {
+		if err := extension.CreateAddonCR(comp, client); err != nil {
+			if ta, getErr := extension.GetAddon(client.TektonAddons(), common.AddonResourceName); getErr == nil {
+				common.RecordComponentFailures(ctx, configInstance, "TektonAddon", ta.Status.Conditions)
+			}
 			return err
 		}
 	}
+	if err := oe.reconcilePipelinesAsCode(ctx, configInstance); err != nil {
+		return err
+	}
 
 	// Run clean up jobs for OpenShift
 	if err := RemoveDeprecatedConfigCRD(ctx, &oe.manifest, configInstance); err != nil {
@@ -104,12 +111,32 @@ func (oe openshiftExtension) PostReconcile(ctx context.Context, comp v1alpha1.Te
 }
 func (oe openshiftExtension) Finalize(ctx context.Context, comp v1alpha1.TektonComponent) error {
 	configInstance := comp.(*v1alpha1.TektonConfig)
+	if err := extension.OpenShiftPipelinesAsCodeCRDelete(oe.operatorClientSet.OperatorV1alpha1().OpenShiftPipelinesAsCodes(), common.PACResourceName); err != nil {
+		return err
+	}
 	if configInstance.Spec.Profile == common.ProfileAll {
 		return extension.TektonAddonCRDelete(oe.operatorClientSet.OperatorV1alpha1().TektonAddons(), common.AddonResourceName)
 	}
 	return nil
 }
 
+// reconcilePipelinesAsCode creates the OpenShiftPipelinesAsCode of
+// spec.pipelinesAsCode, or deletes the one TektonConfig created once it is
+// unset
+func (oe openshiftExtension) reconcilePipelinesAsCode(ctx context.Context, config *v1alpha1.TektonConfig) error {
+	client := oe.operatorClientSet.OperatorV1alpha1()
+	if config.Spec.PipelinesAsCode == nil {
+		return extension.OpenShiftPipelinesAsCodeCRDelete(client.OpenShiftPipelinesAsCodes(), common.PACResourceName)
+	}
+	if err := extension.CreatePipelinesAsCodeCR(config, client); err != nil {
+		if pac, getErr := extension.GetPipelinesAsCode(client.OpenShiftPipelinesAsCodes(), common.PACResourceName); getErr == nil {
+			common.RecordComponentFailures(ctx, config, "OpenShiftPipelinesAsCode", pac.Status.Conditions)
+		}
+		return err
+	}
+	return nil
+}
+
 func RemoveDeprecatedConfigCRD(ctx context.Context, manifest *mf.Manifest, config *v1alpha1.TektonConfig) error {
 	// Remove deprecated config.operator.tekton.dev CRD
 	// by running 'oc delete crd config.operator.tekton.dev' in a kubernetes job
@@ -34,7 +34,7 @@ import (
 
 func CreateAddonCR(instance v1alpha1.TektonComponent, client operatorv1alpha1.OperatorV1alpha1Interface) error {
 	configInstance := instance.(*v1alpha1.TektonConfig)
-	if _, err := ensureTektonAddonExists(client.TektonAddons(), configInstance.Spec.TargetNamespace); err != nil {
+	if _, err := ensureTektonAddonExists(client.TektonAddons(), configInstance.Spec.CommonSpec); err != nil {
 		return errors.New(err.Error())
 	}
 	if _, err := waitForTektonAddonState(client.TektonAddons(), common.AddonResourceName,
@@ -45,7 +45,7 @@ func CreateAddonCR(instance v1alpha1.TektonComponent, client operatorv1alpha1.Op
 	return nil
 }
 
-func ensureTektonAddonExists(clients op.TektonAddonInterface, targetNS string) (*v1alpha1.TektonAddon, error) {
+func ensureTektonAddonExists(clients op.TektonAddonInterface, spec v1alpha1.CommonSpec) (*v1alpha1.TektonAddon, error) {
 	taCR, err := GetAddon(clients, common.AddonResourceName)
 	if err == nil {
 		return taCR, err
@@ -56,9 +56,7 @@ func ensureTektonAddonExists(clients op.TektonAddonInterface, targetNS string) (
 				Name: common.AddonResourceName,
 			},
 			Spec: v1alpha1.TektonAddonSpec{
-				CommonSpec: v1alpha1.CommonSpec{
-					TargetNamespace: targetNS,
-				},
+				CommonSpec: spec,
 			},
 		}
 		return clients.Create(context.TODO(), taCR, metav1.CreateOptions{})
@@ -9,6 +9,7 @@ import (
 
 	mf "github.com/manifestival/manifestival"
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operrors "github.com/tektoncd/operator/pkg/reconciler/errors"
 	"knative.dev/pkg/logging"
 )
 
@@ -23,7 +24,8 @@ func AppendCleanupTarget(ctx context.Context, manifest *mf.Manifest, instance v1
 }
 
 func CleanupTransforms(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
-	return common.Transform(ctx, manifest, instance)
+	// the cleanup jobs run in the namespace of the operator
+	return common.TransformWith(ctx, manifest, instance, common.TransformOptions{Namespace: common.PreserveNamespace})
 }
 
 func RunCleanup(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.TektonComponent) error {
@@ -31,8 +33,9 @@ func RunCleanup(ctx context.Context, manifest *mf.Manifest, instance v1alpha1.Te
 	logger.Debug("Running Cleanup Jobs on OpenShift")
 	status := instance.GetStatus()
 	if err := manifest.Apply(); err != nil {
-		status.MarkInstallFailed(err.Error())
-		return fmt.Errorf("failed to apply cleanup job: %w", err)
+		err = &operrors.ApplyFailed{Err: fmt.Errorf("failed to apply cleanup job: %w", err)}
+		operrors.MarkFailed(status, err)
+		return err
 	}
 	return nil
 }
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	op "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"knative.dev/pkg/test/logging"
+)
+
+// CreatePipelinesAsCodeCR creates the OpenShiftPipelinesAsCode of
+// spec.pipelinesAsCode, or updates its settings, and waits for it to be
+// ready.
+func CreatePipelinesAsCodeCR(instance v1alpha1.TektonComponent, client op.OperatorV1alpha1Interface) error {
+	configInstance := instance.(*v1alpha1.TektonConfig)
+	if _, err := ensureOpenShiftPipelinesAsCodeExists(client.OpenShiftPipelinesAsCodes(), configInstance.Spec.CommonSpec, *configInstance.Spec.PipelinesAsCode); err != nil {
+		return errors.New(err.Error())
+	}
+	if _, err := waitForOpenShiftPipelinesAsCodeState(client.OpenShiftPipelinesAsCodes(), common.PACResourceName,
+		isOpenShiftPipelinesAsCodeReady); err != nil {
+		log.Println("OpenShiftPipelinesAsCode is not in ready state: ", err)
+		return err
+	}
+	return nil
+}
+
+// ensureOpenShiftPipelinesAsCodeExists creates the OpenShiftPipelinesAsCode,
+// labelled as generated so that it is only deleted once spec.pipelinesAsCode
+// is unset if TektonConfig created it. The settings of an existing
+// OpenShiftPipelinesAsCode follow spec.pipelinesAsCode.
+func ensureOpenShiftPipelinesAsCodeExists(clients op.OpenShiftPipelinesAsCodeInterface, spec v1alpha1.CommonSpec, pac v1alpha1.PipelinesAsCodeProperties) (*v1alpha1.OpenShiftPipelinesAsCode, error) {
+	pacCR, err := GetPipelinesAsCode(clients, common.PACResourceName)
+	if err == nil {
+		if equality.Semantic.DeepEqual(pacCR.Spec.PipelinesAsCodeProperties, pac) {
+			return pacCR, nil
+		}
+		pacCR.Spec.PipelinesAsCodeProperties = pac
+		return clients.Update(context.TODO(), pacCR, metav1.UpdateOptions{})
+	}
+	if apierrs.IsNotFound(err) {
+		pacCR = &v1alpha1.OpenShiftPipelinesAsCode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   common.PACResourceName,
+				Labels: map[string]string{common.LabelGenerated: "true"},
+			},
+			Spec: v1alpha1.OpenShiftPipelinesAsCodeSpec{
+				CommonSpec:                spec,
+				PipelinesAsCodeProperties: pac,
+			},
+		}
+		return clients.Create(context.TODO(), pacCR, metav1.CreateOptions{})
+	}
+	return pacCR, err
+}
+
+func GetPipelinesAsCode(clients op.OpenShiftPipelinesAsCodeInterface, name string) (*v1alpha1.OpenShiftPipelinesAsCode, error) {
+	return clients.Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// waitForOpenShiftPipelinesAsCodeState polls the status of the
+// OpenShiftPipelinesAsCode called name from client every `interval` until
+// `inState` returns `true` indicating it is done, returns an error or timeout.
+func waitForOpenShiftPipelinesAsCodeState(clients op.OpenShiftPipelinesAsCodeInterface, name string,
+	inState func(s *v1alpha1.OpenShiftPipelinesAsCode, err error) (bool, error)) (*v1alpha1.OpenShiftPipelinesAsCode, error) {
+	span := logging.GetEmitableSpan(context.Background(), fmt.Sprintf("WaitForOpenShiftPipelinesAsCodeState/%s/%s", name, "OpenShiftPipelinesAsCodeIsReady"))
+	defer span.End()
+
+	var lastState *v1alpha1.OpenShiftPipelinesAsCode
+	waitErr := wait.PollImmediate(common.Interval, common.Timeout, func() (bool, error) {
+		lastState, err := clients.Get(context.TODO(), name, metav1.GetOptions{})
+		return inState(lastState, err)
+	})
+	if waitErr != nil {
+		return lastState, fmt.Errorf("OpenShiftPipelinesAsCode %s is not in desired state, got: %+v: %w: For more info Please check OpenShiftPipelinesAsCode CR status", name, lastState, waitErr)
+	}
+	return lastState, nil
+}
+
+// isOpenShiftPipelinesAsCodeReady will check the status conditions of the
+// OpenShiftPipelinesAsCode and return true if it is ready.
+func isOpenShiftPipelinesAsCodeReady(s *v1alpha1.OpenShiftPipelinesAsCode, err error) (bool, error) {
+	return s.Status.IsReady(), err
+}
+
+// OpenShiftPipelinesAsCodeCRDelete deletes the OpenShiftPipelinesAsCode
+// created by TektonConfig, one created by hand being left alone
+func OpenShiftPipelinesAsCodeCRDelete(clients op.OpenShiftPipelinesAsCodeInterface, name string) error {
+	pacCR, err := GetPipelinesAsCode(clients, name)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if pacCR.Labels[common.LabelGenerated] != "true" {
+		return nil
+	}
+	if err := clients.Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("OpenShiftPipelinesAsCode %q failed to delete: %v", name, err)
+	}
+	err = wait.PollImmediate(common.Interval, common.Timeout, func() (bool, error) {
+		_, err := clients.Get(context.TODO(), name, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("Timed out waiting on OpenShiftPipelinesAsCode to delete %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/client/injection/client/fake"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektonconfig/pipeline"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ts "knative.dev/pkg/reconciler/testing"
+)
+
+func TestEnsureOpenShiftPipelinesAsCodeExists(t *testing.T) {
+	ctx, _, _ := ts.SetupFakeContextWithCancel(t)
+	pacs := fake.Get(ctx).OperatorV1alpha1().OpenShiftPipelinesAsCodes()
+	tConfig := pipeline.GetTektonConfig()
+
+	settings := v1alpha1.PipelinesAsCodeProperties{Settings: map[string]string{"application-name": "CI"}}
+	pac, err := ensureOpenShiftPipelinesAsCodeExists(pacs, tConfig.Spec.CommonSpec, settings)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, pac.Labels[common.LabelGenerated], "true")
+	util.AssertEqual(t, pac.Spec.TargetNamespace, tConfig.Spec.TargetNamespace)
+
+	settings.Settings = map[string]string{"application-name": "Pipelines"}
+	_, err = ensureOpenShiftPipelinesAsCodeExists(pacs, tConfig.Spec.CommonSpec, settings)
+	util.AssertNoError(t, err)
+	pac, err = GetPipelinesAsCode(pacs, common.PACResourceName)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, pac.Spec.Settings["application-name"], "Pipelines")
+
+	util.AssertNoError(t, OpenShiftPipelinesAsCodeCRDelete(pacs, common.PACResourceName))
+	_, err = GetPipelinesAsCode(pacs, common.PACResourceName)
+	util.AssertEqual(t, apierrs.IsNotFound(err), true)
+}
+
+func TestOpenShiftPipelinesAsCodeCRDeleteKeepsUserCR(t *testing.T) {
+	ctx, _, _ := ts.SetupFakeContextWithCancel(t)
+	pacs := fake.Get(ctx).OperatorV1alpha1().OpenShiftPipelinesAsCodes()
+	util.AssertNoError(t, OpenShiftPipelinesAsCodeCRDelete(pacs, common.PACResourceName))
+
+	_, err := pacs.Create(context.TODO(), &v1alpha1.OpenShiftPipelinesAsCode{
+		ObjectMeta: metav1.ObjectMeta{Name: common.PACResourceName},
+	}, metav1.CreateOptions{})
+	util.AssertNoError(t, err)
+	util.AssertNoError(t, OpenShiftPipelinesAsCodeCRDelete(pacs, common.PACResourceName))
+	_, err = GetPipelinesAsCode(pacs, common.PACResourceName)
+	util.AssertNoError(t, err)
+}
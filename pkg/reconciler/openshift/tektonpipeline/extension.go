@@ -34,20 +34,24 @@ const (
 	// DefaultDisableAffinityAssistant is default value of disable affinity assistant flag
 	DefaultDisableAffinityAssistant = "true"
 	AnnotationPreserveNS            = "operator.tekton.dev/preserve-namespace"
-	AnnotationPreserveRBSubjectNS   = "operator.tekton.dev/preserve-rb-subject-namespace"
+	AnnotationPreserveRBSubjectNS   = common.AnnotationPreserveRBSubjectNS
 )
 
 // NoPlatform "generates" a NilExtension
-func OpenShiftExtension(context.Context) common.Extension {
-	return openshiftExtension{}
+func OpenShiftExtension(ctx context.Context) common.Extension {
+	return openshiftExtension{
+		images: common.ImageStoreFromContext(ctx),
+	}
 }
 
-type openshiftExtension struct{}
+type openshiftExtension struct {
+	images *common.ImageStore
+}
 
 func (oe openshiftExtension) Transformers(comp v1alpha1.TektonComponent) []mf.Transformer {
-	images := common.ToLowerCaseKeys(common.ImagesFromEnv(common.PipelinesImagePrefix))
+	images := common.ToLowerCaseKeys(oe.images.Images(common.PipelinesImagePrefix))
 	return []mf.Transformer{
-		common.DeploymentImages(images),
+		common.WorkloadImages(images),
 		injectDefaultSA(DefaultSA),
 		setDisableAffinityAssistant(DefaultDisableAffinityAssistant),
 		injectNamespaceRoleBindingConditional(AnnotationPreserveNS,
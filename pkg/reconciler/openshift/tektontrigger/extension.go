@@ -25,16 +25,20 @@ import (
 )
 
 // NoPlatform "generates" a NilExtension
-func OpenShiftExtension(context.Context) common.Extension {
-	return openshiftExtension{}
+func OpenShiftExtension(ctx context.Context) common.Extension {
+	return openshiftExtension{
+		images: common.ImageStoreFromContext(ctx),
+	}
 }
 
-type openshiftExtension struct{}
+type openshiftExtension struct {
+	images *common.ImageStore
+}
 
 func (oe openshiftExtension) Transformers(comp v1alpha1.TektonComponent) []mf.Transformer {
-	triggerImages := common.ToLowerCaseKeys(common.ImagesFromEnv(common.TriggersImagePrefix))
+	triggerImages := common.ToLowerCaseKeys(oe.images.Images(common.TriggersImagePrefix))
 	return []mf.Transformer{
-		common.DeploymentImages(triggerImages),
+		common.WorkloadImages(triggerImages),
 	}
 }
 func (oe openshiftExtension) PreReconcile(context.Context, v1alpha1.TektonComponent) error {
@@ -0,0 +1,356 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adopt moves a Tekton installed from its release YAML under the
+// management of the operator, without redeploying it.
+package adopt
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	mfc "github.com/manifestival/client-go-client"
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/pkg/injection/sharedmain"
+)
+
+const (
+	// Command is the name of the subcommand of the operator adopting an
+	// existing install
+	Command = "adopt"
+
+	// LabelAdopted marks the resources adopted by the operator, with the
+	// version of the release YAML they were installed from
+	LabelAdopted = "operator.tekton.dev/adopted"
+
+	versionLabel = "app.kubernetes.io/version"
+)
+
+// The outcomes of adopting a component
+const (
+	outcomeAdopted        = "adopted"
+	outcomePlanned        = "planned"
+	outcomeNotInstalled   = "not installed"
+	outcomeManaged        = "managed already"
+	outcomeVersionSkipped = "skipped"
+)
+
+// component is a component the operator can adopt, detected by the
+// deployment of its controller
+type component struct {
+	name       string
+	deployment string
+	new        func() v1alpha1.TektonComponent
+}
+
+var components = []component{
+	{common.PipelineResourceName, "tekton-pipelines-controller", func() v1alpha1.TektonComponent { return &v1alpha1.TektonPipeline{} }},
+	{common.TriggerResourceName, "tekton-triggers-controller", func() v1alpha1.TektonComponent { return &v1alpha1.TektonTrigger{} }},
+	{common.DashboardResourceName, "tekton-dashboard", func() v1alpha1.TektonComponent { return &v1alpha1.TektonDashboard{} }},
+	{common.ChainResourceName, "tekton-chains-controller", func() v1alpha1.TektonComponent { return &v1alpha1.TektonChain{} }},
+}
+
+// Options of an adoption
+type Options struct {
+	// DryRun computes and reports the adoption without changing anything
+	DryRun bool
+	// AllowUpgrade adopts components installed at another version than
+	// the one the operator installs, which the operator then upgrades
+	AllowUpgrade bool
+}
+
+// Result is the outcome of the adoption of a component
+type Result struct {
+	Kind             string
+	Namespace        string
+	InstalledVersion string
+	TargetVersion    string
+	Outcome          string
+	// Adopted are the resources labeled and owned by the component
+	Adopted []string
+	// Missing are the resources of the payload not found, which the
+	// operator creates
+	Missing []string
+	// Owned are the resources controlled by another owner already, which
+	// are labeled only
+	Owned []string
+}
+
+// Main runs the adopt subcommand with the arguments following it, printing
+// the summary of the adoption.
+func Main(args []string) {
+	flags := flag.NewFlagSet(Command, flag.ExitOnError)
+	kubeconfig := flags.String("kubeconfig", "", "Path to a kubeconfig, the in-cluster config if empty")
+	opts := Options{}
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "Report the adoption without changing anything")
+	flags.BoolVar(&opts.AllowUpgrade, "allow-upgrade", false, "Adopt components installed at another version than the one the operator installs")
+	flags.Parse(args)
+
+	cfg, err := sharedmain.GetConfig("", *kubeconfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error building kubeconfig:", err)
+		os.Exit(1)
+	}
+	client, err := mfc.NewClient(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error creating client:", err)
+		os.Exit(1)
+	}
+	results, err := Adopt(client, opts)
+	Print(os.Stdout, results)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Adoption failed:", err)
+		os.Exit(1)
+	}
+}
+
+// Adopt detects the components installed from their release YAML and not
+// managed by the operator, labels their resources as managed by the
+// operator, creates their custom resource and makes the resources owned by
+// it. A component failing to be adopted is left as it was. The operator
+// takes over from there, applying its payload over the resources in place.
+func Adopt(client mf.Client, opts Options) ([]Result, error) {
+	var results []Result
+	for _, c := range components {
+		if _, err := os.Stat(common.ComponentDir(c.new())); err != nil {
+			// not a payload of this operator
+			continue
+		}
+		result, err := adopt(client, c, opts)
+		results = append(results, result)
+		if err != nil {
+			return results, fmt.Errorf("failed to adopt %s: %w", result.Kind, err)
+		}
+	}
+	return results, nil
+}
+
+func adopt(client mf.Client, c component, opts Options) (Result, error) {
+	instance := c.new()
+	kind := instance.GroupVersionKind().Kind
+	result := Result{Kind: kind, TargetVersion: common.TargetVersion(instance)}
+
+	target, err := common.TargetManifest(instance)
+	if err != nil {
+		return result, err
+	}
+	deployments := target.Filter(mf.ByKind("Deployment"), mf.ByName(c.deployment)).Resources()
+	if len(deployments) == 0 {
+		return result, fmt.Errorf("deployment %s not found in the payload", c.deployment)
+	}
+	live, err := client.Get(&deployments[0])
+	if apierrors.IsNotFound(err) {
+		result.Outcome = outcomeNotInstalled
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Namespace = live.GetNamespace()
+	result.InstalledVersion = strings.TrimPrefix(live.GetLabels()[versionLabel], "v")
+	if metav1.GetControllerOf(live) != nil {
+		result.Outcome = outcomeManaged
+		return result, nil
+	}
+	if result.InstalledVersion != result.TargetVersion && !opts.AllowUpgrade {
+		result.Outcome = outcomeVersionSkipped
+		return result, nil
+	}
+
+	// the resources of the payload installed, if the operator ships it
+	manifest := target
+	if installed, err := common.Fetch(filepath.Join(common.ComponentDir(instance), result.InstalledVersion)); err == nil && result.InstalledVersion != "" {
+		manifest = installed
+	}
+	if manifest, err = manifest.Transform(mf.InjectNamespace(result.Namespace)); err != nil {
+		return result, err
+	}
+
+	// the resources are labeled first, for the informers of the operator
+	// to watch them, then owned by the component once it exists, so that
+	// the operator only reconciles resources it selects. Any failure rolls
+	// the changes made back.
+	var originals, toOwn []*unstructured.Unstructured
+	for _, u := range manifest.Resources() {
+		id := resourceID(&u)
+		live, err := client.Get(&u)
+		if apierrors.IsNotFound(err) {
+			result.Missing = append(result.Missing, id)
+			continue
+		}
+		if err != nil {
+			return result, rollback(client, originals, nil, err)
+		}
+		owned := metav1.GetControllerOf(live) != nil
+		if owned {
+			result.Owned = append(result.Owned, id)
+		} else {
+			result.Adopted = append(result.Adopted, id)
+			toOwn = append(toOwn, live)
+		}
+		if opts.DryRun {
+			continue
+		}
+		original := live.DeepCopy()
+		labels := live.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[LabelAdopted] = result.InstalledVersion
+		labels[v1alpha1.ManagedByKey] = v1alpha1.ManagedByValue
+		live.SetLabels(labels)
+		if err := client.Update(live); err != nil {
+			return result, rollback(client, append(originals, original), nil, fmt.Errorf("failed to label %s: %w", id, err))
+		}
+		originals = append(originals, original)
+	}
+	if opts.DryRun {
+		result.Outcome = outcomePlanned
+		return result, nil
+	}
+
+	owner, created, err := ensureComponent(client, c, kind, result.Namespace)
+	if err != nil {
+		return result, rollback(client, originals, created, err)
+	}
+	for _, u := range toOwn {
+		// the operator may be applying the payload already
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			live, err := client.Get(u)
+			if err != nil {
+				return err
+			}
+			live.SetOwnerReferences(append(live.GetOwnerReferences(), *owner))
+			return client.Update(live)
+		})
+		if err != nil {
+			return result, rollback(client, originals, created, fmt.Errorf("failed to adopt %s: %w", resourceID(u), err))
+		}
+	}
+	result.Outcome = outcomeAdopted
+	return result, nil
+}
+
+// ensureComponent creates the custom resource of the component installing
+// to the namespace, unless it exists, and returns the owner reference to
+// it, along with the custom resource if it was created.
+func ensureComponent(client mf.Client, c component, kind, namespace string) (*metav1.OwnerReference, *unstructured.Unstructured, error) {
+	cr := &unstructured.Unstructured{}
+	cr.SetAPIVersion(v1alpha1.SchemeGroupVersion.String())
+	cr.SetKind(kind)
+	cr.SetName(c.name)
+	var created *unstructured.Unstructured
+	live, err := client.Get(cr)
+	if apierrors.IsNotFound(err) {
+		if err := unstructured.SetNestedField(cr.Object, namespace, "spec", "targetNamespace"); err != nil {
+			return nil, nil, err
+		}
+		if err := client.Create(cr); err != nil {
+			return nil, nil, err
+		}
+		created = cr
+		live, err = client.Get(cr)
+	}
+	if err != nil {
+		return nil, created, err
+	}
+	return metav1.NewControllerRef(live, v1alpha1.SchemeGroupVersion.WithKind(kind)), created, nil
+}
+
+// rollback restores the labels and owner references of the resources to
+// the originals and deletes the custom resource created, if any, returning
+// err along with the failures to roll back. The custom resource is kept
+// when resources couldn't be restored, as their owner reference would
+// have them garbage collected with it.
+func rollback(client mf.Client, originals []*unstructured.Unstructured, created *unstructured.Unstructured, err error) error {
+	var failures []string
+	for _, original := range originals {
+		restore := func() error {
+			live, err := client.Get(original)
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			live.SetLabels(original.GetLabels())
+			live.SetOwnerReferences(original.GetOwnerReferences())
+			return client.Update(live)
+		}
+		if err := retry.RetryOnConflict(retry.DefaultRetry, restore); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", resourceID(original), err))
+		}
+	}
+	if created != nil && len(failures) == 0 {
+		if err := client.Delete(created); err != nil && !apierrors.IsNotFound(err) {
+			failures = append(failures, fmt.Sprintf("%s: %v", resourceID(created), err))
+		}
+	}
+	if len(failures) != 0 {
+		return fmt.Errorf("%w, rolling back failed for %s", err, strings.Join(failures, "; "))
+	}
+	return err
+}
+
+func resourceID(u *unstructured.Unstructured) string {
+	if u.GetNamespace() == "" {
+		return u.GetKind() + "/" + u.GetName()
+	}
+	return u.GetKind() + "/" + u.GetNamespace() + "/" + u.GetName()
+}
+
+// Print writes the summary of the adoption, then the resources of each
+// component adopted.
+func Print(w io.Writer, results []Result) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "COMPONENT\tNAMESPACE\tINSTALLED\tTARGET\tOUTCOME\tADOPTED\tOWNED\tMISSING")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%d\n", r.Kind, r.Namespace, r.InstalledVersion, r.TargetVersion,
+			r.Outcome, len(r.Adopted), len(r.Owned), len(r.Missing))
+	}
+	tw.Flush()
+	for _, r := range results {
+		for _, list := range []struct {
+			title     string
+			resources []string
+		}{
+			{"owned by another resource already, labeled only", r.Owned},
+			{"missing, created by the operator", r.Missing},
+		} {
+			if len(list.resources) == 0 {
+				continue
+			}
+			sorted := append([]string(nil), list.resources...)
+			sort.Strings(sorted)
+			fmt.Fprintf(w, "\n%s resources %s:\n", r.Kind, list.title)
+			for _, id := range sorted {
+				fmt.Fprintln(w, "  "+id)
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adopt
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	fake "github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// installed returns the resources of the payload matching the predicate,
+// as installed from the release YAML of the version
+func installed(t *testing.T, instance v1alpha1.TektonComponent, version string, predicate mf.Predicate) []runtime.Object {
+	t.Helper()
+	manifest, err := common.TargetManifest(instance)
+	util.AssertNoError(t, err)
+	var objs []runtime.Object
+	for _, u := range manifest.Filter(predicate).Resources() {
+		u := u
+		if u.GetKind() == "Deployment" {
+			labels := u.GetLabels()
+			labels[versionLabel] = version
+			u.SetLabels(labels)
+		}
+		objs = append(objs, &u)
+	}
+	return objs
+}
+
+func TestAdopt(t *testing.T) {
+	os.Setenv(common.KoEnvKey, "../../../../cmd/kubernetes/kodata")
+	defer os.Unsetenv(common.KoEnvKey)
+
+	pipeline := &v1alpha1.TektonPipeline{}
+	version := "v" + common.TargetVersion(pipeline)
+	objs := installed(t, pipeline, version, mf.Any(
+		mf.All(mf.ByKind("Deployment"), mf.ByName("tekton-pipelines-controller")),
+		mf.All(mf.ByKind("ConfigMap"), mf.ByName("feature-flags"))))
+	// the namespace is owned by another resource already
+	ns := installed(t, pipeline, version, mf.ByKind("Namespace"))[0].(*unstructured.Unstructured)
+	ns.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(&v1alpha1.TektonConfig{ObjectMeta: metav1.ObjectMeta{Name: "config"}}, v1alpha1.SchemeGroupVersion.WithKind("TektonConfig"))})
+	// triggers installed at a version the operator doesn't install
+	triggers := installed(t, &v1alpha1.TektonTrigger{}, "v0.1.0", mf.All(mf.ByKind("Deployment"), mf.ByName("tekton-triggers-controller")))
+	client := fake.New(append(append(objs, ns), triggers...)...)
+
+	results, err := Adopt(client, Options{DryRun: true})
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, len(results), len(components))
+	util.AssertEqual(t, results[0].Outcome, outcomePlanned)
+	util.AssertEqual(t, results[0].Namespace, "tekton-pipelines")
+	util.AssertEqual(t, len(results[0].Adopted), 2)
+	util.AssertEqual(t, len(results[0].Owned), 1)
+	util.AssertEqual(t, results[1].Outcome, outcomeVersionSkipped)
+	util.AssertEqual(t, results[2].Outcome, outcomeNotInstalled)
+	cr := &unstructured.Unstructured{}
+	cr.SetAPIVersion(v1alpha1.SchemeGroupVersion.String())
+	cr.SetKind(v1alpha1.KindTektonPipeline)
+	cr.SetName(common.PipelineResourceName)
+	if _, err := client.Get(cr); err == nil {
+		t.Fatal("the dry run created the TektonPipeline")
+	}
+
+	results, err = Adopt(client, Options{})
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, results[0].Outcome, outcomeAdopted)
+	live, err := client.Get(cr)
+	util.AssertNoError(t, err)
+	targetNamespace, _, _ := unstructured.NestedString(live.Object, "spec", "targetNamespace")
+	util.AssertEqual(t, targetNamespace, "tekton-pipelines")
+	for _, obj := range objs {
+		u, err := client.Get(obj.(*unstructured.Unstructured))
+		util.AssertNoError(t, err)
+		util.AssertEqual(t, u.GetLabels()[LabelAdopted], common.TargetVersion(pipeline))
+		util.AssertEqual(t, u.GetLabels()[v1alpha1.ManagedByKey], v1alpha1.ManagedByValue)
+		util.AssertEqual(t, metav1.GetControllerOf(u).Kind, v1alpha1.KindTektonPipeline)
+	}
+	u, err := client.Get(ns)
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, metav1.GetControllerOf(u).Kind, "TektonConfig")
+
+	// adopted components are managed by the operator from then on
+	results, err = Adopt(client, Options{AllowUpgrade: true})
+	util.AssertNoError(t, err)
+	util.AssertEqual(t, results[0].Outcome, outcomeManaged)
+	util.AssertEqual(t, results[1].Outcome, outcomeAdopted)
+
+	var out bytes.Buffer
+	Print(&out, results)
+	if !strings.Contains(out.String(), "TektonTrigger") || !strings.Contains(out.String(), "missing, created by the operator") {
+		t.Errorf("Print() = %q, wanted the summary of the triggers and their missing resources", out.String())
+	}
+}
+
+func TestAdoptRollback(t *testing.T) {
+	os.Setenv(common.KoEnvKey, "../../../../cmd/kubernetes/kodata")
+	defer os.Unsetenv(common.KoEnvKey)
+
+	pipeline := &v1alpha1.TektonPipeline{}
+	objs := installed(t, pipeline, "v"+common.TargetVersion(pipeline), mf.Any(
+		mf.All(mf.ByKind("Deployment"), mf.ByName("tekton-pipelines-controller")),
+		mf.All(mf.ByKind("ConfigMap"), mf.ByName("feature-flags"))))
+	originals := map[string]map[string]string{}
+	for _, obj := range objs {
+		u := obj.(*unstructured.Unstructured)
+		originals[u.GetName()] = u.DeepCopy().GetLabels()
+	}
+	client := fake.New(objs...)
+	update := client.Stubs.Update
+	client.Stubs.Update = func(u *unstructured.Unstructured) error {
+		if u.GetName() == "feature-flags" && len(u.GetOwnerReferences()) != 0 {
+			return errors.New("boom")
+		}
+		return update(u)
+	}
+
+	if _, err := Adopt(client, Options{}); err == nil {
+		t.Fatal("Adopt() succeeded, wanted the failure to own feature-flags")
+	}
+	cr := &unstructured.Unstructured{}
+	cr.SetAPIVersion(v1alpha1.SchemeGroupVersion.String())
+	cr.SetKind(v1alpha1.KindTektonPipeline)
+	cr.SetName(common.PipelineResourceName)
+	if _, err := client.Get(cr); !apierrors.IsNotFound(err) {
+		t.Fatalf("Get(TektonPipeline) = %v, wanted the created TektonPipeline deleted", err)
+	}
+	for _, obj := range objs {
+		u, err := client.Get(obj.(*unstructured.Unstructured))
+		util.AssertNoError(t, err)
+		util.AssertDeepEqual(t, u.GetLabels(), originals[u.GetName()])
+		util.AssertEqual(t, len(u.GetOwnerReferences()), 0)
+	}
+}
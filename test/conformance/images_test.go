@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"golang.org/x/mod/semver"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	imageOverridesDoc = "../../docs/ImageOverrides.md"
+	cmdDir            = "../../cmd"
+)
+
+var (
+	// documentedKey matches the rows of the key tables, e.g.
+	// | `IMAGE_PIPELINES_WEBHOOK` | `kubernetes/tekton-pipeline` |
+	documentedKey = regexp.MustCompile("^\\| `([A-Z0-9_]+)` \\| (.+) \\|$")
+	payloadRef    = regexp.MustCompile("`([a-z]+)/([a-z-]+)`")
+	prefixes      = []string{common.PipelinesImagePrefix, common.TriggersImagePrefix, common.AddonsImagePrefix, common.ResultsImagePrefix, common.ChainsImagePrefix, common.HubImagePrefix, common.PACImagePrefix, common.MAGImagePrefix}
+)
+
+// documentedKeys returns the payloads of each documented image key.
+func documentedKeys(t *testing.T) map[string][]string {
+	t.Helper()
+	f, err := os.Open(imageOverridesDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	keys := map[string][]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m := documentedKey.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		for _, p := range payloadRef.FindAllStringSubmatch(m[2], -1) {
+			keys[m[1]] = append(keys[m[1]], filepath.Join(p[1], "kodata", p[2]))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) == 0 {
+		t.Fatalf("no image keys documented in %s", imageOverridesDoc)
+	}
+	return keys
+}
+
+// latestPayload loads the latest release of a payload directory, e.g.
+// kubernetes/kodata/tekton-pipeline, with its subdirectories.
+func latestPayload(t *testing.T, payload string) mf.Manifest {
+	t.Helper()
+	dir := filepath.Join(cmdDir, payload)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []string
+	for _, f := range files {
+		if f.IsDir() && semver.IsValid("v"+f.Name()) {
+			versions = append(versions, f.Name())
+		}
+	}
+	if len(versions) == 0 {
+		t.Fatalf("no release of %s", dir)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return semver.Compare("v"+versions[i], "v"+versions[j]) > 0
+	})
+	manifest, err := mf.ManifestFrom(mf.Recursive(filepath.Join(dir, versions[0])))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manifest
+}
+
+// TestDocumentedImageKeys fails when a documented image key doesn't match
+// anything in the latest payloads it is documented for.
+func TestDocumentedImageKeys(t *testing.T) {
+	payloadKeys := map[string]map[string]bool{}
+	for key, payloads := range documentedKeys(t) {
+		prefix := ""
+		for _, p := range prefixes {
+			if strings.HasPrefix(key, p) {
+				prefix = p
+			}
+		}
+		if prefix == "" {
+			t.Errorf("documented key %s has none of the prefixes %v", key, prefixes)
+			continue
+		}
+		name := strings.ToLower(strings.TrimPrefix(key, prefix))
+		for _, payload := range payloads {
+			if _, ok := payloadKeys[payload]; !ok {
+				payloadKeys[payload] = map[string]bool{}
+				for _, k := range common.ImageKeys(latestPayload(t, payload)).List() {
+					payloadKeys[payload][k] = true
+				}
+			}
+			if !payloadKeys[payload][name] {
+				t.Errorf("documented key %s matches nothing in the latest %s payload", key, payload)
+			}
+		}
+	}
+}
+
+// TestImageOverridePrecedence checks the documented precedence of the
+// overrides against the latest pipelines payload: spec.images over the
+// ConfigMap over the environment of the operator.
+func TestImageOverridePrecedence(t *testing.T) {
+	for k, v := range map[string]string{
+		"IMAGE_PIPELINES_TEKTON_PIPELINES_CONTROLLER": "env/controller",
+		"IMAGE_PIPELINES_WEBHOOK":                     "env/webhook",
+		"IMAGE_PIPELINES_ARG__GIT_IMAGE":              "env/git",
+		"IMAGE_PIPELINES_ARG__NOP_IMAGE":              "env/nop",
+	} {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+	store := common.NewImageStore()
+	store.OnConfigMapChanged(&corev1.ConfigMap{Data: map[string]string{
+		"IMAGE_PIPELINES_WEBHOOK":        "configmap/webhook",
+		"IMAGE_PIPELINES_ARG__GIT_IMAGE": "configmap/git",
+	}})
+	spec := map[string]string{"tekton-pipelines-controller": "spec/controller", "arg__git_image": "spec/git"}
+
+	// the order of the transformers of the reconcilers
+	manifest, err := latestPayload(t, "kubernetes/kodata/tekton-pipeline").Transform(
+		common.WorkloadImages(common.ToLowerCaseKeys(store.Images(common.PipelinesImagePrefix))),
+		common.WorkloadImages(common.SpecImages(spec)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	images := map[string]string{}
+	for _, u := range manifest.Filter(mf.ByKind("Deployment")).Resources() {
+		containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "containers")
+		for _, c := range containers {
+			container := c.(map[string]interface{})
+			images[container["name"].(string)] = container["image"].(string)
+			args, _, _ := unstructured.NestedStringSlice(container, "args")
+			for i, arg := range args {
+				if strings.HasSuffix(arg, "-image") && i+1 < len(args) {
+					images[arg] = args[i+1]
+				}
+			}
+		}
+	}
+	for name, want := range map[string]string{
+		"tekton-pipelines-controller": "spec/controller",
+		"webhook":                     "configmap/webhook",
+		"-git-image":                  "spec/git",
+		"-nop-image":                  "env/nop",
+	} {
+		if images[name] != want {
+			t.Errorf("image of %s = %q, wanted %q", name, images[name], want)
+		}
+	}
+}